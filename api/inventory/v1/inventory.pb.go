@@ -0,0 +1,2292 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: api/inventory/v1/inventory.proto
+
+package inventoryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Bucket int32
+
+const (
+	Bucket_BUCKET_UNSPECIFIED Bucket = 0
+	Bucket_BUCKET_AVAILABLE   Bucket = 1
+	Bucket_BUCKET_QUARANTINED Bucket = 2
+	Bucket_BUCKET_DAMAGED     Bucket = 3
+	Bucket_BUCKET_IN_TRANSIT  Bucket = 4
+)
+
+// Enum value maps for Bucket.
+var (
+	Bucket_name = map[int32]string{
+		0: "BUCKET_UNSPECIFIED",
+		1: "BUCKET_AVAILABLE",
+		2: "BUCKET_QUARANTINED",
+		3: "BUCKET_DAMAGED",
+		4: "BUCKET_IN_TRANSIT",
+	}
+	Bucket_value = map[string]int32{
+		"BUCKET_UNSPECIFIED": 0,
+		"BUCKET_AVAILABLE":   1,
+		"BUCKET_QUARANTINED": 2,
+		"BUCKET_DAMAGED":     3,
+		"BUCKET_IN_TRANSIT":  4,
+	}
+)
+
+func (x Bucket) Enum() *Bucket {
+	p := new(Bucket)
+	*p = x
+	return p
+}
+
+func (x Bucket) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Bucket) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_inventory_v1_inventory_proto_enumTypes[0].Descriptor()
+}
+
+func (Bucket) Type() protoreflect.EnumType {
+	return &file_api_inventory_v1_inventory_proto_enumTypes[0]
+}
+
+func (x Bucket) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Bucket.Descriptor instead.
+func (Bucket) EnumDescriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{0}
+}
+
+type Product struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Output only
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Structured details such as color or size; values are strings, numbers or booleans
+	Attributes *structpb.Struct `protobuf:"bytes,4,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	Tags       []string         `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Units available on hand; for products with variants, the sum of variant stock
+	StockQuantity int32 `protobuf:"varint,6,opt,name=stock_quantity,json=stockQuantity,proto3" json:"stock_quantity,omitempty"`
+	// Output only: units allocated to open sales orders
+	ReservedQuantity  int32 `protobuf:"varint,7,opt,name=reserved_quantity,json=reservedQuantity,proto3" json:"reserved_quantity,omitempty"`
+	LowStockThreshold int32 `protobuf:"varint,8,opt,name=low_stock_threshold,json=lowStockThreshold,proto3" json:"low_stock_threshold,omitempty"`
+	// Unit stock is counted in, e.g. each or g (default each)
+	BaseUnit string `protobuf:"bytes,9,opt,name=base_unit,json=baseUnit,proto3" json:"base_unit,omitempty"`
+	// ISO 4217 currency code of unit_cost and sale_price (default USD)
+	Currency string `protobuf:"bytes,10,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Cost per unit in minor units
+	UnitCost int64 `protobuf:"varint,11,opt,name=unit_cost,json=unitCost,proto3" json:"unit_cost,omitempty"`
+	// Sale price per unit in minor units
+	SalePrice int64 `protobuf:"varint,12,opt,name=sale_price,json=salePrice,proto3" json:"sale_price,omitempty"`
+	// Preferred supplier, empty for none
+	SupplierId   string `protobuf:"bytes,13,opt,name=supplier_id,json=supplierId,proto3" json:"supplier_id,omitempty"`
+	LeadTimeDays int32  `protobuf:"varint,14,opt,name=lead_time_days,json=leadTimeDays,proto3" json:"lead_time_days,omitempty"`
+	LotTracked   bool   `protobuf:"varint,15,opt,name=lot_tracked,json=lotTracked,proto3" json:"lot_tracked,omitempty"`
+	Serialized   bool   `protobuf:"varint,16,opt,name=serialized,proto3" json:"serialized,omitempty"`
+	// Output only
+	CategoryIds []string `protobuf:"bytes,17,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	// Output only
+	Options []*OptionAxis `protobuf:"bytes,18,rep,name=options,proto3" json:"options,omitempty"`
+	// Output only
+	Variants []*Variant `protobuf:"bytes,19,rep,name=variants,proto3" json:"variants,omitempty"`
+	// Output only: products a kit is made of
+	Components []*KitComponent `protobuf:"bytes,20,rep,name=components,proto3" json:"components,omitempty"`
+	// Output only
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Output only
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetAttributes() *structpb.Struct {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Product) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Product) GetStockQuantity() int32 {
+	if x != nil {
+		return x.StockQuantity
+	}
+	return 0
+}
+
+func (x *Product) GetReservedQuantity() int32 {
+	if x != nil {
+		return x.ReservedQuantity
+	}
+	return 0
+}
+
+func (x *Product) GetLowStockThreshold() int32 {
+	if x != nil {
+		return x.LowStockThreshold
+	}
+	return 0
+}
+
+func (x *Product) GetBaseUnit() string {
+	if x != nil {
+		return x.BaseUnit
+	}
+	return ""
+}
+
+func (x *Product) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Product) GetUnitCost() int64 {
+	if x != nil {
+		return x.UnitCost
+	}
+	return 0
+}
+
+func (x *Product) GetSalePrice() int64 {
+	if x != nil {
+		return x.SalePrice
+	}
+	return 0
+}
+
+func (x *Product) GetSupplierId() string {
+	if x != nil {
+		return x.SupplierId
+	}
+	return ""
+}
+
+func (x *Product) GetLeadTimeDays() int32 {
+	if x != nil {
+		return x.LeadTimeDays
+	}
+	return 0
+}
+
+func (x *Product) GetLotTracked() bool {
+	if x != nil {
+		return x.LotTracked
+	}
+	return false
+}
+
+func (x *Product) GetSerialized() bool {
+	if x != nil {
+		return x.Serialized
+	}
+	return false
+}
+
+func (x *Product) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+func (x *Product) GetOptions() []*OptionAxis {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Product) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *Product) GetComponents() []*KitComponent {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *Product) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type OptionAxis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OptionAxis) Reset() {
+	*x = OptionAxis{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptionAxis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptionAxis) ProtoMessage() {}
+
+func (x *OptionAxis) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptionAxis.ProtoReflect.Descriptor instead.
+func (*OptionAxis) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OptionAxis) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OptionAxis) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type Variant struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku   string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	// Value per option axis, e.g. size: M
+	Options           map[string]string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	StockQuantity     int32             `protobuf:"varint,4,opt,name=stock_quantity,json=stockQuantity,proto3" json:"stock_quantity,omitempty"`
+	ReservedQuantity  int32             `protobuf:"varint,5,opt,name=reserved_quantity,json=reservedQuantity,proto3" json:"reserved_quantity,omitempty"`
+	LowStockThreshold int32             `protobuf:"varint,6,opt,name=low_stock_threshold,json=lowStockThreshold,proto3" json:"low_stock_threshold,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Variant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Variant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *Variant) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Variant) GetStockQuantity() int32 {
+	if x != nil {
+		return x.StockQuantity
+	}
+	return 0
+}
+
+func (x *Variant) GetReservedQuantity() int32 {
+	if x != nil {
+		return x.ReservedQuantity
+	}
+	return 0
+}
+
+func (x *Variant) GetLowStockThreshold() int32 {
+	if x != nil {
+		return x.LowStockThreshold
+	}
+	return 0
+}
+
+type KitComponent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ComponentId string                 `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	// Units of the component per kit
+	Quantity      int32 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KitComponent) Reset() {
+	*x = KitComponent{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KitComponent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KitComponent) ProtoMessage() {}
+
+func (x *KitComponent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KitComponent.ProtoReflect.Descriptor instead.
+func (*KitComponent) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KitComponent) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
+}
+
+func (x *KitComponent) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CreateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateProductRequest) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type GetProductRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Return the product as it was at this time, with stock replayed from the ledger
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetProductRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type ListProductsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only products assigned to this category
+	CategoryId string `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Also match products in sub-categories of category_id (default true)
+	IncludeDescendants *bool `protobuf:"varint,2,opt,name=include_descendants,json=includeDescendants,proto3,oneof" json:"include_descendants,omitempty"`
+	// Only products whose attributes have all of these values
+	Attributes map[string]string `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Only products carrying every one of these tags
+	Tags []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Only products whose stock is at or below their low-stock threshold
+	LowStock bool `protobuf:"varint,5,opt,name=low_stock,json=lowStock,proto3" json:"low_stock,omitempty"`
+	// List products as they were at this time, with stock replayed from the ledger
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsRequest) Reset() {
+	*x = ListProductsRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsRequest) ProtoMessage() {}
+
+func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListProductsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetIncludeDescendants() bool {
+	if x != nil && x.IncludeDescendants != nil {
+		return *x.IncludeDescendants
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetLowStock() bool {
+	if x != nil {
+		return x.LowStock
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type ListProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsResponse) ProtoMessage() {}
+
+func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type SearchProductsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Search text; supports quoted phrases, `or` and `-` exclusions
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Page size, 1 to 100 (default 20)
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRequest) ProtoMessage() {}
+
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SearchProductsResponse struct {
+	state   protoimpl.MessageState           `protogen:"open.v1"`
+	Results []*SearchProductsResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// Matches across all pages
+	Total         int64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchProductsResponse) GetResults() []*SearchProductsResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchProductsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchProductsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type UpdateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Product       *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type DeleteProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductRequest) Reset() {
+	*x = DeleteProductRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductRequest) ProtoMessage() {}
+
+func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RestoreProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreProductRequest) Reset() {
+	*x = RestoreProductRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreProductRequest) ProtoMessage() {}
+
+func (x *RestoreProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreProductRequest.ProtoReflect.Descriptor instead.
+func (*RestoreProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RestoreProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StockChangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Product ID
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Variant to change; required for products with variants
+	VariantId string `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	// Units to change, in unit; must be greater than 0
+	Quantity int32 `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Serial numbers, one per unit, for serialized products
+	Serials []string `protobuf:"bytes,4,rep,name=serials,proto3" json:"serials,omitempty"`
+	// Unit of quantity; defaults to the product's base unit
+	Unit          string `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockChangeRequest) Reset() {
+	*x = StockChangeRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockChangeRequest) ProtoMessage() {}
+
+func (x *StockChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockChangeRequest.ProtoReflect.Descriptor instead.
+func (*StockChangeRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StockChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StockChangeRequest) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *StockChangeRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockChangeRequest) GetSerials() []string {
+	if x != nil {
+		return x.Serials
+	}
+	return nil
+}
+
+func (x *StockChangeRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type StockChangeResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Units added or removed
+	Quantity      int32 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockChangeResponse) Reset() {
+	*x = StockChangeResponse{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockChangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockChangeResponse) ProtoMessage() {}
+
+func (x *StockChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockChangeResponse.ProtoReflect.Descriptor instead.
+func (*StockChangeResponse) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StockChangeResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *StockChangeResponse) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VariantId     string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReserveStockRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveStockResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Units reserved, which may be fewer than requested
+	Reserved      int32 `protobuf:"varint,2,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReserveStockResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ReserveStockResponse) GetReserved() int32 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+type ReleaseStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VariantId     string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseStockRequest) Reset() {
+	*x = ReleaseStockRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseStockRequest) ProtoMessage() {}
+
+func (x *ReleaseStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseStockRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReleaseStockRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReleaseStockRequest) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *ReleaseStockRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type MoveStockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Required for products with variants
+	VariantId string `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	From      Bucket `protobuf:"varint,3,opt,name=from,proto3,enum=inventory.v1.Bucket" json:"from,omitempty"`
+	To        Bucket `protobuf:"varint,4,opt,name=to,proto3,enum=inventory.v1.Bucket" json:"to,omitempty"`
+	// Units to move, in unit
+	Quantity int32 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Lot the units leave or enter when moving out of or into available stock
+	LotNumber string `protobuf:"bytes,6,opt,name=lot_number,json=lotNumber,proto3" json:"lot_number,omitempty"`
+	// Serial numbers moved out of or into available stock, for serialized products
+	Serials []string `protobuf:"bytes,7,rep,name=serials,proto3" json:"serials,omitempty"`
+	Note    string   `protobuf:"bytes,8,opt,name=note,proto3" json:"note,omitempty"`
+	// Unit of quantity; defaults to the product's base unit
+	Unit          string `protobuf:"bytes,9,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveStockRequest) Reset() {
+	*x = MoveStockRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveStockRequest) ProtoMessage() {}
+
+func (x *MoveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveStockRequest.ProtoReflect.Descriptor instead.
+func (*MoveStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MoveStockRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveStockRequest) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *MoveStockRequest) GetFrom() Bucket {
+	if x != nil {
+		return x.From
+	}
+	return Bucket_BUCKET_UNSPECIFIED
+}
+
+func (x *MoveStockRequest) GetTo() Bucket {
+	if x != nil {
+		return x.To
+	}
+	return Bucket_BUCKET_UNSPECIFIED
+}
+
+func (x *MoveStockRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *MoveStockRequest) GetLotNumber() string {
+	if x != nil {
+		return x.LotNumber
+	}
+	return ""
+}
+
+func (x *MoveStockRequest) GetSerials() []string {
+	if x != nil {
+		return x.Serials
+	}
+	return nil
+}
+
+func (x *MoveStockRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *MoveStockRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type GetStockBucketsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockBucketsRequest) Reset() {
+	*x = GetStockBucketsRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockBucketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockBucketsRequest) ProtoMessage() {}
+
+func (x *GetStockBucketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockBucketsRequest.ProtoReflect.Descriptor instead.
+func (*GetStockBucketsRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetStockBucketsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// BucketLevels is the stock of a product, or one of its variants, in every bucket.
+type BucketLevels struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Available     int32                  `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	Quarantined   int32                  `protobuf:"varint,4,opt,name=quarantined,proto3" json:"quarantined,omitempty"`
+	Damaged       int32                  `protobuf:"varint,5,opt,name=damaged,proto3" json:"damaged,omitempty"`
+	InTransit     int32                  `protobuf:"varint,6,opt,name=in_transit,json=inTransit,proto3" json:"in_transit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketLevels) Reset() {
+	*x = BucketLevels{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketLevels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketLevels) ProtoMessage() {}
+
+func (x *BucketLevels) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketLevels.ProtoReflect.Descriptor instead.
+func (*BucketLevels) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *BucketLevels) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *BucketLevels) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *BucketLevels) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *BucketLevels) GetQuarantined() int32 {
+	if x != nil {
+		return x.Quarantined
+	}
+	return 0
+}
+
+func (x *BucketLevels) GetDamaged() int32 {
+	if x != nil {
+		return x.Damaged
+	}
+	return 0
+}
+
+func (x *BucketLevels) GetInTransit() int32 {
+	if x != nil {
+		return x.InTransit
+	}
+	return 0
+}
+
+type StockBuckets struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Levels        []*BucketLevels        `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockBuckets) Reset() {
+	*x = StockBuckets{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockBuckets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockBuckets) ProtoMessage() {}
+
+func (x *StockBuckets) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockBuckets.ProtoReflect.Descriptor instead.
+func (*StockBuckets) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *StockBuckets) GetLevels() []*BucketLevels {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+type ListMovementsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Product ID
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReferenceType string `protobuf:"bytes,2,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"`
+	ReferenceId   string `protobuf:"bytes,3,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	// Only movements naming this serial number
+	Serial string `protobuf:"bytes,4,opt,name=serial,proto3" json:"serial,omitempty"`
+	// Only movements at or after this time
+	Since *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	// Only movements before this time
+	Until         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMovementsRequest) Reset() {
+	*x = ListMovementsRequest{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMovementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMovementsRequest) ProtoMessage() {}
+
+func (x *ListMovementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMovementsRequest.ProtoReflect.Descriptor instead.
+func (*ListMovementsRequest) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListMovementsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ListMovementsRequest) GetReferenceType() string {
+	if x != nil {
+		return x.ReferenceType
+	}
+	return ""
+}
+
+func (x *ListMovementsRequest) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
+func (x *ListMovementsRequest) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *ListMovementsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListMovementsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+type StockMovement struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId string                 `protobuf:"bytes,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	LotId     string                 `protobuf:"bytes,4,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Serials   []string               `protobuf:"bytes,5,rep,name=serials,proto3" json:"serials,omitempty"`
+	// Signed change in units; negative for stock leaving
+	Quantity int32 `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// For moves between buckets, the bucket the units left for or came from
+	Bucket Bucket `protobuf:"varint,7,opt,name=bucket,proto3,enum=inventory.v1.Bucket" json:"bucket,omitempty"`
+	// Why the stock changed, e.g. increment, receipt, sale or transfer
+	Reason string `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Cost per unit in minor units for stock entering; zero for stock leaving
+	UnitCost int64  `protobuf:"varint,9,opt,name=unit_cost,json=unitCost,proto3" json:"unit_cost,omitempty"`
+	Currency string `protobuf:"bytes,10,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Kind of document that caused the movement
+	ReferenceType string                 `protobuf:"bytes,11,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"`
+	ReferenceId   string                 `protobuf:"bytes,12,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	Note          string                 `protobuf:"bytes,13,opt,name=note,proto3" json:"note,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockMovement) Reset() {
+	*x = StockMovement{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockMovement) ProtoMessage() {}
+
+func (x *StockMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockMovement.ProtoReflect.Descriptor instead.
+func (*StockMovement) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StockMovement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StockMovement) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockMovement) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *StockMovement) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *StockMovement) GetSerials() []string {
+	if x != nil {
+		return x.Serials
+	}
+	return nil
+}
+
+func (x *StockMovement) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockMovement) GetBucket() Bucket {
+	if x != nil {
+		return x.Bucket
+	}
+	return Bucket_BUCKET_UNSPECIFIED
+}
+
+func (x *StockMovement) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StockMovement) GetUnitCost() int64 {
+	if x != nil {
+		return x.UnitCost
+	}
+	return 0
+}
+
+func (x *StockMovement) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *StockMovement) GetReferenceType() string {
+	if x != nil {
+		return x.ReferenceType
+	}
+	return ""
+}
+
+func (x *StockMovement) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
+func (x *StockMovement) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *StockMovement) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type ListMovementsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movements     []*StockMovement       `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMovementsResponse) Reset() {
+	*x = ListMovementsResponse{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMovementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMovementsResponse) ProtoMessage() {}
+
+func (x *ListMovementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMovementsResponse.ProtoReflect.Descriptor instead.
+func (*ListMovementsResponse) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListMovementsResponse) GetMovements() []*StockMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+type SearchProductsResponse_Result struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Relevance; higher is a better match
+	Rank float64 `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	// Name and description with matched words wrapped in <mark> tags
+	NameHighlight        string `protobuf:"bytes,3,opt,name=name_highlight,json=nameHighlight,proto3" json:"name_highlight,omitempty"`
+	DescriptionHighlight string `protobuf:"bytes,4,opt,name=description_highlight,json=descriptionHighlight,proto3" json:"description_highlight,omitempty"`
+	// The variant SKU that matched, if any
+	MatchedSku    string `protobuf:"bytes,5,opt,name=matched_sku,json=matchedSku,proto3" json:"matched_sku,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse_Result) Reset() {
+	*x = SearchProductsResponse_Result{}
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse_Result) ProtoMessage() {}
+
+func (x *SearchProductsResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_api_inventory_v1_inventory_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse_Result.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse_Result) Descriptor() ([]byte, []int) {
+	return file_api_inventory_v1_inventory_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *SearchProductsResponse_Result) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse_Result) GetRank() float64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchProductsResponse_Result) GetNameHighlight() string {
+	if x != nil {
+		return x.NameHighlight
+	}
+	return ""
+}
+
+func (x *SearchProductsResponse_Result) GetDescriptionHighlight() string {
+	if x != nil {
+		return x.DescriptionHighlight
+	}
+	return ""
+}
+
+func (x *SearchProductsResponse_Result) GetMatchedSku() string {
+	if x != nil {
+		return x.MatchedSku
+	}
+	return ""
+}
+
+var File_api_inventory_v1_inventory_proto protoreflect.FileDescriptor
+
+const file_api_inventory_v1_inventory_proto_rawDesc = "" +
+	"\n" +
+	" api/inventory/v1/inventory.proto\x12\finventory.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd9\x06\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x127\n" +
+	"\n" +
+	"attributes\x18\x04 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"attributes\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12%\n" +
+	"\x0estock_quantity\x18\x06 \x01(\x05R\rstockQuantity\x12+\n" +
+	"\x11reserved_quantity\x18\a \x01(\x05R\x10reservedQuantity\x12.\n" +
+	"\x13low_stock_threshold\x18\b \x01(\x05R\x11lowStockThreshold\x12\x1b\n" +
+	"\tbase_unit\x18\t \x01(\tR\bbaseUnit\x12\x1a\n" +
+	"\bcurrency\x18\n" +
+	" \x01(\tR\bcurrency\x12\x1b\n" +
+	"\tunit_cost\x18\v \x01(\x03R\bunitCost\x12\x1d\n" +
+	"\n" +
+	"sale_price\x18\f \x01(\x03R\tsalePrice\x12\x1f\n" +
+	"\vsupplier_id\x18\r \x01(\tR\n" +
+	"supplierId\x12$\n" +
+	"\x0elead_time_days\x18\x0e \x01(\x05R\fleadTimeDays\x12\x1f\n" +
+	"\vlot_tracked\x18\x0f \x01(\bR\n" +
+	"lotTracked\x12\x1e\n" +
+	"\n" +
+	"serialized\x18\x10 \x01(\bR\n" +
+	"serialized\x12!\n" +
+	"\fcategory_ids\x18\x11 \x03(\tR\vcategoryIds\x122\n" +
+	"\aoptions\x18\x12 \x03(\v2\x18.inventory.v1.OptionAxisR\aoptions\x121\n" +
+	"\bvariants\x18\x13 \x03(\v2\x15.inventory.v1.VariantR\bvariants\x12:\n" +
+	"\n" +
+	"components\x18\x14 \x03(\v2\x1a.inventory.v1.KitComponentR\n" +
+	"components\x129\n" +
+	"\n" +
+	"created_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"8\n" +
+	"\n" +
+	"OptionAxis\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\"\xa9\x02\n" +
+	"\aVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12<\n" +
+	"\aoptions\x18\x03 \x03(\v2\".inventory.v1.Variant.OptionsEntryR\aoptions\x12%\n" +
+	"\x0estock_quantity\x18\x04 \x01(\x05R\rstockQuantity\x12+\n" +
+	"\x11reserved_quantity\x18\x05 \x01(\x05R\x10reservedQuantity\x12.\n" +
+	"\x13low_stock_threshold\x18\x06 \x01(\x05R\x11lowStockThreshold\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"M\n" +
+	"\fKitComponent\x12!\n" +
+	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"G\n" +
+	"\x14CreateProductRequest\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.inventory.v1.ProductR\aproduct\"T\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\x05as_of\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\xf8\x02\n" +
+	"\x13ListProductsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x124\n" +
+	"\x13include_descendants\x18\x02 \x01(\bH\x00R\x12includeDescendants\x88\x01\x01\x12Q\n" +
+	"\n" +
+	"attributes\x18\x03 \x03(\v21.inventory.v1.ListProductsRequest.AttributesEntryR\n" +
+	"attributes\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x1b\n" +
+	"\tlow_stock\x18\x05 \x01(\bR\blowStock\x12/\n" +
+	"\x05as_of\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x16\n" +
+	"\x14_include_descendants\"I\n" +
+	"\x14ListProductsResponse\x121\n" +
+	"\bproducts\x18\x01 \x03(\v2\x15.inventory.v1.ProductR\bproducts\"[\n" +
+	"\x15SearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\xf0\x02\n" +
+	"\x16SearchProductsResponse\x12E\n" +
+	"\aresults\x18\x01 \x03(\v2+.inventory.v1.SearchProductsResponse.ResultR\aresults\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\x1a\xca\x01\n" +
+	"\x06Result\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.inventory.v1.ProductR\aproduct\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12%\n" +
+	"\x0ename_highlight\x18\x03 \x01(\tR\rnameHighlight\x123\n" +
+	"\x15description_highlight\x18\x04 \x01(\tR\x14descriptionHighlight\x12\x1f\n" +
+	"\vmatched_sku\x18\x05 \x01(\tR\n" +
+	"matchedSku\"W\n" +
+	"\x14UpdateProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\aproduct\x18\x02 \x01(\v2\x15.inventory.v1.ProductR\aproduct\"&\n" +
+	"\x14DeleteProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"'\n" +
+	"\x15RestoreProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8d\x01\n" +
+	"\x12StockChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x18\n" +
+	"\aserials\x18\x04 \x03(\tR\aserials\x12\x12\n" +
+	"\x04unit\x18\x05 \x01(\tR\x04unit\"b\n" +
+	"\x13StockChangeResponse\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.inventory.v1.ProductR\aproduct\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"`\n" +
+	"\x13ReserveStockRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"c\n" +
+	"\x14ReserveStockResponse\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.inventory.v1.ProductR\aproduct\x12\x1a\n" +
+	"\breserved\x18\x02 \x01(\x05R\breserved\"`\n" +
+	"\x13ReleaseStockRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\x8e\x02\n" +
+	"\x10MoveStockRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12(\n" +
+	"\x04from\x18\x03 \x01(\x0e2\x14.inventory.v1.BucketR\x04from\x12$\n" +
+	"\x02to\x18\x04 \x01(\x0e2\x14.inventory.v1.BucketR\x02to\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"lot_number\x18\x06 \x01(\tR\tlotNumber\x12\x18\n" +
+	"\aserials\x18\a \x03(\tR\aserials\x12\x12\n" +
+	"\x04note\x18\b \x01(\tR\x04note\x12\x12\n" +
+	"\x04unit\x18\t \x01(\tR\x04unit\"(\n" +
+	"\x16GetStockBucketsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xc5\x01\n" +
+	"\fBucketLevels\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x1c\n" +
+	"\tavailable\x18\x03 \x01(\x05R\tavailable\x12 \n" +
+	"\vquarantined\x18\x04 \x01(\x05R\vquarantined\x12\x18\n" +
+	"\adamaged\x18\x05 \x01(\x05R\adamaged\x12\x1d\n" +
+	"\n" +
+	"in_transit\x18\x06 \x01(\x05R\tinTransit\"B\n" +
+	"\fStockBuckets\x122\n" +
+	"\x06levels\x18\x01 \x03(\v2\x1a.inventory.v1.BucketLevelsR\x06levels\"\xec\x01\n" +
+	"\x14ListMovementsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0ereference_type\x18\x02 \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\x03 \x01(\tR\vreferenceId\x12\x16\n" +
+	"\x06serial\x18\x04 \x01(\tR\x06serial\x120\n" +
+	"\x05since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\"\xc4\x03\n" +
+	"\rStockMovement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\tR\tvariantId\x12\x15\n" +
+	"\x06lot_id\x18\x04 \x01(\tR\x05lotId\x12\x18\n" +
+	"\aserials\x18\x05 \x03(\tR\aserials\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x05R\bquantity\x12,\n" +
+	"\x06bucket\x18\a \x01(\x0e2\x14.inventory.v1.BucketR\x06bucket\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x1b\n" +
+	"\tunit_cost\x18\t \x01(\x03R\bunitCost\x12\x1a\n" +
+	"\bcurrency\x18\n" +
+	" \x01(\tR\bcurrency\x12%\n" +
+	"\x0ereference_type\x18\v \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\f \x01(\tR\vreferenceId\x12\x12\n" +
+	"\x04note\x18\r \x01(\tR\x04note\x12;\n" +
+	"\voccurred_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"R\n" +
+	"\x15ListMovementsResponse\x129\n" +
+	"\tmovements\x18\x01 \x03(\v2\x1b.inventory.v1.StockMovementR\tmovements*y\n" +
+	"\x06Bucket\x12\x16\n" +
+	"\x12BUCKET_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10BUCKET_AVAILABLE\x10\x01\x12\x16\n" +
+	"\x12BUCKET_QUARANTINED\x10\x02\x12\x12\n" +
+	"\x0eBUCKET_DAMAGED\x10\x03\x12\x15\n" +
+	"\x11BUCKET_IN_TRANSIT\x10\x042\x84\t\n" +
+	"\x0eProductService\x12J\n" +
+	"\rCreateProduct\x12\".inventory.v1.CreateProductRequest\x1a\x15.inventory.v1.Product\x12D\n" +
+	"\n" +
+	"GetProduct\x12\x1f.inventory.v1.GetProductRequest\x1a\x15.inventory.v1.Product\x12U\n" +
+	"\fListProducts\x12!.inventory.v1.ListProductsRequest\x1a\".inventory.v1.ListProductsResponse\x12[\n" +
+	"\x0eSearchProducts\x12#.inventory.v1.SearchProductsRequest\x1a$.inventory.v1.SearchProductsResponse\x12J\n" +
+	"\rUpdateProduct\x12\".inventory.v1.UpdateProductRequest\x1a\x15.inventory.v1.Product\x12K\n" +
+	"\rDeleteProduct\x12\".inventory.v1.DeleteProductRequest\x1a\x16.google.protobuf.Empty\x12L\n" +
+	"\x0eRestoreProduct\x12#.inventory.v1.RestoreProductRequest\x1a\x15.inventory.v1.Product\x12U\n" +
+	"\x0eIncrementStock\x12 .inventory.v1.StockChangeRequest\x1a!.inventory.v1.StockChangeResponse\x12U\n" +
+	"\x0eDecrementStock\x12 .inventory.v1.StockChangeRequest\x1a!.inventory.v1.StockChangeResponse\x12U\n" +
+	"\fReserveStock\x12!.inventory.v1.ReserveStockRequest\x1a\".inventory.v1.ReserveStockResponse\x12H\n" +
+	"\fReleaseStock\x12!.inventory.v1.ReleaseStockRequest\x1a\x15.inventory.v1.Product\x12G\n" +
+	"\tMoveStock\x12\x1e.inventory.v1.MoveStockRequest\x1a\x1a.inventory.v1.StockBuckets\x12S\n" +
+	"\x0fGetStockBuckets\x12$.inventory.v1.GetStockBucketsRequest\x1a\x1a.inventory.v1.StockBuckets\x12X\n" +
+	"\rListMovements\x12\".inventory.v1.ListMovementsRequest\x1a#.inventory.v1.ListMovementsResponseBHZFgithub.com/xxthunderblastxx/ase-challenge/api/inventory/v1;inventoryv1b\x06proto3"
+
+var (
+	file_api_inventory_v1_inventory_proto_rawDescOnce sync.Once
+	file_api_inventory_v1_inventory_proto_rawDescData []byte
+)
+
+func file_api_inventory_v1_inventory_proto_rawDescGZIP() []byte {
+	file_api_inventory_v1_inventory_proto_rawDescOnce.Do(func() {
+		file_api_inventory_v1_inventory_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_inventory_v1_inventory_proto_rawDesc), len(file_api_inventory_v1_inventory_proto_rawDesc)))
+	})
+	return file_api_inventory_v1_inventory_proto_rawDescData
+}
+
+var file_api_inventory_v1_inventory_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_inventory_v1_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_api_inventory_v1_inventory_proto_goTypes = []any{
+	(Bucket)(0),                           // 0: inventory.v1.Bucket
+	(*Product)(nil),                       // 1: inventory.v1.Product
+	(*OptionAxis)(nil),                    // 2: inventory.v1.OptionAxis
+	(*Variant)(nil),                       // 3: inventory.v1.Variant
+	(*KitComponent)(nil),                  // 4: inventory.v1.KitComponent
+	(*CreateProductRequest)(nil),          // 5: inventory.v1.CreateProductRequest
+	(*GetProductRequest)(nil),             // 6: inventory.v1.GetProductRequest
+	(*ListProductsRequest)(nil),           // 7: inventory.v1.ListProductsRequest
+	(*ListProductsResponse)(nil),          // 8: inventory.v1.ListProductsResponse
+	(*SearchProductsRequest)(nil),         // 9: inventory.v1.SearchProductsRequest
+	(*SearchProductsResponse)(nil),        // 10: inventory.v1.SearchProductsResponse
+	(*UpdateProductRequest)(nil),          // 11: inventory.v1.UpdateProductRequest
+	(*DeleteProductRequest)(nil),          // 12: inventory.v1.DeleteProductRequest
+	(*RestoreProductRequest)(nil),         // 13: inventory.v1.RestoreProductRequest
+	(*StockChangeRequest)(nil),            // 14: inventory.v1.StockChangeRequest
+	(*StockChangeResponse)(nil),           // 15: inventory.v1.StockChangeResponse
+	(*ReserveStockRequest)(nil),           // 16: inventory.v1.ReserveStockRequest
+	(*ReserveStockResponse)(nil),          // 17: inventory.v1.ReserveStockResponse
+	(*ReleaseStockRequest)(nil),           // 18: inventory.v1.ReleaseStockRequest
+	(*MoveStockRequest)(nil),              // 19: inventory.v1.MoveStockRequest
+	(*GetStockBucketsRequest)(nil),        // 20: inventory.v1.GetStockBucketsRequest
+	(*BucketLevels)(nil),                  // 21: inventory.v1.BucketLevels
+	(*StockBuckets)(nil),                  // 22: inventory.v1.StockBuckets
+	(*ListMovementsRequest)(nil),          // 23: inventory.v1.ListMovementsRequest
+	(*StockMovement)(nil),                 // 24: inventory.v1.StockMovement
+	(*ListMovementsResponse)(nil),         // 25: inventory.v1.ListMovementsResponse
+	nil,                                   // 26: inventory.v1.Variant.OptionsEntry
+	nil,                                   // 27: inventory.v1.ListProductsRequest.AttributesEntry
+	(*SearchProductsResponse_Result)(nil), // 28: inventory.v1.SearchProductsResponse.Result
+	(*structpb.Struct)(nil),               // 29: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),         // 30: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                 // 31: google.protobuf.Empty
+}
+var file_api_inventory_v1_inventory_proto_depIdxs = []int32{
+	29, // 0: inventory.v1.Product.attributes:type_name -> google.protobuf.Struct
+	2,  // 1: inventory.v1.Product.options:type_name -> inventory.v1.OptionAxis
+	3,  // 2: inventory.v1.Product.variants:type_name -> inventory.v1.Variant
+	4,  // 3: inventory.v1.Product.components:type_name -> inventory.v1.KitComponent
+	30, // 4: inventory.v1.Product.created_at:type_name -> google.protobuf.Timestamp
+	30, // 5: inventory.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 6: inventory.v1.Variant.options:type_name -> inventory.v1.Variant.OptionsEntry
+	1,  // 7: inventory.v1.CreateProductRequest.product:type_name -> inventory.v1.Product
+	30, // 8: inventory.v1.GetProductRequest.as_of:type_name -> google.protobuf.Timestamp
+	27, // 9: inventory.v1.ListProductsRequest.attributes:type_name -> inventory.v1.ListProductsRequest.AttributesEntry
+	30, // 10: inventory.v1.ListProductsRequest.as_of:type_name -> google.protobuf.Timestamp
+	1,  // 11: inventory.v1.ListProductsResponse.products:type_name -> inventory.v1.Product
+	28, // 12: inventory.v1.SearchProductsResponse.results:type_name -> inventory.v1.SearchProductsResponse.Result
+	1,  // 13: inventory.v1.UpdateProductRequest.product:type_name -> inventory.v1.Product
+	1,  // 14: inventory.v1.StockChangeResponse.product:type_name -> inventory.v1.Product
+	1,  // 15: inventory.v1.ReserveStockResponse.product:type_name -> inventory.v1.Product
+	0,  // 16: inventory.v1.MoveStockRequest.from:type_name -> inventory.v1.Bucket
+	0,  // 17: inventory.v1.MoveStockRequest.to:type_name -> inventory.v1.Bucket
+	21, // 18: inventory.v1.StockBuckets.levels:type_name -> inventory.v1.BucketLevels
+	30, // 19: inventory.v1.ListMovementsRequest.since:type_name -> google.protobuf.Timestamp
+	30, // 20: inventory.v1.ListMovementsRequest.until:type_name -> google.protobuf.Timestamp
+	0,  // 21: inventory.v1.StockMovement.bucket:type_name -> inventory.v1.Bucket
+	30, // 22: inventory.v1.StockMovement.occurred_at:type_name -> google.protobuf.Timestamp
+	24, // 23: inventory.v1.ListMovementsResponse.movements:type_name -> inventory.v1.StockMovement
+	1,  // 24: inventory.v1.SearchProductsResponse.Result.product:type_name -> inventory.v1.Product
+	5,  // 25: inventory.v1.ProductService.CreateProduct:input_type -> inventory.v1.CreateProductRequest
+	6,  // 26: inventory.v1.ProductService.GetProduct:input_type -> inventory.v1.GetProductRequest
+	7,  // 27: inventory.v1.ProductService.ListProducts:input_type -> inventory.v1.ListProductsRequest
+	9,  // 28: inventory.v1.ProductService.SearchProducts:input_type -> inventory.v1.SearchProductsRequest
+	11, // 29: inventory.v1.ProductService.UpdateProduct:input_type -> inventory.v1.UpdateProductRequest
+	12, // 30: inventory.v1.ProductService.DeleteProduct:input_type -> inventory.v1.DeleteProductRequest
+	13, // 31: inventory.v1.ProductService.RestoreProduct:input_type -> inventory.v1.RestoreProductRequest
+	14, // 32: inventory.v1.ProductService.IncrementStock:input_type -> inventory.v1.StockChangeRequest
+	14, // 33: inventory.v1.ProductService.DecrementStock:input_type -> inventory.v1.StockChangeRequest
+	16, // 34: inventory.v1.ProductService.ReserveStock:input_type -> inventory.v1.ReserveStockRequest
+	18, // 35: inventory.v1.ProductService.ReleaseStock:input_type -> inventory.v1.ReleaseStockRequest
+	19, // 36: inventory.v1.ProductService.MoveStock:input_type -> inventory.v1.MoveStockRequest
+	20, // 37: inventory.v1.ProductService.GetStockBuckets:input_type -> inventory.v1.GetStockBucketsRequest
+	23, // 38: inventory.v1.ProductService.ListMovements:input_type -> inventory.v1.ListMovementsRequest
+	1,  // 39: inventory.v1.ProductService.CreateProduct:output_type -> inventory.v1.Product
+	1,  // 40: inventory.v1.ProductService.GetProduct:output_type -> inventory.v1.Product
+	8,  // 41: inventory.v1.ProductService.ListProducts:output_type -> inventory.v1.ListProductsResponse
+	10, // 42: inventory.v1.ProductService.SearchProducts:output_type -> inventory.v1.SearchProductsResponse
+	1,  // 43: inventory.v1.ProductService.UpdateProduct:output_type -> inventory.v1.Product
+	31, // 44: inventory.v1.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
+	1,  // 45: inventory.v1.ProductService.RestoreProduct:output_type -> inventory.v1.Product
+	15, // 46: inventory.v1.ProductService.IncrementStock:output_type -> inventory.v1.StockChangeResponse
+	15, // 47: inventory.v1.ProductService.DecrementStock:output_type -> inventory.v1.StockChangeResponse
+	17, // 48: inventory.v1.ProductService.ReserveStock:output_type -> inventory.v1.ReserveStockResponse
+	1,  // 49: inventory.v1.ProductService.ReleaseStock:output_type -> inventory.v1.Product
+	22, // 50: inventory.v1.ProductService.MoveStock:output_type -> inventory.v1.StockBuckets
+	22, // 51: inventory.v1.ProductService.GetStockBuckets:output_type -> inventory.v1.StockBuckets
+	25, // 52: inventory.v1.ProductService.ListMovements:output_type -> inventory.v1.ListMovementsResponse
+	39, // [39:53] is the sub-list for method output_type
+	25, // [25:39] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_api_inventory_v1_inventory_proto_init() }
+func file_api_inventory_v1_inventory_proto_init() {
+	if File_api_inventory_v1_inventory_proto != nil {
+		return
+	}
+	file_api_inventory_v1_inventory_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_inventory_v1_inventory_proto_rawDesc), len(file_api_inventory_v1_inventory_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_inventory_v1_inventory_proto_goTypes,
+		DependencyIndexes: file_api_inventory_v1_inventory_proto_depIdxs,
+		EnumInfos:         file_api_inventory_v1_inventory_proto_enumTypes,
+		MessageInfos:      file_api_inventory_v1_inventory_proto_msgTypes,
+	}.Build()
+	File_api_inventory_v1_inventory_proto = out.File
+	file_api_inventory_v1_inventory_proto_goTypes = nil
+	file_api_inventory_v1_inventory_proto_depIdxs = nil
+}
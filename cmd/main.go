@@ -2,9 +2,13 @@ package main
 
 import (
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/xxthunderblastxx/ase-challenge/internal/server"
 	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/router"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/rpc"
 )
 
 func main() {
@@ -14,8 +18,30 @@ func main() {
 	// Register routes
 	router.NewRouter(s).RegisterRoutes()
 
+	// Serve the gRPC API on its own port
+	g := rpc.New(s.Appconfig, s.PostgresConn, s.Events)
+
 	// Run the application
-	if err := s.Listen(":" + s.Appconfig.Port); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
+	go func() {
+		if err := s.Listen(":" + s.Appconfig.Port); err != nil {
+			log.Fatalf("Failed to run server: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := g.ListenAndServe(":" + s.Appconfig.GRPCPort); err != nil {
+			log.Fatalf("Failed to run gRPC server: %v", err)
+		}
+	}()
+
+	// Wait for an interrupt and shut down gracefully
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	g.Shutdown(s.Appconfig.ShutdownTimeout)
+	if err := s.Shutdown(); err != nil {
+		log.Fatalf("Failed to shut down cleanly: %v", err)
 	}
 }
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,14 +16,113 @@ type PostgresConfig struct {
 	Password string
 	DB       string
 	Port     string
+
+	// Connection pool settings
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// Startup retry settings
+	ConnectRetries int
+	ConnectBackoff time.Duration
+
+	// Interval between background health checks
+	HealthCheckInterval time.Duration
+
+	// Optional read replica. Reads stay on the primary when ReplicaHost is empty.
+	ReplicaHost string
+	ReplicaPort string
+}
+
+// RateLimitConfig holds the per-client request budgets and body-size limits.
+type RateLimitConfig struct {
+	Enabled bool
+
+	// Token-bucket budgets, in requests per minute with the given burst size
+	ReadPerMinute  int
+	ReadBurst      int
+	WritePerMinute int
+	WriteBurst     int
+	BulkPerMinute  int
+	BulkBurst      int
+
+	// API keys that identify a client; requests with any other key are limited by IP
+	APIKeys []string
+
+	// Most clients each budget keeps a bucket for before evicting the least recently seen
+	MaxClients int
+
+	// Maximum request body size in bytes
+	BodyLimit int
+}
+
+// StorageConfig holds where attachment files are kept and how large they may be.
+type StorageConfig struct {
+	// Directory the local storage backend writes files under
+	Dir string
+
+	// Maximum attachment size in bytes; the upload route is exempt from the general body
+	// limit and held to MaxUploadBytes instead
+	MaxAttachmentBytes int
+
+	// How long the attachments of a deleted product are kept, so that restoring the product
+	// brings them back, before purging may remove them
+	OrphanRetention time.Duration
+}
+
+// multipartOverhead allows for the multipart headers and boundaries around an upload.
+const multipartOverhead = 64 * 1024
+
+// MaxUploadBytes is the largest request body the attachment upload route accepts.
+func (c StorageConfig) MaxUploadBytes() int {
+	return c.MaxAttachmentBytes + multipartOverhead
+}
+
+// TenantConfig holds how each request's tenant is resolved.
+type TenantConfig struct {
+	// Key that bearer tokens naming the tenant are signed with (HS256). When set, every
+	// tenant-scoped request must carry a token; the X-Tenant-ID header may only repeat its tenant.
+	TokenSecret string
+
+	// Tenant of requests that name none, unless Required is set
+	Default string
+
+	// Reject requests that name no tenant instead of using Default
+	Required bool
+}
+
+// GraphQLConfig holds the limits on GraphQL queries.
+type GraphQLConfig struct {
+	// Deepest nesting of fields a query may select
+	MaxDepth int
+
+	// Highest cost a query may have, counting each field once per item its lists can return
+	MaxComplexity int
+}
+
+// StreamConfig holds the settings of the real-time stock event streams.
+type StreamConfig struct {
+	// Interval between heartbeats on idle streams
+	HeartbeatInterval time.Duration
+
+	// Number of recent events kept for clients resuming with Last-Event-ID
+	HistorySize int
 }
 
 // AppConfig holds the application wide configuration.
 type AppConfig struct {
-	Port   string
-	Uptime time.Time
+	Port            string
+	GRPCPort        string
+	Uptime          time.Time
+	ShutdownTimeout time.Duration
 
-	PostgresConfig PostgresConfig
+	PostgresConfig  PostgresConfig
+	RateLimitConfig RateLimitConfig
+	StorageConfig   StorageConfig
+	TenantConfig    TenantConfig
+	GraphQLConfig   GraphQLConfig
+	StreamConfig    StreamConfig
 }
 
 // New reads the .env file and returns an AppConfig instance populated with environment variables.
@@ -32,14 +133,109 @@ func New() *AppConfig {
 	}
 
 	return &AppConfig{
-		Port:   os.Getenv("PORT"),
-		Uptime: time.Now(),
+		Port:            os.Getenv("PORT"),
+		GRPCPort:        getEnv("GRPC_PORT", "9090"),
+		Uptime:          time.Now(),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
 		PostgresConfig: PostgresConfig{
 			Host:     os.Getenv("POSTGRES_HOST"),
 			User:     os.Getenv("POSTGRES_USER"),
 			Password: os.Getenv("POSTGRES_PASSWORD"),
 			DB:       os.Getenv("POSTGRES_DB"),
 			Port:     os.Getenv("POSTGRES_PORT"),
+
+			MaxOpenConns:    getEnvInt("POSTGRES_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getEnvInt("POSTGRES_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetime: getEnvDuration("POSTGRES_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: getEnvDuration("POSTGRES_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+			ConnectRetries: getEnvInt("POSTGRES_CONNECT_RETRIES", 5),
+			ConnectBackoff: getEnvDuration("POSTGRES_CONNECT_BACKOFF", time.Second),
+
+			HealthCheckInterval: getEnvDuration("POSTGRES_HEALTH_CHECK_INTERVAL", 15*time.Second),
+
+			ReplicaHost: os.Getenv("POSTGRES_REPLICA_HOST"),
+			ReplicaPort: getEnv("POSTGRES_REPLICA_PORT", os.Getenv("POSTGRES_PORT")),
+		},
+		RateLimitConfig: RateLimitConfig{
+			Enabled: getEnvBool("RATE_LIMIT_ENABLED", true),
+
+			ReadPerMinute:  getEnvInt("RATE_LIMIT_READ_PER_MINUTE", 600),
+			ReadBurst:      getEnvInt("RATE_LIMIT_READ_BURST", 100),
+			WritePerMinute: getEnvInt("RATE_LIMIT_WRITE_PER_MINUTE", 120),
+			WriteBurst:     getEnvInt("RATE_LIMIT_WRITE_BURST", 20),
+			BulkPerMinute:  getEnvInt("RATE_LIMIT_BULK_PER_MINUTE", 10),
+			BulkBurst:      getEnvInt("RATE_LIMIT_BULK_BURST", 2),
+
+			APIKeys:    getEnvList("RATE_LIMIT_API_KEYS"),
+			MaxClients: getEnvInt("RATE_LIMIT_MAX_CLIENTS", 10000),
+
+			BodyLimit: getEnvInt("BODY_LIMIT_BYTES", 1024*1024),
+		},
+		StorageConfig: StorageConfig{
+			Dir:                getEnv("STORAGE_DIR", "data/attachments"),
+			MaxAttachmentBytes: getEnvInt("ATTACHMENT_MAX_BYTES", 10*1024*1024),
+			OrphanRetention:    getEnvDuration("ATTACHMENT_ORPHAN_RETENTION", 30*24*time.Hour),
+		},
+		TenantConfig: TenantConfig{
+			TokenSecret: os.Getenv("TENANT_TOKEN_SECRET"),
+			Default:     getEnv("TENANT_DEFAULT", "default"),
+			Required:    getEnvBool("TENANT_REQUIRED", false),
+		},
+		GraphQLConfig: GraphQLConfig{
+			MaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 8),
+			MaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 5000),
 		},
+		StreamConfig: StreamConfig{
+			HeartbeatInterval: getEnvDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+			HistorySize:       getEnvInt("STREAM_HISTORY_SIZE", 1000),
+		},
+	}
+}
+
+// getEnv returns the value of the environment variable or the fallback when it is unset.
+func getEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getEnvInt parses the environment variable as an int, returning the fallback when it is unset or invalid.
+func getEnvInt(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// getEnvBool parses the environment variable as a bool, returning the fallback when it is unset or invalid.
+func getEnvBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// getEnvList splits the comma-separated environment variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// getEnvDuration parses the environment variable as a time.Duration (e.g. "30s", "5m"),
+// returning the fallback when it is unset or invalid.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
 	}
+	return v
 }
@@ -0,0 +1,43 @@
+package attachment
+
+import (
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+)
+
+// Kind says how an attachment is presented.
+type Kind string
+
+const (
+	KindImage    Kind = "image"
+	KindDocument Kind = "document"
+)
+
+// ContentTypes maps each content type that can be uploaded to the kind of attachment it makes.
+var ContentTypes = map[string]Kind{
+	"image/jpeg":      KindImage,
+	"image/png":       KindImage,
+	"image/gif":       KindImage,
+	"image/webp":      KindImage,
+	"application/pdf": KindDocument,
+}
+
+// Attachment is a file, such as a product image or spec sheet, stored for a product.
+type Attachment struct {
+	model.BaseModel
+	ProductID    uuid.UUID `json:"product_id" gorm:"type:uuid;not null;index" openapi:"readonly"`
+	Filename     string    `json:"filename" gorm:"not null"`
+	ContentType  string    `json:"content_type" gorm:"not null"`
+	Size         int64     `json:"size" gorm:"not null" doc:"Size in bytes"`
+	Kind         Kind      `json:"kind" gorm:"not null" enum:"image,document"`
+	HasThumbnail bool      `json:"has_thumbnail" gorm:"not null;default:false" doc:"Images have a PNG thumbnail at most 256 pixels on each side"`
+	StorageKey   string    `json:"-" gorm:"not null"`
+	ThumbnailKey string    `json:"-"`
+}
+
+// Upload is a file received for a product.
+type Upload struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,26 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Repository interface {
+	Create(context.Context, *Attachment) error
+	GetAll(ctx context.Context, productID string) ([]Attachment, error)
+	GetByID(ctx context.Context, productID string, id string) (*Attachment, error)
+	// Delete removes the metadata row for good.
+	Delete(ctx context.Context, id string) error
+	// ListOrphaned returns the attachments of products that no longer exist, or that were
+	// deleted before deletedBefore.
+	ListOrphaned(ctx context.Context, deletedBefore time.Time) ([]Attachment, error)
+}
+
+// Storage keeps the contents of attachments under opaque keys.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,255 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	// UploadAttachment stores a file for the product, with a thumbnail if it is an image.
+	UploadAttachment(ctx context.Context, productID string, upload Upload) (*Attachment, error)
+	GetAttachments(ctx context.Context, productID string) ([]Attachment, error)
+	GetAttachmentByID(ctx context.Context, productID string, id string) (*Attachment, error)
+	// OpenAttachment returns the attachment and a reader for its file, or for its
+	// thumbnail when thumbnail is set. The caller must close the reader.
+	OpenAttachment(ctx context.Context, productID string, id string, thumbnail bool) (*Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, productID string, id string) error
+	// PurgeOrphaned removes the files and metadata of attachments whose product has been
+	// deleted for longer than the retention period, and returns how many it removed.
+	PurgeOrphaned(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo     Repository
+	storage  Storage
+	products product.Service
+	maxSize  int64
+	retain   time.Duration
+}
+
+// NewService returns a Service that stores files through storage and accepts uploads of
+// up to maxSize bytes. The attachments of a deleted product are kept for retain, so that
+// restoring the product within that time brings them back.
+func NewService(repo Repository, storage Storage, products product.Service, maxSize int64, retain time.Duration) Service {
+	return &service{
+		repo:     repo,
+		storage:  storage,
+		products: products,
+		maxSize:  maxSize,
+		retain:   retain,
+	}
+}
+
+// UploadAttachment implements Service.
+func (s *service) UploadAttachment(ctx context.Context, productID string, upload Upload) (*Attachment, error) {
+	p, err := s.products.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(upload.Data) == 0 {
+		return nil, apperrors.NewMissingRequiredDataError("file")
+	}
+
+	if int64(len(upload.Data)) > s.maxSize {
+		return nil, apperrors.NewAttachmentTooLargeError(s.maxSize)
+	}
+
+	contentType, kind, err := validateContentType(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Attachment{
+		ProductID:   p.ID,
+		Filename:    cleanFilename(upload.Filename),
+		ContentType: contentType,
+		Size:        int64(len(upload.Data)),
+		Kind:        kind,
+	}
+	a.ID = uuid.New()
+	a.StorageKey = storageKey(a, "original")
+
+	var thumbnail []byte
+	if kind == KindImage {
+		if thumbnail, err = makeThumbnail(upload.Data); err != nil {
+			return nil, err
+		}
+		a.ThumbnailKey = storageKey(a, "thumbnail")
+		a.HasThumbnail = true
+	}
+
+	if err := s.storage.Put(ctx, a.StorageKey, upload.Data); err != nil {
+		return nil, apperrors.NewInternalServerError("failed to store attachment: " + err.Error())
+	}
+
+	if thumbnail != nil {
+		if err := s.storage.Put(ctx, a.ThumbnailKey, thumbnail); err != nil {
+			s.removeFiles(ctx, a)
+			return nil, apperrors.NewInternalServerError("failed to store thumbnail: " + err.Error())
+		}
+	}
+
+	if err := s.repo.Create(ctx, a); err != nil {
+		s.removeFiles(ctx, a)
+		return nil, apperrors.NewDatabaseError("failed to create attachment: " + err.Error())
+	}
+
+	return a, nil
+}
+
+// GetAttachments implements Service.
+func (s *service) GetAttachments(ctx context.Context, productID string) ([]Attachment, error) {
+	if _, err := s.products.GetProductByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.GetAll(ctx, productID)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve attachments: " + err.Error())
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentByID implements Service.
+func (s *service) GetAttachmentByID(ctx context.Context, productID string, id string) (*Attachment, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("attachmentId")
+	}
+
+	if _, err := s.products.GetProductByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	a, err := s.repo.GetByID(ctx, productID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewAttachmentNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve attachment: " + err.Error())
+	}
+
+	return a, nil
+}
+
+// OpenAttachment implements Service.
+func (s *service) OpenAttachment(ctx context.Context, productID string, id string, thumbnail bool) (*Attachment, io.ReadCloser, error) {
+	a, err := s.GetAttachmentByID(ctx, productID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := a.StorageKey
+	if thumbnail {
+		if !a.HasThumbnail {
+			return nil, nil, apperrors.NewNotFoundError("Attachment " + id + " has no thumbnail")
+		}
+		key = a.ThumbnailKey
+	}
+
+	r, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalServerError("failed to read attachment: " + err.Error())
+	}
+
+	return a, r, nil
+}
+
+// DeleteAttachment implements Service. The metadata row goes first, so a failure to
+// remove the files leaves unreferenced files rather than a row pointing at nothing.
+func (s *service) DeleteAttachment(ctx context.Context, productID string, id string) error {
+	a, err := s.GetAttachmentByID(ctx, productID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.remove(ctx, a)
+}
+
+// PurgeOrphaned implements Service.
+func (s *service) PurgeOrphaned(ctx context.Context) (int, error) {
+	orphaned, err := s.repo.ListOrphaned(ctx, time.Now().Add(-s.retain))
+	if err != nil {
+		return 0, apperrors.NewDatabaseError("failed to retrieve orphaned attachments: " + err.Error())
+	}
+
+	for i := range orphaned {
+		if err := s.remove(ctx, &orphaned[i]); err != nil {
+			return i, err
+		}
+	}
+
+	return len(orphaned), nil
+}
+
+func (s *service) remove(ctx context.Context, a *Attachment) error {
+	if err := s.repo.Delete(ctx, a.ID.String()); err != nil {
+		return apperrors.NewDatabaseError("failed to delete attachment: " + err.Error())
+	}
+
+	for _, key := range []string{a.StorageKey, a.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return apperrors.NewInternalServerError("failed to delete attachment file: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// removeFiles deletes the files of an attachment whose upload failed part-way. Errors are
+// ignored: the upload has already failed and the files are unreferenced.
+func (s *service) removeFiles(ctx context.Context, a *Attachment) {
+	_ = s.storage.Delete(ctx, a.StorageKey)
+	if a.ThumbnailKey != "" {
+		_ = s.storage.Delete(ctx, a.ThumbnailKey)
+	}
+}
+
+// validateContentType checks that the declared content type may be uploaded and that the
+// file's contents match it, so a renamed executable is not stored as a PDF.
+func validateContentType(upload Upload) (string, Kind, error) {
+	declared, _, err := mime.ParseMediaType(upload.ContentType)
+	if err != nil {
+		return "", "", apperrors.NewInvalidInputError("invalid content type: " + upload.ContentType)
+	}
+
+	kind, ok := ContentTypes[declared]
+	if !ok {
+		return "", "", apperrors.NewInvalidInputError("content type " + declared + " is not allowed; upload a JPEG, PNG, GIF or WebP image, or a PDF")
+	}
+
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(upload.Data))
+	if detected != declared {
+		return "", "", apperrors.NewInvalidInputError("file contents are " + detected + ", not " + declared)
+	}
+
+	return declared, kind, nil
+}
+
+// cleanFilename keeps the base name of an uploaded file, dropping any client-side path.
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+func storageKey(a *Attachment, variant string) string {
+	return "products/" + a.ProductID.String() + "/" + a.ID.String() + "/" + variant
+}
@@ -0,0 +1,269 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+// Attachments of products missing from products, or deleted before the cutoff, are orphaned.
+type mockRepo struct {
+	attachments map[string]*Attachment
+	products    map[string]*product.Product
+}
+
+func (m *mockRepo) Create(_ context.Context, a *Attachment) error {
+	stored := *a
+	m.attachments[a.ID.String()] = &stored
+	return nil
+}
+
+func (m *mockRepo) GetAll(_ context.Context, productID string) ([]Attachment, error) {
+	out := []Attachment{}
+	for _, a := range m.attachments {
+		if a.ProductID.String() == productID {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRepo) GetByID(_ context.Context, productID string, id string) (*Attachment, error) {
+	a, ok := m.attachments[id]
+	if !ok || a.ProductID.String() != productID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (m *mockRepo) Delete(_ context.Context, id string) error {
+	delete(m.attachments, id)
+	return nil
+}
+
+func (m *mockRepo) ListOrphaned(_ context.Context, deletedBefore time.Time) ([]Attachment, error) {
+	var out []Attachment
+	for _, a := range m.attachments {
+		if p, ok := m.products[a.ProductID.String()]; !ok || p.DeletedAt.Valid && p.DeletedAt.Time.Before(deletedBefore) {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+// memoryStorage keeps objects in a map.
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Put(_ context.Context, key string, data []byte) error {
+	s[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s memoryStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+type stubProducts struct {
+	product.Service
+
+	products map[string]*product.Product
+}
+
+func (s *stubProducts) GetProductByID(_ context.Context, id string) (*product.Product, error) {
+	p, ok := s.products[id]
+	if !ok {
+		return nil, apperrors.NewProductNotFoundError(id)
+	}
+	return p, nil
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func pngImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestService_Attachments(t *testing.T) {
+	widget := &product.Product{Name: "Widget"}
+	widget.ID = uuid.New()
+	pid := widget.ID.String()
+
+	products := map[string]*product.Product{pid: widget}
+	repo := &mockRepo{attachments: map[string]*Attachment{}, products: products}
+	files := memoryStorage{}
+
+	svc := NewService(repo, files, &stubProducts{products: products}, 64*1024, time.Hour)
+	ctx := context.Background()
+
+	var photo *Attachment
+
+	t.Run("image upload stores a thumbnail", func(t *testing.T) {
+		var err error
+		photo, err = svc.UploadAttachment(ctx, pid, Upload{
+			Filename:    `C:\photos\widget.png`,
+			ContentType: "image/png",
+			Data:        pngImage(t, 1024, 512),
+		})
+		assertNoError(t, err)
+
+		if photo.Kind != KindImage || !photo.HasThumbnail || photo.Filename != "widget.png" {
+			t.Fatalf("unexpected attachment: %+v", photo)
+		}
+
+		_, r, err := svc.OpenAttachment(ctx, pid, photo.ID.String(), true)
+		assertNoError(t, err)
+		defer r.Close()
+
+		cfg, err := png.DecodeConfig(r)
+		assertNoError(t, err)
+		if cfg.Width != ThumbnailSize || cfg.Height != ThumbnailSize/2 {
+			t.Fatalf("expected a %dx%d thumbnail, got %dx%d", ThumbnailSize, ThumbnailSize/2, cfg.Width, cfg.Height)
+		}
+	})
+
+	t.Run("document upload has no thumbnail", func(t *testing.T) {
+		a, err := svc.UploadAttachment(ctx, pid, Upload{
+			Filename:    "spec.pdf",
+			ContentType: "application/pdf",
+			Data:        []byte("%PDF-1.7\n%spec sheet\n"),
+		})
+		assertNoError(t, err)
+
+		if a.Kind != KindDocument || a.HasThumbnail {
+			t.Fatalf("unexpected attachment: %+v", a)
+		}
+
+		_, _, err = svc.OpenAttachment(ctx, pid, a.ID.String(), true)
+		assertAppErrorCode(t, err, apperrors.NotFoundError)
+	})
+
+	t.Run("error when contents do not match the content type", func(t *testing.T) {
+		_, err := svc.UploadAttachment(ctx, pid, Upload{Filename: "spec.pdf", ContentType: "application/pdf", Data: []byte("MZ\x90\x00")})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on a content type that is not allowed", func(t *testing.T) {
+		_, err := svc.UploadAttachment(ctx, pid, Upload{Filename: "notes.txt", ContentType: "text/plain", Data: []byte("hello")})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on a file over the size limit", func(t *testing.T) {
+		data := append([]byte("%PDF-1.7\n"), make([]byte, 64*1024)...)
+		_, err := svc.UploadAttachment(ctx, pid, Upload{Filename: "big.pdf", ContentType: "application/pdf", Data: data})
+		assertAppErrorCode(t, err, apperrors.PayloadTooLarge)
+	})
+
+	t.Run("error on unknown product", func(t *testing.T) {
+		_, err := svc.GetAttachments(ctx, uuid.NewString())
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+	})
+
+	t.Run("error on an attachment of another product", func(t *testing.T) {
+		other := &product.Product{Name: "Gadget"}
+		other.ID = uuid.New()
+		products[other.ID.String()] = other
+
+		_, err := svc.GetAttachmentByID(ctx, other.ID.String(), photo.ID.String())
+		assertAppErrorCode(t, err, apperrors.AttachmentNotFound)
+	})
+
+	t.Run("delete removes the files", func(t *testing.T) {
+		assertNoError(t, svc.DeleteAttachment(ctx, pid, photo.ID.String()))
+
+		if _, ok := files[photo.StorageKey]; ok {
+			t.Fatal("expected the file to be removed")
+		}
+		if _, ok := files[photo.ThumbnailKey]; ok {
+			t.Fatal("expected the thumbnail to be removed")
+		}
+	})
+
+	t.Run("purge keeps attachments of recently deleted products", func(t *testing.T) {
+		widget.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+		removed, err := svc.PurgeOrphaned(ctx)
+		assertNoError(t, err)
+
+		if removed != 0 || len(repo.attachments) != 1 {
+			t.Fatalf("expected the spec sheet kept for a restore, removed %d", removed)
+		}
+	})
+
+	t.Run("purge removes attachments once the retention has passed", func(t *testing.T) {
+		widget.DeletedAt = gorm.DeletedAt{Time: time.Now().Add(-2 * time.Hour), Valid: true}
+
+		removed, err := svc.PurgeOrphaned(ctx)
+		assertNoError(t, err)
+
+		if removed != 1 || len(repo.attachments) != 0 || len(files) != 0 {
+			t.Fatalf("expected the spec sheet purged, removed %d with %d rows and %d files left", removed, len(repo.attachments), len(files))
+		}
+	})
+}
+
+func TestThumbnailBounds(t *testing.T) {
+	tests := []struct {
+		w, h   int
+		tw, th int
+	}{
+		{100, 50, 100, 50},
+		{1024, 512, 256, 128},
+		{300, 1200, 64, 256},
+		{5000, 1, 256, 1},
+	}
+
+	for _, tt := range tests {
+		if w, h := thumbnailBounds(tt.w, tt.h); w != tt.tw || h != tt.th {
+			t.Errorf("thumbnailBounds(%d, %d) = %d, %d; want %d, %d", tt.w, tt.h, w, h, tt.tw, tt.th)
+		}
+	}
+}
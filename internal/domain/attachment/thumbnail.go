@@ -0,0 +1,60 @@
+package attachment
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// ThumbnailSize bounds the width and height of a thumbnail in pixels.
+	ThumbnailSize = 256
+	// maxPixels rejects images that would take too much memory to decode.
+	maxPixels = 40_000_000
+)
+
+// makeThumbnail scales the image to fit within ThumbnailSize on each side, keeping its
+// aspect ratio and never enlarging it, and encodes the result as PNG.
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("image could not be decoded: " + err.Error())
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, apperrors.NewInvalidInputError("image dimensions are too large")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("image could not be decoded: " + err.Error())
+	}
+
+	w, h := thumbnailBounds(src.Bounds().Dx(), src.Bounds().Dy())
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, apperrors.NewInternalServerError("failed to encode thumbnail: " + err.Error())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// thumbnailBounds returns the size of a w by h image scaled to fit ThumbnailSize.
+func thumbnailBounds(w, h int) (int, int) {
+	if w <= ThumbnailSize && h <= ThumbnailSize {
+		return w, h
+	}
+
+	if w >= h {
+		return ThumbnailSize, max(1, h*ThumbnailSize/w)
+	}
+	return max(1, w*ThumbnailSize/h), ThumbnailSize
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ignoredFields change on every write and would only add noise to a diff.
+var ignoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// NewEntry records an operation on an entity, attributed to the actor and request in ctx.
+// before is nil for a create and after is nil for a delete; both are compared by their
+// JSON encoding, so the changes are keyed by JSON field name.
+func NewEntry(ctx context.Context, entityType string, entityID uuid.UUID, op Operation, before, after any) (*Entry, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Operation:  op,
+		Actor:      ActorFrom(ctx),
+		RequestID:  RequestIDFrom(ctx),
+		Changes:    changes,
+		OccurredAt: time.Now().UTC(),
+	}, nil
+}
+
+// Diff returns the top-level JSON fields whose values differ between before and after.
+// A nil side has no fields, so every field of the other side is a change.
+func Diff(before, after any) (map[string]Change, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	for name, v := range old {
+		if nv, ok := updated[name]; !ignoredFields[name] && (!ok || !reflect.DeepEqual(v, nv)) {
+			changes[name] = Change{Old: v, New: nv}
+		}
+	}
+	for name, nv := range updated {
+		if _, ok := old[name]; !ok && !ignoredFields[name] {
+			changes[name] = Change{New: nv}
+		}
+	}
+
+	return changes, nil
+}
+
+// fields decodes the JSON encoding of v into its top-level fields.
+func fields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
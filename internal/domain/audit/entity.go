@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operation is the kind of change an entry records.
+type Operation string
+
+const (
+	OperationCreate  Operation = "create"
+	OperationUpdate  Operation = "update"
+	OperationDelete  Operation = "delete"
+	OperationRestore Operation = "restore"
+)
+
+// EntityProduct is the entity type of product entries.
+const EntityProduct = "product"
+
+// entityTypes lists the entity types that are audited.
+var entityTypes = map[string]bool{
+	EntityProduct: true,
+}
+
+// Change is the value of a field before and after an operation. Old is null for
+// created entities and New is null for deleted ones.
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Entry records one change to an entity. Entries are append-only: they are never updated
+// or deleted once written.
+type Entry struct {
+	ID         uuid.UUID         `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	TenantID   string            `json:"-" gorm:"not null;default:'default';index"`
+	EntityType string            `json:"entity_type" gorm:"not null;index:idx_audit_entries_entity,priority:1" enum:"product"`
+	EntityID   uuid.UUID         `json:"entity_id" gorm:"type:uuid;not null;index:idx_audit_entries_entity,priority:2"`
+	Operation  Operation         `json:"operation" gorm:"not null" enum:"create,update,delete,restore"`
+	Actor      string            `json:"actor" gorm:"not null" doc:"Who made the change, from the X-Actor request header"`
+	RequestID  string            `json:"request_id" gorm:"index" doc:"ID of the request that made the change, from the X-Request-ID header"`
+	Changes    map[string]Change `json:"changes" gorm:"type:jsonb;serializer:json;not null" doc:"Old and new value of each field that changed, keyed by JSON field name"`
+	OccurredAt time.Time         `json:"occurred_at" gorm:"not null;index"`
+}
+
+func (Entry) TableName() string {
+	return "audit_entries"
+}
+
+// Filter selects audit entries. EntityType is required.
+type Filter struct {
+	EntityType string
+	EntityID   string
+	// Limit caps the number of entries returned, most recent first
+	Limit int
+}
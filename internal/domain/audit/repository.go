@@ -0,0 +1,10 @@
+package audit
+
+import "context"
+
+// Repository stores audit entries. It can only add and read them.
+type Repository interface {
+	Create(context.Context, *Entry) error
+	// List returns matching entries, most recent first.
+	List(context.Context, Filter) ([]Entry, error)
+}
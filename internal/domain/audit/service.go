@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+type Service interface {
+	// GetEntries lists the audit entries of an entity type, or of one entity, most recent first.
+	GetEntries(ctx context.Context, filter Filter) ([]Entry, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+// GetEntries implements Service.
+func (s *service) GetEntries(ctx context.Context, filter Filter) ([]Entry, error) {
+	if filter.EntityType == "" {
+		return nil, apperrors.NewMissingRequiredDataError("entity")
+	}
+
+	if !entityTypes[filter.EntityType] {
+		return nil, apperrors.NewInvalidInputError("entity " + filter.EntityType + " is not audited")
+	}
+
+	if filter.EntityID != "" {
+		if _, err := uuid.Parse(filter.EntityID); err != nil {
+			return nil, apperrors.NewInvalidFormatError("id")
+		}
+	}
+
+	switch {
+	case filter.Limit < 0:
+		return nil, apperrors.NewInvalidInputError("limit cannot be negative")
+	case filter.Limit == 0:
+		filter.Limit = DefaultLimit
+	case filter.Limit > MaxLimit:
+		filter.Limit = MaxLimit
+	}
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve audit entries: " + err.Error())
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+type mockRepo struct {
+	entries []Entry
+	last    Filter
+}
+
+func (m *mockRepo) Create(_ context.Context, e *Entry) error {
+	m.entries = append(m.entries, *e)
+	return nil
+}
+
+func (m *mockRepo) List(_ context.Context, filter Filter) ([]Entry, error) {
+	m.last = filter
+	return m.entries, nil
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+type record struct {
+	Name      string   `json:"name"`
+	Stock     int      `json:"stock"`
+	Tags      []string `json:"tags,omitempty"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+func TestNewEntry(t *testing.T) {
+	ctx := WithRequestID(WithActor(context.Background(), "alice"), "req-1")
+	id := uuid.New()
+
+	t.Run("update records only the changed fields", func(t *testing.T) {
+		before := &record{Name: "Widget", Stock: 5, UpdatedAt: "monday"}
+		after := &record{Name: "Widget", Stock: 3, Tags: []string{"sale"}, UpdatedAt: "tuesday"}
+
+		e, err := NewEntry(ctx, EntityProduct, id, OperationUpdate, before, after)
+		assertNoError(t, err)
+
+		if e.Actor != "alice" || e.RequestID != "req-1" || e.EntityID != id {
+			t.Fatalf("unexpected attribution: %+v", e)
+		}
+		if len(e.Changes) != 2 {
+			t.Fatalf("expected stock and tags to change, got %+v", e.Changes)
+		}
+		if c := e.Changes["stock"]; c.Old != float64(5) || c.New != float64(3) {
+			t.Fatalf("unexpected stock change: %+v", c)
+		}
+		if c := e.Changes["tags"]; c.Old != nil || c.New == nil {
+			t.Fatalf("unexpected tags change: %+v", c)
+		}
+	})
+
+	t.Run("create records every field as new", func(t *testing.T) {
+		e, err := NewEntry(context.Background(), EntityProduct, id, OperationCreate, nil, &record{Name: "Widget"})
+		assertNoError(t, err)
+
+		if e.Actor != AnonymousActor {
+			t.Fatalf("expected anonymous actor, got %q", e.Actor)
+		}
+		if c := e.Changes["name"]; c.Old != nil || c.New != "Widget" {
+			t.Fatalf("unexpected name change: %+v", c)
+		}
+	})
+
+	t.Run("delete records every field as old", func(t *testing.T) {
+		var after *record
+		e, err := NewEntry(ctx, EntityProduct, id, OperationDelete, &record{Name: "Widget"}, after)
+		assertNoError(t, err)
+
+		if c := e.Changes["name"]; c.Old != "Widget" || c.New != nil {
+			t.Fatalf("unexpected name change: %+v", c)
+		}
+	})
+}
+
+func TestService_GetEntries(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("defaults the limit", func(t *testing.T) {
+		_, err := svc.GetEntries(ctx, Filter{EntityType: EntityProduct})
+		assertNoError(t, err)
+
+		if repo.last.Limit != DefaultLimit {
+			t.Fatalf("expected limit %d, got %d", DefaultLimit, repo.last.Limit)
+		}
+	})
+
+	t.Run("caps the limit", func(t *testing.T) {
+		_, err := svc.GetEntries(ctx, Filter{EntityType: EntityProduct, Limit: MaxLimit + 1})
+		assertNoError(t, err)
+
+		if repo.last.Limit != MaxLimit {
+			t.Fatalf("expected limit %d, got %d", MaxLimit, repo.last.Limit)
+		}
+	})
+
+	t.Run("error without an entity type", func(t *testing.T) {
+		_, err := svc.GetEntries(ctx, Filter{})
+		assertAppErrorCode(t, err, apperrors.MissingRequiredData)
+	})
+
+	t.Run("error on an entity type that is not audited", func(t *testing.T) {
+		_, err := svc.GetEntries(ctx, Filter{EntityType: "invoice"})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on a malformed ID", func(t *testing.T) {
+		_, err := svc.GetEntries(ctx, Filter{EntityType: EntityProduct, EntityID: "not-a-uuid"})
+		assertAppErrorCode(t, err, apperrors.InvalidFormat)
+	})
+}
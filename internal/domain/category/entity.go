@@ -0,0 +1,102 @@
+package category
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+)
+
+type Category struct {
+	model.BaseModel
+	Name        string     `json:"name" gorm:"not null" validate:"required"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
+	Children    []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	// AttributeSchema lists attributes that products assigned to the category must
+	// satisfy; attributes not listed are unrestricted
+	AttributeSchema []AttributeSpec `json:"attribute_schema,omitempty" gorm:"type:jsonb;serializer:json"`
+}
+
+// AttributeType is the JSON type of a product attribute value.
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+)
+
+// AttributeSpec constrains one product attribute.
+type AttributeSpec struct {
+	Name     string        `json:"name" validate:"required"`
+	Type     AttributeType `json:"type" validate:"required,oneof=string number boolean"`
+	Required bool          `json:"required"`
+	Values   []string      `json:"values,omitempty" doc:"Allowed values of a string attribute; empty allows any"`
+}
+
+// ValidateSchema checks that every spec has a unique name and a known type.
+func (c *Category) ValidateSchema() error {
+	seen := make(map[string]bool, len(c.AttributeSchema))
+	for _, spec := range c.AttributeSchema {
+		if spec.Name == "" {
+			return apperrors.NewMissingRequiredDataError("attribute_schema.name")
+		}
+		if seen[spec.Name] {
+			return apperrors.NewInvalidInputError("duplicate attribute " + spec.Name)
+		}
+		seen[spec.Name] = true
+
+		switch spec.Type {
+		case AttributeString, AttributeNumber, AttributeBoolean:
+		default:
+			return apperrors.NewInvalidInputError("attribute " + spec.Name + " must be of type string, number or boolean")
+		}
+
+		if len(spec.Values) > 0 && spec.Type != AttributeString {
+			return apperrors.NewInvalidInputError("only string attributes can list allowed values")
+		}
+	}
+
+	return nil
+}
+
+// ValidateAttributes checks product attributes, as decoded from JSON, against the
+// category's attribute schema.
+func (c *Category) ValidateAttributes(attributes map[string]any) error {
+	for _, spec := range c.AttributeSchema {
+		v, ok := attributes[spec.Name]
+		if !ok || v == nil {
+			if spec.Required {
+				return apperrors.NewValidationError("attribute " + spec.Name + " is required in category " + c.Name)
+			}
+			continue
+		}
+
+		valid := false
+		switch spec.Type {
+		case AttributeString:
+			s, isString := v.(string)
+			valid = isString && (len(spec.Values) == 0 || slices.Contains(spec.Values, s))
+		case AttributeNumber:
+			_, valid = v.(float64)
+		case AttributeBoolean:
+			_, valid = v.(bool)
+		}
+
+		if !valid {
+			return apperrors.NewValidationError("attribute " + spec.Name + " is not a valid " + string(spec.Type) + " in category " + c.Name)
+		}
+	}
+
+	return nil
+}
+
+// StockSummary aggregates the stock of every product assigned to a category or any of its descendants.
+type StockSummary struct {
+	CategoryID    uuid.UUID `json:"category_id"`
+	ProductCount  int64     `json:"product_count"`
+	TotalUnits    int64     `json:"total_units"`
+	LowStockCount int64     `json:"low_stock_count"`
+}
@@ -0,0 +1,36 @@
+package category
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(context.Context, *Category) error
+	GetAll(context.Context) ([]Category, error)
+	GetByID(context.Context, string) (*Category, error)
+	Update(context.Context, *Category) error
+	Delete(context.Context, string) error
+
+	// Transaction runs fn with a Repository whose calls share one database transaction.
+	Transaction(context.Context, func(Repository) error) error
+	// LockAncestors locks the category and the chain from parentID up to its root until the
+	// surrounding transaction ends, and returns the IDs of that chain as read once locked.
+	// The chain is empty when parentID is not a live category.
+	LockAncestors(ctx context.Context, id string, parentID string) ([]uuid.UUID, error)
+
+	// DescendantIDs returns the IDs of every category below the given one.
+	DescendantIDs(context.Context, string) ([]uuid.UUID, error)
+	// LockProducts locks the live products among the IDs until the surrounding transaction
+	// ends, and returns the IDs of those it found.
+	LockProducts(context.Context, []uuid.UUID) ([]uuid.UUID, error)
+	// LockAssignedProducts locks the live products assigned to the category until the
+	// surrounding transaction ends, and returns their IDs.
+	LockAssignedProducts(ctx context.Context, categoryID string) ([]uuid.UUID, error)
+	// ProductAttributes returns the attributes of each of the listed products.
+	ProductAttributes(context.Context, []uuid.UUID) (map[uuid.UUID]map[string]any, error)
+	AssignProducts(ctx context.Context, categoryID string, productIDs []uuid.UUID) error
+	UnassignProduct(ctx context.Context, categoryID string, productID string) error
+	StockSummary(context.Context, string) (*StockSummary, error)
+}
@@ -0,0 +1,347 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreateCategory(context.Context, *Category) error
+	GetAllCategories(context.Context) ([]Category, error)
+	GetCategoryTree(context.Context) ([]Category, error)
+	GetCategoryByID(context.Context, string) (*Category, error)
+	UpdateCategory(context.Context, string, *Category) error
+	DeleteCategory(context.Context, string) error
+
+	AssignProducts(ctx context.Context, categoryID string, productIDs []uuid.UUID) error
+	UnassignProduct(ctx context.Context, categoryID string, productID string) error
+	GetStockSummary(context.Context, string) (*StockSummary, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+// CreateCategory implements Service.
+func (s *service) CreateCategory(ctx context.Context, category *Category) error {
+	if category.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+
+	if err := category.ValidateSchema(); err != nil {
+		return err
+	}
+
+	if category.ParentID != nil {
+		if _, err := s.GetCategoryByID(ctx, category.ParentID.String()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Create(ctx, category); err != nil {
+		return apperrors.NewDatabaseError("failed to create category: " + err.Error())
+	}
+
+	return nil
+}
+
+// GetAllCategories implements Service.
+func (s *service) GetAllCategories(ctx context.Context) ([]Category, error) {
+	categories, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve categories: " + err.Error())
+	}
+
+	return categories, nil
+}
+
+// GetCategoryTree implements Service. It returns the root categories with their descendants nested under Children.
+func (s *service) GetCategoryTree(ctx context.Context) ([]Category, error) {
+	categories, err := s.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(categories), nil
+}
+
+// buildTree nests categories under their parents. Categories whose parent is
+// missing from the list are treated as roots.
+func buildTree(categories []Category) []Category {
+	children := make(map[uuid.UUID][]Category)
+	known := make(map[uuid.UUID]bool, len(categories))
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+
+	var roots []Category
+	for _, c := range categories {
+		if c.ParentID == nil || !known[*c.ParentID] {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var attach func(c *Category)
+	attach = func(c *Category) {
+		c.Children = children[c.ID]
+		for i := range c.Children {
+			attach(&c.Children[i])
+		}
+	}
+
+	tree := []Category{}
+	for _, root := range roots {
+		attach(&root)
+		tree = append(tree, root)
+	}
+
+	return tree
+}
+
+// GetCategoryByID implements Service.
+func (s *service) GetCategoryByID(ctx context.Context, id string) (*Category, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewCategoryNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve category: " + err.Error())
+	}
+
+	return category, nil
+}
+
+// UpdateCategory implements Service.
+func (s *service) UpdateCategory(ctx context.Context, id string, category *Category) error {
+	if category.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+
+	if err := category.ValidateSchema(); err != nil {
+		return err
+	}
+
+	existing, err := s.GetCategoryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if category.ParentID != nil && *category.ParentID == existing.ID {
+		return apperrors.NewInvalidInputError("a category cannot be its own parent")
+	}
+
+	category.ID = existing.ID
+
+	// Check the hierarchy and save in one transaction holding the rows involved, so that
+	// concurrent moves cannot each pass the check and together form a cycle, and products
+	// being assigned are checked against either the old schema or the new one
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		if category.ParentID == nil {
+			if _, err := lockCategory(ctx, repo, id); err != nil {
+				return err
+			}
+		} else {
+			ancestors, err := repo.LockAncestors(ctx, id, category.ParentID.String())
+			if err != nil {
+				return apperrors.NewDatabaseError("failed to check category hierarchy: " + err.Error())
+			}
+			if len(ancestors) == 0 {
+				return apperrors.NewCategoryNotFoundError(category.ParentID.String())
+			}
+
+			// Moving a category under one of its own descendants would create a cycle
+			if slices.Contains(ancestors, existing.ID) {
+				return apperrors.NewInvalidInputError("a category cannot be moved under one of its descendants")
+			}
+		}
+
+		if !reflect.DeepEqual(category.AttributeSchema, existing.AttributeSchema) {
+			if err := validateAssignedProducts(ctx, repo, category); err != nil {
+				return err
+			}
+		}
+
+		if err := repo.Update(ctx, category); err != nil {
+			return apperrors.NewDatabaseError("failed to update category: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// validateAssignedProducts rejects a schema that the products already assigned to the
+// category do not satisfy. The products stay locked until the schema is saved, so that
+// concurrent product updates are checked against the new schema.
+func validateAssignedProducts(ctx context.Context, repo Repository, category *Category) error {
+	if len(category.AttributeSchema) == 0 {
+		return nil
+	}
+
+	ids, err := repo.LockAssignedProducts(ctx, category.ID.String())
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to lock assigned products: " + err.Error())
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	attributes, err := repo.ProductAttributes(ctx, ids)
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to retrieve product attributes: " + err.Error())
+	}
+
+	for _, id := range ids {
+		if err := category.ValidateAttributes(attributes[id]); err != nil {
+			var appErr *apperrors.AppError
+			if errors.As(err, &appErr) {
+				return apperrors.NewValidationError("assigned product " + id.String() + " does not fit the new schema: " + appErr.Message)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCategory implements Service.
+func (s *service) DeleteCategory(ctx context.Context, id string) error {
+	if _, err := s.GetCategoryByID(ctx, id); err != nil {
+		return err
+	}
+
+	descendants, err := s.repo.DescendantIDs(ctx, id)
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to check category hierarchy: " + err.Error())
+	}
+	if len(descendants) > 0 {
+		return apperrors.NewBusinessLogicError("category has sub-categories; move or delete them first")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return apperrors.NewDatabaseError("failed to delete category: " + err.Error())
+	}
+
+	return nil
+}
+
+// AssignProducts implements Service. The category and the products stay locked from the
+// check until the assignment commits, so that neither a schema change nor a product update
+// can slip in between.
+func (s *service) AssignProducts(ctx context.Context, categoryID string, productIDs []uuid.UUID) error {
+	if len(productIDs) == 0 {
+		return apperrors.NewMissingRequiredDataError("product_ids")
+	}
+
+	if _, err := s.GetCategoryByID(ctx, categoryID); err != nil {
+		return err
+	}
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		category, err := lockCategory(ctx, repo, categoryID)
+		if err != nil {
+			return err
+		}
+
+		found, err := repo.LockProducts(ctx, productIDs)
+		if err != nil {
+			return apperrors.NewDatabaseError("failed to lock products: " + err.Error())
+		}
+		for _, id := range productIDs {
+			if !slices.Contains(found, id) {
+				return apperrors.NewProductNotFoundError(id.String())
+			}
+		}
+
+		if len(category.AttributeSchema) > 0 {
+			attributes, err := repo.ProductAttributes(ctx, productIDs)
+			if err != nil {
+				return apperrors.NewDatabaseError("failed to retrieve product attributes: " + err.Error())
+			}
+
+			for _, id := range productIDs {
+				if err := category.ValidateAttributes(attributes[id]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := repo.AssignProducts(ctx, categoryID, productIDs); err != nil {
+			return apperrors.NewDatabaseError("failed to assign products: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// lockCategory locks the category row, and those above it, for the rest of the transaction
+// and returns the category as read once locked.
+func lockCategory(ctx context.Context, repo Repository, id string) (*Category, error) {
+	chain, err := repo.LockAncestors(ctx, id, id)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to lock category: " + err.Error())
+	}
+	if len(chain) == 0 {
+		return nil, apperrors.NewCategoryNotFoundError(id)
+	}
+
+	category, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewCategoryNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve category: " + err.Error())
+	}
+
+	return category, nil
+}
+
+// UnassignProduct implements Service.
+func (s *service) UnassignProduct(ctx context.Context, categoryID string, productID string) error {
+	if productID == "" {
+		return apperrors.NewMissingRequiredDataError("productId")
+	}
+
+	if _, err := s.GetCategoryByID(ctx, categoryID); err != nil {
+		return err
+	}
+
+	if err := s.repo.UnassignProduct(ctx, categoryID, productID); err != nil {
+		return apperrors.NewDatabaseError("failed to unassign product: " + err.Error())
+	}
+
+	return nil
+}
+
+// GetStockSummary implements Service.
+func (s *service) GetStockSummary(ctx context.Context, id string) (*StockSummary, error) {
+	c, err := s.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.StockSummary(ctx, id)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to summarise category stock: " + err.Error())
+	}
+	summary.CategoryID = c.ID
+
+	return summary, nil
+}
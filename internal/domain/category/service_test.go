@@ -0,0 +1,339 @@
+package category
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+	"gorm.io/gorm"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+type mockRepo struct {
+	categories map[uuid.UUID]*Category
+	updated    *Category
+	attributes map[uuid.UUID]map[string]any
+	assigned   []uuid.UUID
+	// missing are product IDs that do not exist
+	missing []uuid.UUID
+	// locked are the product IDs locked inside a transaction
+	locked        []uuid.UUID
+	inTransaction bool
+}
+
+func newMockRepo(categories ...*Category) *mockRepo {
+	m := &mockRepo{categories: make(map[uuid.UUID]*Category)}
+	for _, c := range categories {
+		m.categories[c.ID] = c
+	}
+	return m
+}
+
+func (m *mockRepo) Create(_ context.Context, c *Category) error {
+	c.ID = uuid.New()
+	m.categories[c.ID] = c
+	return nil
+}
+
+func (m *mockRepo) GetAll(context.Context) ([]Category, error) {
+	out := make([]Category, 0, len(m.categories))
+	for _, c := range m.categories {
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+func (m *mockRepo) GetByID(_ context.Context, id string) (*Category, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	c, ok := m.categories[uid]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return c, nil
+}
+
+func (m *mockRepo) Update(_ context.Context, c *Category) error {
+	m.updated = c
+	return nil
+}
+
+func (m *mockRepo) Delete(_ context.Context, id string) error {
+	delete(m.categories, uuid.MustParse(id))
+	return nil
+}
+
+func (m *mockRepo) Transaction(_ context.Context, fn func(Repository) error) error {
+	m.inTransaction = true
+	defer func() { m.inTransaction = false }()
+	return fn(m)
+}
+
+func (m *mockRepo) LockAncestors(_ context.Context, _ string, parentID string) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	for c := m.categories[uuid.MustParse(parentID)]; c != nil && !slices.Contains(out, c.ID); {
+		out = append(out, c.ID)
+		if c.ParentID == nil {
+			break
+		}
+		c = m.categories[*c.ParentID]
+	}
+	return out, nil
+}
+
+func (m *mockRepo) DescendantIDs(_ context.Context, id string) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	var walk func(parent uuid.UUID)
+	walk = func(parent uuid.UUID) {
+		for _, c := range m.categories {
+			if c.ParentID != nil && *c.ParentID == parent {
+				out = append(out, c.ID)
+				walk(c.ID)
+			}
+		}
+	}
+	walk(uuid.MustParse(id))
+	return out, nil
+}
+
+func (m *mockRepo) LockProducts(_ context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var found []uuid.UUID
+	for _, id := range ids {
+		if !slices.Contains(m.missing, id) {
+			found = append(found, id)
+		}
+	}
+	if m.inTransaction {
+		m.locked = append(m.locked, found...)
+	}
+	return found, nil
+}
+
+func (m *mockRepo) LockAssignedProducts(context.Context, string) ([]uuid.UUID, error) {
+	return m.assigned, nil
+}
+
+func (m *mockRepo) ProductAttributes(context.Context, []uuid.UUID) (map[uuid.UUID]map[string]any, error) {
+	return m.attributes, nil
+}
+
+func (m *mockRepo) AssignProducts(_ context.Context, _ string, ids []uuid.UUID) error {
+	m.assigned = append(m.assigned, ids...)
+	return nil
+}
+
+func (m *mockRepo) UnassignProduct(context.Context, string, string) error { return nil }
+
+func (m *mockRepo) StockSummary(context.Context, string) (*StockSummary, error) {
+	return &StockSummary{}, nil
+}
+
+func newCategory(name string, parent *Category) *Category {
+	c := &Category{BaseModel: model.BaseModel{ID: uuid.New()}, Name: name}
+	if parent != nil {
+		c.ParentID = &parent.ID
+	}
+	return c
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+func TestService_UpdateCategory(t *testing.T) {
+	root := newCategory("Apparel", nil)
+	child := newCategory("Shirts", root)
+	grandchild := newCategory("T-Shirts", child)
+	other := newCategory("Electronics", nil)
+
+	ctx := context.Background()
+
+	t.Run("moves a category under another branch", func(t *testing.T) {
+		repo := newMockRepo(root, child, grandchild, other)
+		svc := NewService(repo)
+
+		err := svc.UpdateCategory(ctx, child.ID.String(), &Category{Name: "Shirts", ParentID: &other.ID})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if repo.updated == nil || *repo.updated.ParentID != other.ID {
+			t.Fatal("expected the category to be re-parented")
+		}
+	})
+
+	t.Run("rejects making a category its own parent", func(t *testing.T) {
+		svc := NewService(newMockRepo(root, child, grandchild, other))
+
+		err := svc.UpdateCategory(ctx, child.ID.String(), &Category{Name: "Shirts", ParentID: &child.ID})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("rejects moving a category under its descendant", func(t *testing.T) {
+		svc := NewService(newMockRepo(root, child, grandchild, other))
+
+		err := svc.UpdateCategory(ctx, root.ID.String(), &Category{Name: "Apparel", ParentID: &grandchild.ID})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("rejects an unknown parent", func(t *testing.T) {
+		svc := NewService(newMockRepo(root, child, grandchild, other))
+		missing := uuid.New()
+
+		err := svc.UpdateCategory(ctx, child.ID.String(), &Category{Name: "Shirts", ParentID: &missing})
+		assertAppErrorCode(t, err, apperrors.CategoryNotFound)
+	})
+}
+
+func TestService_DeleteCategory(t *testing.T) {
+	root := newCategory("Apparel", nil)
+	child := newCategory("Shirts", root)
+	ctx := context.Background()
+
+	t.Run("rejects deleting a category with sub-categories", func(t *testing.T) {
+		svc := NewService(newMockRepo(root, child))
+
+		err := svc.DeleteCategory(ctx, root.ID.String())
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("deletes a leaf category", func(t *testing.T) {
+		repo := newMockRepo(root, child)
+		svc := NewService(repo)
+
+		if err := svc.DeleteCategory(ctx, child.ID.String()); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if _, ok := repo.categories[child.ID]; ok {
+			t.Fatal("expected the category to be deleted")
+		}
+	})
+}
+
+func TestService_AttributeSchema(t *testing.T) {
+	shirts := newCategory("Shirts", nil)
+	shirts.AttributeSchema = []AttributeSpec{
+		{Name: "color", Type: AttributeString, Required: true, Values: []string{"red", "blue"}},
+		{Name: "chest_cm", Type: AttributeNumber},
+	}
+	ctx := context.Background()
+
+	valid, invalid := uuid.New(), uuid.New()
+
+	t.Run("assigns products whose attributes satisfy the schema", func(t *testing.T) {
+		repo := newMockRepo(shirts)
+		repo.attributes = map[uuid.UUID]map[string]any{valid: {"color": "red", "chest_cm": 96.0, "fit": "slim"}}
+		svc := NewService(repo)
+
+		if err := svc.AssignProducts(ctx, shirts.ID.String(), []uuid.UUID{valid}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(repo.assigned) != 1 {
+			t.Fatalf("expected the product to be assigned, got %v", repo.assigned)
+		}
+		if !slices.Equal(repo.locked, []uuid.UUID{valid}) {
+			t.Fatalf("expected the product locked in the assigning transaction, got %v", repo.locked)
+		}
+	})
+
+	t.Run("rejects unknown products", func(t *testing.T) {
+		repo := newMockRepo(shirts)
+		repo.missing = []uuid.UUID{invalid}
+		svc := NewService(repo)
+
+		err := svc.AssignProducts(ctx, shirts.ID.String(), []uuid.UUID{invalid})
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+		if len(repo.assigned) != 0 {
+			t.Fatal("expected nothing assigned")
+		}
+	})
+
+	t.Run("rejects products that break the schema", func(t *testing.T) {
+		cases := map[string]map[string]any{
+			"missing required":  {"chest_cm": 96.0},
+			"value not allowed": {"color": "green"},
+			"wrong type":        {"color": "red", "chest_cm": "96"},
+		}
+
+		for name, attributes := range cases {
+			repo := newMockRepo(shirts)
+			repo.attributes = map[uuid.UUID]map[string]any{invalid: attributes}
+			svc := NewService(repo)
+
+			err := svc.AssignProducts(ctx, shirts.ID.String(), []uuid.UUID{invalid})
+			if err == nil {
+				t.Fatalf("%s: expected an error", name)
+			}
+			assertAppErrorCode(t, err, apperrors.ValidationError)
+			if len(repo.assigned) != 0 {
+				t.Fatalf("%s: expected nothing assigned", name)
+			}
+		}
+	})
+
+	t.Run("rejects schema changes that assigned products do not fit", func(t *testing.T) {
+		repo := newMockRepo(shirts)
+		repo.assigned = []uuid.UUID{valid}
+		repo.attributes = map[uuid.UUID]map[string]any{valid: {"color": "red", "chest_cm": 96.0}}
+		svc := NewService(repo)
+
+		update := &Category{Name: "Shirts", AttributeSchema: []AttributeSpec{
+			{Name: "color", Type: AttributeString, Required: true, Values: []string{"blue"}},
+		}}
+		err := svc.UpdateCategory(ctx, shirts.ID.String(), update)
+		assertAppErrorCode(t, err, apperrors.ValidationError)
+		if repo.updated != nil {
+			t.Fatal("expected the category not to be updated")
+		}
+
+		update.AttributeSchema = append(shirts.AttributeSchema, AttributeSpec{Name: "sleeve", Type: AttributeString})
+		if err := svc.UpdateCategory(ctx, shirts.ID.String(), update); err != nil {
+			t.Fatalf("expected a compatible schema change to be saved, got: %v", err)
+		}
+	})
+
+	t.Run("rejects a malformed schema", func(t *testing.T) {
+		svc := NewService(newMockRepo())
+
+		err := svc.CreateCategory(ctx, &Category{Name: "Shoes", AttributeSchema: []AttributeSpec{{Name: "size", Type: "integer"}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
+
+func TestBuildTree(t *testing.T) {
+	root := newCategory("Apparel", nil)
+	child := newCategory("Shirts", root)
+	grandchild := newCategory("T-Shirts", child)
+	orphan := newCategory("Orphan", newCategory("Deleted", nil))
+
+	tree := buildTree([]Category{*grandchild, *child, *root, *orphan})
+
+	if len(tree) != 2 {
+		t.Fatalf("expected 2 roots (including the orphan), got %d", len(tree))
+	}
+
+	for _, r := range tree {
+		if r.ID != root.ID {
+			continue
+		}
+		if len(r.Children) != 1 || r.Children[0].ID != child.ID {
+			t.Fatalf("expected Shirts under Apparel, got %+v", r.Children)
+		}
+		if len(r.Children[0].Children) != 1 || r.Children[0].Children[0].ID != grandchild.ID {
+			t.Fatalf("expected T-Shirts under Shirts, got %+v", r.Children[0].Children)
+		}
+		return
+	}
+	t.Fatal("root category missing from tree")
+}
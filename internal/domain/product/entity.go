@@ -1,13 +1,370 @@
 package product
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
 	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
 )
 
 type Product struct {
 	model.BaseModel
-	Name             string `json:"name" gorm:"not null"`
-	Description      string `json:"description"`
-	StockQuantity    int    `json:"stock_quantity" gorm:"not null"`
-	LowStockThresold int    `json:"low_stock_threshold" gorm:"not null"`
+	Name             string              `json:"name" gorm:"not null" validate:"required"`
+	Description      string              `json:"description"`
+	Attributes       map[string]any      `json:"attributes,omitempty" gorm:"type:jsonb;serializer:json;index:idx_products_attributes,type:gin" doc:"Structured details such as color or size; values are strings, numbers or booleans, checked against the attribute schema of the product's categories"`
+	Tags             []string            `json:"tags,omitempty" gorm:"type:jsonb;serializer:json;index:idx_products_tags,type:gin"`
+	StockQuantity    int                 `json:"stock_quantity" gorm:"not null" validate:"min=0" doc:"Units available on hand; for products with variants, the sum of variant stock. Quarantined, damaged and in-transit units are held in separate stock buckets"`
+	ReservedQuantity int                 `json:"reserved_quantity" gorm:"not null;default:0" openapi:"readonly" doc:"Units on hand allocated to open sales orders, which cannot be sold otherwise; tracked per variant for products with variants"`
+	LowStockThresold int                 `json:"low_stock_threshold" gorm:"not null" validate:"min=0"`
+	BaseUnit         string              `json:"base_unit" gorm:"not null;default:each" doc:"Unit that stock quantities are counted in, e.g. each or g (default each); can only change while the product has no stock"`
+	Currency         string              `json:"currency" gorm:"size:3;not null;default:USD" doc:"ISO 4217 currency code of unit_cost and sale_price (default USD)"`
+	UnitCost         int64               `json:"unit_cost" gorm:"not null;default:0" validate:"min=0" doc:"Cost per unit in minor units, used to value stock received without an explicit cost"`
+	SalePrice        int64               `json:"sale_price" gorm:"not null;default:0" validate:"min=0" doc:"Sale price per unit in minor units"`
+	SupplierID       *uuid.UUID          `json:"supplier_id" gorm:"type:uuid;index" doc:"Preferred supplier, used for generated purchase orders"`
+	LeadTimeDays     int                 `json:"lead_time_days" gorm:"not null;default:0" validate:"min=0" doc:"Days to restock; zero uses the supplier's lead time"`
+	LotTracked       bool                `json:"lot_tracked" gorm:"not null;default:false" doc:"Stock is held in lots with expiry dates and consumed first-expired-first-out; can only change while the product has no stock"`
+	Serialized       bool                `json:"serialized" gorm:"not null;default:false" doc:"Every unit has a unique serial number, given when stock enters or leaves; can only change while the product has no stock"`
+	Categories       []category.Category `json:"categories,omitempty" gorm:"many2many:product_categories" openapi:"readonly"`
+	Options          []OptionAxis        `json:"options,omitempty" gorm:"foreignKey:ProductID" openapi:"readonly"`
+	Variants         []Variant           `json:"variants,omitempty" gorm:"foreignKey:ProductID" openapi:"readonly"`
+	Units            []UnitOfMeasure     `json:"units,omitempty" gorm:"foreignKey:ProductID" openapi:"readonly"`
+	Components       []KitComponent      `json:"components,omitempty" gorm:"foreignKey:KitID" openapi:"readonly" doc:"Products a kit is made of; a kit's stock_quantity is the number of kits its components can make"`
+}
+
+// IsKit reports whether the product is a kit made of other products.
+func (p *Product) IsKit() bool {
+	return len(p.Components) > 0
+}
+
+// CheckVariant requires variantID to name one of the product's variants exactly when the
+// product has any.
+func (p *Product) CheckVariant(variantID *uuid.UUID) error {
+	if len(p.Variants) == 0 {
+		if variantID != nil {
+			return apperrors.NewInvalidInputError("product " + p.ID.String() + " has no variants")
+		}
+		return nil
+	}
+
+	if variantID == nil {
+		return apperrors.NewMissingRequiredDataError("variant_id")
+	}
+
+	for _, v := range p.Variants {
+		if v.ID == *variantID {
+			return nil
+		}
+	}
+
+	return apperrors.NewVariantNotFoundError(variantID.String())
+}
+
+// KitComponent is the quantity of a product that goes into one unit of a kit.
+type KitComponent struct {
+	model.BaseModel
+	KitID       uuid.UUID `json:"kit_id" gorm:"type:uuid;not null;uniqueIndex:idx_kit_components_kit_component,priority:1" openapi:"readonly"`
+	ComponentID uuid.UUID `json:"component_id" gorm:"type:uuid;not null;uniqueIndex:idx_kit_components_kit_component,priority:2;index" validate:"required"`
+	Quantity    int       `json:"quantity" gorm:"not null" validate:"required,min=1" doc:"Units of the component per kit"`
+	Component   *Product  `json:"-" gorm:"foreignKey:ComponentID"`
+}
+
+// ProductVersion is a product's row as it was from ValidFrom until ValidTo, or until now
+// for the current version. Versions are written by a database trigger whenever a product
+// row is inserted or updated, including when it is deleted or restored.
+type ProductVersion struct {
+	ID        uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	TenantID  string          `gorm:"not null;default:'default';index"`
+	ProductID uuid.UUID       `gorm:"type:uuid;not null;index:idx_product_versions_product,priority:1"`
+	ValidFrom time.Time       `gorm:"not null;index:idx_product_versions_product,priority:2"`
+	ValidTo   *time.Time      `gorm:"index"`
+	Snapshot  json.RawMessage `gorm:"type:jsonb;not null"`
+}
+
+// UnitOfMeasure is a unit a product's stock can be given in besides its base unit, such as
+// a case of 24 or a kilogram of a product counted in grams.
+type UnitOfMeasure struct {
+	model.BaseModel
+	ProductID uuid.UUID        `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_product_units_product_name,priority:1" openapi:"readonly"`
+	Name      string           `json:"name" gorm:"not null;uniqueIndex:idx_product_units_product_name,priority:2" validate:"required"`
+	Factor    quantity.Decimal `json:"factor" gorm:"type:text;not null" validate:"required" doc:"Base units in one of this unit, as an exact decimal, e.g. 24 or 453.59237"`
+}
+
+func (UnitOfMeasure) TableName() string {
+	return "product_units"
+}
+
+// OptionAxis is a dimension along which a product's variants differ, such as size or colour.
+type OptionAxis struct {
+	model.BaseModel
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null;index" openapi:"readonly"`
+	Name      string    `json:"name" gorm:"not null" validate:"required"`
+	Values    []string  `json:"values" gorm:"type:jsonb;serializer:json" validate:"required"`
+}
+
+// Variant is a sellable version of a product with one value per option axis and its own stock level.
+type Variant struct {
+	model.BaseModel
+	ProductID         uuid.UUID         `json:"product_id" gorm:"type:uuid;not null;index" openapi:"readonly"`
+	SKU               string            `json:"sku"`
+	Options           map[string]string `json:"options" gorm:"type:jsonb;serializer:json" validate:"required" doc:"Value per option axis, e.g. {\"size\": \"M\", \"colour\": \"red\"}"`
+	StockQuantity     int               `json:"stock_quantity" gorm:"not null" validate:"min=0"`
+	ReservedQuantity  int               `json:"reserved_quantity" gorm:"not null;default:0" openapi:"readonly" doc:"Units allocated to open sales orders"`
+	LowStockThreshold int               `json:"low_stock_threshold" gorm:"not null" validate:"min=0"`
+}
+
+// Lot is a batch of a lot-tracked product sharing a lot number and expiry date.
+type Lot struct {
+	model.BaseModel
+	ProductID uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_lots_product_number,priority:1" openapi:"readonly"`
+	LotNumber string     `json:"lot_number" gorm:"not null;uniqueIndex:idx_lots_product_number,priority:2"`
+	ExpiresAt *time.Time `json:"expires_at" gorm:"index" doc:"Units cannot be sold from this time on; null for lots that do not expire"`
+	Quantity  int        `json:"quantity" gorm:"not null"`
+}
+
+// Expired reports whether the lot has expired at now.
+func (l Lot) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// Serial is one uniquely numbered unit of a serialized product.
+type Serial struct {
+	model.BaseModel
+	ProductID    uuid.UUID `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_serials_product_number,priority:1"`
+	SerialNumber string    `json:"serial_number" gorm:"not null;uniqueIndex:idx_serials_product_number,priority:2"`
+	InStock      bool      `json:"in_stock" gorm:"not null;index"`
+}
+
+// SerialHistory is a serial with every stock movement that named it, oldest first.
+type SerialHistory struct {
+	Serial
+	Movements []StockMovement `json:"movements"`
+}
+
+// Bucket is a stock status. Units in the available bucket are the product's or variant's
+// StockQuantity; only they can be sold and count toward low-stock checks.
+type Bucket string
+
+const (
+	BucketAvailable   Bucket = "available"
+	BucketQuarantined Bucket = "quarantined"
+	BucketDamaged     Bucket = "damaged"
+	BucketInTransit   Bucket = "in_transit"
+)
+
+// StockBucket holds the units of a product, or one of its variants, in a bucket other
+// than available.
+type StockBucket struct {
+	model.BaseModel
+	ProductID uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index"`
+	VariantID *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid"`
+	Bucket    Bucket     `json:"bucket" gorm:"not null"`
+	Quantity  int        `json:"quantity" gorm:"not null"`
+	Value     int64      `json:"value" gorm:"not null;default:0" doc:"Cost of the units held, in minor units"`
+}
+
+// BucketLevels is the stock of a product, or one of its variants, in every bucket.
+type BucketLevels struct {
+	ProductID   uuid.UUID  `json:"product_id"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty"`
+	Available   int        `json:"available"`
+	Quarantined int        `json:"quarantined"`
+	Damaged     int        `json:"damaged"`
+	InTransit   int        `json:"in_transit"`
+}
+
+// BucketMove moves units of a product, or one of its variants, between buckets.
+type BucketMove struct {
+	VariantID string   `json:"variant_id" doc:"Required for products with variants"`
+	From      Bucket   `json:"from" validate:"required" enum:"available,quarantined,damaged,in_transit"`
+	To        Bucket   `json:"to" validate:"required" enum:"available,quarantined,damaged,in_transit"`
+	Quantity  int      `json:"quantity" validate:"required,min=1" doc:"Units to move, in unit"`
+	Unit      string   `json:"unit,omitempty" doc:"Unit of quantity; defaults to the base unit"`
+	LotNumber string   `json:"lot_number,omitempty" doc:"Lot the units leave or enter when moving out of or into available stock; required into available for lot-tracked products"`
+	Serials   []string `json:"serials,omitempty" doc:"Serial numbers moved out of or into available stock; required for serialized products, one per unit"`
+	Note      string   `json:"note"`
+
+	// Reason classifies the ledger movement for moves out of or into available stock;
+	// zero uses ReasonTransfer
+	Reason        MovementReason `json:"-"`
+	ReferenceType string         `json:"-"`
+	ReferenceID   string         `json:"-"`
+}
+
+// LotReceipt is stock received into a lot.
+type LotReceipt struct {
+	LotNumber string     `json:"lot_number" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at" doc:"Expiry of a new lot; must match the expiry of an existing one"`
+	Quantity  int        `json:"quantity" validate:"required,min=1"`
+	UnitCost  int64      `json:"unit_cost" validate:"min=0" doc:"Cost per unit in minor units; defaults to the product's unit cost"`
+}
+
+// Filter narrows a product listing. The zero value matches every product.
+type Filter struct {
+	// CategoryID restricts the listing to products assigned to the category
+	CategoryID string
+	// IncludeDescendants also matches products assigned to any sub-category of CategoryID
+	IncludeDescendants bool
+	// IDs restricts the listing to the given products when set
+	IDs []string
+	// Attributes matches products having every one of these attribute values
+	Attributes map[string]string
+	// Tags matches products carrying every one of these tags
+	Tags []string
+	// AsOf lists products as they were at this time, when set
+	AsOf time.Time
+	// LowStock keeps products whose stock is at or below their low-stock threshold
+	LowStock bool
+	// Limit caps the listing, oldest product first, at this many products when set
+	Limit int
+	// Offset skips this many products of the listing
+	Offset int
+}
+
+// SearchQuery is a free-text product search with paging.
+type SearchQuery struct {
+	Text   string
+	Limit  int
+	Offset int
+}
+
+// SearchHit is a product matched by a search, as found by the repository.
+type SearchHit struct {
+	ProductID            uuid.UUID
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+	SKU                  string
+}
+
+// SearchResult is a product matched by a search.
+type SearchResult struct {
+	Product    Product          `json:"product"`
+	Rank       float64          `json:"rank" doc:"Relevance; higher is a better match"`
+	Highlights SearchHighlights `json:"highlights"`
+	MatchedSKU string           `json:"matched_sku,omitempty" doc:"The variant SKU that matched, if any"`
+}
+
+// SearchHighlights are the product's name and description, HTML-escaped, with matched words
+// wrapped in <mark> tags.
+type SearchHighlights struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SearchPage is one page of search results, best match first.
+type SearchPage struct {
+	Results []SearchResult `json:"results"`
+	Total   int64          `json:"total" doc:"Matches across all pages"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
+
+// MovementReason classifies an entry in the stock ledger.
+type MovementReason string
+
+const (
+	ReasonOpening    MovementReason = "opening"
+	ReasonIncrement  MovementReason = "increment"
+	ReasonDecrement  MovementReason = "decrement"
+	ReasonAdjustment MovementReason = "adjustment"
+	ReasonReceipt    MovementReason = "receipt"
+	ReasonCount      MovementReason = "count"
+	ReasonSale       MovementReason = "sale"
+	ReasonReturn     MovementReason = "return"
+	ReasonQuarantine MovementReason = "quarantine"
+	ReasonScrap      MovementReason = "scrap"
+	ReasonTransfer   MovementReason = "transfer"
+)
+
+// StockMovement is an entry in the append-only stock ledger recording a signed change to
+// the stock of a product or one of its variants. Replaying a product's movements in order
+// reproduces its stock level at any point in time.
+type StockMovement struct {
+	model.BaseModel
+	ProductID     uuid.UUID      `json:"product_id" gorm:"type:uuid;not null;index:idx_stock_movements_product,priority:1"`
+	VariantID     *uuid.UUID     `json:"variant_id,omitempty" gorm:"type:uuid;index"`
+	LotID         *uuid.UUID     `json:"lot_id,omitempty" gorm:"type:uuid;index"`
+	Serials       []string       `json:"serials,omitempty" gorm:"type:jsonb;serializer:json;index:idx_stock_movements_serials,type:gin" doc:"Serial numbers of the units moved, for serialized products"`
+	Quantity      int            `json:"quantity" gorm:"not null" doc:"Signed change in units; negative for stock leaving"`
+	Bucket        Bucket         `json:"bucket,omitempty" doc:"For moves between buckets, the bucket the units left for or came from"`
+	Reason        MovementReason `json:"reason" gorm:"not null" enum:"opening,increment,decrement,adjustment,receipt,count,sale,return,quarantine,scrap,transfer"`
+	UnitCost      int64          `json:"unit_cost" gorm:"not null;default:0" doc:"Cost per unit in minor units for stock entering, or for units leaving for another bucket; zero for other stock leaving"`
+	Currency      string         `json:"currency" gorm:"size:3;not null"`
+	ReferenceType string         `json:"reference_type,omitempty" gorm:"index:idx_stock_movements_reference,priority:1" doc:"Kind of document that caused the movement"`
+	ReferenceID   string         `json:"reference_id,omitempty" gorm:"index:idx_stock_movements_reference,priority:2"`
+	Note          string         `json:"note,omitempty"`
+	OccurredAt    time.Time      `json:"occurred_at" gorm:"not null;index:idx_stock_movements_product,priority:2"`
+}
+
+// StockChange is a stock change to post through the ledger.
+type StockChange struct {
+	ProductID string
+	// VariantID targets one variant; required for products with variants
+	VariantID string
+	// Quantity is the signed change in units
+	Quantity int
+	Reason   MovementReason
+	// LotNumber targets one lot of a lot-tracked product. Stock entering creates the lot
+	// if needed; stock leaving without a lot is taken first-expired-first-out.
+	LotNumber string
+	// ExpiresAt is the expiry of a lot created by this change
+	ExpiresAt *time.Time
+	// Serials names the units moved; required for serialized products, one per unit
+	Serials []string
+	// Bucket is the bucket units left for or came from, for moves between buckets
+	Bucket Bucket
+	// UnitCost values stock entering; zero uses the product's unit cost. Units moved back
+	// into the available bucket carry the cost they left it at.
+	UnitCost      int64
+	ReferenceType string
+	ReferenceID   string
+	Note          string
+}
+
+// MovementFilter narrows a stock ledger query. The zero value matches every movement.
+type MovementFilter struct {
+	ProductID string
+	// ProductIDs matches movements of any of these products when set
+	ProductIDs    []string
+	ReferenceType string
+	ReferenceID   string
+	// Serial matches movements naming this serial number
+	Serial string
+	// Since excludes movements before this time when set
+	Since time.Time
+	// Until excludes movements at or after this time when set
+	Until time.Time
+	// LastPerProduct keeps only the latest this many movements of each product when set
+	LastPerProduct int
+}
+
+// EventType names a kind of stock event.
+type EventType string
+
+const (
+	// EventStockChanged is published when the stock of a product or variant changes.
+	EventStockChanged EventType = "stock.changed"
+	// EventLowStock is published, after EventStockChanged, when a change takes stock from
+	// above its low-stock threshold to at or below it.
+	EventLowStock EventType = "stock.low"
+)
+
+// Event is a committed change to the stock of a product, or of one of its variants when
+// VariantID is set, with the levels it left behind.
+type Event struct {
+	Type              EventType      `json:"type" enum:"stock.changed,stock.low"`
+	ProductID         uuid.UUID      `json:"product_id"`
+	VariantID         *uuid.UUID     `json:"variant_id,omitempty"`
+	Name              string         `json:"name"`
+	SKU               string         `json:"sku,omitempty" doc:"Variant SKU, for variant events"`
+	StockQuantity     int            `json:"stock_quantity"`
+	ReservedQuantity  int            `json:"reserved_quantity"`
+	LowStockThreshold int            `json:"low_stock_threshold"`
+	Change            int            `json:"change" doc:"Net change in units made by the operation"`
+	Reason            MovementReason `json:"reason"`
+	OccurredAt        time.Time      `json:"occurred_at"`
 }
@@ -0,0 +1,594 @@
+package product
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"gorm.io/gorm"
+)
+
+// memoryVersion is a product as it was from a point in time, as the versioning trigger records it.
+type memoryVersion struct {
+	product Product
+	from    time.Time
+}
+
+// memoryRepository is an in-memory implementation of the Repository interface for tests.
+type memoryRepository struct {
+	products  map[string]*Product
+	lots      []Lot
+	serials   []Serial
+	buckets   []StockBucket
+	movements []StockMovement
+	deleted   map[string]*Product
+	versions  []memoryVersion
+	audit     []audit.Entry
+
+	// commitHooks wait for the running transaction to commit
+	inTransaction bool
+	commitHooks   []func()
+
+	// For verifying that update functions were called with expected values.
+	lastUpdatedID          string
+	lastUpdatedColumn      string
+	lastUpdatedColumnValue any
+}
+
+// NewMemoryRepository returns a Repository that keeps everything in memory, so that the
+// tests of packages posting stock through a Service run against the real ledger rules.
+func NewMemoryRepository() Repository {
+	return newMemoryRepository()
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		products: make(map[string]*Product),
+		deleted:  make(map[string]*Product),
+	}
+}
+
+func (m *memoryRepository) Create(_ context.Context, p *Product) error {
+	p.ID = uuid.New()
+	m.products[p.ID.String()] = p
+	m.version(p)
+	return nil
+}
+
+// version records the product's current row, without associations.
+func (m *memoryRepository) version(p *Product) {
+	v := Product{BaseModel: p.BaseModel, Name: p.Name, Description: p.Description, StockQuantity: p.StockQuantity, LowStockThresold: p.LowStockThresold, SalePrice: p.SalePrice}
+	m.versions = append(m.versions, memoryVersion{product: v, from: time.Now()})
+}
+
+func (m *memoryRepository) GetVersion(_ context.Context, id string, asOf time.Time) (*Product, error) {
+	var found *Product
+	for i, v := range m.versions {
+		if v.product.ID.String() == id && !v.from.After(asOf) {
+			found = &m.versions[i].product
+		}
+	}
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *found
+	return &copied, nil
+}
+
+func (m *memoryRepository) StockAsOf(_ context.Context, productIDs []string, asOf time.Time) (map[string]int, error) {
+	stock := map[string]int{}
+	for _, mv := range m.movements {
+		if id := mv.ProductID.String(); slices.Contains(productIDs, id) && !mv.OccurredAt.After(asOf) {
+			stock[id] += mv.Quantity
+		}
+	}
+	return stock, nil
+}
+
+func (m *memoryRepository) GetAll(ctx context.Context, filter Filter) ([]Product, error) {
+	if !filter.AsOf.IsZero() {
+		out := []Product{}
+		seen := map[uuid.UUID]bool{}
+		for _, v := range m.versions {
+			if seen[v.product.ID] {
+				continue
+			}
+			seen[v.product.ID] = true
+			if p, err := m.GetVersion(ctx, v.product.ID.String(), filter.AsOf); err == nil && !p.DeletedAt.Valid {
+				out = append(out, *p)
+			}
+		}
+		return out, nil
+	}
+
+	out := make([]Product, 0, len(m.products))
+	for id, p := range m.products {
+		if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, id) {
+			continue
+		}
+		out = append(out, *m.withComponents(p))
+	}
+	slices.SortFunc(out, func(a, b Product) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	out = out[min(filter.Offset, len(out)):]
+	if filter.Limit > 0 {
+		out = out[:min(filter.Limit, len(out))]
+	}
+	return out, nil
+}
+
+func (m *memoryRepository) Count(ctx context.Context, filter Filter) (int64, error) {
+	filter.Limit, filter.Offset = 0, 0
+	out, err := m.GetAll(ctx, filter)
+	return int64(len(out)), err
+}
+
+// Search matches products whose name contains the text, ranked by name length so that
+// tests get a stable order.
+func (m *memoryRepository) Search(_ context.Context, query SearchQuery) ([]SearchHit, int64, error) {
+	var hits []SearchHit
+	for _, p := range m.products {
+		if strings.Contains(strings.ToLower(p.Name), strings.ToLower(query.Text)) {
+			hits = append(hits, SearchHit{ProductID: p.ID, Rank: 1 / float64(len(p.Name)), NameHighlight: p.Name})
+		}
+	}
+	slices.SortFunc(hits, func(a, b SearchHit) int { return cmp.Compare(b.Rank, a.Rank) })
+
+	total := int64(len(hits))
+	hits = hits[min(query.Offset, len(hits)):]
+	return hits[:min(query.Limit, len(hits))], total, nil
+}
+
+func (m *memoryRepository) GetByID(_ context.Context, id string) (*Product, error) {
+	p, ok := m.products[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.withComponents(p), nil
+}
+
+// withComponents returns a kit with its components resolved, as the repository preloads
+// them; other products are returned as stored.
+func (m *memoryRepository) withComponents(p *Product) *Product {
+	if !p.IsKit() {
+		return p
+	}
+
+	kit := *p
+	kit.Components = make([]KitComponent, len(p.Components))
+	for i, c := range p.Components {
+		c.Component = m.products[c.ComponentID.String()]
+		kit.Components[i] = c
+	}
+	return &kit
+}
+
+func (m *memoryRepository) UpdateAllColumn(_ context.Context, id string, p *Product) error {
+	existing, ok := m.products[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	// Associations are left alone, as the real repository omits them
+	p.Categories = existing.Categories
+	p.BaseModel = existing.BaseModel
+	m.products[id] = p
+	m.version(p)
+	m.lastUpdatedID = id
+	m.lastUpdatedColumn = "ALL"
+	return nil
+}
+
+func (m *memoryRepository) UpdateSingleColumn(_ context.Context, id string, column string, value any) error {
+	p, ok := m.products[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	switch column {
+	case "stock_quantity":
+		if v, ok := value.(int); ok {
+			p.StockQuantity = v
+		}
+	case "reserved_quantity":
+		if v, ok := value.(int); ok {
+			p.ReservedQuantity = v
+		}
+	default:
+		// ignore unknown column for test simplicity
+	}
+	m.lastUpdatedID = id
+	m.lastUpdatedColumn = column
+	m.lastUpdatedColumnValue = value
+	return nil
+}
+
+func (m *memoryRepository) Delete(_ context.Context, id string) error {
+	if _, ok := m.products[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	p := m.products[id]
+	p.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	m.version(p)
+	m.deleted[id] = p
+	delete(m.products, id)
+	return nil
+}
+
+func (m *memoryRepository) GetDeletedByID(_ context.Context, id string) (*Product, error) {
+	p, ok := m.deleted[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return p, nil
+}
+
+func (m *memoryRepository) Restore(_ context.Context, id string) error {
+	p := m.deleted[id]
+	p.DeletedAt = gorm.DeletedAt{}
+	m.version(p)
+	m.products[id] = p
+	delete(m.deleted, id)
+	return nil
+}
+
+func (m *memoryRepository) CreateAuditEntry(_ context.Context, e *audit.Entry) error {
+	m.audit = append(m.audit, *e)
+	return nil
+}
+
+func (m *memoryRepository) Transaction(_ context.Context, fn func(Repository) error) error {
+	if m.inTransaction {
+		return fn(m)
+	}
+
+	m.inTransaction = true
+	err := fn(m)
+	m.inTransaction = false
+
+	hooks := m.commitHooks
+	m.commitHooks = nil
+	if err == nil {
+		for _, hook := range hooks {
+			hook()
+		}
+	}
+	return err
+}
+
+func (m *memoryRepository) AfterCommit(fn func()) {
+	if !m.inTransaction {
+		fn()
+		return
+	}
+	m.commitHooks = append(m.commitHooks, fn)
+}
+
+func (m *memoryRepository) LockProduct(_ context.Context, id string) error {
+	if _, ok := m.products[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (m *memoryRepository) ReplaceOptions(_ context.Context, productID string, options []OptionAxis) error {
+	p, ok := m.products[productID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	p.Options = options
+	return nil
+}
+
+func (m *memoryRepository) ReplaceUnits(_ context.Context, productID string, units []UnitOfMeasure) error {
+	p, ok := m.products[productID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	p.Units = units
+	return nil
+}
+
+func (m *memoryRepository) ReplaceKitComponents(_ context.Context, kitID string, components []KitComponent) error {
+	p, ok := m.products[kitID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	p.Components = components
+	return nil
+}
+
+func (m *memoryRepository) IsKitComponent(_ context.Context, productID string) (bool, error) {
+	for _, p := range m.products {
+		for _, c := range p.Components {
+			if c.ComponentID.String() == productID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryRepository) GetKitIDs(_ context.Context, productID string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, p := range m.products {
+		for _, c := range p.Components {
+			if c.ComponentID.String() == productID {
+				ids = append(ids, p.ID)
+			}
+		}
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return cmp.Compare(a.String(), b.String()) })
+	return ids, nil
+}
+
+func (m *memoryRepository) GetKitComponents(_ context.Context, kitIDs []string) ([]KitComponent, error) {
+	var components []KitComponent
+	for _, id := range kitIDs {
+		if p, ok := m.products[id]; ok {
+			components = append(components, p.Components...)
+		}
+	}
+	return components, nil
+}
+
+func (m *memoryRepository) CreateVariant(_ context.Context, v *Variant) error {
+	v.ID = uuid.New()
+	for _, p := range m.products {
+		if p.ID == v.ProductID {
+			p.Variants = append(p.Variants, *v)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *memoryRepository) findVariant(variantID string) (*Product, int) {
+	for _, p := range m.products {
+		for i := range p.Variants {
+			if p.Variants[i].ID.String() == variantID {
+				return p, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+func (m *memoryRepository) GetVariantByID(_ context.Context, productID string, variantID string) (*Variant, error) {
+	p, i := m.findVariant(variantID)
+	if p == nil || m.products[productID] != p {
+		return nil, gorm.ErrRecordNotFound
+	}
+	v := p.Variants[i]
+	return &v, nil
+}
+
+func (m *memoryRepository) UpdateVariant(_ context.Context, v *Variant) error {
+	p, i := m.findVariant(v.ID.String())
+	if p == nil {
+		return gorm.ErrRecordNotFound
+	}
+	p.Variants[i] = *v
+	return nil
+}
+
+func (m *memoryRepository) UpdateVariantStock(_ context.Context, variantID string, quantity int) error {
+	p, i := m.findVariant(variantID)
+	if p == nil {
+		return gorm.ErrRecordNotFound
+	}
+	p.Variants[i].StockQuantity = quantity
+	return nil
+}
+
+func (m *memoryRepository) UpdateVariantReserved(_ context.Context, variantID string, quantity int) error {
+	p, i := m.findVariant(variantID)
+	if p == nil {
+		return gorm.ErrRecordNotFound
+	}
+	p.Variants[i].ReservedQuantity = quantity
+	return nil
+}
+
+func (m *memoryRepository) DeleteVariant(_ context.Context, variantID string) error {
+	p, i := m.findVariant(variantID)
+	if p == nil {
+		return gorm.ErrRecordNotFound
+	}
+	p.Variants = append(p.Variants[:i], p.Variants[i+1:]...)
+	return nil
+}
+
+func (m *memoryRepository) SyncVariantStock(_ context.Context, productID string) error {
+	p, ok := m.products[productID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	total := 0
+	for _, v := range p.Variants {
+		total += v.StockQuantity
+	}
+	p.StockQuantity = total
+	return nil
+}
+
+func (m *memoryRepository) CreateLot(_ context.Context, lot *Lot) error {
+	lot.ID = uuid.New()
+	m.lots = append(m.lots, *lot)
+	return nil
+}
+
+func (m *memoryRepository) GetLots(_ context.Context, productID string) ([]Lot, error) {
+	var out []Lot
+	for _, lot := range m.lots {
+		if lot.ProductID.String() == productID {
+			out = append(out, lot)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Lot) int {
+		switch {
+		case a.ExpiresAt == nil && b.ExpiresAt == nil:
+			return 0
+		case a.ExpiresAt == nil:
+			return 1
+		case b.ExpiresAt == nil:
+			return -1
+		}
+		return a.ExpiresAt.Compare(*b.ExpiresAt)
+	})
+	return out, nil
+}
+
+func (m *memoryRepository) UpdateLotQuantity(_ context.Context, lotID string, quantity int) error {
+	for i := range m.lots {
+		if m.lots[i].ID.String() == lotID {
+			m.lots[i].Quantity = quantity
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *memoryRepository) ListExpiringLots(_ context.Context, before time.Time) ([]Lot, error) {
+	var out []Lot
+	for _, lot := range m.lots {
+		if lot.Quantity > 0 && lot.ExpiresAt != nil && lot.ExpiresAt.Before(before) {
+			out = append(out, lot)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepository) CreateSerial(_ context.Context, serial *Serial) error {
+	serial.ID = uuid.New()
+	m.serials = append(m.serials, *serial)
+	return nil
+}
+
+func (m *memoryRepository) GetSerials(_ context.Context, productID string, numbers []string) ([]Serial, error) {
+	out := []Serial{}
+	for _, serial := range m.serials {
+		if serial.ProductID.String() == productID && (numbers == nil || slices.Contains(numbers, serial.SerialNumber)) {
+			out = append(out, serial)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepository) SetSerialsInStock(_ context.Context, productID string, numbers []string, inStock bool) error {
+	for i := range m.serials {
+		if m.serials[i].ProductID.String() == productID && slices.Contains(numbers, m.serials[i].SerialNumber) {
+			m.serials[i].InStock = inStock
+		}
+	}
+	return nil
+}
+
+func (m *memoryRepository) GetBuckets(_ context.Context, productID string) ([]StockBucket, error) {
+	var out []StockBucket
+	for _, b := range m.buckets {
+		if b.ProductID.String() == productID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepository) CreateBucket(_ context.Context, b *StockBucket) error {
+	b.ID = uuid.New()
+	m.buckets = append(m.buckets, *b)
+	return nil
+}
+
+func (m *memoryRepository) UpdateBucket(_ context.Context, bucketID string, quantity int, value int64) error {
+	for i := range m.buckets {
+		if m.buckets[i].ID.String() == bucketID {
+			m.buckets[i].Quantity, m.buckets[i].Value = quantity, value
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *memoryRepository) CreateMovement(_ context.Context, mv *StockMovement) error {
+	m.movements = append(m.movements, *mv)
+	return nil
+}
+
+// IssueCost replays the ledger first-in-first-out and costs the oldest units left.
+func (m *memoryRepository) IssueCost(_ context.Context, productID string, variantID *uuid.UUID, quantity int) (int64, error) {
+	type layer struct {
+		quantity int
+		unitCost int64
+	}
+
+	var layers []layer
+	for _, mv := range m.movements {
+		if mv.ProductID.String() != productID || !sameVariant(mv.VariantID, variantID) {
+			continue
+		}
+		if mv.Quantity > 0 {
+			layers = append(layers, layer{mv.Quantity, mv.UnitCost})
+			continue
+		}
+		for out := -mv.Quantity; out > 0 && len(layers) > 0; {
+			take := min(out, layers[0].quantity)
+			layers[0].quantity -= take
+			out -= take
+			if layers[0].quantity == 0 {
+				layers = layers[1:]
+			}
+		}
+	}
+
+	var cost int64
+	for _, l := range layers {
+		take := min(quantity, l.quantity)
+		cost += int64(take) * l.unitCost
+		quantity -= take
+	}
+	return cost, nil
+}
+
+func (m *memoryRepository) ListMovements(_ context.Context, filter MovementFilter) ([]StockMovement, error) {
+	var out []StockMovement
+	for _, mv := range m.movements {
+		if filter.ProductID != "" && mv.ProductID.String() != filter.ProductID {
+			continue
+		}
+		if len(filter.ProductIDs) > 0 && !slices.Contains(filter.ProductIDs, mv.ProductID.String()) {
+			continue
+		}
+		if filter.ReferenceType != "" && mv.ReferenceType != filter.ReferenceType {
+			continue
+		}
+		if filter.ReferenceID != "" && mv.ReferenceID != filter.ReferenceID {
+			continue
+		}
+		if filter.Serial != "" && !slices.Contains(mv.Serials, filter.Serial) {
+			continue
+		}
+		if !filter.Since.IsZero() && mv.OccurredAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !mv.OccurredAt.Before(filter.Until) {
+			continue
+		}
+		out = append(out, mv)
+	}
+
+	if filter.LastPerProduct > 0 {
+		kept := map[uuid.UUID]int{}
+		for i := len(out) - 1; i >= 0; i-- {
+			kept[out[i].ProductID]++
+			if kept[out[i].ProductID] > filter.LastPerProduct {
+				out = slices.Delete(out, i, i+1)
+			}
+		}
+	}
+	return out, nil
+}
@@ -1,10 +1,96 @@
 package product
 
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+)
+
 type Repository interface {
-	Create(*Product) error
-	GetAll() ([]Product, error)
-	GetByID(string) (*Product, error)
-	UpdateAllColumn(string, *Product) error
-	UpdateSingleColumn(string, string, any) error
-	Delete(string) error
+	Create(context.Context, *Product) error
+	// GetAll returns the matching products, or their versions at Filter.AsOf when it is set.
+	// Versions carry no associations.
+	GetAll(context.Context, Filter) ([]Product, error)
+	// Count returns the number of products matching the filter across all pages.
+	Count(context.Context, Filter) (int64, error)
+	GetByID(context.Context, string) (*Product, error)
+	// GetVersion returns the product as it was at asOf, without associations. A product
+	// deleted by then is returned with DeletedAt set.
+	GetVersion(ctx context.Context, id string, asOf time.Time) (*Product, error)
+	UpdateAllColumn(context.Context, string, *Product) error
+	UpdateSingleColumn(context.Context, string, string, any) error
+	Delete(context.Context, string) error
+	// GetDeletedByID returns a product only if it has been deleted.
+	GetDeletedByID(context.Context, string) (*Product, error)
+	// Restore undoes the deletion of a product.
+	Restore(context.Context, string) error
+	// Search returns one page of products matching the query, best match first, and the
+	// number of matches across all pages.
+	Search(ctx context.Context, query SearchQuery) ([]SearchHit, int64, error)
+
+	// Transaction runs fn with a Repository whose calls share one database transaction.
+	Transaction(context.Context, func(Repository) error) error
+	// AfterCommit runs fn once the surrounding transaction, including any it is nested in,
+	// commits; fn never runs if it rolls back. Outside of a transaction fn runs at once.
+	AfterCommit(fn func())
+	// LockProduct locks the product row until the surrounding transaction ends.
+	LockProduct(ctx context.Context, id string) error
+
+	ReplaceOptions(ctx context.Context, productID string, options []OptionAxis) error
+	CreateVariant(context.Context, *Variant) error
+	GetVariantByID(ctx context.Context, productID string, variantID string) (*Variant, error)
+	UpdateVariant(context.Context, *Variant) error
+	UpdateVariantStock(ctx context.Context, variantID string, quantity int) error
+	UpdateVariantReserved(ctx context.Context, variantID string, quantity int) error
+	DeleteVariant(ctx context.Context, variantID string) error
+	// SyncVariantStock sets the product's stock to the sum of its variants' stock.
+	SyncVariantStock(ctx context.Context, productID string) error
+
+	ReplaceUnits(ctx context.Context, productID string, units []UnitOfMeasure) error
+
+	ReplaceKitComponents(ctx context.Context, kitID string, components []KitComponent) error
+	// IsKitComponent reports whether the product is a component of any kit that has not
+	// been deleted.
+	IsKitComponent(ctx context.Context, productID string) (bool, error)
+	// GetKitIDs returns the IDs of the kits, not deleted, that the product is a component
+	// of, in ID order.
+	GetKitIDs(ctx context.Context, productID string) ([]uuid.UUID, error)
+	// GetKitComponents returns the components of the kits, deleted or not, without the
+	// component products.
+	GetKitComponents(ctx context.Context, kitIDs []string) ([]KitComponent, error)
+
+	CreateLot(context.Context, *Lot) error
+	// GetLots returns the product's lots, earliest expiry first and lots without expiry last.
+	GetLots(ctx context.Context, productID string) ([]Lot, error)
+	UpdateLotQuantity(ctx context.Context, lotID string, quantity int) error
+	// ListExpiringLots returns lots still holding stock that expire before the given time,
+	// earliest expiry first.
+	ListExpiringLots(ctx context.Context, before time.Time) ([]Lot, error)
+
+	CreateSerial(context.Context, *Serial) error
+	// GetSerials returns the product's serials with the given numbers, or all of them when
+	// numbers is nil.
+	GetSerials(ctx context.Context, productID string, numbers []string) ([]Serial, error)
+	SetSerialsInStock(ctx context.Context, productID string, numbers []string, inStock bool) error
+
+	// GetBuckets returns the product's stock held outside the available bucket.
+	GetBuckets(ctx context.Context, productID string) ([]StockBucket, error)
+	CreateBucket(context.Context, *StockBucket) error
+	// UpdateBucket sets the units a bucket holds and their cost.
+	UpdateBucket(ctx context.Context, bucketID string, quantity int, value int64) error
+
+	// CreateAuditEntry appends an entry to the audit trail.
+	CreateAuditEntry(context.Context, *audit.Entry) error
+
+	CreateMovement(context.Context, *StockMovement) error
+	// StockAsOf replays the ledger up to and including asOf, returning each product's
+	// available stock at that time. Products without movements by then are left out.
+	StockAsOf(ctx context.Context, productIDs []string, asOf time.Time) (map[string]int, error)
+	// IssueCost returns the cost of the next quantity units to leave the available stock of
+	// a product, or of one of its variants, taking the oldest receipts still on hand first.
+	IssueCost(ctx context.Context, productID string, variantID *uuid.UUID, quantity int) (int64, error)
+	// ListMovements returns matching ledger entries in the order they occurred.
+	ListMovements(context.Context, MovementFilter) ([]StockMovement, error)
 }
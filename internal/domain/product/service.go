@@ -1,25 +1,88 @@
 package product
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
 	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/money"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
 	"gorm.io/gorm"
 )
 
 type Service interface {
-	CreateProduct(*Product) error
-	GetAllProducts() ([]Product, error)
-	GetProductByID(string) (*Product, error)
-	UpdateProduct(string, *Product) error
-	DeleteProduct(string) error
-
-	IncermentStock(id string, quantity int) error
-	DecrementStock(id string, quantity int) error
+	CreateProduct(context.Context, *Product) error
+	GetAllProducts(context.Context, Filter) ([]Product, error)
+	// CountProducts returns the number of products GetAllProducts matches across all pages.
+	CountProducts(context.Context, Filter) (int, error)
+	GetProductByID(context.Context, string) (*Product, error)
+	// GetProductAsOf returns the product as it was at the given time, with its stock
+	// replayed from the ledger. Associations such as variants are not versioned and are
+	// left out.
+	GetProductAsOf(ctx context.Context, id string, asOf time.Time) (*Product, error)
+	// SearchProducts finds products by name, description or variant SKU, tolerating typos.
+	SearchProducts(ctx context.Context, query SearchQuery) (*SearchPage, error)
+	UpdateProduct(context.Context, string, *Product) error
+	DeleteProduct(context.Context, string) error
+	// RestoreProduct undoes the deletion of a product.
+	RestoreProduct(context.Context, string) error
+
+	IncermentStock(ctx context.Context, id string, quantity int) error
+	DecrementStock(ctx context.Context, id string, quantity int) error
+
+	SetOptions(ctx context.Context, productID string, options []OptionAxis) error
+	CreateVariant(ctx context.Context, productID string, variant *Variant) error
+	UpdateVariant(ctx context.Context, productID string, variantID string, variant *Variant) error
+	DeleteVariant(ctx context.Context, productID string, variantID string) error
+	IncrementVariantStock(ctx context.Context, productID string, variantID string, quantity int) error
+	DecrementVariantStock(ctx context.Context, productID string, variantID string, quantity int) error
+
+	// ReceiveLot increments the stock of a lot-tracked product, creating the lot if needed.
+	ReceiveLot(ctx context.Context, productID string, receipt LotReceipt) error
+	GetLots(ctx context.Context, productID string) ([]Lot, error)
+	// GetExpiringLots lists lots still holding stock that expire within the given number
+	// of days, including lots that have already expired.
+	GetExpiringLots(ctx context.Context, days int) ([]Lot, error)
+
+	// IncrementSerials adds one unit of a serialized product per serial number.
+	IncrementSerials(ctx context.Context, productID string, serials []string) error
+	// DecrementSerials removes the named units of a serialized product.
+	DecrementSerials(ctx context.Context, productID string, serials []string) error
+	GetSerials(ctx context.Context, productID string, inStockOnly bool) ([]Serial, error)
+	GetSerialHistory(ctx context.Context, productID string, serial string) (*SerialHistory, error)
+
+	// SetUnits replaces the units the product's stock can be given in besides its base unit.
+	SetUnits(ctx context.Context, productID string, units []UnitOfMeasure) error
+	// ToBaseUnits converts a quantity in the named unit to the product's base unit. An
+	// empty unit is the base unit. The result must be a whole number of base units.
+	ToBaseUnits(ctx context.Context, productID string, q quantity.Decimal, unit string) (int, error)
+
+	// SetKitComponents makes the product a kit of the given components, replacing any it
+	// had; an empty list makes it an ordinary product again.
+	SetKitComponents(ctx context.Context, kitID string, components []KitComponent) error
+
+	// ReserveStock allocates up to quantity available units of a product, or of one of its
+	// variants, and returns how many it reserved.
+	ReserveStock(ctx context.Context, productID string, variantID string, quantity int) (int, error)
+	ReleaseStock(ctx context.Context, productID string, variantID string, quantity int) error
+
+	// MoveStock moves units between stock buckets. Moves out of or into the available
+	// bucket change StockQuantity and are posted to the ledger.
+	MoveStock(ctx context.Context, productID string, move BucketMove) error
+	// GetStockBuckets returns the stock of the product, or of each of its variants, per bucket.
+	GetStockBuckets(ctx context.Context, productID string) ([]BucketLevels, error)
+
+	// ApplyStockChanges posts the changes to the stock ledger in one transaction. Nothing is
+	// applied if any change would take a product or variant below zero.
+	ApplyStockChanges(ctx context.Context, changes ...StockChange) ([]StockMovement, error)
+	ListMovements(ctx context.Context, filter MovementFilter) ([]StockMovement, error)
 }
 
 type service struct {
-	repo Repository
+	repo   Repository
+	events Publisher
 }
 
 func NewService(repo Repository) Service {
@@ -28,8 +91,17 @@ func NewService(repo Repository) Service {
 	}
 }
 
-// CreateProduct implements Service.
-func (s *service) CreateProduct(product *Product) error {
+// NewServiceWithEvents returns a Service that publishes the stock changes it commits to events.
+func NewServiceWithEvents(repo Repository, events Publisher) Service {
+	return &service{
+		repo:   repo,
+		events: events,
+	}
+}
+
+// CreateProduct implements Service. Initial stock is recorded in the ledger as an opening
+// movement. Creates, updates, deletes and restores are recorded in the audit trail.
+func (s *service) CreateProduct(ctx context.Context, product *Product) error {
 	// Validate required fields
 	if product.Name == "" {
 		return apperrors.NewMissingRequiredDataError("name")
@@ -39,56 +111,151 @@ func (s *service) CreateProduct(product *Product) error {
 		return apperrors.NewInvalidInputError("stock quantity cannot be negative")
 	}
 
-	err := s.repo.Create(product)
-	if err != nil {
-		// Handle duplicate entry errors (if name should be unique)
-		// This depends on your database constraints
-		return apperrors.NewDatabaseError("failed to create product: " + err.Error())
+	if err := validateDetails(product); err != nil {
+		return err
+	}
+
+	if product.LotTracked && product.StockQuantity > 0 {
+		return apperrors.NewInvalidInputError("lot-tracked products start without stock; receive stock into a lot instead")
 	}
 
-	return nil
+	if product.Serialized && product.StockQuantity > 0 {
+		return apperrors.NewInvalidInputError("serialized products start without stock; increment with serial numbers instead")
+	}
+
+	if product.Serialized && product.LotTracked {
+		return apperrors.NewInvalidInputError("a product cannot be both serialized and lot-tracked")
+	}
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		if err := repo.Create(ctx, product); err != nil {
+			// Handle duplicate entry errors (if name should be unique)
+			// This depends on your database constraints
+			return apperrors.NewDatabaseError("failed to create product: " + err.Error())
+		}
+
+		if product.StockQuantity > 0 {
+			m := newMovement(product, product.StockQuantity, StockChange{Reason: ReasonOpening})
+			if err := createMovement(ctx, repo, m); err != nil {
+				return err
+			}
+
+			if err := s.publishStock(ctx, repo, []StockMovement{*m}); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(ctx, repo, audit.OperationCreate, nil, product)
+	})
+}
+
+// validateDetails defaults the currency and base unit, rejects negative prices and lead
+// times, and normalizes attributes and tags.
+func validateDetails(product *Product) error {
+	if product.Currency == "" {
+		product.Currency = money.DefaultCurrency
+	}
+
+	if product.BaseUnit == "" {
+		product.BaseUnit = DefaultBaseUnit
+	}
+
+	if !money.ValidCurrency(product.Currency) {
+		return apperrors.NewInvalidInputError("currency must be a three-letter ISO 4217 code")
+	}
+
+	if product.UnitCost < 0 || product.SalePrice < 0 {
+		return apperrors.NewInvalidInputError("unit cost and sale price cannot be negative")
+	}
+
+	if product.LeadTimeDays < 0 {
+		return apperrors.NewInvalidInputError("lead time cannot be negative")
+	}
+
+	return normalizeAttributes(product)
 }
 
 // DeleteProduct implements Service.
-func (s *service) DeleteProduct(id string) error {
+func (s *service) DeleteProduct(ctx context.Context, id string) error {
 	if id == "" {
 		return apperrors.NewMissingRequiredDataError("id")
 	}
 
-	// Check if product exists first
-	_, err := s.repo.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperrors.NewProductNotFoundError(id)
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		// Check if product exists first
+		existing, err := lockProduct(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		// Kits cannot be assembled without their components
+		used, err := repo.IsKitComponent(ctx, id)
+		if err != nil {
+			return apperrors.NewDatabaseError("failed to check kit components: " + err.Error())
+		}
+		if used {
+			return apperrors.NewBusinessLogicError("product is a component of a kit; remove it from the kit first")
 		}
-		return apperrors.NewDatabaseError("failed to check product existence: " + err.Error())
+
+		if err := repo.Delete(ctx, id); err != nil {
+			return apperrors.NewDatabaseError("failed to delete product: " + err.Error())
+		}
+
+		return recordAudit(ctx, repo, audit.OperationDelete, existing, nil)
+	})
+}
+
+// GetAllProducts implements Service. With Filter.AsOf set, products are listed as they
+// were at that time, as GetProductAsOf returns them.
+func (s *service) GetAllProducts(ctx context.Context, filter Filter) ([]Product, error) {
+	if !filter.AsOf.IsZero() {
+		filter.AsOf = pastOrNow(filter.AsOf)
 	}
 
-	err = s.repo.Delete(id)
+	products, err := s.repo.GetAll(ctx, filter)
 	if err != nil {
-		return apperrors.NewDatabaseError("failed to delete product: " + err.Error())
+		return nil, apperrors.NewDatabaseError("failed to retrieve products: " + err.Error())
 	}
 
-	return nil
+	if !filter.AsOf.IsZero() {
+		if err := replayStock(ctx, s.repo, products, filter.AsOf); err != nil {
+			return nil, err
+		}
+		return products, nil
+	}
+
+	for i := range products {
+		setKitStock(&products[i])
+	}
+
+	return products, nil
 }
 
-// GetAllProducts implements Service.
-func (s *service) GetAllProducts() ([]Product, error) {
-	products, err := s.repo.GetAll()
+// CountProducts implements Service.
+func (s *service) CountProducts(ctx context.Context, filter Filter) (int, error) {
+	if !filter.AsOf.IsZero() {
+		filter.AsOf = pastOrNow(filter.AsOf)
+	}
+
+	count, err := s.repo.Count(ctx, filter)
 	if err != nil {
-		return nil, apperrors.NewDatabaseError("failed to retrieve products: " + err.Error())
+		return 0, apperrors.NewDatabaseError("failed to count products: " + err.Error())
 	}
 
-	return products, nil
+	return int(count), nil
 }
 
 // GetProductByID implements Service.
-func (s *service) GetProductByID(id string) (*Product, error) {
+func (s *service) GetProductByID(ctx context.Context, id string) (*Product, error) {
+	return getProduct(ctx, s.repo, id)
+}
+
+func getProduct(ctx context.Context, repo Repository, id string) (*Product, error) {
 	if id == "" {
 		return nil, apperrors.NewMissingRequiredDataError("id")
 	}
 
-	product, err := s.repo.GetByID(id)
+	product, err := repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewProductNotFoundError(id)
@@ -96,11 +263,15 @@ func (s *service) GetProductByID(id string) (*Product, error) {
 		return nil, apperrors.NewDatabaseError("failed to retrieve product: " + err.Error())
 	}
 
+	setKitStock(product)
+
 	return product, nil
 }
 
-// UpdateProduct implements Service.
-func (s *service) UpdateProduct(id string, product *Product) error {
+// UpdateProduct implements Service. Stock is never written here: it only changes through
+// stock operations, which record it in the ledger. An empty currency or base unit keeps the
+// current one.
+func (s *service) UpdateProduct(ctx context.Context, id string, product *Product) error {
 	if id == "" {
 		return apperrors.NewMissingRequiredDataError("id")
 	}
@@ -110,29 +281,71 @@ func (s *service) UpdateProduct(id string, product *Product) error {
 		return apperrors.NewMissingRequiredDataError("name")
 	}
 
-	if product.StockQuantity < 0 {
-		return apperrors.NewInvalidInputError("stock quantity cannot be negative")
-	}
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		// Check if product exists
+		existing, err := lockProduct(ctx, repo, id)
+		if err != nil {
+			return err
+		}
 
-	// Check if product exists
-	_, err := s.repo.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperrors.NewProductNotFoundError(id)
+		// An empty currency or base unit keeps the current one rather than the default
+		if product.Currency == "" {
+			product.Currency = existing.Currency
+		}
+		if product.BaseUnit == "" {
+			product.BaseUnit = existing.BaseUnit
 		}
-		return apperrors.NewDatabaseError("failed to check product existence: " + err.Error())
-	}
 
-	err = s.repo.UpdateAllColumn(id, product)
-	if err != nil {
-		return apperrors.NewDatabaseError("failed to update product: " + err.Error())
-	}
+		if err := validateDetails(product); err != nil {
+			return err
+		}
+
+		if err := validateCategoryAttributes(existing, product); err != nil {
+			return err
+		}
+
+		if err := validateLotTracking(existing, product); err != nil {
+			return err
+		}
+
+		if err := validateSerialTracking(existing, product); err != nil {
+			return err
+		}
+
+		if product.BaseUnit != baseUnit(existing) && existing.StockQuantity != 0 {
+			return apperrors.NewBusinessLogicError("the base unit can only change while the product has no stock")
+		}
+
+		if existing.IsKit() && (product.LotTracked || product.Serialized) {
+			return apperrors.NewBusinessLogicError("kits cannot be lot-tracked or serialized")
+		}
+
+		// Stock only changes through stock operations, which record it in the ledger
+		product.StockQuantity = existing.StockQuantity
+		product.ReservedQuantity = existing.ReservedQuantity
+
+		// A kit's stock is computed from its components and never stored
+		if existing.IsKit() {
+			product.StockQuantity = 0
+		}
 
-	return nil
+		if err := repo.UpdateAllColumn(ctx, id, product); err != nil {
+			return apperrors.NewDatabaseError("failed to update product: " + err.Error())
+		}
+
+		product.ID = existing.ID
+
+		updated, err := getProduct(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repo, audit.OperationUpdate, existing, updated)
+	})
 }
 
 // IncrementStock implements Service.
-func (s *service) IncermentStock(id string, quantity int) error {
+func (s *service) IncermentStock(ctx context.Context, id string, quantity int) error {
 	if id == "" {
 		return apperrors.NewMissingRequiredDataError("id")
 	}
@@ -141,26 +354,12 @@ func (s *service) IncermentStock(id string, quantity int) error {
 		return apperrors.NewInvalidInputError("increment quantity must be greater than 0")
 	}
 
-	p, err := s.repo.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperrors.NewProductNotFoundError(id)
-		}
-		return apperrors.NewDatabaseError("failed to retrieve product: " + err.Error())
-	}
-
-	p.StockQuantity += quantity
-
-	err = s.repo.UpdateSingleColumn(id, "stock_quantity", p.StockQuantity)
-	if err != nil {
-		return apperrors.NewDatabaseError("failed to update product stock: " + err.Error())
-	}
-
-	return nil
+	_, err := s.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: quantity, Reason: ReasonIncrement})
+	return err
 }
 
 // DecrementStock implements Service.
-func (s *service) DecrementStock(id string, quantity int) error {
+func (s *service) DecrementStock(ctx context.Context, id string, quantity int) error {
 	if id == "" {
 		return apperrors.NewMissingRequiredDataError("id")
 	}
@@ -169,24 +368,6 @@ func (s *service) DecrementStock(id string, quantity int) error {
 		return apperrors.NewInvalidInputError("decrement quantity must be greater than 0")
 	}
 
-	p, err := s.repo.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperrors.NewProductNotFoundError(id)
-		}
-		return apperrors.NewDatabaseError("failed to retrieve product: " + err.Error())
-	}
-
-	if p.StockQuantity < quantity {
-		return apperrors.NewInsufficientStockError(p.StockQuantity, quantity)
-	}
-
-	p.StockQuantity -= quantity
-
-	err = s.repo.UpdateSingleColumn(id, "stock_quantity", p.StockQuantity)
-	if err != nil {
-		return apperrors.NewDatabaseError("failed to update product stock: " + err.Error())
-	}
-
-	return nil
+	_, err := s.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: -quantity, Reason: ReasonDecrement})
+	return err
 }
@@ -0,0 +1,53 @@
+package product
+
+import (
+	"strings"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// normalizeAttributes checks that attribute names are non-empty and values are strings,
+// numbers or booleans, and trims, de-duplicates and drops empty tags.
+func normalizeAttributes(product *Product) error {
+	if product.Attributes == nil {
+		product.Attributes = map[string]any{}
+	}
+
+	for name, v := range product.Attributes {
+		if strings.TrimSpace(name) == "" {
+			return apperrors.NewInvalidInputError("attribute names cannot be empty")
+		}
+
+		switch v.(type) {
+		case string, float64, bool:
+		default:
+			return apperrors.NewInvalidInputError("attribute " + name + " must be a string, number or boolean")
+		}
+	}
+
+	tags := make([]string, 0, len(product.Tags))
+	seen := make(map[string]bool, len(product.Tags))
+	for _, tag := range product.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	product.Tags = tags
+
+	return nil
+}
+
+// validateCategoryAttributes checks the product's attributes against the attribute schema
+// of each category it is assigned to.
+func validateCategoryAttributes(existing *Product, product *Product) error {
+	for i := range existing.Categories {
+		if err := existing.Categories[i].ValidateAttributes(product.Attributes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package product
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_Attributes(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{
+		Name: "T-Shirt",
+		Categories: []category.Category{{
+			Name:            "Shirts",
+			AttributeSchema: []category.AttributeSpec{{Name: "color", Type: category.AttributeString, Required: true}},
+		}},
+	}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("update normalizes tags", func(t *testing.T) {
+		assertNoError(t, svc.UpdateProduct(ctx, id, &Product{
+			Name:       "T-Shirt",
+			Attributes: map[string]any{"color": "red", "organic": true},
+			Tags:       []string{" clearance", "summer", "clearance", ""},
+		}))
+
+		if got := repo.products[id].Tags; !slices.Equal(got, []string{"clearance", "summer"}) {
+			t.Fatalf("expected tags [clearance summer], got %v", got)
+		}
+	})
+
+	t.Run("error on an attribute the category schema rejects", func(t *testing.T) {
+		err := svc.UpdateProduct(ctx, id, &Product{Name: "T-Shirt", Attributes: map[string]any{"size": "M"}})
+		assertAppErrorCode(t, err, apperrors.ValidationError)
+	})
+
+	t.Run("error on a nested attribute value", func(t *testing.T) {
+		err := svc.CreateProduct(ctx, &Product{Name: "Mug", Attributes: map[string]any{"dims": map[string]any{"h": 10.0}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
@@ -0,0 +1,66 @@
+package product
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// RestoreProduct implements Service.
+func (s *service) RestoreProduct(ctx context.Context, id string) error {
+	if id == "" {
+		return apperrors.NewMissingRequiredDataError("id")
+	}
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		deleted, err := repo.GetDeletedByID(ctx, id)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.NewDatabaseError("failed to retrieve product: " + err.Error())
+			}
+			if _, err := getProduct(ctx, repo, id); err != nil {
+				return err
+			}
+			return apperrors.NewBusinessLogicError("product " + id + " is not deleted")
+		}
+
+		if err := repo.Restore(ctx, id); err != nil {
+			return apperrors.NewDatabaseError("failed to restore product: " + err.Error())
+		}
+
+		restored, err := getProduct(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repo, audit.OperationRestore, deleted, restored)
+	})
+}
+
+// recordAudit appends the change from before to after to the product's audit trail in
+// the surrounding transaction. before is nil for a create and after is nil for a delete.
+// Updates that change nothing are not recorded.
+func recordAudit(ctx context.Context, repo Repository, op audit.Operation, before, after *Product) error {
+	id := after
+	if id == nil {
+		id = before
+	}
+
+	entry, err := audit.NewEntry(ctx, audit.EntityProduct, id.ID, op, before, after)
+	if err != nil {
+		return apperrors.NewInternalServerError("failed to record audit entry: " + err.Error())
+	}
+
+	if op == audit.OperationUpdate && len(entry.Changes) == 0 {
+		return nil
+	}
+
+	if err := repo.CreateAuditEntry(ctx, entry); err != nil {
+		return apperrors.NewDatabaseError("failed to record audit entry: " + err.Error())
+	}
+
+	return nil
+}
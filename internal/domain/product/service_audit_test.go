@@ -0,0 +1,88 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_Audit(t *testing.T) {
+	repo := newMemoryRepository()
+
+	svc := NewService(repo)
+	ctx := audit.WithRequestID(audit.WithActor(context.Background(), "alice"), "req-1")
+
+	p := &Product{Name: "Widget", StockQuantity: 5, LowStockThresold: 2}
+	assertNoError(t, svc.CreateProduct(ctx, p))
+	id := p.ID.String()
+
+	last := func() audit.Entry {
+		return repo.audit[len(repo.audit)-1]
+	}
+
+	t.Run("create is recorded with the actor and request", func(t *testing.T) {
+		e := last()
+		if e.Operation != audit.OperationCreate || e.Actor != "alice" || e.RequestID != "req-1" || e.EntityID != p.ID {
+			t.Fatalf("unexpected entry: %+v", e)
+		}
+		if c := e.Changes["name"]; c.Old != nil || c.New != "Widget" {
+			t.Fatalf("unexpected name change: %+v", c)
+		}
+	})
+
+	t.Run("update records the changed fields", func(t *testing.T) {
+		assertNoError(t, svc.UpdateProduct(ctx, id, &Product{Name: "Widget", Description: "Blue", LowStockThresold: 3}))
+
+		e := last()
+		if e.Operation != audit.OperationUpdate {
+			t.Fatalf("expected an update, got %s", e.Operation)
+		}
+		if _, ok := e.Changes["name"]; ok {
+			t.Fatalf("expected the unchanged name to be left out, got %+v", e.Changes)
+		}
+		if c := e.Changes["low_stock_threshold"]; c.Old != float64(2) || c.New != float64(3) {
+			t.Fatalf("unexpected threshold change: %+v", c)
+		}
+		if c := e.Changes["description"]; c.Old != "" || c.New != "Blue" {
+			t.Fatalf("unexpected description change: %+v", c)
+		}
+	})
+
+	t.Run("update that changes nothing is not recorded", func(t *testing.T) {
+		n := len(repo.audit)
+		assertNoError(t, svc.UpdateProduct(ctx, id, &Product{Name: "Widget", Description: "Blue", LowStockThresold: 3}))
+
+		if len(repo.audit) != n {
+			t.Fatalf("expected no entry, got %+v", last())
+		}
+	})
+
+	t.Run("delete and restore are recorded", func(t *testing.T) {
+		assertNoError(t, svc.DeleteProduct(ctx, id))
+		if e := last(); e.Operation != audit.OperationDelete || e.Changes["name"].Old != "Widget" {
+			t.Fatalf("unexpected delete entry: %+v", e)
+		}
+
+		assertNoError(t, svc.RestoreProduct(ctx, id))
+		if e := last(); e.Operation != audit.OperationRestore {
+			t.Fatalf("unexpected restore entry: %+v", e)
+		}
+
+		if _, err := svc.GetProductByID(ctx, id); err != nil {
+			t.Fatalf("expected the product to be restored, got %v", err)
+		}
+	})
+
+	t.Run("error restoring a product that is not deleted", func(t *testing.T) {
+		err := svc.RestoreProduct(ctx, id)
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error restoring an unknown product", func(t *testing.T) {
+		err := svc.RestoreProduct(ctx, uuid.NewString())
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+	})
+}
@@ -0,0 +1,234 @@
+package product
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/money"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+)
+
+// MoveStock implements Service. Units leaving the available bucket must be on hand, but
+// may be reserved; units leaving any other bucket must be held in it.
+func (s *service) MoveStock(ctx context.Context, productID string, move BucketMove) error {
+	if !validBucket(move.From) || !validBucket(move.To) {
+		return apperrors.NewInvalidInputError("buckets must be available, quarantined, damaged or in_transit")
+	}
+
+	if move.From == move.To {
+		return apperrors.NewInvalidInputError("units must move to a different bucket")
+	}
+
+	if move.Quantity <= 0 {
+		return apperrors.NewInvalidInputError("move quantity must be greater than 0")
+	}
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		p, err := lockProduct(ctx, repo, productID)
+		if err != nil {
+			return err
+		}
+
+		if p.IsKit() {
+			return apperrors.NewBusinessLogicError("kits hold no stock of their own; move their components instead")
+		}
+
+		if move.Unit != "" {
+			if move.Quantity, err = p.ToBaseUnits(quantity.FromInt(move.Quantity), move.Unit); err != nil {
+				return err
+			}
+		}
+
+		var variantID *uuid.UUID
+		if move.VariantID != "" {
+			v, err := getVariant(ctx, repo, productID, move.VariantID)
+			if err != nil {
+				return err
+			}
+			variantID = &v.ID
+		} else if len(p.Variants) > 0 {
+			return newVariantStockOnlyError()
+		}
+
+		buckets, err := repo.GetBuckets(ctx, productID)
+		if err != nil {
+			return apperrors.NewDatabaseError("failed to retrieve stock buckets: " + err.Error())
+		}
+
+		// The units keep the cost they left the available bucket at, so that moving them
+		// back does not re-cost them at the product's current unit cost
+		var cost int64
+		if move.From != BucketAvailable {
+			from := findBucket(buckets, variantID, move.From)
+			held := 0
+			if from != nil {
+				held = from.Quantity
+			}
+			if held < move.Quantity {
+				return apperrors.NewInsufficientStockError(held, move.Quantity)
+			}
+			cost = money.MulDiv(from.Value, int64(move.Quantity), int64(held))
+			if err := updateBucket(ctx, repo, from, held-move.Quantity, from.Value-cost); err != nil {
+				return err
+			}
+		} else {
+			cost, err = repo.IssueCost(ctx, productID, variantID, move.Quantity)
+			if err != nil {
+				return apperrors.NewDatabaseError("failed to cost the units moved: " + err.Error())
+			}
+		}
+
+		if move.From == BucketAvailable || move.To == BucketAvailable {
+			change := StockChange{
+				ProductID:     productID,
+				VariantID:     move.VariantID,
+				Quantity:      move.Quantity,
+				Reason:        move.Reason,
+				LotNumber:     move.LotNumber,
+				Serials:       move.Serials,
+				Bucket:        move.From,
+				ReferenceType: move.ReferenceType,
+				ReferenceID:   move.ReferenceID,
+				Note:          move.Note,
+			}
+			if move.From == BucketAvailable {
+				change.Quantity = -move.Quantity
+				change.Bucket = move.To
+			}
+			if change.Reason == "" {
+				change.Reason = ReasonTransfer
+			}
+
+			var applied []StockMovement
+			for _, c := range costChanges(change, cost) {
+				movements, err := applyStockChange(ctx, repo, c)
+				if err != nil {
+					return err
+				}
+				applied = append(applied, movements...)
+			}
+
+			if err := s.publishStock(ctx, repo, applied); err != nil {
+				return err
+			}
+		}
+
+		if move.To != BucketAvailable {
+			to := findBucket(buckets, variantID, move.To)
+			if to == nil {
+				to = &StockBucket{ProductID: p.ID, VariantID: variantID, Bucket: move.To}
+				if err := repo.CreateBucket(ctx, to); err != nil {
+					return apperrors.NewDatabaseError("failed to create stock bucket: " + err.Error())
+				}
+			}
+			if err := updateBucket(ctx, repo, to, to.Quantity+move.Quantity, to.Value+cost); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetStockBuckets implements Service.
+func (s *service) GetStockBuckets(ctx context.Context, productID string) ([]BucketLevels, error) {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := s.repo.GetBuckets(ctx, p.ID.String())
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve stock buckets: " + err.Error())
+	}
+
+	var levels []BucketLevels
+	if len(p.Variants) == 0 {
+		levels = []BucketLevels{{ProductID: p.ID, Available: p.StockQuantity}}
+	} else {
+		for _, v := range p.Variants {
+			levels = append(levels, BucketLevels{ProductID: p.ID, VariantID: &v.ID, Available: v.StockQuantity})
+		}
+	}
+
+	for i := range levels {
+		l := &levels[i]
+		for _, b := range buckets {
+			if !sameVariant(b.VariantID, l.VariantID) {
+				continue
+			}
+			switch b.Bucket {
+			case BucketQuarantined:
+				l.Quarantined += b.Quantity
+			case BucketDamaged:
+				l.Damaged += b.Quantity
+			case BucketInTransit:
+				l.InTransit += b.Quantity
+			}
+		}
+	}
+
+	return levels, nil
+}
+
+// costChanges splits the change so that its units carry exactly cost between them: the
+// units cost the quotient of the division each, and the remainder is carried one minor
+// unit at a time by the last units.
+func costChanges(change StockChange, cost int64) []StockChange {
+	units := int64(change.Quantity)
+	sign := 1
+	if units < 0 {
+		units, sign = -units, -1
+	}
+
+	change.UnitCost = cost / units
+	remainder := int(cost % units)
+	if remainder == 0 {
+		return []StockChange{change}
+	}
+
+	rest := change
+	rest.Quantity = sign * remainder
+	rest.UnitCost++
+	change.Quantity -= rest.Quantity
+	if len(change.Serials) > 0 {
+		split := len(change.Serials) - remainder
+		change.Serials, rest.Serials = change.Serials[:split], change.Serials[split:]
+	}
+
+	return []StockChange{change, rest}
+}
+
+func updateBucket(ctx context.Context, repo Repository, b *StockBucket, quantity int, value int64) error {
+	if err := repo.UpdateBucket(ctx, b.ID.String(), quantity, value); err != nil {
+		return apperrors.NewDatabaseError("failed to update stock bucket: " + err.Error())
+	}
+
+	b.Quantity, b.Value = quantity, value
+	return nil
+}
+
+func findBucket(buckets []StockBucket, variantID *uuid.UUID, bucket Bucket) *StockBucket {
+	for i := range buckets {
+		if buckets[i].Bucket == bucket && sameVariant(buckets[i].VariantID, variantID) {
+			return &buckets[i]
+		}
+	}
+	return nil
+}
+
+func sameVariant(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func validBucket(b Bucket) bool {
+	switch b {
+	case BucketAvailable, BucketQuarantined, BucketDamaged, BucketInTransit:
+		return true
+	}
+	return false
+}
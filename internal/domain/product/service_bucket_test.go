@@ -0,0 +1,136 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_StockBuckets(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Widget", StockQuantity: 10, LowStockThresold: 8}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	levels := func(t *testing.T) BucketLevels {
+		t.Helper()
+		got, err := svc.GetStockBuckets(ctx, id)
+		assertNoError(t, err)
+		return got[0]
+	}
+
+	t.Run("moving out of available lowers the stock and posts a transfer", func(t *testing.T) {
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketAvailable, To: BucketQuarantined, Quantity: 3, Note: "on hold"}))
+
+		if got := levels(t); got.Available != 7 || got.Quarantined != 3 {
+			t.Fatalf("expected 7 available and 3 quarantined, got %+v", got)
+		}
+
+		m := repo.movements[len(repo.movements)-1]
+		if m.Quantity != -3 || m.Reason != ReasonTransfer || m.Bucket != BucketQuarantined || m.Note != "on hold" {
+			t.Fatalf("unexpected movement: %+v", m)
+		}
+	})
+
+	t.Run("only available units can be sold", func(t *testing.T) {
+		assertAppErrorCode(t, svc.DecrementStock(ctx, id, 8), apperrors.InsufficientStock)
+	})
+
+	t.Run("only available units count toward low stock", func(t *testing.T) {
+		got, err := svc.GetProductByID(ctx, id)
+		assertNoError(t, err)
+		if got.StockQuantity > got.LowStockThresold {
+			t.Fatalf("expected stock %d to be at or below the threshold %d", got.StockQuantity, got.LowStockThresold)
+		}
+	})
+
+	t.Run("moving between other buckets leaves the ledger alone", func(t *testing.T) {
+		before := len(repo.movements)
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketQuarantined, To: BucketDamaged, Quantity: 1}))
+
+		if got := levels(t); got.Available != 7 || got.Quarantined != 2 || got.Damaged != 1 {
+			t.Fatalf("unexpected levels: %+v", got)
+		}
+		if len(repo.movements) != before {
+			t.Fatalf("expected no movement, got %d", len(repo.movements)-before)
+		}
+	})
+
+	t.Run("moving back into available raises the stock", func(t *testing.T) {
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketQuarantined, To: BucketAvailable, Quantity: 2}))
+
+		if got := levels(t); got.Available != 9 || got.Quarantined != 0 {
+			t.Fatalf("unexpected levels: %+v", got)
+		}
+		if m := repo.movements[len(repo.movements)-1]; m.Quantity != 2 || m.Bucket != BucketQuarantined {
+			t.Fatalf("unexpected movement: %+v", m)
+		}
+	})
+
+	t.Run("error moving more than the bucket holds", func(t *testing.T) {
+		err := svc.MoveStock(ctx, id, BucketMove{From: BucketInTransit, To: BucketAvailable, Quantity: 1})
+		assertAppErrorCode(t, err, apperrors.InsufficientStock)
+	})
+
+	t.Run("error moving to the same bucket", func(t *testing.T) {
+		err := svc.MoveStock(ctx, id, BucketMove{From: BucketDamaged, To: BucketDamaged, Quantity: 1})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on unknown bucket", func(t *testing.T) {
+		err := svc.MoveStock(ctx, id, BucketMove{From: BucketAvailable, To: "lost", Quantity: 1})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
+
+func TestService_MoveStock_Cost(t *testing.T) {
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	// 10 units at 1.00, then 10 at 2.00.
+	p := &Product{Name: "Widget", StockQuantity: 10, Currency: "EUR", UnitCost: 100}
+	assertNoError(t, svc.CreateProduct(ctx, p))
+	id := p.ID.String()
+	_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: 10, Reason: ReasonReceipt, UnitCost: 200})
+	assertNoError(t, err)
+
+	t.Run("units moved out keep the cost of the oldest stock", func(t *testing.T) {
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketAvailable, To: BucketQuarantined, Quantity: 12}))
+
+		if b := repo.buckets[0]; b.Quantity != 12 || b.Value != 1400 {
+			t.Fatalf("expected 12 units worth 1400 quarantined, got %+v", b)
+		}
+		out := repo.movements[len(repo.movements)-2:]
+		if out[0].Quantity != -4 || out[0].UnitCost != 116 || out[1].Quantity != -8 || out[1].UnitCost != 117 {
+			t.Fatalf("expected the transfer to record the 1400 the units took, got %+v", out)
+		}
+	})
+
+	t.Run("moves between other buckets carry their share of the cost", func(t *testing.T) {
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketQuarantined, To: BucketDamaged, Quantity: 6}))
+
+		if q, d := repo.buckets[0], repo.buckets[1]; q.Value != 700 || d.Value != 700 {
+			t.Fatalf("expected 700 left quarantined and 700 damaged, got %d and %d", q.Value, d.Value)
+		}
+	})
+
+	t.Run("units moved back are not re-costed at the current unit cost", func(t *testing.T) {
+		assertNoError(t, svc.UpdateProduct(ctx, id, &Product{Name: "Widget", Currency: "EUR", UnitCost: 500}))
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketQuarantined, To: BucketAvailable, Quantity: 3}))
+
+		back := repo.movements[len(repo.movements)-2:]
+		if back[0].Quantity != 1 || back[0].UnitCost != 116 || back[1].Quantity != 2 || back[1].UnitCost != 117 {
+			t.Fatalf("expected 3 units back worth exactly 3.50, got %+v", back)
+		}
+		if b := repo.buckets[0]; b.Quantity != 3 || b.Value != 350 {
+			t.Fatalf("expected 3 units worth 350 left quarantined, got %+v", b)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package product
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// Publisher receives the events of committed stock changes. Publish must not block; ctx
+// is the context of the operation that made the change.
+type Publisher interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// publishStock builds the events of the movements from the levels they left, and publishes
+// them once the transaction of repo commits. It does nothing for services without events.
+func (s *service) publishStock(ctx context.Context, repo Repository, movements []StockMovement) error {
+	if s.events == nil || len(movements) == 0 {
+		return nil
+	}
+
+	events, err := stockEvents(ctx, repo, movements)
+	if err != nil {
+		return err
+	}
+
+	repo.AfterCommit(func() {
+		for _, e := range events {
+			s.events.Publish(ctx, e)
+		}
+	})
+
+	return nil
+}
+
+// stockKey identifies the stock of a product, or of one of its variants.
+type stockKey struct {
+	productID uuid.UUID
+	variantID uuid.UUID
+}
+
+// stockEvents returns an EventStockChanged for each product or variant the movements
+// changed, in the order they were first changed, then one for each kit whose stock those
+// changes moved, each followed by an EventLowStock when the change took it down to its
+// low-stock threshold. Levels are read through repo, so they are those of the transaction
+// that made the movements.
+func stockEvents(ctx context.Context, repo Repository, movements []StockMovement) ([]Event, error) {
+	var keys []stockKey
+	changes := map[stockKey]*Event{}
+
+	for _, m := range movements {
+		key := stockKey{productID: m.ProductID}
+		if m.VariantID != nil {
+			key.variantID = *m.VariantID
+		}
+
+		e, ok := changes[key]
+		if !ok {
+			e = &Event{Type: EventStockChanged, ProductID: m.ProductID, VariantID: m.VariantID, Reason: m.Reason}
+			changes[key] = e
+			keys = append(keys, key)
+		}
+
+		e.Change += m.Quantity
+		e.OccurredAt = m.OccurredAt
+	}
+
+	products := map[uuid.UUID]*Product{}
+	events := make([]Event, 0, len(keys))
+
+	for _, key := range keys {
+		p, ok := products[key.productID]
+		if !ok {
+			var err error
+			if p, err = getProduct(ctx, repo, key.productID.String()); err != nil {
+				return nil, err
+			}
+			products[key.productID] = p
+		}
+
+		e := changes[key]
+		e.Name = p.Name
+		e.StockQuantity, e.ReservedQuantity, e.LowStockThreshold = p.StockQuantity, p.ReservedQuantity, p.LowStockThresold
+
+		if e.VariantID != nil {
+			for _, v := range p.Variants {
+				if v.ID == *e.VariantID {
+					e.SKU = v.SKU
+					e.StockQuantity, e.ReservedQuantity, e.LowStockThreshold = v.StockQuantity, v.ReservedQuantity, v.LowStockThreshold
+				}
+			}
+		}
+
+		events = appendStockEvent(events, *e)
+	}
+
+	kits, err := kitEvents(ctx, repo, keys, changes)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range kits {
+		events = appendStockEvent(events, e)
+	}
+
+	return events, nil
+}
+
+// kitEvents returns an EventStockChanged for each kit that the changes to its components
+// took to another number of whole kits. Kits whose count did not move are left out.
+func kitEvents(ctx context.Context, repo Repository, keys []stockKey, changes map[stockKey]*Event) ([]Event, error) {
+	var events []Event
+	seen := map[uuid.UUID]bool{}
+
+	for _, key := range keys {
+		// Products with variants cannot be kit components
+		if key.variantID != uuid.Nil {
+			continue
+		}
+
+		ids, err := repo.GetKitIDs(ctx, key.productID.String())
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("failed to retrieve kits: " + err.Error())
+		}
+
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			kit, err := getProduct(ctx, repo, id.String())
+			if err != nil {
+				return nil, err
+			}
+
+			// The kits the components made before the changes
+			before := *kit
+			before.Components = make([]KitComponent, len(kit.Components))
+			for i, c := range kit.Components {
+				if e, ok := changes[stockKey{productID: c.ComponentID}]; ok && c.Component != nil {
+					component := *c.Component
+					component.StockQuantity -= e.Change
+					c.Component = &component
+				}
+				before.Components[i] = c
+			}
+			setKitStock(&before)
+
+			if kit.StockQuantity == before.StockQuantity {
+				continue
+			}
+
+			trigger := changes[key]
+			events = append(events, Event{
+				Type:              EventStockChanged,
+				ProductID:         kit.ID,
+				Name:              kit.Name,
+				Reason:            trigger.Reason,
+				Change:            kit.StockQuantity - before.StockQuantity,
+				StockQuantity:     kit.StockQuantity,
+				LowStockThreshold: kit.LowStockThresold,
+				OccurredAt:        trigger.OccurredAt,
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// appendStockEvent appends e to events, followed by an EventLowStock when the change took
+// the stock down to its low-stock threshold.
+func appendStockEvent(events []Event, e Event) []Event {
+	events = append(events, e)
+
+	if before := e.StockQuantity - e.Change; e.StockQuantity <= e.LowStockThreshold && before > e.LowStockThreshold {
+		low := e
+		low.Type = EventLowStock
+		events = append(events, low)
+	}
+
+	return events
+}
@@ -0,0 +1,196 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// recordingPublisher records the events published to it.
+type recordingPublisher struct {
+	events []Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e Event) {
+	p.events = append(p.events, e)
+}
+
+func (p *recordingPublisher) take() []Event {
+	events := p.events
+	p.events = nil
+	return events
+}
+
+func TestService_StockEvents(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Widget", StockQuantity: 12, LowStockThresold: 5}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	events := &recordingPublisher{}
+	svc := NewServiceWithEvents(repo, events)
+	ctx := context.Background()
+
+	t.Run("stock change publishes the levels it left", func(t *testing.T) {
+		assertNoError(t, svc.IncermentStock(ctx, id, 3))
+
+		got := events.take()
+		if len(got) != 1 {
+			t.Fatalf("expected 1 event, got %+v", got)
+		}
+		if e := got[0]; e.Type != EventStockChanged || e.ProductID != p.ID || e.Name != "Widget" ||
+			e.StockQuantity != 15 || e.Change != 3 || e.LowStockThreshold != 5 || e.Reason != ReasonIncrement {
+			t.Fatalf("unexpected event: %+v", e)
+		}
+	})
+
+	t.Run("crossing the threshold publishes a low-stock event", func(t *testing.T) {
+		assertNoError(t, svc.DecrementStock(ctx, id, 10))
+
+		got := events.take()
+		if len(got) != 2 || got[0].Type != EventStockChanged || got[1].Type != EventLowStock || got[1].StockQuantity != 5 {
+			t.Fatalf("expected a stock change then a low-stock event at 5, got %+v", got)
+		}
+	})
+
+	t.Run("changes while already low publish no low-stock event", func(t *testing.T) {
+		assertNoError(t, svc.DecrementStock(ctx, id, 1))
+
+		if got := events.take(); len(got) != 1 || got[0].Type != EventStockChanged {
+			t.Fatalf("expected only a stock change, got %+v", got)
+		}
+	})
+
+	t.Run("changes of one operation are combined per product", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx,
+			StockChange{ProductID: id, Quantity: 4, Reason: ReasonReceipt},
+			StockChange{ProductID: id, Quantity: 2, Reason: ReasonReceipt},
+		)
+		assertNoError(t, err)
+
+		if got := events.take(); len(got) != 1 || got[0].Change != 6 || got[0].StockQuantity != 10 {
+			t.Fatalf("expected one event for a change of 6, got %+v", got)
+		}
+	})
+
+	t.Run("failed change publishes nothing", func(t *testing.T) {
+		assertAppErrorCode(t, svc.DecrementStock(ctx, id, 100), apperrors.InsufficientStock)
+
+		if got := events.take(); len(got) != 0 {
+			t.Fatalf("expected no events, got %+v", got)
+		}
+	})
+
+	t.Run("events wait for the transaction to commit", func(t *testing.T) {
+		err := repo.Transaction(ctx, func(Repository) error {
+			if err := svc.IncermentStock(ctx, id, 1); err != nil {
+				return err
+			}
+			if len(events.events) != 0 {
+				t.Fatalf("expected no events before commit, got %+v", events.events)
+			}
+			return apperrors.NewBusinessLogicError("rolled back")
+		})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+
+		if got := events.take(); len(got) != 0 {
+			t.Fatalf("expected no events for a rolled-back transaction, got %+v", got)
+		}
+	})
+
+	t.Run("opening stock is published", func(t *testing.T) {
+		created := &Product{Name: "Gadget", StockQuantity: 2, LowStockThresold: 5}
+		assertNoError(t, svc.CreateProduct(ctx, created))
+
+		// Stock that starts low never crossed the threshold, so raises no low-stock event
+		got := events.take()
+		if len(got) != 1 || got[0].ProductID != created.ID || got[0].Reason != ReasonOpening || got[0].Change != 2 {
+			t.Fatalf("expected only an opening stock change, got %+v", got)
+		}
+	})
+}
+
+func TestService_VariantStockEvents(t *testing.T) {
+	repo, id := newApparelRepo()
+	events := &recordingPublisher{}
+	svc := NewServiceWithEvents(repo, events)
+	ctx := context.Background()
+
+	v := &Variant{SKU: "SHIRT-M-RED", Options: map[string]string{"size": "M", "colour": "red"}, StockQuantity: 8, LowStockThreshold: 3}
+	assertNoError(t, svc.CreateVariant(ctx, id, v))
+	events.take()
+
+	assertNoError(t, svc.DecrementVariantStock(ctx, id, v.ID.String(), 5))
+
+	got := events.take()
+	if len(got) != 2 {
+		t.Fatalf("expected a stock change and a low-stock event, got %+v", got)
+	}
+	if e := got[0]; e.VariantID == nil || *e.VariantID != v.ID || e.SKU != "SHIRT-M-RED" || e.StockQuantity != 3 || e.LowStockThreshold != 3 {
+		t.Fatalf("expected the variant's levels, got %+v", e)
+	}
+}
+
+func TestService_KitStockEvents(t *testing.T) {
+	repo := newMemoryRepository()
+
+	newProduct := func(name string, stock int) *Product {
+		p := &Product{Name: name, StockQuantity: stock, LowStockThresold: 2}
+		p.ID = uuid.New()
+		repo.products[p.ID.String()] = p
+		return p
+	}
+
+	kit := newProduct("Starter Kit", 0)
+	kit.LowStockThresold = 1
+	bowl := newProduct("Bowl", 10)
+	spoon := newProduct("Spoon", 7)
+
+	events := &recordingPublisher{}
+	svc := NewServiceWithEvents(repo, events)
+	ctx := context.Background()
+
+	assertNoError(t, svc.SetKitComponents(ctx, kit.ID.String(), []KitComponent{
+		{ComponentID: bowl.ID, Quantity: 1},
+		{ComponentID: spoon.ID, Quantity: 2},
+	}))
+
+	t.Run("component change publishes the kit's new count", func(t *testing.T) {
+		assertNoError(t, svc.DecrementStock(ctx, spoon.ID.String(), 2))
+
+		got := events.take()
+		if len(got) != 2 || got[0].ProductID != spoon.ID {
+			t.Fatalf("expected the spoon's event then the kit's, got %+v", got)
+		}
+		if e := got[1]; e.Type != EventStockChanged || e.ProductID != kit.ID || e.Name != "Starter Kit" ||
+			e.StockQuantity != 2 || e.Change != -1 || e.Reason != ReasonDecrement {
+			t.Fatalf("expected the kit to go from 3 to 2, got %+v", e)
+		}
+	})
+
+	t.Run("component change that makes no other count publishes no kit event", func(t *testing.T) {
+		assertNoError(t, svc.DecrementStock(ctx, bowl.ID.String(), 1))
+
+		if got := events.take(); len(got) != 1 || got[0].ProductID != bowl.ID {
+			t.Fatalf("expected only the bowl's event, got %+v", got)
+		}
+	})
+
+	t.Run("kit change publishes its components and itself once", func(t *testing.T) {
+		assertNoError(t, svc.DecrementStock(ctx, kit.ID.String(), 1))
+
+		got := events.take()
+		var kits []Event
+		for _, e := range got {
+			if e.ProductID == kit.ID {
+				kits = append(kits, e)
+			}
+		}
+		if len(kits) != 2 || kits[0].Type != EventStockChanged || kits[0].StockQuantity != 1 || kits[0].Change != -1 || kits[1].Type != EventLowStock {
+			t.Fatalf("expected the kit to go from 2 to 1 and fall to its threshold, got %+v", got)
+		}
+	})
+}
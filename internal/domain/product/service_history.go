@@ -0,0 +1,98 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// GetProductAsOf implements Service.
+func (s *service) GetProductAsOf(ctx context.Context, id string, asOf time.Time) (*Product, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	asOf = pastOrNow(asOf)
+
+	p, err := s.repo.GetVersion(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewProductNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve product history: " + err.Error())
+	}
+
+	// A product deleted by then no longer existed
+	if p.DeletedAt.Valid {
+		return nil, apperrors.NewProductNotFoundError(id)
+	}
+
+	products := []Product{*p}
+	if err := replayStock(ctx, s.repo, products, asOf); err != nil {
+		return nil, err
+	}
+
+	return &products[0], nil
+}
+
+// pastOrNow treats a time in the future as now, the latest state that is known.
+func pastOrNow(asOf time.Time) time.Time {
+	if now := time.Now(); asOf.After(now) {
+		return now
+	}
+	return asOf
+}
+
+// replayStock sets the stock of product versions to the ledger's balance at asOf. Stock
+// in versions is not used: the ledger records when each change took effect. A kit gets
+// the number of whole kits its components' replayed stock made; components are not
+// versioned, so those are its current components.
+func replayStock(ctx context.Context, repo Repository, products []Product, asOf time.Time) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	for i, p := range products {
+		ids[i] = p.ID.String()
+	}
+
+	components, err := repo.GetKitComponents(ctx, ids)
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to retrieve kit components: " + err.Error())
+	}
+
+	kits := map[string][]KitComponent{}
+	replayed := slices.Clone(ids)
+	for _, c := range components {
+		kitID, componentID := c.KitID.String(), c.ComponentID.String()
+		kits[kitID] = append(kits[kitID], c)
+		if !slices.Contains(replayed, componentID) {
+			replayed = append(replayed, componentID)
+		}
+	}
+
+	stock, err := repo.StockAsOf(ctx, replayed, asOf)
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to replay the stock ledger: " + err.Error())
+	}
+
+	for i := range products {
+		products[i].StockQuantity = stock[ids[i]]
+
+		if kit, ok := kits[ids[i]]; ok {
+			products[i].StockQuantity = -1
+			for _, c := range kit {
+				if n := max(stock[c.ComponentID.String()], 0) / c.Quantity; products[i].StockQuantity < 0 || n < products[i].StockQuantity {
+					products[i].StockQuantity = n
+				}
+			}
+		}
+	}
+
+	return nil
+}
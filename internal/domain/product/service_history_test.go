@@ -0,0 +1,112 @@
+package product
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_AsOf(t *testing.T) {
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	p := &Product{Name: "Widget", StockQuantity: 10, LowStockThresold: 2, SalePrice: 500}
+	assertNoError(t, svc.CreateProduct(ctx, p))
+	id := p.ID.String()
+	created := time.Now()
+
+	assertNoError(t, svc.DecrementStock(ctx, id, 4))
+	sold := time.Now()
+
+	assertNoError(t, svc.UpdateProduct(ctx, id, &Product{Name: "Widget Pro", StockQuantity: 6, LowStockThresold: 2, SalePrice: 700}))
+	renamed := time.Now()
+
+	assertNoError(t, svc.DeleteProduct(ctx, id))
+	deleted := time.Now()
+
+	t.Run("returns the product as it was", func(t *testing.T) {
+		got, err := svc.GetProductAsOf(ctx, id, created)
+		assertNoError(t, err)
+
+		if got.Name != "Widget" || got.SalePrice != 500 || got.StockQuantity != 10 {
+			t.Fatalf("expected the original Widget with 10 units, got %+v", got)
+		}
+	})
+
+	t.Run("replays stock from the ledger", func(t *testing.T) {
+		got, err := svc.GetProductAsOf(ctx, id, sold)
+		assertNoError(t, err)
+
+		if got.Name != "Widget" || got.StockQuantity != 6 {
+			t.Fatalf("expected Widget with 6 units, got %s with %d", got.Name, got.StockQuantity)
+		}
+	})
+
+	t.Run("lists products as they were", func(t *testing.T) {
+		products, err := svc.GetAllProducts(ctx, Filter{AsOf: renamed})
+		assertNoError(t, err)
+
+		if len(products) != 1 || products[0].Name != "Widget Pro" || products[0].SalePrice != 700 {
+			t.Fatalf("expected Widget Pro, got %+v", products)
+		}
+	})
+
+	t.Run("deleted products are not found", func(t *testing.T) {
+		_, err := svc.GetProductAsOf(ctx, id, deleted)
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+
+		products, err := svc.GetAllProducts(ctx, Filter{AsOf: deleted})
+		assertNoError(t, err)
+		if len(products) != 0 {
+			t.Fatalf("expected no products, got %+v", products)
+		}
+	})
+
+	t.Run("products did not exist before they were created", func(t *testing.T) {
+		_, err := svc.GetProductAsOf(ctx, id, created.Add(-time.Hour))
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+	})
+}
+
+func TestService_KitAsOf(t *testing.T) {
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	bowl := &Product{Name: "Bowl", StockQuantity: 10}
+	spoon := &Product{Name: "Spoon", StockQuantity: 7}
+	kit := &Product{Name: "Starter Kit"}
+	for _, p := range []*Product{bowl, spoon, kit} {
+		assertNoError(t, svc.CreateProduct(ctx, p))
+	}
+	assertNoError(t, svc.SetKitComponents(ctx, kit.ID.String(), []KitComponent{
+		{ComponentID: bowl.ID, Quantity: 1},
+		{ComponentID: spoon.ID, Quantity: 2},
+	}))
+	assembled := time.Now()
+
+	assertNoError(t, svc.DecrementStock(ctx, kit.ID.String(), 2))
+
+	t.Run("kit stock is replayed from its components", func(t *testing.T) {
+		got, err := svc.GetProductAsOf(ctx, kit.ID.String(), assembled)
+		assertNoError(t, err)
+
+		if got.StockQuantity != 3 {
+			t.Fatalf("expected 3 kits from 10 bowls and 7 spoons, got %d", got.StockQuantity)
+		}
+	})
+
+	t.Run("listed kits are replayed too", func(t *testing.T) {
+		products, err := svc.GetAllProducts(ctx, Filter{AsOf: time.Now()})
+		assertNoError(t, err)
+
+		i := slices.IndexFunc(products, func(p Product) bool { return p.ID == kit.ID })
+		if i < 0 || products[i].StockQuantity != 1 {
+			t.Fatalf("expected 1 kit from 8 bowls and 3 spoons, got %+v", products)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// ReferenceTypeKit marks component movements posted for a change to a kit.
+const ReferenceTypeKit = "kit"
+
+// SetKitComponents implements Service.
+func (s *service) SetKitComponents(ctx context.Context, kitID string, components []KitComponent) error {
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		kit, err := lockProduct(ctx, repo, kitID)
+		if err != nil {
+			return err
+		}
+
+		if len(components) > 0 {
+			if err := validateKit(ctx, repo, kit); err != nil {
+				return err
+			}
+		}
+
+		seen := make(map[uuid.UUID]bool, len(components))
+		for i := range components {
+			c := &components[i]
+
+			if c.Quantity <= 0 {
+				return apperrors.NewInvalidInputError("component quantity must be greater than 0")
+			}
+			if c.ComponentID == kit.ID {
+				return apperrors.NewBusinessLogicError("a kit cannot be a component of itself")
+			}
+			if seen[c.ComponentID] {
+				return apperrors.NewInvalidInputError("component " + c.ComponentID.String() + " is listed more than once")
+			}
+			seen[c.ComponentID] = true
+
+			component, err := getProduct(ctx, repo, c.ComponentID.String())
+			if err != nil {
+				return err
+			}
+			if component.IsKit() {
+				return apperrors.NewBusinessLogicError("kits cannot be components of other kits")
+			}
+			if len(component.Variants) > 0 || component.Serialized {
+				return apperrors.NewBusinessLogicError("products with variants or serials cannot be kit components")
+			}
+
+			c.ID = uuid.Nil
+			c.KitID = kit.ID
+			c.Component = nil
+		}
+
+		if err := repo.ReplaceKitComponents(ctx, kitID, components); err != nil {
+			return apperrors.NewDatabaseError("failed to update kit components: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// validateKit checks that the product can become a kit: it must hold no stock of its own
+// and must not itself be a component of another kit.
+func validateKit(ctx context.Context, repo Repository, kit *Product) error {
+	if !kit.IsKit() && (kit.StockQuantity != 0 || kit.ReservedQuantity != 0) {
+		return apperrors.NewBusinessLogicError("a product can only become a kit while it has no stock")
+	}
+
+	if len(kit.Variants) > 0 || kit.LotTracked || kit.Serialized {
+		return apperrors.NewBusinessLogicError("products with variants, lots or serials cannot be kits")
+	}
+
+	used, err := repo.IsKitComponent(ctx, kit.ID.String())
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to check kit components: " + err.Error())
+	}
+	if used {
+		return apperrors.NewBusinessLogicError("a kit component cannot itself be a kit")
+	}
+
+	return nil
+}
+
+// applyKitChange takes a kit's components out of stock, or puts them back, in the
+// quantities that make up the change; the kit itself holds no stock. Components are
+// locked in ID order so that concurrent kit changes cannot deadlock.
+func applyKitChange(ctx context.Context, repo Repository, kit *Product, change StockChange) ([]StockMovement, error) {
+	if change.VariantID != "" || change.LotNumber != "" || len(change.Serials) > 0 {
+		return nil, apperrors.NewInvalidInputError("kit stock cannot target a variant, lot or serial")
+	}
+
+	if change.ReferenceType == "" {
+		change.ReferenceType = ReferenceTypeKit
+		change.ReferenceID = kit.ID.String()
+	}
+
+	var movements []StockMovement
+	for _, c := range sortedComponents(kit) {
+		sub := change
+		sub.ProductID = c.ComponentID.String()
+		sub.Quantity = change.Quantity * c.Quantity
+		sub.UnitCost = 0
+
+		applied, err := applyStockChange(ctx, repo, sub)
+		if err != nil {
+			return nil, componentError(c, err)
+		}
+		movements = append(movements, applied...)
+	}
+
+	return movements, nil
+}
+
+// componentError names the short component in an insufficient-stock error.
+func componentError(c KitComponent, err error) error {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.InsufficientStock {
+		return err
+	}
+
+	details, _ := appErr.Details.(map[string]int)
+
+	name := ""
+	if c.Component != nil {
+		name = c.Component.Name
+	}
+
+	return apperrors.NewComponentInsufficientStockError(c.ComponentID.String(), name, details["available"], details["required"])
+}
+
+// setKitStock sets a kit's stock quantity to the number of whole kits its components'
+// unreserved stock can make. Other products are left alone.
+func setKitStock(p *Product) {
+	if !p.IsKit() {
+		return
+	}
+
+	kits := -1
+	for _, c := range p.Components {
+		available := 0
+		if c.Component != nil {
+			available = max(c.Component.StockQuantity-c.Component.ReservedQuantity, 0)
+		}
+		if n := available / c.Quantity; kits < 0 || n < kits {
+			kits = n
+		}
+	}
+
+	p.StockQuantity = kits
+	p.ReservedQuantity = 0
+}
+
+func sortedComponents(kit *Product) []KitComponent {
+	components := append([]KitComponent(nil), kit.Components...)
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].ComponentID.String() < components[j].ComponentID.String()
+	})
+	return components
+}
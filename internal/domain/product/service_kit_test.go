@@ -0,0 +1,129 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_Kits(t *testing.T) {
+	repo := newMemoryRepository()
+
+	newProduct := func(name string, stock int) *Product {
+		p := &Product{Name: name, StockQuantity: stock, LowStockThresold: 2}
+		p.ID = uuid.New()
+		repo.products[p.ID.String()] = p
+		return p
+	}
+
+	kit := newProduct("Starter Kit", 0)
+	bowl := newProduct("Bowl", 10)
+	spoon := newProduct("Spoon", 7)
+	id := kit.ID.String()
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	kitStock := func() int {
+		p, err := svc.GetProductByID(ctx, id)
+		assertNoError(t, err)
+		return p.StockQuantity
+	}
+
+	t.Run("components make the product a kit", func(t *testing.T) {
+		assertNoError(t, svc.SetKitComponents(ctx, id, []KitComponent{
+			{ComponentID: bowl.ID, Quantity: 1},
+			{ComponentID: spoon.ID, Quantity: 2},
+		}))
+
+		if got := kitStock(); got != 3 {
+			t.Fatalf("expected 3 kits from 10 bowls and 7 spoons, got %d", got)
+		}
+	})
+
+	t.Run("decrement takes every component", func(t *testing.T) {
+		assertNoError(t, svc.DecrementStock(ctx, id, 2))
+
+		if bowl.StockQuantity != 8 || spoon.StockQuantity != 3 {
+			t.Fatalf("expected 8 bowls and 3 spoons, got %d and %d", bowl.StockQuantity, spoon.StockQuantity)
+		}
+		if got := kitStock(); got != 1 {
+			t.Fatalf("expected 1 kit, got %d", got)
+		}
+	})
+
+	t.Run("error names the short component and changes nothing", func(t *testing.T) {
+		err := svc.DecrementStock(ctx, id, 2)
+		assertAppErrorCode(t, err, apperrors.InsufficientStock)
+
+		var appErr *apperrors.AppError
+		errors.As(err, &appErr)
+		details, _ := appErr.Details.(map[string]any)
+		if details["component_id"] != spoon.ID.String() || details["available"] != 3 || details["required"] != 4 {
+			t.Fatalf("expected spoon to be named short, got %+v", appErr.Details)
+		}
+	})
+
+	t.Run("kits appear in the low-stock listing", func(t *testing.T) {
+		products, err := svc.GetAllProducts(ctx, Filter{})
+		assertNoError(t, err)
+
+		for _, p := range products {
+			if p.ID == kit.ID && p.StockQuantity > p.LowStockThresold {
+				t.Fatalf("expected kit to be low on stock, got %d", p.StockQuantity)
+			}
+		}
+	})
+
+	t.Run("reserving a kit reserves its components", func(t *testing.T) {
+		reserved, err := svc.ReserveStock(ctx, id, "", 5)
+		assertNoError(t, err)
+
+		if reserved != 1 || bowl.ReservedQuantity != 1 || spoon.ReservedQuantity != 2 {
+			t.Fatalf("expected 1 kit reserved, got %d with bowls %d and spoons %d", reserved, bowl.ReservedQuantity, spoon.ReservedQuantity)
+		}
+		if got := kitStock(); got != 0 {
+			t.Fatalf("expected no kits left to sell, got %d", got)
+		}
+
+		assertNoError(t, svc.ReleaseStock(ctx, id, "", 1))
+		if bowl.ReservedQuantity != 0 || spoon.ReservedQuantity != 0 {
+			t.Fatalf("expected components released, got bowls %d and spoons %d", bowl.ReservedQuantity, spoon.ReservedQuantity)
+		}
+	})
+
+	t.Run("error on a component that is a kit", func(t *testing.T) {
+		other := newProduct("Gift Box", 0)
+		err := svc.SetKitComponents(ctx, other.ID.String(), []KitComponent{{ComponentID: kit.ID, Quantity: 1}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error on a component listed twice", func(t *testing.T) {
+		err := svc.SetKitComponents(ctx, id, []KitComponent{
+			{ComponentID: bowl.ID, Quantity: 1},
+			{ComponentID: bowl.ID, Quantity: 1},
+		})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error making a product with stock a kit", func(t *testing.T) {
+		err := svc.SetKitComponents(ctx, bowl.ID.String(), []KitComponent{{ComponentID: spoon.ID, Quantity: 1}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error deleting a kit component", func(t *testing.T) {
+		assertAppErrorCode(t, svc.DeleteProduct(ctx, bowl.ID.String()), apperrors.BusinessLogicError)
+
+		if _, err := svc.GetProductByID(ctx, bowl.ID.String()); err != nil {
+			t.Fatalf("expected the bowl to remain, got: %v", err)
+		}
+	})
+
+	t.Run("components of a deleted kit can be deleted", func(t *testing.T) {
+		assertNoError(t, svc.DeleteProduct(ctx, id))
+		assertNoError(t, svc.DeleteProduct(ctx, bowl.ID.String()))
+	})
+}
@@ -0,0 +1,190 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ApplyStockChanges implements Service.
+func (s *service) ApplyStockChanges(ctx context.Context, changes ...StockChange) ([]StockMovement, error) {
+	if len(changes) == 0 {
+		return nil, apperrors.NewMissingRequiredDataError("changes")
+	}
+
+	movements := make([]StockMovement, 0, len(changes))
+
+	err := s.repo.Transaction(ctx, func(repo Repository) error {
+		for _, change := range changes {
+			applied, err := applyStockChange(ctx, repo, change)
+			if err != nil {
+				return err
+			}
+			movements = append(movements, applied...)
+		}
+		return s.publishStock(ctx, repo, movements)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// ListMovements implements Service.
+func (s *service) ListMovements(ctx context.Context, filter MovementFilter) ([]StockMovement, error) {
+	movements, err := s.repo.ListMovements(ctx, filter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve stock movements: " + err.Error())
+	}
+
+	return movements, nil
+}
+
+// applyStockChange updates the stock of the targeted product, variant or lots and records
+// the movements. It must run inside a transaction so that the row lock is held until commit.
+func applyStockChange(ctx context.Context, repo Repository, change StockChange) ([]StockMovement, error) {
+	if change.Quantity == 0 {
+		return nil, apperrors.NewInvalidInputError("stock change quantity cannot be zero")
+	}
+
+	p, err := lockProduct(ctx, repo, change.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.IsKit() {
+		return applyKitChange(ctx, repo, p, change)
+	}
+
+	if change.LotNumber != "" && !p.LotTracked {
+		return nil, apperrors.NewInvalidInputError("product is not lot-tracked")
+	}
+
+	if len(change.Serials) > 0 && !p.Serialized {
+		return nil, apperrors.NewInvalidInputError("product is not serialized")
+	}
+
+	movements := []StockMovement{*newMovement(p, change.Quantity, change)}
+
+	if change.VariantID != "" {
+		v, err := getVariant(ctx, repo, change.ProductID, change.VariantID)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.StockQuantity+change.Quantity < 0 {
+			return nil, apperrors.NewInsufficientStockError(v.StockQuantity, -change.Quantity)
+		}
+
+		if available := v.StockQuantity - v.ReservedQuantity; isSale(change.Reason) && available+change.Quantity < 0 {
+			return nil, apperrors.NewInsufficientStockError(max(available, 0), -change.Quantity)
+		}
+
+		if err := repo.UpdateVariantStock(ctx, change.VariantID, v.StockQuantity+change.Quantity); err != nil {
+			return nil, apperrors.NewDatabaseError("failed to update variant stock: " + err.Error())
+		}
+
+		if err := syncVariantStock(ctx, repo, change.ProductID); err != nil {
+			return nil, err
+		}
+
+		movements[0].VariantID = &v.ID
+	} else {
+		if len(p.Variants) > 0 {
+			return nil, newVariantStockOnlyError()
+		}
+
+		if p.StockQuantity+change.Quantity < 0 {
+			return nil, apperrors.NewInsufficientStockError(p.StockQuantity, -change.Quantity)
+		}
+
+		if available := p.StockQuantity - p.ReservedQuantity; isSale(change.Reason) && available+change.Quantity < 0 {
+			return nil, apperrors.NewInsufficientStockError(max(available, 0), -change.Quantity)
+		}
+
+		if p.LotTracked {
+			if movements, err = applyLotChange(ctx, repo, p, change); err != nil {
+				return nil, err
+			}
+		}
+
+		if p.Serialized {
+			if err := applySerialChange(ctx, repo, p, change); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := repo.UpdateSingleColumn(ctx, change.ProductID, "stock_quantity", p.StockQuantity+change.Quantity); err != nil {
+			return nil, apperrors.NewDatabaseError("failed to update product stock: " + err.Error())
+		}
+	}
+
+	for i := range movements {
+		if err := createMovement(ctx, repo, &movements[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return movements, nil
+}
+
+// newMovement builds the ledger entry for a change of quantity units to p. Stock entering
+// is valued at the change's unit cost, falling back to the product's; units leaving for
+// another bucket record the cost they take with them.
+func newMovement(p *Product, quantity int, change StockChange) *StockMovement {
+	m := &StockMovement{
+		ProductID:     p.ID,
+		Quantity:      quantity,
+		Reason:        change.Reason,
+		Currency:      p.Currency,
+		ReferenceType: change.ReferenceType,
+		ReferenceID:   change.ReferenceID,
+		Serials:       change.Serials,
+		Bucket:        change.Bucket,
+		Note:          change.Note,
+		OccurredAt:    time.Now().UTC(),
+	}
+
+	if m.Reason == "" {
+		m.Reason = ReasonAdjustment
+	}
+
+	if quantity > 0 {
+		m.UnitCost = change.UnitCost
+		if m.UnitCost == 0 {
+			m.UnitCost = p.UnitCost
+		}
+	} else if change.Bucket != "" {
+		m.UnitCost = change.UnitCost
+	}
+
+	return m
+}
+
+func createMovement(ctx context.Context, repo Repository, m *StockMovement) error {
+	if err := repo.CreateMovement(ctx, m); err != nil {
+		return apperrors.NewDatabaseError("failed to record stock movement: " + err.Error())
+	}
+
+	return nil
+}
+
+// lockProduct locks the product row for the rest of the transaction and returns the product.
+func lockProduct(ctx context.Context, repo Repository, id string) (*Product, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	if err := repo.LockProduct(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewProductNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to lock product: " + err.Error())
+	}
+
+	return getProduct(ctx, repo, id)
+}
@@ -0,0 +1,110 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_StockLedger(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Widget", StockQuantity: 10, Currency: "EUR", UnitCost: 250}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("stock operations record movements", func(t *testing.T) {
+		assertNoError(t, svc.IncermentStock(ctx, id, 5))
+		assertNoError(t, svc.DecrementStock(ctx, id, 3))
+
+		movements, _ := svc.ListMovements(ctx, MovementFilter{ProductID: id})
+		if len(movements) != 2 {
+			t.Fatalf("expected 2 movements, got %d", len(movements))
+		}
+
+		in, out := movements[0], movements[1]
+		if in.Quantity != 5 || in.Reason != ReasonIncrement || in.UnitCost != 250 || in.Currency != "EUR" {
+			t.Fatalf("unexpected increment movement: %+v", in)
+		}
+		if out.Quantity != -3 || out.Reason != ReasonDecrement || out.UnitCost != 0 {
+			t.Fatalf("unexpected decrement movement: %+v", out)
+		}
+	})
+
+	t.Run("changes carry their cost and reference", func(t *testing.T) {
+		movements, err := svc.ApplyStockChanges(ctx, StockChange{
+			ProductID:     id,
+			Quantity:      4,
+			Reason:        ReasonIncrement,
+			UnitCost:      300,
+			ReferenceType: "purchase_order",
+			ReferenceID:   "PO-1",
+		})
+		assertNoError(t, err)
+
+		if movements[0].UnitCost != 300 || movements[0].ReferenceID != "PO-1" {
+			t.Fatalf("unexpected movement: %+v", movements[0])
+		}
+		if p.StockQuantity != 16 {
+			t.Fatalf("expected stock 16, got %d", p.StockQuantity)
+		}
+	})
+
+	t.Run("error on zero quantity change", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error when change would go below zero", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: -100})
+		assertAppErrorCode(t, err, apperrors.InsufficientStock)
+	})
+
+	t.Run("update leaves stock to stock operations", func(t *testing.T) {
+		before, stock := len(repo.movements), repo.products[id].StockQuantity
+
+		err := svc.UpdateProduct(ctx, id, &Product{Name: "Widget", StockQuantity: 20, UnitCost: 250})
+		assertNoError(t, err)
+
+		if len(repo.movements) != before || repo.products[id].StockQuantity != stock {
+			t.Fatalf("expected stock %d and no movements, got %d with %d new movements",
+				stock, repo.products[id].StockQuantity, len(repo.movements)-before)
+		}
+		if got := repo.products[id].Currency; got != "EUR" {
+			t.Fatalf("expected the omitted currency to be kept, got %q", got)
+		}
+	})
+}
+
+func TestService_CreateProductPricing(t *testing.T) {
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("defaults the currency and records opening stock", func(t *testing.T) {
+		p := &Product{Name: "Widget", StockQuantity: 7, UnitCost: 100}
+		assertNoError(t, svc.CreateProduct(ctx, p))
+
+		if p.Currency != "USD" {
+			t.Fatalf("expected default currency USD, got %q", p.Currency)
+		}
+		if len(repo.movements) != 1 || repo.movements[0].Reason != ReasonOpening || repo.movements[0].Quantity != 7 {
+			t.Fatalf("expected an opening movement of 7, got %+v", repo.movements)
+		}
+	})
+
+	t.Run("error on invalid currency", func(t *testing.T) {
+		err := svc.CreateProduct(ctx, &Product{Name: "Widget", Currency: "dollars"})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on negative price", func(t *testing.T) {
+		err := svc.CreateProduct(ctx, &Product{Name: "Widget", SalePrice: -1})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
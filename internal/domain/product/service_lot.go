@@ -0,0 +1,203 @@
+package product
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// ReceiveLot implements Service.
+func (s *service) ReceiveLot(ctx context.Context, productID string, receipt LotReceipt) error {
+	if receipt.LotNumber == "" {
+		return apperrors.NewMissingRequiredDataError("lot_number")
+	}
+
+	if receipt.Quantity <= 0 {
+		return apperrors.NewInvalidInputError("increment quantity must be greater than 0")
+	}
+
+	_, err := s.ApplyStockChanges(ctx, StockChange{
+		ProductID: productID,
+		Quantity:  receipt.Quantity,
+		Reason:    ReasonIncrement,
+		LotNumber: receipt.LotNumber,
+		ExpiresAt: receipt.ExpiresAt,
+		UnitCost:  receipt.UnitCost,
+	})
+	return err
+}
+
+// GetLots implements Service.
+func (s *service) GetLots(ctx context.Context, productID string) ([]Lot, error) {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := s.repo.GetLots(ctx, p.ID.String())
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve lots: " + err.Error())
+	}
+
+	return lots, nil
+}
+
+// GetExpiringLots implements Service.
+func (s *service) GetExpiringLots(ctx context.Context, days int) ([]Lot, error) {
+	if days < 0 {
+		return nil, apperrors.NewInvalidInputError("days cannot be negative")
+	}
+
+	lots, err := s.repo.ListExpiringLots(ctx, time.Now().UTC().AddDate(0, 0, days))
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve expiring lots: " + err.Error())
+	}
+
+	return lots, nil
+}
+
+// applyLotChange spreads a change to a lot-tracked product over its lots and returns one
+// movement per lot touched. Stock entering goes into the named lot. Stock leaving comes
+// from the named lot, or else from the earliest-expiring lots first; expired lots are
+// never sold from, but can still be written off.
+func applyLotChange(ctx context.Context, repo Repository, p *Product, change StockChange) ([]StockMovement, error) {
+	lots, err := repo.GetLots(ctx, p.ID.String())
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve lots: " + err.Error())
+	}
+
+	now := time.Now().UTC()
+
+	if change.Quantity > 0 {
+		lot, err := receiveIntoLot(ctx, repo, p, lots, change)
+		if err != nil {
+			return nil, err
+		}
+		return []StockMovement{*lotMovement(p, lot, change.Quantity, change)}, nil
+	}
+
+	candidates := lots
+	if change.LotNumber != "" {
+		lot := findLot(lots, change.LotNumber)
+		if lot == nil {
+			return nil, apperrors.NewNotFoundError("Lot " + change.LotNumber + " not found")
+		}
+		if isSale(change.Reason) && lot.Expired(now) {
+			return nil, apperrors.NewBusinessLogicError("lot " + lot.LotNumber + " has expired and cannot be sold")
+		}
+		candidates = []Lot{*lot}
+	}
+
+	available := 0
+	for _, lot := range candidates {
+		if !isSale(change.Reason) || !lot.Expired(now) {
+			available += lot.Quantity
+		}
+	}
+	if isSale(change.Reason) && change.LotNumber == "" {
+		available = max(available-p.ReservedQuantity, 0)
+	}
+
+	required := -change.Quantity
+	if available < required {
+		return nil, apperrors.NewInsufficientStockError(available, required)
+	}
+
+	var movements []StockMovement
+	for i := range candidates {
+		lot := &candidates[i]
+		if required == 0 {
+			break
+		}
+		if lot.Quantity == 0 || (isSale(change.Reason) && lot.Expired(now)) {
+			continue
+		}
+
+		take := min(lot.Quantity, required)
+		if err := repo.UpdateLotQuantity(ctx, lot.ID.String(), lot.Quantity-take); err != nil {
+			return nil, apperrors.NewDatabaseError("failed to update lot stock: " + err.Error())
+		}
+
+		movements = append(movements, *lotMovement(p, lot, -take, change))
+		required -= take
+	}
+
+	return movements, nil
+}
+
+// receiveIntoLot adds the change to the named lot, creating it if needed.
+func receiveIntoLot(ctx context.Context, repo Repository, p *Product, lots []Lot, change StockChange) (*Lot, error) {
+	if change.LotNumber == "" {
+		return nil, apperrors.NewBusinessLogicError("product is lot-tracked; stock must be received into a lot")
+	}
+
+	lot := findLot(lots, change.LotNumber)
+	if lot == nil {
+		lot = &Lot{ProductID: p.ID, LotNumber: change.LotNumber, ExpiresAt: change.ExpiresAt}
+		if err := repo.CreateLot(ctx, lot); err != nil {
+			return nil, apperrors.NewDatabaseError("failed to create lot: " + err.Error())
+		}
+	} else if change.ExpiresAt != nil && (lot.ExpiresAt == nil || !lot.ExpiresAt.Equal(*change.ExpiresAt)) {
+		return nil, apperrors.NewInvalidInputError("lot " + lot.LotNumber + " already exists with a different expiry date")
+	}
+
+	if err := repo.UpdateLotQuantity(ctx, lot.ID.String(), lot.Quantity+change.Quantity); err != nil {
+		return nil, apperrors.NewDatabaseError("failed to update lot stock: " + err.Error())
+	}
+
+	return lot, nil
+}
+
+func lotMovement(p *Product, lot *Lot, quantity int, change StockChange) *StockMovement {
+	m := newMovement(p, quantity, change)
+	m.LotID = &lot.ID
+	return m
+}
+
+func findLot(lots []Lot, number string) *Lot {
+	for i := range lots {
+		if lots[i].LotNumber == number {
+			return &lots[i]
+		}
+	}
+	return nil
+}
+
+// isSale reports whether stock leaving for this reason is sold, and so must not come
+// from an expired lot or from stock reserved for sales orders.
+func isSale(reason MovementReason) bool {
+	return reason == ReasonDecrement || reason == ReasonSale
+}
+
+// sellableLotStock returns the units in the product's unexpired lots.
+func sellableLotStock(ctx context.Context, repo Repository, p *Product) (int, error) {
+	lots, err := repo.GetLots(ctx, p.ID.String())
+	if err != nil {
+		return 0, apperrors.NewDatabaseError("failed to retrieve lots: " + err.Error())
+	}
+
+	now := time.Now().UTC()
+	total := 0
+	for _, lot := range lots {
+		if !lot.Expired(now) {
+			total += lot.Quantity
+		}
+	}
+
+	return total, nil
+}
+
+// validateLotTracking checks that lot tracking is only switched while the product holds
+// no stock, and never for products with variants.
+func validateLotTracking(existing *Product, product *Product) error {
+	if product.LotTracked && len(existing.Variants) > 0 {
+		return apperrors.NewBusinessLogicError("products with variants cannot be lot-tracked")
+	}
+
+	if product.LotTracked != existing.LotTracked && existing.StockQuantity != 0 {
+		return apperrors.NewBusinessLogicError("lot tracking can only change while the product has no stock")
+	}
+
+	return nil
+}
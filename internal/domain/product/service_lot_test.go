@@ -0,0 +1,153 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_Lots(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Milk", LotTracked: true}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	expired := time.Now().UTC().AddDate(0, 0, -1)
+	soon := time.Now().UTC().AddDate(0, 0, 3)
+	later := time.Now().UTC().AddDate(0, 0, 20)
+
+	receive := func(t *testing.T, number string, expiresAt *time.Time, quantity int) {
+		t.Helper()
+		assertNoError(t, svc.ReceiveLot(ctx, id, LotReceipt{LotNumber: number, ExpiresAt: expiresAt, Quantity: quantity}))
+	}
+	quantities := func() map[string]int {
+		lots, _ := svc.GetLots(ctx, id)
+		out := make(map[string]int, len(lots))
+		for _, lot := range lots {
+			out[lot.LotNumber] = lot.Quantity
+		}
+		return out
+	}
+
+	receive(t, "L-LATER", &later, 5)
+	receive(t, "L-SOON", &soon, 4)
+	receive(t, "L-EXPIRED", &expired, 2)
+	receive(t, "L-NONE", nil, 1)
+
+	t.Run("receiving sums into the product's stock", func(t *testing.T) {
+		if p.StockQuantity != 12 {
+			t.Fatalf("expected stock 12, got %d", p.StockQuantity)
+		}
+	})
+
+	t.Run("receiving into an existing lot adds to it", func(t *testing.T) {
+		receive(t, "L-SOON", nil, 1)
+		if got := quantities()["L-SOON"]; got != 5 {
+			t.Fatalf("expected 5 in L-SOON, got %d", got)
+		}
+	})
+
+	t.Run("error on a different expiry for an existing lot", func(t *testing.T) {
+		err := svc.ReceiveLot(ctx, id, LotReceipt{LotNumber: "L-SOON", ExpiresAt: &later, Quantity: 1})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error incrementing without a lot", func(t *testing.T) {
+		assertAppErrorCode(t, svc.IncermentStock(ctx, id, 1), apperrors.BusinessLogicError)
+	})
+
+	t.Run("decrement consumes lots first-expired-first-out, skipping expired lots", func(t *testing.T) {
+		movements, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: -7, Reason: ReasonDecrement})
+		assertNoError(t, err)
+
+		got := quantities()
+		if got["L-EXPIRED"] != 2 || got["L-SOON"] != 0 || got["L-LATER"] != 3 || got["L-NONE"] != 1 {
+			t.Fatalf("unexpected lot quantities: %v", got)
+		}
+		if len(movements) != 2 || movements[0].Quantity != -5 || movements[1].Quantity != -2 || movements[0].LotID == nil {
+			t.Fatalf("expected one movement per lot consumed, got %+v", movements)
+		}
+		if p.StockQuantity != 6 {
+			t.Fatalf("expected stock 6, got %d", p.StockQuantity)
+		}
+	})
+
+	t.Run("expired lots do not count as available for sale", func(t *testing.T) {
+		err := svc.DecrementStock(ctx, id, 5)
+		assertAppErrorCode(t, err, apperrors.InsufficientStock)
+		if details := err.(*apperrors.AppError).Details.(map[string]int); details["available"] != 4 {
+			t.Fatalf("expected 4 available, got %v", details)
+		}
+	})
+
+	t.Run("error selling from an expired lot", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: -1, Reason: ReasonDecrement, LotNumber: "L-EXPIRED"})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("expired lots can be written off", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: -2, Reason: ReasonAdjustment, LotNumber: "L-EXPIRED"})
+		assertNoError(t, err)
+		if got := quantities()["L-EXPIRED"]; got != 0 {
+			t.Fatalf("expected L-EXPIRED to be empty, got %d", got)
+		}
+	})
+
+	t.Run("expiring lots include only lots with stock", func(t *testing.T) {
+		receive(t, "L-EXPIRED-2", &expired, 1)
+
+		lots, err := svc.GetExpiringLots(ctx, 7)
+		assertNoError(t, err)
+		if len(lots) != 1 || lots[0].LotNumber != "L-EXPIRED-2" {
+			t.Fatalf("expected only L-EXPIRED-2, got %+v", lots)
+		}
+
+		lots, _ = svc.GetExpiringLots(ctx, 30)
+		if len(lots) != 2 {
+			t.Fatalf("expected 2 lots within 30 days, got %d", len(lots))
+		}
+	})
+
+	t.Run("error on negative look-ahead", func(t *testing.T) {
+		_, err := svc.GetExpiringLots(ctx, -1)
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error switching off lot tracking with stock on hand", func(t *testing.T) {
+		err := svc.UpdateProduct(ctx, id, &Product{Name: "Milk"})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+}
+
+func TestService_LotTrackingRules(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Widget", StockQuantity: 3}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("error creating a lot-tracked product with stock", func(t *testing.T) {
+		err := svc.CreateProduct(ctx, &Product{Name: "Cheese", StockQuantity: 5, LotTracked: true})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error receiving a lot for an untracked product", func(t *testing.T) {
+		err := svc.ReceiveLot(ctx, id, LotReceipt{LotNumber: "L-1", Quantity: 1})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error switching on lot tracking with stock on hand", func(t *testing.T) {
+		err := svc.UpdateProduct(ctx, id, &Product{Name: "Widget", StockQuantity: 3, LotTracked: true})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+}
@@ -0,0 +1,176 @@
+package product
+
+import (
+	"context"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// ReserveStock implements Service. Stock that is already reserved, or sits in expired
+// lots, is not available to reserve. Reserving a kit reserves its components.
+func (s *service) ReserveStock(ctx context.Context, productID string, variantID string, quantity int) (int, error) {
+	if quantity <= 0 {
+		return 0, apperrors.NewInvalidInputError("reserve quantity must be greater than 0")
+	}
+
+	reserved := 0
+
+	err := s.repo.Transaction(ctx, func(repo Repository) error {
+		p, err := lockProduct(ctx, repo, productID)
+		if err != nil {
+			return err
+		}
+
+		if p.IsKit() {
+			reserved, err = reserveKit(ctx, repo, p, variantID, quantity)
+			return err
+		}
+
+		if variantID != "" {
+			v, err := getVariant(ctx, repo, productID, variantID)
+			if err != nil {
+				return err
+			}
+
+			reserved = min(quantity, max(v.StockQuantity-v.ReservedQuantity, 0))
+			if reserved == 0 {
+				return nil
+			}
+			return setReserved(ctx, repo, p, variantID, v.ReservedQuantity+reserved)
+		}
+
+		if len(p.Variants) > 0 {
+			return newVariantStockOnlyError()
+		}
+
+		onHand := p.StockQuantity
+		if p.LotTracked {
+			if onHand, err = sellableLotStock(ctx, repo, p); err != nil {
+				return err
+			}
+		}
+
+		reserved = min(quantity, max(onHand-p.ReservedQuantity, 0))
+		if reserved == 0 {
+			return nil
+		}
+		return setReserved(ctx, repo, p, "", p.ReservedQuantity+reserved)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return reserved, nil
+}
+
+// ReleaseStock implements Service.
+func (s *service) ReleaseStock(ctx context.Context, productID string, variantID string, quantity int) error {
+	if quantity <= 0 {
+		return apperrors.NewInvalidInputError("release quantity must be greater than 0")
+	}
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		p, err := lockProduct(ctx, repo, productID)
+		if err != nil {
+			return err
+		}
+
+		if p.IsKit() {
+			return releaseKit(ctx, repo, p, variantID, quantity)
+		}
+
+		current := p.ReservedQuantity
+		if variantID != "" {
+			v, err := getVariant(ctx, repo, productID, variantID)
+			if err != nil {
+				return err
+			}
+			current = v.ReservedQuantity
+		}
+
+		if current < quantity {
+			return apperrors.NewBusinessLogicError("cannot release more stock than is reserved")
+		}
+
+		return setReserved(ctx, repo, p, variantID, current-quantity)
+	})
+}
+
+func setReserved(ctx context.Context, repo Repository, p *Product, variantID string, quantity int) error {
+	var err error
+	if variantID != "" {
+		err = repo.UpdateVariantReserved(ctx, variantID, quantity)
+	} else {
+		err = repo.UpdateSingleColumn(ctx, p.ID.String(), "reserved_quantity", quantity)
+	}
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to update reserved stock: " + err.Error())
+	}
+
+	return nil
+}
+
+// reserveKit reserves the components of up to quantity kits and returns how many kits
+// were reserved.
+func reserveKit(ctx context.Context, repo Repository, kit *Product, variantID string, quantity int) (int, error) {
+	if variantID != "" {
+		return 0, apperrors.NewInvalidInputError("kit stock cannot target a variant, lot or serial")
+	}
+
+	components := sortedComponents(kit)
+	locked := make([]*Product, len(components))
+
+	kits := quantity
+	for i, c := range components {
+		p, err := lockProduct(ctx, repo, c.ComponentID.String())
+		if err != nil {
+			return 0, err
+		}
+
+		onHand := p.StockQuantity
+		if p.LotTracked {
+			if onHand, err = sellableLotStock(ctx, repo, p); err != nil {
+				return 0, err
+			}
+		}
+
+		locked[i] = p
+		kits = min(kits, max(onHand-p.ReservedQuantity, 0)/c.Quantity)
+	}
+
+	if kits == 0 {
+		return 0, nil
+	}
+
+	for i, c := range components {
+		if err := setReserved(ctx, repo, locked[i], "", locked[i].ReservedQuantity+kits*c.Quantity); err != nil {
+			return 0, err
+		}
+	}
+
+	return kits, nil
+}
+
+// releaseKit releases the components reserved for quantity kits.
+func releaseKit(ctx context.Context, repo Repository, kit *Product, variantID string, quantity int) error {
+	if variantID != "" {
+		return apperrors.NewInvalidInputError("kit stock cannot target a variant, lot or serial")
+	}
+
+	for _, c := range sortedComponents(kit) {
+		p, err := lockProduct(ctx, repo, c.ComponentID.String())
+		if err != nil {
+			return err
+		}
+
+		if p.ReservedQuantity < quantity*c.Quantity {
+			return apperrors.NewBusinessLogicError("cannot release more stock than is reserved")
+		}
+
+		if err := setReserved(ctx, repo, p, "", p.ReservedQuantity-quantity*c.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
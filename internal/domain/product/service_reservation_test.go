@@ -0,0 +1,84 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_Reservations(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Widget", StockQuantity: 5}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("reserves up to what is available", func(t *testing.T) {
+		reserved, err := svc.ReserveStock(ctx, id, "", 3)
+		assertNoError(t, err)
+		if reserved != 3 {
+			t.Fatalf("expected 3 reserved, got %d", reserved)
+		}
+
+		reserved, err = svc.ReserveStock(ctx, id, "", 4)
+		assertNoError(t, err)
+		if reserved != 2 || p.ReservedQuantity != 5 {
+			t.Fatalf("expected 2 more reserved for 5 in total, got %d and %d", reserved, p.ReservedQuantity)
+		}
+	})
+
+	t.Run("reserved stock cannot be sold", func(t *testing.T) {
+		assertAppErrorCode(t, svc.DecrementStock(ctx, id, 1), apperrors.InsufficientStock)
+	})
+
+	t.Run("reserved stock can still be adjusted", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: 1, Reason: ReasonAdjustment})
+		assertNoError(t, err)
+	})
+
+	t.Run("released stock can be sold again", func(t *testing.T) {
+		assertNoError(t, svc.ReleaseStock(ctx, id, "", 2))
+		assertNoError(t, svc.DecrementStock(ctx, id, 3))
+		if p.StockQuantity != 3 || p.ReservedQuantity != 3 {
+			t.Fatalf("expected stock 3 with 3 reserved, got %d with %d", p.StockQuantity, p.ReservedQuantity)
+		}
+	})
+
+	t.Run("error releasing more than is reserved", func(t *testing.T) {
+		assertAppErrorCode(t, svc.ReleaseStock(ctx, id, "", 4), apperrors.BusinessLogicError)
+	})
+
+	t.Run("updating the product keeps its reservations", func(t *testing.T) {
+		assertNoError(t, svc.UpdateProduct(ctx, id, &Product{Name: "Widget", StockQuantity: 3}))
+		if got := repo.products[id].ReservedQuantity; got != 3 {
+			t.Fatalf("expected 3 reserved, got %d", got)
+		}
+	})
+
+	t.Run("expired lots cannot be reserved", func(t *testing.T) {
+		milk := &Product{Name: "Milk", LotTracked: true}
+		milk.ID = uuid.New()
+		repo.products[milk.ID.String()] = milk
+
+		expired := time.Now().UTC().AddDate(0, 0, -1)
+		assertNoError(t, svc.ReceiveLot(ctx, milk.ID.String(), LotReceipt{LotNumber: "L-OLD", ExpiresAt: &expired, Quantity: 4}))
+		assertNoError(t, svc.ReceiveLot(ctx, milk.ID.String(), LotReceipt{LotNumber: "L-NEW", Quantity: 2}))
+
+		reserved, err := svc.ReserveStock(ctx, milk.ID.String(), "", 5)
+		assertNoError(t, err)
+		if reserved != 2 {
+			t.Fatalf("expected 2 reserved, got %d", reserved)
+		}
+	})
+
+	t.Run("error on non-positive quantity", func(t *testing.T) {
+		_, err := svc.ReserveStock(ctx, id, "", 0)
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
@@ -0,0 +1,78 @@
+package product
+
+import (
+	"context"
+	"strings"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+const (
+	// DefaultSearchLimit is the page size of a search that does not give one.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit caps the page size of a search.
+	MaxSearchLimit = 100
+)
+
+// SearchProducts implements Service.
+func (s *service) SearchProducts(ctx context.Context, query SearchQuery) (*SearchPage, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, apperrors.NewMissingRequiredDataError("q")
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxSearchLimit {
+		return nil, apperrors.NewInvalidInputError("limit must be between 1 and 100")
+	}
+	if query.Offset < 0 {
+		return nil, apperrors.NewInvalidInputError("offset cannot be negative")
+	}
+
+	hits, total, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to search products: " + err.Error())
+	}
+
+	page := &SearchPage{Results: []SearchResult{}, Total: total, Limit: query.Limit, Offset: query.Offset}
+	if len(hits) == 0 {
+		return page, nil
+	}
+
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ProductID.String()
+	}
+
+	products, err := s.GetAllProducts(ctx, Filter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]Product, len(products))
+	for _, p := range products {
+		byID[p.ID.String()] = p
+	}
+
+	for _, hit := range hits {
+		p, ok := byID[hit.ProductID.String()]
+		if !ok {
+			// Deleted between the search and the load
+			continue
+		}
+
+		page.Results = append(page.Results, SearchResult{
+			Product: p,
+			Rank:    hit.Rank,
+			Highlights: SearchHighlights{
+				Name:        hit.NameHighlight,
+				Description: hit.DescriptionHighlight,
+			},
+			MatchedSKU: hit.SKU,
+		})
+	}
+
+	return page, nil
+}
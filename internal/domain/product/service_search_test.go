@@ -0,0 +1,61 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_SearchProducts(t *testing.T) {
+	repo := newMemoryRepository()
+	for _, name := range []string{"Blue Mug", "Blue Mug Set", "Red Mug", "Kettle"} {
+		p := &Product{Name: name}
+		p.ID = uuid.New()
+		repo.products[p.ID.String()] = p
+	}
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("returns matches best first with the total", func(t *testing.T) {
+		page, err := svc.SearchProducts(ctx, SearchQuery{Text: "  mug "})
+		assertNoError(t, err)
+
+		if page.Total != 3 || len(page.Results) != 3 || page.Limit != DefaultSearchLimit {
+			t.Fatalf("expected 3 of 3 matches with the default limit, got %d of %d with limit %d", len(page.Results), page.Total, page.Limit)
+		}
+		if page.Results[0].Product.Name != "Red Mug" || page.Results[0].Highlights.Name != "Red Mug" {
+			t.Fatalf("expected Red Mug first, got %+v", page.Results[0])
+		}
+	})
+
+	t.Run("pages through matches", func(t *testing.T) {
+		page, err := svc.SearchProducts(ctx, SearchQuery{Text: "mug", Limit: 2, Offset: 2})
+		assertNoError(t, err)
+
+		if page.Total != 3 || len(page.Results) != 1 || page.Results[0].Product.Name != "Blue Mug Set" {
+			t.Fatalf("expected the last match on page two, got %+v", page)
+		}
+	})
+
+	t.Run("a page past the end is empty", func(t *testing.T) {
+		page, err := svc.SearchProducts(ctx, SearchQuery{Text: "mug", Offset: 10})
+		assertNoError(t, err)
+
+		if page.Total != 3 || page.Results == nil || len(page.Results) != 0 {
+			t.Fatalf("expected no results with total 3, got %+v", page)
+		}
+	})
+
+	t.Run("error on empty text", func(t *testing.T) {
+		_, err := svc.SearchProducts(ctx, SearchQuery{Text: " "})
+		assertAppErrorCode(t, err, apperrors.MissingRequiredData)
+	})
+
+	t.Run("error on a limit out of range", func(t *testing.T) {
+		_, err := svc.SearchProducts(ctx, SearchQuery{Text: "mug", Limit: MaxSearchLimit + 1})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
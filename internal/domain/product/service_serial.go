@@ -0,0 +1,144 @@
+package product
+
+import (
+	"context"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// IncrementSerials implements Service.
+func (s *service) IncrementSerials(ctx context.Context, productID string, serials []string) error {
+	if len(serials) == 0 {
+		return apperrors.NewMissingRequiredDataError("serials")
+	}
+
+	_, err := s.ApplyStockChanges(ctx, StockChange{ProductID: productID, Quantity: len(serials), Reason: ReasonIncrement, Serials: serials})
+	return err
+}
+
+// DecrementSerials implements Service.
+func (s *service) DecrementSerials(ctx context.Context, productID string, serials []string) error {
+	if len(serials) == 0 {
+		return apperrors.NewMissingRequiredDataError("serials")
+	}
+
+	_, err := s.ApplyStockChanges(ctx, StockChange{ProductID: productID, Quantity: -len(serials), Reason: ReasonDecrement, Serials: serials})
+	return err
+}
+
+// GetSerials implements Service.
+func (s *service) GetSerials(ctx context.Context, productID string, inStockOnly bool) ([]Serial, error) {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	serials, err := s.repo.GetSerials(ctx, p.ID.String(), nil)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve serials: " + err.Error())
+	}
+
+	if !inStockOnly {
+		return serials, nil
+	}
+
+	out := []Serial{}
+	for _, serial := range serials {
+		if serial.InStock {
+			out = append(out, serial)
+		}
+	}
+
+	return out, nil
+}
+
+// GetSerialHistory implements Service.
+func (s *service) GetSerialHistory(ctx context.Context, productID string, serial string) (*SerialHistory, error) {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	serials, err := s.repo.GetSerials(ctx, p.ID.String(), []string{serial})
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve serial: " + err.Error())
+	}
+	if len(serials) == 0 {
+		return nil, apperrors.NewNotFoundError("Serial " + serial + " not found")
+	}
+
+	movements, err := s.ListMovements(ctx, MovementFilter{ProductID: p.ID.String(), Serial: serial})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SerialHistory{Serial: serials[0], Movements: movements}, nil
+}
+
+// applySerialChange checks that a change to a serialized product names one unique serial
+// per unit, none of them already in stock when entering and all of them in stock when
+// leaving, and records where each serial now is. A serial that left stock may come back,
+// e.g. as a return.
+func applySerialChange(ctx context.Context, repo Repository, p *Product, change StockChange) error {
+	count := max(change.Quantity, -change.Quantity)
+	if len(change.Serials) != count {
+		return apperrors.NewInvalidInputError("product is serialized; name exactly one serial number per unit")
+	}
+
+	seen := make(map[string]bool, len(change.Serials))
+	for _, number := range change.Serials {
+		if number == "" {
+			return apperrors.NewInvalidInputError("serial numbers cannot be empty")
+		}
+		if seen[number] {
+			return apperrors.NewInvalidInputError("serial " + number + " is listed more than once")
+		}
+		seen[number] = true
+	}
+
+	existing, err := repo.GetSerials(ctx, p.ID.String(), change.Serials)
+	if err != nil {
+		return apperrors.NewDatabaseError("failed to retrieve serials: " + err.Error())
+	}
+
+	known := make(map[string]Serial, len(existing))
+	for _, serial := range existing {
+		known[serial.SerialNumber] = serial
+	}
+
+	entering := change.Quantity > 0
+	for _, number := range change.Serials {
+		serial, ok := known[number]
+
+		switch {
+		case entering && ok && serial.InStock:
+			return apperrors.NewDuplicateEntryError("serial", number)
+		case entering && !ok:
+			if err := repo.CreateSerial(ctx, &Serial{ProductID: p.ID, SerialNumber: number}); err != nil {
+				return apperrors.NewDatabaseError("failed to create serial: " + err.Error())
+			}
+		case !entering && (!ok || !serial.InStock):
+			return apperrors.NewBusinessLogicError("serial " + number + " is not in stock")
+		}
+	}
+
+	if err := repo.SetSerialsInStock(ctx, p.ID.String(), change.Serials, entering); err != nil {
+		return apperrors.NewDatabaseError("failed to update serials: " + err.Error())
+	}
+
+	return nil
+}
+
+// validateSerialTracking checks that serial tracking is only switched while the product
+// holds no stock, and never for products with variants or lots.
+func validateSerialTracking(existing *Product, product *Product) error {
+	if product.Serialized && (len(existing.Variants) > 0 || product.LotTracked) {
+		return apperrors.NewBusinessLogicError("products with variants or lots cannot be serialized")
+	}
+
+	if product.Serialized != existing.Serialized && existing.StockQuantity != 0 {
+		return apperrors.NewBusinessLogicError("serial tracking can only change while the product has no stock")
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func TestService_Serials(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Laptop", Serialized: true}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	inStock := func() int {
+		serials, _ := svc.GetSerials(ctx, id, true)
+		return len(serials)
+	}
+
+	t.Run("increment creates one serial per unit", func(t *testing.T) {
+		assertNoError(t, svc.IncrementSerials(ctx, id, []string{"SN-1", "SN-2", "SN-3"}))
+
+		if p.StockQuantity != 3 || inStock() != 3 {
+			t.Fatalf("expected 3 in stock, got stock %d with %d serials", p.StockQuantity, inStock())
+		}
+	})
+
+	t.Run("error on a serial already in stock", func(t *testing.T) {
+		err := svc.IncrementSerials(ctx, id, []string{"SN-4", "SN-1"})
+		assertAppErrorCode(t, err, apperrors.DuplicateEntry)
+		if p.StockQuantity != 3 {
+			t.Fatalf("expected stock to stay 3, got %d", p.StockQuantity)
+		}
+	})
+
+	t.Run("error on a serial listed twice", func(t *testing.T) {
+		assertAppErrorCode(t, svc.IncrementSerials(ctx, id, []string{"SN-5", "SN-5"}), apperrors.InvalidInput)
+	})
+
+	t.Run("error changing stock without serials", func(t *testing.T) {
+		assertAppErrorCode(t, svc.IncermentStock(ctx, id, 1), apperrors.InvalidInput)
+		assertAppErrorCode(t, svc.DecrementStock(ctx, id, 1), apperrors.InvalidInput)
+	})
+
+	t.Run("decrement takes the named serials out of stock", func(t *testing.T) {
+		assertNoError(t, svc.DecrementSerials(ctx, id, []string{"SN-2"}))
+
+		if p.StockQuantity != 2 || inStock() != 2 {
+			t.Fatalf("expected 2 in stock, got stock %d with %d serials", p.StockQuantity, inStock())
+		}
+	})
+
+	t.Run("error decrementing a serial not in stock", func(t *testing.T) {
+		assertAppErrorCode(t, svc.DecrementSerials(ctx, id, []string{"SN-2"}), apperrors.BusinessLogicError)
+		assertAppErrorCode(t, svc.DecrementSerials(ctx, id, []string{"SN-9"}), apperrors.BusinessLogicError)
+	})
+
+	t.Run("a serial that left stock can come back", func(t *testing.T) {
+		_, err := svc.ApplyStockChanges(ctx, StockChange{ProductID: id, Quantity: 1, Reason: ReasonAdjustment, Serials: []string{"SN-2"}})
+		assertNoError(t, err)
+
+		if p.StockQuantity != 3 || inStock() != 3 {
+			t.Fatalf("expected 3 in stock, got stock %d with %d serials", p.StockQuantity, inStock())
+		}
+	})
+
+	t.Run("history lists every movement naming the serial", func(t *testing.T) {
+		history, err := svc.GetSerialHistory(ctx, id, "SN-2")
+		assertNoError(t, err)
+
+		if !history.InStock || len(history.Movements) != 3 {
+			t.Fatalf("expected SN-2 in stock with 3 movements, got %+v", history)
+		}
+		if history.Movements[1].Quantity != -1 || history.Movements[1].Reason != ReasonDecrement {
+			t.Fatalf("unexpected second movement: %+v", history.Movements[1])
+		}
+	})
+
+	t.Run("error on unknown serial", func(t *testing.T) {
+		_, err := svc.GetSerialHistory(ctx, id, "SN-9")
+		assertAppErrorCode(t, err, apperrors.NotFoundError)
+	})
+
+	t.Run("error on serials for an untracked product", func(t *testing.T) {
+		other := &Product{Name: "Cable"}
+		other.ID = uuid.New()
+		repo.products[other.ID.String()] = other
+
+		assertAppErrorCode(t, svc.IncrementSerials(ctx, other.ID.String(), []string{"SN-1"}), apperrors.InvalidInput)
+	})
+
+	t.Run("error switching off serial tracking with stock on hand", func(t *testing.T) {
+		assertAppErrorCode(t, svc.UpdateProduct(ctx, id, &Product{Name: "Laptop"}), apperrors.BusinessLogicError)
+	})
+}
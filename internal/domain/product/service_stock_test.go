@@ -1,86 +1,12 @@
 package product
 
 import (
+	"context"
 	"testing"
 
 	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
-	"gorm.io/gorm"
 )
 
-// mockRepo is an in-memory implementation of the Repository interface for testing.
-type mockRepo struct {
-	products map[string]*Product
-
-	// For verifying that update functions were called with expected values.
-	lastUpdatedID          string
-	lastUpdatedColumn      string
-	lastUpdatedColumnValue any
-}
-
-func newMockRepo() *mockRepo {
-	return &mockRepo{
-		products: make(map[string]*Product),
-	}
-}
-
-func (m *mockRepo) Create(p *Product) error {
-	// Not needed for current tests
-	return nil
-}
-
-func (m *mockRepo) GetAll() ([]Product, error) {
-	out := make([]Product, 0, len(m.products))
-	for _, p := range m.products {
-		out = append(out, *p)
-	}
-	return out, nil
-}
-
-func (m *mockRepo) GetByID(id string) (*Product, error) {
-	p, ok := m.products[id]
-	if !ok {
-		return nil, gorm.ErrRecordNotFound
-	}
-	return p, nil
-}
-
-func (m *mockRepo) UpdateAllColumn(id string, p *Product) error {
-	if _, ok := m.products[id]; !ok {
-		return gorm.ErrRecordNotFound
-	}
-	m.products[id] = p
-	m.lastUpdatedID = id
-	m.lastUpdatedColumn = "ALL"
-	return nil
-}
-
-func (m *mockRepo) UpdateSingleColumn(id string, column string, value any) error {
-	p, ok := m.products[id]
-	if !ok {
-		return gorm.ErrRecordNotFound
-	}
-	switch column {
-	case "stock_quantity":
-		if v, ok := value.(int); ok {
-			p.StockQuantity = v
-		}
-	default:
-		// ignore unknown column for test simplicity
-	}
-	m.lastUpdatedID = id
-	m.lastUpdatedColumn = column
-	m.lastUpdatedColumnValue = value
-	return nil
-}
-
-func (m *mockRepo) Delete(id string) error {
-	if _, ok := m.products[id]; !ok {
-		return gorm.ErrRecordNotFound
-	}
-	delete(m.products, id)
-	return nil
-}
-
 // --- Helper assertions ---
 
 func assertNoError(t *testing.T, err error) {
@@ -107,7 +33,7 @@ func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
 // --- Tests ---
 
 func TestService_IncermentStock(t *testing.T) {
-	repo := newMockRepo()
+	repo := newMemoryRepository()
 	repo.products["p1"] = &Product{
 		Name:             "Widget",
 		Description:      "Test product",
@@ -115,12 +41,13 @@ func TestService_IncermentStock(t *testing.T) {
 		LowStockThresold: 5,
 	}
 	svc := NewService(repo)
+	ctx := context.Background()
 
 	t.Run("successfully increments stock", func(t *testing.T) {
-		err := svc.IncermentStock("p1", 5)
+		err := svc.IncermentStock(ctx, "p1", 5)
 		assertNoError(t, err)
 
-		p, _ := repo.GetByID("p1")
+		p, _ := repo.GetByID(ctx, "p1")
 		if p.StockQuantity != 15 {
 			t.Fatalf("expected stock 15, got %d", p.StockQuantity)
 		}
@@ -133,28 +60,28 @@ func TestService_IncermentStock(t *testing.T) {
 	})
 
 	t.Run("error on zero quantity", func(t *testing.T) {
-		err := svc.IncermentStock("p1", 0)
+		err := svc.IncermentStock(ctx, "p1", 0)
 		assertAppErrorCode(t, err, apperrors.InvalidInput)
 	})
 
 	t.Run("error on negative quantity", func(t *testing.T) {
-		err := svc.IncermentStock("p1", -3)
+		err := svc.IncermentStock(ctx, "p1", -3)
 		assertAppErrorCode(t, err, apperrors.InvalidInput)
 	})
 
 	t.Run("error on empty id", func(t *testing.T) {
-		err := svc.IncermentStock("", 5)
+		err := svc.IncermentStock(ctx, "", 5)
 		assertAppErrorCode(t, err, apperrors.MissingRequiredData)
 	})
 
 	t.Run("error when product not found", func(t *testing.T) {
-		err := svc.IncermentStock("does-not-exist", 5)
+		err := svc.IncermentStock(ctx, "does-not-exist", 5)
 		assertAppErrorCode(t, err, apperrors.ProductNotFound)
 	})
 }
 
 func TestService_DecrementStock(t *testing.T) {
-	repo := newMockRepo()
+	repo := newMemoryRepository()
 	repo.products["p1"] = &Product{
 		Name:             "Widget",
 		Description:      "Test product",
@@ -162,12 +89,13 @@ func TestService_DecrementStock(t *testing.T) {
 		LowStockThresold: 5,
 	}
 	svc := NewService(repo)
+	ctx := context.Background()
 
 	t.Run("successfully decrements stock", func(t *testing.T) {
-		err := svc.DecrementStock("p1", 3)
+		err := svc.DecrementStock(ctx, "p1", 3)
 		assertNoError(t, err)
 
-		p, _ := repo.GetByID("p1")
+		p, _ := repo.GetByID(ctx, "p1")
 		if p.StockQuantity != 7 {
 			t.Fatalf("expected stock 7, got %d", p.StockQuantity)
 		}
@@ -183,38 +111,38 @@ func TestService_DecrementStock(t *testing.T) {
 			StockQuantity:    5,
 			LowStockThresold: 3,
 		}
-		err := svc.DecrementStock("p2", 5)
+		err := svc.DecrementStock(ctx, "p2", 5)
 		assertNoError(t, err)
-		p, _ := repo.GetByID("p2")
+		p, _ := repo.GetByID(ctx, "p2")
 		if p.StockQuantity != 0 {
 			t.Fatalf("expected stock 0, got %d", p.StockQuantity)
 		}
 	})
 
 	t.Run("error on zero quantity", func(t *testing.T) {
-		err := svc.DecrementStock("p1", 0)
+		err := svc.DecrementStock(ctx, "p1", 0)
 		assertAppErrorCode(t, err, apperrors.InvalidInput)
 	})
 
 	t.Run("error on negative quantity", func(t *testing.T) {
-		err := svc.DecrementStock("p1", -2)
+		err := svc.DecrementStock(ctx, "p1", -2)
 		assertAppErrorCode(t, err, apperrors.InvalidInput)
 	})
 
 	t.Run("error on empty id", func(t *testing.T) {
-		err := svc.DecrementStock("", 2)
+		err := svc.DecrementStock(ctx, "", 2)
 		assertAppErrorCode(t, err, apperrors.MissingRequiredData)
 	})
 
 	t.Run("error when product not found", func(t *testing.T) {
-		err := svc.DecrementStock("does-not-exist", 1)
+		err := svc.DecrementStock(ctx, "does-not-exist", 1)
 		assertAppErrorCode(t, err, apperrors.ProductNotFound)
 	})
 
 	t.Run("error when decrement exceeds available stock", func(t *testing.T) {
 		// Current p1 stock is 7 from earlier test
 		before := repo.products["p1"].StockQuantity
-		err := svc.DecrementStock("p1", before+1)
+		err := svc.DecrementStock(ctx, "p1", before+1)
 		assertAppErrorCode(t, err, apperrors.InsufficientStock)
 
 		after := repo.products["p1"].StockQuantity
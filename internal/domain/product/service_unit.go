@@ -0,0 +1,102 @@
+package product
+
+import (
+	"context"
+	"errors"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+)
+
+// DefaultBaseUnit is the base unit of products created without one.
+const DefaultBaseUnit = "each"
+
+// SetUnits implements Service.
+func (s *service) SetUnits(ctx context.Context, productID string, units []UnitOfMeasure) error {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(units))
+	for i, unit := range units {
+		if unit.Name == "" {
+			return apperrors.NewMissingRequiredDataError("name")
+		}
+		if unit.Name == baseUnit(p) {
+			return apperrors.NewInvalidInputError("unit " + unit.Name + " is the product's base unit")
+		}
+		if seen[unit.Name] {
+			return apperrors.NewInvalidInputError("duplicate unit " + unit.Name)
+		}
+		seen[unit.Name] = true
+
+		if !unit.Factor.Positive() {
+			return apperrors.NewInvalidInputError("factor of unit " + unit.Name + " must be a decimal greater than 0")
+		}
+
+		units[i].ProductID = p.ID
+	}
+
+	if err := s.repo.ReplaceUnits(ctx, productID, units); err != nil {
+		return apperrors.NewDatabaseError("failed to set product units: " + err.Error())
+	}
+
+	return nil
+}
+
+// ToBaseUnits implements Service.
+func (s *service) ToBaseUnits(ctx context.Context, productID string, q quantity.Decimal, unit string) (int, error) {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.ToBaseUnits(q, unit)
+}
+
+// ToBaseUnits converts q of the product's named unit to its base unit. An empty unit is
+// the base unit. The result must be a whole number of base units.
+func (p *Product) ToBaseUnits(q quantity.Decimal, unit string) (int, error) {
+	if !q.Positive() {
+		return 0, apperrors.NewInvalidInputError("quantity must be a decimal greater than 0")
+	}
+
+	factor, ok := unitFactor(p, unit)
+	if !ok {
+		return 0, apperrors.NewInvalidInputError("unit " + unit + " is not defined for this product")
+	}
+
+	n, err := quantity.ToBase(q, factor)
+	if errors.Is(err, quantity.ErrNotWhole) {
+		return 0, apperrors.NewInvalidInputError(string(q) + " " + unit + " is not a whole number of " + baseUnit(p))
+	}
+	if err != nil {
+		return 0, apperrors.NewInvalidInputError(err.Error())
+	}
+
+	return n, nil
+}
+
+// unitFactor returns the base units in one of the named unit.
+func unitFactor(p *Product, unit string) (quantity.Decimal, bool) {
+	if unit == "" || unit == baseUnit(p) {
+		return quantity.BaseFactor, true
+	}
+
+	for _, u := range p.Units {
+		if u.Name == unit {
+			return u.Factor, true
+		}
+	}
+
+	return "", false
+}
+
+// baseUnit returns the product's base unit, which is each if none was given.
+func baseUnit(p *Product) string {
+	if p.BaseUnit == "" {
+		return DefaultBaseUnit
+	}
+	return p.BaseUnit
+}
@@ -0,0 +1,91 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+)
+
+func TestService_Units(t *testing.T) {
+	repo := newMemoryRepository()
+	p := &Product{Name: "Coffee Beans", BaseUnit: "g"}
+	p.ID = uuid.New()
+	id := p.ID.String()
+	repo.products[id] = p
+
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	assertNoError(t, svc.SetUnits(ctx, id, []UnitOfMeasure{
+		{Name: "kg", Factor: "1000"},
+		{Name: "lb", Factor: "453.59237"},
+		{Name: "case", Factor: "12000"},
+	}))
+
+	t.Run("converts to base units exactly", func(t *testing.T) {
+		cases := []struct {
+			q    string
+			unit string
+			want int
+		}{
+			{"250", "", 250},
+			{"250", "g", 250},
+			{"0.1", "kg", 100},
+			{"1.234", "kg", 1234},
+			{"2", "lb", 0},
+			{"1.5", "case", 18000},
+		}
+
+		for _, tc := range cases {
+			got, err := svc.ToBaseUnits(ctx, id, quantity.Decimal(tc.q), tc.unit)
+			if tc.want == 0 {
+				assertAppErrorCode(t, err, apperrors.InvalidInput)
+				continue
+			}
+			assertNoError(t, err)
+			if got != tc.want {
+				t.Errorf("%s %s: expected %d g, got %d", tc.q, tc.unit, tc.want, got)
+			}
+		}
+	})
+
+	t.Run("error on an unknown unit", func(t *testing.T) {
+		_, err := svc.ToBaseUnits(ctx, id, "1", "pallet")
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on a zero quantity", func(t *testing.T) {
+		_, err := svc.ToBaseUnits(ctx, id, "0", "kg")
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on a unit named like the base unit", func(t *testing.T) {
+		assertAppErrorCode(t, svc.SetUnits(ctx, id, []UnitOfMeasure{{Name: "g", Factor: "1"}}), apperrors.InvalidInput)
+	})
+
+	t.Run("error on a non-positive factor", func(t *testing.T) {
+		assertAppErrorCode(t, svc.SetUnits(ctx, id, []UnitOfMeasure{{Name: "box", Factor: "0"}}), apperrors.InvalidInput)
+	})
+
+	t.Run("error changing the base unit with stock on hand", func(t *testing.T) {
+		assertNoError(t, svc.IncermentStock(ctx, id, 500))
+		assertAppErrorCode(t, svc.UpdateProduct(ctx, id, &Product{Name: "Coffee Beans", BaseUnit: "kg"}), apperrors.BusinessLogicError)
+	})
+
+	t.Run("bucket moves convert to base units", func(t *testing.T) {
+		assertNoError(t, svc.IncermentStock(ctx, id, 1500))
+		assertNoError(t, svc.MoveStock(ctx, id, BucketMove{From: BucketAvailable, To: BucketDamaged, Quantity: 1, Unit: "kg"}))
+
+		got, err := svc.GetStockBuckets(ctx, id)
+		assertNoError(t, err)
+		if got[0].Available != 1000 || got[0].Damaged != 1000 {
+			t.Fatalf("expected 1000 g available and 1000 g damaged, got %+v", got[0])
+		}
+
+		err = svc.MoveStock(ctx, id, BucketMove{From: BucketDamaged, To: BucketAvailable, Quantity: 1, Unit: "lb"})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
@@ -0,0 +1,224 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// newVariantStockOnlyError is returned by product-level stock operations on products with
+// variants, whose stock is the aggregate of their variants.
+func newVariantStockOnlyError() *apperrors.AppError {
+	return apperrors.NewBusinessLogicError("product has variants; adjust stock on a variant instead")
+}
+
+// SetOptions implements Service. It replaces the product's option axes, which is only
+// allowed before any variant has been created.
+func (s *service) SetOptions(ctx context.Context, productID string, options []OptionAxis) error {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if len(p.Variants) > 0 {
+		return apperrors.NewBusinessLogicError("option axes cannot change once the product has variants")
+	}
+
+	seen := make(map[string]bool, len(options))
+	for i, axis := range options {
+		if axis.Name == "" {
+			return apperrors.NewMissingRequiredDataError("name")
+		}
+		if len(axis.Values) == 0 {
+			return apperrors.NewInvalidInputError("option " + axis.Name + " must have at least one value")
+		}
+		if seen[axis.Name] {
+			return apperrors.NewInvalidInputError("duplicate option " + axis.Name)
+		}
+		seen[axis.Name] = true
+
+		options[i].ProductID = p.ID
+	}
+
+	if err := s.repo.ReplaceOptions(ctx, productID, options); err != nil {
+		return apperrors.NewDatabaseError("failed to set product options: " + err.Error())
+	}
+
+	return nil
+}
+
+// validateVariantOptions checks that the variant has exactly one allowed value per
+// option axis, and that no other variant of the product has the same combination.
+func validateVariantOptions(p *Product, variant *Variant) error {
+	if p.LotTracked || p.Serialized {
+		return apperrors.NewBusinessLogicError("lot-tracked and serialized products cannot have variants")
+	}
+
+	if len(p.Options) == 0 {
+		return apperrors.NewBusinessLogicError("define the product's option axes before adding variants")
+	}
+
+	if len(variant.Options) != len(p.Options) {
+		return apperrors.NewInvalidInputError("variant must have exactly one value for each option axis")
+	}
+
+	for _, axis := range p.Options {
+		value, ok := variant.Options[axis.Name]
+		if !ok {
+			return apperrors.NewMissingRequiredDataError("options." + axis.Name)
+		}
+		if !slices.Contains(axis.Values, value) {
+			return apperrors.NewInvalidInputError("invalid value " + value + " for option " + axis.Name)
+		}
+	}
+
+	for _, existing := range p.Variants {
+		if existing.ID != variant.ID && maps.Equal(existing.Options, variant.Options) {
+			return apperrors.NewDuplicateEntryError("variant options", existing.ID.String())
+		}
+	}
+
+	return nil
+}
+
+// CreateVariant implements Service.
+func (s *service) CreateVariant(ctx context.Context, productID string, variant *Variant) error {
+	if variant.StockQuantity < 0 {
+		return apperrors.NewInvalidInputError("stock quantity cannot be negative")
+	}
+
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if err := validateVariantOptions(p, variant); err != nil {
+		return err
+	}
+
+	variant.ProductID = p.ID
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		if err := repo.CreateVariant(ctx, variant); err != nil {
+			return apperrors.NewDatabaseError("failed to create variant: " + err.Error())
+		}
+
+		if err := syncVariantStock(ctx, repo, productID); err != nil {
+			return err
+		}
+
+		if variant.StockQuantity == 0 {
+			return nil
+		}
+
+		m := newMovement(p, variant.StockQuantity, StockChange{Reason: ReasonOpening})
+		m.VariantID = &variant.ID
+		if err := createMovement(ctx, repo, m); err != nil {
+			return err
+		}
+
+		return s.publishStock(ctx, repo, []StockMovement{*m})
+	})
+}
+
+// UpdateVariant implements Service. Stock is changed through the stock operations, not here.
+func (s *service) UpdateVariant(ctx context.Context, productID string, variantID string, variant *Variant) error {
+	p, err := s.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := getVariant(ctx, s.repo, productID, variantID)
+	if err != nil {
+		return err
+	}
+
+	variant.ID = existing.ID
+	variant.ProductID = existing.ProductID
+	variant.StockQuantity = existing.StockQuantity
+	variant.ReservedQuantity = existing.ReservedQuantity
+
+	if err := validateVariantOptions(p, variant); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateVariant(ctx, variant); err != nil {
+		return apperrors.NewDatabaseError("failed to update variant: " + err.Error())
+	}
+
+	return nil
+}
+
+// DeleteVariant implements Service.
+func (s *service) DeleteVariant(ctx context.Context, productID string, variantID string) error {
+	if _, err := getVariant(ctx, s.repo, productID, variantID); err != nil {
+		return err
+	}
+
+	return s.repo.Transaction(ctx, func(repo Repository) error {
+		if err := repo.DeleteVariant(ctx, variantID); err != nil {
+			return apperrors.NewDatabaseError("failed to delete variant: " + err.Error())
+		}
+
+		return syncVariantStock(ctx, repo, productID)
+	})
+}
+
+// IncrementVariantStock implements Service.
+func (s *service) IncrementVariantStock(ctx context.Context, productID string, variantID string, quantity int) error {
+	if quantity <= 0 {
+		return apperrors.NewInvalidInputError("increment quantity must be greater than 0")
+	}
+
+	if variantID == "" {
+		return apperrors.NewMissingRequiredDataError("variantId")
+	}
+
+	_, err := s.ApplyStockChanges(ctx, StockChange{ProductID: productID, VariantID: variantID, Quantity: quantity, Reason: ReasonIncrement})
+	return err
+}
+
+// DecrementVariantStock implements Service.
+func (s *service) DecrementVariantStock(ctx context.Context, productID string, variantID string, quantity int) error {
+	if quantity <= 0 {
+		return apperrors.NewInvalidInputError("decrement quantity must be greater than 0")
+	}
+
+	if variantID == "" {
+		return apperrors.NewMissingRequiredDataError("variantId")
+	}
+
+	_, err := s.ApplyStockChanges(ctx, StockChange{ProductID: productID, VariantID: variantID, Quantity: -quantity, Reason: ReasonDecrement})
+	return err
+}
+
+func getVariant(ctx context.Context, repo Repository, productID string, variantID string) (*Variant, error) {
+	if productID == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+	if variantID == "" {
+		return nil, apperrors.NewMissingRequiredDataError("variantId")
+	}
+
+	v, err := repo.GetVariantByID(ctx, productID, variantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewVariantNotFoundError(variantID)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve variant: " + err.Error())
+	}
+
+	return v, nil
+}
+
+func syncVariantStock(ctx context.Context, repo Repository, productID string) error {
+	if err := repo.SyncVariantStock(ctx, productID); err != nil {
+		return apperrors.NewDatabaseError("failed to update product stock: " + err.Error())
+	}
+
+	return nil
+}
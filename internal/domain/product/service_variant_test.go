@@ -0,0 +1,109 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// newApparelRepo returns a mock repository holding a shirt with size and colour axes.
+func newApparelRepo() (*memoryRepository, string) {
+	repo := newMemoryRepository()
+
+	p := &Product{Name: "Shirt", LowStockThresold: 5}
+	p.ID = uuid.New()
+	p.Options = []OptionAxis{
+		{Name: "size", Values: []string{"S", "M", "L"}},
+		{Name: "colour", Values: []string{"red", "blue"}},
+	}
+
+	id := p.ID.String()
+	repo.products[id] = p
+	return repo, id
+}
+
+func TestService_CreateVariant(t *testing.T) {
+	repo, id := newApparelRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	t.Run("creates variant and updates aggregate stock", func(t *testing.T) {
+		v := &Variant{Options: map[string]string{"size": "M", "colour": "red"}, StockQuantity: 4}
+		assertNoError(t, svc.CreateVariant(ctx, id, v))
+
+		p, _ := repo.GetByID(ctx, id)
+		if len(p.Variants) != 1 || p.StockQuantity != 4 {
+			t.Fatalf("expected 1 variant and stock 4, got %d variants and stock %d", len(p.Variants), p.StockQuantity)
+		}
+	})
+
+	t.Run("error on duplicate option combination", func(t *testing.T) {
+		v := &Variant{Options: map[string]string{"size": "M", "colour": "red"}}
+		assertAppErrorCode(t, svc.CreateVariant(ctx, id, v), apperrors.DuplicateEntry)
+	})
+
+	t.Run("error on value outside the axis", func(t *testing.T) {
+		v := &Variant{Options: map[string]string{"size": "XL", "colour": "red"}}
+		assertAppErrorCode(t, svc.CreateVariant(ctx, id, v), apperrors.InvalidInput)
+	})
+
+	t.Run("error on missing axis", func(t *testing.T) {
+		v := &Variant{Options: map[string]string{"size": "S"}}
+		assertAppErrorCode(t, svc.CreateVariant(ctx, id, v), apperrors.InvalidInput)
+	})
+
+	t.Run("error when product has no axes", func(t *testing.T) {
+		plain := &Product{Name: "Mug"}
+		plain.ID = uuid.New()
+		repo.products[plain.ID.String()] = plain
+
+		v := &Variant{Options: map[string]string{"size": "S"}}
+		assertAppErrorCode(t, svc.CreateVariant(ctx, plain.ID.String(), v), apperrors.BusinessLogicError)
+	})
+}
+
+func TestService_VariantStock(t *testing.T) {
+	repo, id := newApparelRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	small := &Variant{Options: map[string]string{"size": "S", "colour": "blue"}, StockQuantity: 2}
+	large := &Variant{Options: map[string]string{"size": "L", "colour": "blue"}, StockQuantity: 3}
+	assertNoError(t, svc.CreateVariant(ctx, id, small))
+	assertNoError(t, svc.CreateVariant(ctx, id, large))
+
+	t.Run("increment updates variant and aggregate", func(t *testing.T) {
+		assertNoError(t, svc.IncrementVariantStock(ctx, id, small.ID.String(), 5))
+
+		v, _ := repo.GetVariantByID(ctx, id, small.ID.String())
+		p, _ := repo.GetByID(ctx, id)
+		if v.StockQuantity != 7 || p.StockQuantity != 10 {
+			t.Fatalf("expected variant 7 and aggregate 10, got %d and %d", v.StockQuantity, p.StockQuantity)
+		}
+	})
+
+	t.Run("decrement beyond variant stock fails", func(t *testing.T) {
+		err := svc.DecrementVariantStock(ctx, id, large.ID.String(), 4)
+		assertAppErrorCode(t, err, apperrors.InsufficientStock)
+	})
+
+	t.Run("product-level stock operations are rejected", func(t *testing.T) {
+		assertAppErrorCode(t, svc.IncermentStock(ctx, id, 1), apperrors.BusinessLogicError)
+		assertAppErrorCode(t, svc.DecrementStock(ctx, id, 1), apperrors.BusinessLogicError)
+	})
+
+	t.Run("error when variant belongs to another product", func(t *testing.T) {
+		other, otherID := newApparelRepo()
+		repo.products[otherID] = other.products[otherID]
+
+		err := svc.IncrementVariantStock(ctx, otherID, small.ID.String(), 1)
+		assertAppErrorCode(t, err, apperrors.VariantNotFound)
+	})
+
+	t.Run("option axes are locked once variants exist", func(t *testing.T) {
+		err := svc.SetOptions(ctx, id, []OptionAxis{{Name: "size", Values: []string{"S"}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+}
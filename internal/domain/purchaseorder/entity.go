@@ -0,0 +1,74 @@
+package purchaseorder
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+)
+
+// ReferenceType identifies purchase orders as the reference of stock movements.
+const ReferenceType = "purchase_order"
+
+// Status is the stage of a purchase order. Orders move from draft to sent, then to
+// partially received and received as goods arrive.
+type Status string
+
+const (
+	StatusDraft             Status = "draft"
+	StatusSent              Status = "sent"
+	StatusPartiallyReceived Status = "partially_received"
+	StatusReceived          Status = "received"
+)
+
+type PurchaseOrder struct {
+	model.BaseModel
+	SupplierID uuid.UUID          `json:"supplier_id" gorm:"type:uuid;not null;index" validate:"required"`
+	Supplier   *supplier.Supplier `json:"supplier,omitempty" openapi:"readonly"`
+	Status     Status             `json:"status" gorm:"not null;index" enum:"draft,sent,partially_received,received" openapi:"readonly"`
+	Currency   string             `json:"currency" gorm:"size:3;not null" doc:"ISO 4217 code of the line costs; defaults to the currency of the products"`
+	ExpectedAt *time.Time         `json:"expected_at" doc:"When the goods are expected to arrive"`
+	Note       string             `json:"note"`
+	Lines      []Line             `json:"lines" gorm:"foreignKey:PurchaseOrderID" validate:"required"`
+}
+
+// Line is the quantity of one product, or one of its variants, ordered from the supplier.
+type Line struct {
+	model.BaseModel
+	PurchaseOrderID  uuid.UUID  `json:"purchase_order_id" gorm:"type:uuid;not null;index" openapi:"readonly"`
+	ProductID        uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index" validate:"required"`
+	VariantID        *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid" doc:"Required for products with variants"`
+	QuantityOrdered  int        `json:"quantity_ordered" gorm:"not null" validate:"required,min=1" doc:"Units ordered, kept in the product's base unit"`
+	Unit             string     `json:"unit,omitempty" gorm:"-" doc:"Unit quantity_ordered is given in, converted to the base unit; defaults to the base unit"`
+	QuantityReceived int        `json:"quantity_received" gorm:"not null;default:0" openapi:"readonly"`
+	UnitCost         int64      `json:"unit_cost" gorm:"not null;default:0" validate:"min=0" doc:"Cost per base unit in minor units; defaults to the product's unit cost"`
+}
+
+func (Line) TableName() string {
+	return "purchase_order_lines"
+}
+
+// Receipt records goods received against the lines of a purchase order.
+type Receipt struct {
+	Lines []ReceiptLine `json:"lines"`
+	Note  string        `json:"note"`
+	// Close marks the order received even when some lines are short
+	Close bool `json:"close" doc:"Mark the order received even if some lines were delivered short"`
+}
+
+// ReceiptLine is the quantity received for one order line. It may exceed what is outstanding.
+type ReceiptLine struct {
+	LineID    uuid.UUID  `json:"line_id" validate:"required"`
+	Quantity  int        `json:"quantity" validate:"required,min=1"`
+	Unit      string     `json:"unit,omitempty" doc:"Unit of quantity; defaults to the base unit"`
+	LotNumber string     `json:"lot_number,omitempty" doc:"Lot the goods are received into; required for lot-tracked products"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" doc:"Expiry date of a new lot"`
+	Serials   []string   `json:"serials,omitempty" doc:"Serial numbers received; required for serialized products, one per unit"`
+}
+
+// Filter narrows a purchase order listing. The zero value matches every order.
+type Filter struct {
+	SupplierID string
+	Status     Status
+}
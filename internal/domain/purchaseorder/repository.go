@@ -0,0 +1,18 @@
+package purchaseorder
+
+import "context"
+
+type Repository interface {
+	// Create inserts the order together with its lines.
+	Create(context.Context, *PurchaseOrder) error
+	GetAll(context.Context, Filter) ([]PurchaseOrder, error)
+	GetByID(context.Context, string) (*PurchaseOrder, error)
+	// Update saves the order's header fields and replaces its lines.
+	Update(context.Context, *PurchaseOrder) error
+	Delete(context.Context, string) error
+
+	// Lock locks the order row until the surrounding transaction ends.
+	Lock(ctx context.Context, id string) error
+	UpdateStatus(ctx context.Context, id string, status Status) error
+	UpdateLineReceived(ctx context.Context, lineID string, quantity int) error
+}
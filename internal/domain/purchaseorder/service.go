@@ -0,0 +1,326 @@
+package purchaseorder
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreatePurchaseOrder(context.Context, *PurchaseOrder) error
+	GetAllPurchaseOrders(context.Context, Filter) ([]PurchaseOrder, error)
+	GetPurchaseOrderByID(context.Context, string) (*PurchaseOrder, error)
+	UpdatePurchaseOrder(context.Context, string, *PurchaseOrder) error
+	DeletePurchaseOrder(context.Context, string) error
+
+	SendPurchaseOrder(ctx context.Context, id string) error
+	// ReceivePurchaseOrder posts the received quantities as stock increments linked to the order.
+	ReceivePurchaseOrder(ctx context.Context, id string, receipt Receipt) error
+	GetReceiptMovements(ctx context.Context, id string) ([]product.StockMovement, error)
+}
+
+// Transactor runs fn inside one database transaction, passing a Repository and a
+// product.Service that both take part in it.
+type Transactor func(ctx context.Context, fn func(Repository, product.Service) error) error
+
+type service struct {
+	repo      Repository
+	products  product.Service
+	suppliers supplier.Service
+	tx        Transactor
+}
+
+func NewService(repo Repository, products product.Service, suppliers supplier.Service, tx Transactor) Service {
+	return &service{
+		repo:      repo,
+		products:  products,
+		suppliers: suppliers,
+		tx:        tx,
+	}
+}
+
+// CreatePurchaseOrder implements Service. New orders start as drafts.
+func (s *service) CreatePurchaseOrder(ctx context.Context, po *PurchaseOrder) error {
+	if err := s.validate(ctx, po); err != nil {
+		return err
+	}
+
+	po.Status = StatusDraft
+
+	if err := s.repo.Create(ctx, po); err != nil {
+		return apperrors.NewDatabaseError("failed to create purchase order: " + err.Error())
+	}
+
+	return nil
+}
+
+// GetAllPurchaseOrders implements Service.
+func (s *service) GetAllPurchaseOrders(ctx context.Context, filter Filter) ([]PurchaseOrder, error) {
+	orders, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve purchase orders: " + err.Error())
+	}
+
+	return orders, nil
+}
+
+// GetPurchaseOrderByID implements Service.
+func (s *service) GetPurchaseOrderByID(ctx context.Context, id string) (*PurchaseOrder, error) {
+	return getPurchaseOrder(ctx, s.repo, id)
+}
+
+// UpdatePurchaseOrder implements Service. Only drafts can be changed.
+func (s *service) UpdatePurchaseOrder(ctx context.Context, id string, po *PurchaseOrder) error {
+	existing, err := s.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.Status != StatusDraft {
+		return apperrors.NewBusinessLogicError("only draft purchase orders can be changed")
+	}
+
+	if err := s.validate(ctx, po); err != nil {
+		return err
+	}
+
+	po.ID = existing.ID
+	po.Status = existing.Status
+
+	if err := s.repo.Update(ctx, po); err != nil {
+		return apperrors.NewDatabaseError("failed to update purchase order: " + err.Error())
+	}
+
+	return nil
+}
+
+// DeletePurchaseOrder implements Service. Only drafts can be deleted.
+func (s *service) DeletePurchaseOrder(ctx context.Context, id string) error {
+	existing, err := s.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.Status != StatusDraft {
+		return apperrors.NewBusinessLogicError("only draft purchase orders can be deleted")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return apperrors.NewDatabaseError("failed to delete purchase order: " + err.Error())
+	}
+
+	return nil
+}
+
+// SendPurchaseOrder implements Service.
+func (s *service) SendPurchaseOrder(ctx context.Context, id string) error {
+	existing, err := s.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.Status != StatusDraft {
+		return apperrors.NewBusinessLogicError("only draft purchase orders can be sent")
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, StatusSent); err != nil {
+		return apperrors.NewDatabaseError("failed to update purchase order status: " + err.Error())
+	}
+
+	return nil
+}
+
+// ReceivePurchaseOrder implements Service. Quantities may exceed what is outstanding
+// (over-receipt); an order delivered short stays partially received unless the receipt
+// closes it. The stock increments, received quantities and status change commit together.
+func (s *service) ReceivePurchaseOrder(ctx context.Context, id string, receipt Receipt) error {
+	if len(receipt.Lines) == 0 && !receipt.Close {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		if err := lockPurchaseOrder(ctx, repo, id); err != nil {
+			return err
+		}
+
+		po, err := getPurchaseOrder(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if po.Status != StatusSent && po.Status != StatusPartiallyReceived {
+			return apperrors.NewBusinessLogicError("only sent or partially received purchase orders can receive goods")
+		}
+
+		lines := make(map[string]*Line, len(po.Lines))
+		for i := range po.Lines {
+			lines[po.Lines[i].ID.String()] = &po.Lines[i]
+		}
+
+		changes := make([]product.StockChange, 0, len(receipt.Lines))
+		for _, rl := range receipt.Lines {
+			line, ok := lines[rl.LineID.String()]
+			if !ok {
+				return apperrors.NewInvalidInputError("line " + rl.LineID.String() + " is not on this purchase order")
+			}
+			if rl.Quantity <= 0 {
+				return apperrors.NewInvalidInputError("received quantity must be greater than 0")
+			}
+			if rl.Unit != "" {
+				if rl.Quantity, err = products.ToBaseUnits(ctx, line.ProductID.String(), quantity.FromInt(rl.Quantity), rl.Unit); err != nil {
+					return err
+				}
+			}
+
+			line.QuantityReceived += rl.Quantity
+			if err := repo.UpdateLineReceived(ctx, line.ID.String(), line.QuantityReceived); err != nil {
+				return apperrors.NewDatabaseError("failed to update received quantity: " + err.Error())
+			}
+
+			change := product.StockChange{
+				ProductID:     line.ProductID.String(),
+				Quantity:      rl.Quantity,
+				Reason:        product.ReasonReceipt,
+				LotNumber:     rl.LotNumber,
+				ExpiresAt:     rl.ExpiresAt,
+				Serials:       rl.Serials,
+				UnitCost:      line.UnitCost,
+				ReferenceType: ReferenceType,
+				ReferenceID:   po.ID.String(),
+				Note:          receipt.Note,
+			}
+			if line.VariantID != nil {
+				change.VariantID = line.VariantID.String()
+			}
+			changes = append(changes, change)
+		}
+
+		if len(changes) > 0 {
+			if _, err := products.ApplyStockChanges(ctx, changes...); err != nil {
+				return err
+			}
+		}
+
+		status := StatusReceived
+		if !receipt.Close && !fullyReceived(po.Lines) {
+			status = StatusPartiallyReceived
+		}
+
+		if err := repo.UpdateStatus(ctx, id, status); err != nil {
+			return apperrors.NewDatabaseError("failed to update purchase order status: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// GetReceiptMovements implements Service.
+func (s *service) GetReceiptMovements(ctx context.Context, id string) ([]product.StockMovement, error) {
+	po, err := s.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.products.ListMovements(ctx, product.MovementFilter{
+		ReferenceType: ReferenceType,
+		ReferenceID:   po.ID.String(),
+	})
+}
+
+// validate checks the supplier and every line, defaulting the currency and line costs
+// from the products ordered.
+func (s *service) validate(ctx context.Context, po *PurchaseOrder) error {
+	if _, err := s.suppliers.GetSupplierByID(ctx, po.SupplierID.String()); err != nil {
+		return err
+	}
+
+	if len(po.Lines) == 0 {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	for i := range po.Lines {
+		line := &po.Lines[i]
+
+		if line.QuantityOrdered <= 0 {
+			return apperrors.NewInvalidInputError("ordered quantity must be greater than 0")
+		}
+		if line.UnitCost < 0 {
+			return apperrors.NewInvalidInputError("unit cost cannot be negative")
+		}
+
+		p, err := s.products.GetProductByID(ctx, line.ProductID.String())
+		if err != nil {
+			return err
+		}
+
+		if err := p.CheckVariant(line.VariantID); err != nil {
+			return err
+		}
+
+		if line.Unit != "" {
+			if line.QuantityOrdered, err = p.ToBaseUnits(quantity.FromInt(line.QuantityOrdered), line.Unit); err != nil {
+				return err
+			}
+			line.Unit = ""
+		}
+
+		if po.Currency == "" {
+			po.Currency = p.Currency
+		}
+		if p.Currency != po.Currency {
+			return apperrors.NewInvalidInputError("product " + p.ID.String() + " is priced in " + p.Currency + ", not " + po.Currency)
+		}
+
+		if line.UnitCost == 0 {
+			line.UnitCost = p.UnitCost
+		}
+		line.QuantityReceived = 0
+	}
+
+	return nil
+}
+
+func fullyReceived(lines []Line) bool {
+	for _, line := range lines {
+		if line.QuantityReceived < line.QuantityOrdered {
+			return false
+		}
+	}
+	return true
+}
+
+func getPurchaseOrder(ctx context.Context, repo Repository, id string) (*PurchaseOrder, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	po, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewPurchaseOrderNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve purchase order: " + err.Error())
+	}
+
+	return po, nil
+}
+
+func lockPurchaseOrder(ctx context.Context, repo Repository, id string) error {
+	if id == "" {
+		return apperrors.NewMissingRequiredDataError("id")
+	}
+
+	if err := repo.Lock(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewPurchaseOrderNotFoundError(id)
+		}
+		return apperrors.NewDatabaseError("failed to lock purchase order: " + err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package purchaseorder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+type mockRepo struct {
+	orders map[string]*PurchaseOrder
+}
+
+func newMockRepo() *mockRepo {
+	return &mockRepo{orders: make(map[string]*PurchaseOrder)}
+}
+
+func (m *mockRepo) Create(_ context.Context, po *PurchaseOrder) error {
+	po.ID = uuid.New()
+	for i := range po.Lines {
+		po.Lines[i].ID = uuid.New()
+		po.Lines[i].PurchaseOrderID = po.ID
+	}
+	stored := *po
+	stored.Lines = append([]Line(nil), po.Lines...)
+	m.orders[po.ID.String()] = &stored
+	return nil
+}
+
+func (m *mockRepo) GetAll(context.Context, Filter) ([]PurchaseOrder, error) {
+	out := make([]PurchaseOrder, 0, len(m.orders))
+	for _, po := range m.orders {
+		out = append(out, *po)
+	}
+	return out, nil
+}
+
+func (m *mockRepo) GetByID(_ context.Context, id string) (*PurchaseOrder, error) {
+	po, ok := m.orders[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *po
+	copied.Lines = append([]Line(nil), po.Lines...)
+	return &copied, nil
+}
+
+func (m *mockRepo) Update(_ context.Context, po *PurchaseOrder) error {
+	m.orders[po.ID.String()] = po
+	return nil
+}
+
+func (m *mockRepo) Delete(_ context.Context, id string) error {
+	delete(m.orders, id)
+	return nil
+}
+
+func (m *mockRepo) Lock(_ context.Context, id string) error {
+	if _, ok := m.orders[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (m *mockRepo) UpdateStatus(_ context.Context, id string, status Status) error {
+	m.orders[id].Status = status
+	return nil
+}
+
+func (m *mockRepo) UpdateLineReceived(_ context.Context, lineID string, quantity int) error {
+	for _, po := range m.orders {
+		for i := range po.Lines {
+			if po.Lines[i].ID.String() == lineID {
+				po.Lines[i].QuantityReceived = quantity
+				return nil
+			}
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+type stubSuppliers struct {
+	supplier.Service
+
+	id uuid.UUID
+}
+
+func (s stubSuppliers) GetSupplierByID(_ context.Context, id string) (*supplier.Supplier, error) {
+	if id != s.id.String() {
+		return nil, apperrors.NewSupplierNotFoundError(id)
+	}
+	return &supplier.Supplier{Name: "Acme"}, nil
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+// fixture posts stock through a real product service over the product package's
+// in-memory repository, so receipts are held to the ledger's rules.
+type fixture struct {
+	svc      Service
+	repo     *mockRepo
+	products product.Service
+	supplier uuid.UUID
+	widget   *product.Product
+	shirt    *product.Product
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &fixture{repo: newMockRepo(), products: product.NewService(product.NewMemoryRepository()), supplier: uuid.New()}
+
+	f.widget = &product.Product{Name: "Widget", Currency: "USD", UnitCost: 150}
+	f.shirt = &product.Product{Name: "Shirt", Currency: "USD"}
+	for _, p := range []*product.Product{f.widget, f.shirt} {
+		if err := f.products.CreateProduct(ctx, p); err != nil {
+			t.Fatalf("failed to create %s: %v", p.Name, err)
+		}
+	}
+
+	shirtID := f.shirt.ID.String()
+	if err := f.products.SetOptions(ctx, shirtID, []product.OptionAxis{{Name: "size", Values: []string{"M"}}}); err != nil {
+		t.Fatalf("failed to set the shirt's options: %v", err)
+	}
+	if err := f.products.CreateVariant(ctx, shirtID, &product.Variant{Options: map[string]string{"size": "M"}}); err != nil {
+		t.Fatalf("failed to create the shirt variant: %v", err)
+	}
+
+	tx := func(_ context.Context, fn func(Repository, product.Service) error) error {
+		return fn(f.repo, f.products)
+	}
+	f.svc = NewService(f.repo, f.products, stubSuppliers{id: f.supplier}, tx)
+
+	return f
+}
+
+func (f *fixture) order(lines ...Line) *PurchaseOrder {
+	return &PurchaseOrder{SupplierID: f.supplier, Lines: lines}
+}
+
+func TestService_CreatePurchaseOrder(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	t.Run("creates a draft with defaults from the product", func(t *testing.T) {
+		po := f.order(Line{ProductID: f.widget.ID, QuantityOrdered: 10})
+		if err := f.svc.CreatePurchaseOrder(ctx, po); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if po.Status != StatusDraft || po.Currency != "USD" || po.Lines[0].UnitCost != 150 {
+			t.Fatalf("unexpected order: status %s, currency %s, unit cost %d", po.Status, po.Currency, po.Lines[0].UnitCost)
+		}
+	})
+
+	t.Run("error on unknown supplier", func(t *testing.T) {
+		po := &PurchaseOrder{SupplierID: uuid.New(), Lines: []Line{{ProductID: f.widget.ID, QuantityOrdered: 1}}}
+		assertAppErrorCode(t, f.svc.CreatePurchaseOrder(ctx, po), apperrors.SupplierNotFound)
+	})
+
+	t.Run("error without lines", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CreatePurchaseOrder(ctx, f.order()), apperrors.MissingRequiredData)
+	})
+
+	t.Run("error on product with variants but no variant", func(t *testing.T) {
+		po := f.order(Line{ProductID: f.shirt.ID, QuantityOrdered: 1})
+		assertAppErrorCode(t, f.svc.CreatePurchaseOrder(ctx, po), apperrors.MissingRequiredData)
+	})
+
+	t.Run("error on currency mismatch", func(t *testing.T) {
+		po := f.order(Line{ProductID: f.widget.ID, QuantityOrdered: 1})
+		po.Currency = "EUR"
+		assertAppErrorCode(t, f.svc.CreatePurchaseOrder(ctx, po), apperrors.InvalidInput)
+	})
+}
+
+func TestService_ReceivePurchaseOrder(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	variantID := f.shirt.Variants[0].ID
+	po := f.order(
+		Line{ProductID: f.widget.ID, QuantityOrdered: 10, UnitCost: 200},
+		Line{ProductID: f.shirt.ID, VariantID: &variantID, QuantityOrdered: 5},
+	)
+	if err := f.svc.CreatePurchaseOrder(ctx, po); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	id := po.ID.String()
+	widgetLine, shirtLine := po.Lines[0].ID, po.Lines[1].ID
+
+	t.Run("error when the order has not been sent", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: widgetLine, Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	if err := f.svc.SendPurchaseOrder(ctx, id); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	t.Run("under-receipt leaves the order partially received", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: widgetLine, Quantity: 4}}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if got := f.repo.orders[id].Status; got != StatusPartiallyReceived {
+			t.Fatalf("expected partially_received, got %s", got)
+		}
+
+		movements, _ := f.svc.GetReceiptMovements(ctx, id)
+		if len(movements) != 1 {
+			t.Fatalf("expected one receipt movement, got %+v", movements)
+		}
+		if m := movements[0]; m.Quantity != 4 || m.UnitCost != 200 || m.Reason != product.ReasonReceipt || m.ReferenceType != ReferenceType || m.ReferenceID != id {
+			t.Fatalf("unexpected receipt movement: %+v", m)
+		}
+		if f.widget.StockQuantity != 4 {
+			t.Fatalf("expected 4 widgets in stock, got %d", f.widget.StockQuantity)
+		}
+	})
+
+	t.Run("over-receipt completes the order", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{
+			{LineID: widgetLine, Quantity: 8},
+			{LineID: shirtLine, Quantity: 5},
+		}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		stored := f.repo.orders[id]
+		if stored.Status != StatusReceived || stored.Lines[0].QuantityReceived != 12 {
+			t.Fatalf("expected received with 12 widgets, got %s with %d", stored.Status, stored.Lines[0].QuantityReceived)
+		}
+		shirt, _ := f.products.GetProductByID(ctx, f.shirt.ID.String())
+		if shirt.StockQuantity != 5 || shirt.Variants[0].StockQuantity != 5 {
+			t.Fatalf("expected the shirt receipt to stock its variant, got %+v", shirt.Variants)
+		}
+	})
+
+	t.Run("error once fully received", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: widgetLine, Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+}
+
+func TestService_ReceivePurchaseOrderClose(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	po := f.order(Line{ProductID: f.widget.ID, QuantityOrdered: 10})
+	_ = f.svc.CreatePurchaseOrder(ctx, po)
+	id := po.ID.String()
+	_ = f.svc.SendPurchaseOrder(ctx, id)
+
+	t.Run("error on a line from another order", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: uuid.New(), Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("closing a short delivery marks the order received", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: po.Lines[0].ID, Quantity: 7}}, Close: true})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if got := f.repo.orders[id].Status; got != StatusReceived {
+			t.Fatalf("expected received, got %s", got)
+		}
+	})
+
+	t.Run("error when changing a sent order", func(t *testing.T) {
+		err := f.svc.UpdatePurchaseOrder(ctx, id, f.order(Line{ProductID: f.widget.ID, QuantityOrdered: 1}))
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+}
+
+func TestService_PurchaseOrderUnits(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	if err := f.products.SetUnits(ctx, f.widget.ID.String(), []product.UnitOfMeasure{{Name: "case", Factor: "12"}}); err != nil {
+		t.Fatalf("failed to set the widget's units: %v", err)
+	}
+
+	po := f.order(Line{ProductID: f.widget.ID, QuantityOrdered: 2, Unit: "case"})
+	if err := f.svc.CreatePurchaseOrder(ctx, po); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if line := po.Lines[0]; line.QuantityOrdered != 24 || line.Unit != "" {
+		t.Fatalf("expected 24 widgets ordered in the base unit, got %d %s", line.QuantityOrdered, line.Unit)
+	}
+	id := po.ID.String()
+	_ = f.svc.SendPurchaseOrder(ctx, id)
+
+	t.Run("receipts convert to base units", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: po.Lines[0].ID, Quantity: 1, Unit: "case"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if f.widget.StockQuantity != 12 || f.repo.orders[id].Lines[0].QuantityReceived != 12 {
+			t.Fatalf("expected 12 widgets received, got %d in stock", f.widget.StockQuantity)
+		}
+	})
+
+	t.Run("error on a unit the product does not have", func(t *testing.T) {
+		err := f.svc.ReceivePurchaseOrder(ctx, id, Receipt{Lines: []ReceiptLine{{LineID: po.Lines[0].ID, Quantity: 1, Unit: "pallet"}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+
+		err = f.svc.CreatePurchaseOrder(ctx, f.order(Line{ProductID: f.widget.ID, QuantityOrdered: 1, Unit: "pallet"}))
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
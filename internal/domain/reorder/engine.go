@@ -0,0 +1,95 @@
+package reorder
+
+import (
+	"math"
+	"time"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+)
+
+// consumptionReasons are the movements that count as demand; corrections and write-offs do not.
+var consumptionReasons = map[product.MovementReason]bool{
+	product.ReasonDecrement: true,
+	product.ReasonSale:      true,
+}
+
+// dailyConsumption buckets the consumption in movements into one total per day of the
+// window starting at since, including the days without any.
+func dailyConsumption(movements []product.StockMovement, since time.Time, days int) []float64 {
+	series := make([]float64, days)
+
+	for _, m := range movements {
+		if m.Quantity >= 0 || !consumptionReasons[m.Reason] {
+			continue
+		}
+
+		day := int(m.OccurredAt.Sub(since) / (24 * time.Hour))
+		if day < 0 || day >= days {
+			continue
+		}
+		series[day] += float64(-m.Quantity)
+	}
+
+	return series
+}
+
+// meanStdDev returns the mean and population standard deviation of xs.
+func meanStdDev(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+
+	var variance float64
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+
+	return mean, math.Sqrt(variance / float64(len(xs)))
+}
+
+// zScore returns the standard normal quantile for the service level.
+func zScore(serviceLevel float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*serviceLevel-1)
+}
+
+// suggest computes the reorder point and quantity for p. Safety stock covers demand
+// variability over the lead time at the requested service level:
+//
+//	safety stock  = z × σ(daily demand) × √lead time
+//	reorder point = mean daily demand × lead time + safety stock
+//
+// An order brings the stock position (on hand plus on order) up to the demand expected
+// over the lead time and cover period, plus safety stock.
+func suggest(p product.Product, series []float64, leadTime, onOrder int, params Params) Suggestion {
+	mean, sd := meanStdDev(series)
+
+	safety := int(math.Ceil(zScore(params.ServiceLevel) * sd * math.Sqrt(float64(leadTime))))
+	reorderPoint := max(int(math.Ceil(mean*float64(leadTime)))+safety, p.LowStockThresold)
+
+	position := p.StockQuantity + onOrder
+	target := max(int(math.Ceil(mean*float64(leadTime+params.CoverDays)))+safety, reorderPoint+1)
+
+	s := Suggestion{
+		ProductID:               p.ID,
+		Name:                    p.Name,
+		SupplierID:              p.SupplierID,
+		OnHand:                  p.StockQuantity,
+		OnOrder:                 onOrder,
+		AverageDailyConsumption: math.Round(mean*100) / 100,
+		LeadTimeDays:            leadTime,
+		SafetyStock:             safety,
+		ReorderPoint:            reorderPoint,
+		NeedsReorder:            position <= reorderPoint,
+	}
+	if s.NeedsReorder {
+		s.SuggestedQuantity = target - position
+	}
+
+	return s
+}
@@ -0,0 +1,54 @@
+package reorder
+
+import (
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+)
+
+// Params tunes the reorder calculation.
+type Params struct {
+	// LookbackDays is the window of decrement history used to measure consumption
+	LookbackDays int
+	// ServiceLevel is the desired probability of not running out during the lead time, in (0, 1)
+	ServiceLevel float64
+	// CoverDays is how many days of consumption an order covers beyond the lead time
+	CoverDays int
+	// All includes products that do not need reordering yet
+	All bool
+}
+
+// DefaultParams returns a 30-day lookback, a 95% service level and two weeks of cover.
+func DefaultParams() Params {
+	return Params{
+		LookbackDays: 30,
+		ServiceLevel: 0.95,
+		CoverDays:    14,
+	}
+}
+
+// Suggestion is the reorder point and quantity computed for one product.
+type Suggestion struct {
+	ProductID               uuid.UUID  `json:"product_id"`
+	Name                    string     `json:"name"`
+	SupplierID              *uuid.UUID `json:"supplier_id"`
+	OnHand                  int        `json:"on_hand"`
+	OnOrder                 int        `json:"on_order" doc:"Outstanding quantity on draft, sent and partially received purchase orders"`
+	AverageDailyConsumption float64    `json:"average_daily_consumption"`
+	LeadTimeDays            int        `json:"lead_time_days"`
+	SafetyStock             int        `json:"safety_stock"`
+	ReorderPoint            int        `json:"reorder_point" doc:"Reorder when on hand plus on order falls to this level; never below the low-stock threshold"`
+	SuggestedQuantity       int        `json:"suggested_quantity"`
+	NeedsReorder            bool       `json:"needs_reorder"`
+}
+
+// DraftOrders is the outcome of turning suggestions into draft purchase orders.
+type DraftOrders struct {
+	PurchaseOrders []purchaseorder.PurchaseOrder `json:"purchase_orders"`
+	Skipped        []Skipped                     `json:"skipped"`
+}
+
+// Skipped is a product that needs reordering but could not be put on a draft order.
+type Skipped struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Reason    string    `json:"reason"`
+}
@@ -0,0 +1,201 @@
+package reorder
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type Service interface {
+	GetSuggestions(context.Context, Params) ([]Suggestion, error)
+	// CreateDraftOrders puts every product that needs reordering on a draft purchase
+	// order to its preferred supplier, one order per supplier.
+	CreateDraftOrders(context.Context, Params) (*DraftOrders, error)
+}
+
+type service struct {
+	products  product.Service
+	orders    purchaseorder.Service
+	suppliers supplier.Service
+
+	now func() time.Time
+}
+
+func NewService(products product.Service, orders purchaseorder.Service, suppliers supplier.Service) Service {
+	return &service{
+		products:  products,
+		orders:    orders,
+		suppliers: suppliers,
+		now:       time.Now,
+	}
+}
+
+// GetSuggestions implements Service. Only products that need reordering are returned
+// unless params.All is set.
+func (s *service) GetSuggestions(ctx context.Context, params Params) ([]Suggestion, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
+	products, err := s.products.GetAllProducts(ctx, product.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The window ends with today, so its last day is still in progress
+	since := s.now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-params.LookbackDays)
+	movements, err := s.products.ListMovements(ctx, product.MovementFilter{Since: since})
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uuid.UUID][]product.StockMovement)
+	for _, m := range movements {
+		byProduct[m.ProductID] = append(byProduct[m.ProductID], m)
+	}
+
+	onOrder, err := s.onOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	leadTimes, err := s.supplierLeadTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []Suggestion{}
+	for _, p := range products {
+		leadTime := p.LeadTimeDays
+		if leadTime == 0 && p.SupplierID != nil {
+			leadTime = leadTimes[*p.SupplierID]
+		}
+
+		series := dailyConsumption(byProduct[p.ID], since, params.LookbackDays)
+		suggestion := suggest(p, series, leadTime, onOrder[p.ID], params)
+
+		if suggestion.NeedsReorder || params.All {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// CreateDraftOrders implements Service. Products without a preferred supplier, and
+// products with variants (which are ordered per variant), are skipped.
+func (s *service) CreateDraftOrders(ctx context.Context, params Params) (*DraftOrders, error) {
+	params.All = false
+
+	suggestions, err := s.GetSuggestions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DraftOrders{
+		PurchaseOrders: []purchaseorder.PurchaseOrder{},
+		Skipped:        []Skipped{},
+	}
+
+	var suppliers []uuid.UUID
+	lines := make(map[uuid.UUID][]purchaseorder.Line)
+
+	for _, suggestion := range suggestions {
+		if suggestion.SupplierID == nil {
+			result.Skipped = append(result.Skipped, Skipped{ProductID: suggestion.ProductID, Reason: "no preferred supplier"})
+			continue
+		}
+
+		p, err := s.products.GetProductByID(ctx, suggestion.ProductID.String())
+		if err != nil {
+			return nil, err
+		}
+		if len(p.Variants) > 0 {
+			result.Skipped = append(result.Skipped, Skipped{ProductID: suggestion.ProductID, Reason: "product has variants; order them individually"})
+			continue
+		}
+
+		supplierID := *suggestion.SupplierID
+		if _, ok := lines[supplierID]; !ok {
+			suppliers = append(suppliers, supplierID)
+		}
+		lines[supplierID] = append(lines[supplierID], purchaseorder.Line{
+			ProductID:       suggestion.ProductID,
+			QuantityOrdered: suggestion.SuggestedQuantity,
+		})
+	}
+
+	for _, supplierID := range suppliers {
+		po := purchaseorder.PurchaseOrder{
+			SupplierID: supplierID,
+			Note:       "Generated from reorder suggestions",
+			Lines:      lines[supplierID],
+		}
+		if err := s.orders.CreatePurchaseOrder(ctx, &po); err != nil {
+			return nil, err
+		}
+		result.PurchaseOrders = append(result.PurchaseOrders, po)
+	}
+
+	return result, nil
+}
+
+// onOrder sums the outstanding quantity per product over open purchase orders. Drafts
+// count too, so that generating draft orders twice does not order twice.
+func (s *service) onOrder(ctx context.Context) (map[uuid.UUID]int, error) {
+	out := make(map[uuid.UUID]int)
+
+	for _, status := range []purchaseorder.Status{
+		purchaseorder.StatusDraft,
+		purchaseorder.StatusSent,
+		purchaseorder.StatusPartiallyReceived,
+	} {
+		orders, err := s.orders.GetAllPurchaseOrders(ctx, purchaseorder.Filter{Status: status})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, po := range orders {
+			for _, line := range po.Lines {
+				out[line.ProductID] += max(line.QuantityOrdered-line.QuantityReceived, 0)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (s *service) supplierLeadTimes(ctx context.Context) (map[uuid.UUID]int, error) {
+	suppliers, err := s.suppliers.GetAllSuppliers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[uuid.UUID]int, len(suppliers))
+	for _, sup := range suppliers {
+		out[sup.ID] = sup.LeadTimeDays
+	}
+
+	return out, nil
+}
+
+func validateParams(params Params) error {
+	if params.LookbackDays < 1 {
+		return apperrors.NewInvalidInputError("days must be at least 1")
+	}
+
+	if params.ServiceLevel <= 0 || params.ServiceLevel >= 1 {
+		return apperrors.NewInvalidInputError("service_level must be between 0 and 1")
+	}
+
+	if params.CoverDays < 0 {
+		return apperrors.NewInvalidInputError("cover_days cannot be negative")
+	}
+
+	return nil
+}
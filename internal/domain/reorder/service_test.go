@@ -0,0 +1,225 @@
+package reorder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+var today = time.Date(2025, 3, 31, 15, 0, 0, 0, time.UTC)
+
+type stubProducts struct {
+	product.Service
+
+	products  []product.Product
+	movements []product.StockMovement
+}
+
+func (s *stubProducts) GetAllProducts(context.Context, product.Filter) ([]product.Product, error) {
+	return s.products, nil
+}
+
+func (s *stubProducts) GetProductByID(_ context.Context, id string) (*product.Product, error) {
+	for i := range s.products {
+		if s.products[i].ID.String() == id {
+			return &s.products[i], nil
+		}
+	}
+	return nil, apperrors.NewProductNotFoundError(id)
+}
+
+func (s *stubProducts) ListMovements(_ context.Context, filter product.MovementFilter) ([]product.StockMovement, error) {
+	var out []product.StockMovement
+	for _, m := range s.movements {
+		if !m.OccurredAt.Before(filter.Since) {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+type stubOrders struct {
+	purchaseorder.Service
+
+	open    []purchaseorder.PurchaseOrder
+	created []purchaseorder.PurchaseOrder
+}
+
+func (s *stubOrders) GetAllPurchaseOrders(_ context.Context, filter purchaseorder.Filter) ([]purchaseorder.PurchaseOrder, error) {
+	var out []purchaseorder.PurchaseOrder
+	for _, po := range s.open {
+		if po.Status == filter.Status {
+			out = append(out, po)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubOrders) CreatePurchaseOrder(_ context.Context, po *purchaseorder.PurchaseOrder) error {
+	po.Status = purchaseorder.StatusDraft
+	s.created = append(s.created, *po)
+	return nil
+}
+
+type stubSuppliers struct {
+	supplier.Service
+
+	suppliers []supplier.Supplier
+}
+
+func (s stubSuppliers) GetAllSuppliers(context.Context) ([]supplier.Supplier, error) {
+	return s.suppliers, nil
+}
+
+// decrements records quantity units sold on each of the last days days.
+func decrements(productID uuid.UUID, days, quantity int) []product.StockMovement {
+	var out []product.StockMovement
+	for d := range days {
+		out = append(out, product.StockMovement{
+			ProductID:  productID,
+			Quantity:   -quantity,
+			Reason:     product.ReasonDecrement,
+			OccurredAt: today.AddDate(0, 0, -d),
+		})
+	}
+	return out
+}
+
+func TestSuggest(t *testing.T) {
+	params := DefaultParams()
+
+	t.Run("steady demand needs no safety stock", func(t *testing.T) {
+		p := product.Product{StockQuantity: 20}
+		series := []float64{5, 5, 5, 5}
+
+		s := suggest(p, series, 3, 0, params)
+		if s.SafetyStock != 0 || s.ReorderPoint != 15 || s.NeedsReorder {
+			t.Fatalf("unexpected suggestion: %+v", s)
+		}
+	})
+
+	t.Run("variable demand adds safety stock", func(t *testing.T) {
+		p := product.Product{StockQuantity: 10}
+		series := []float64{0, 10, 0, 10}
+
+		// σ = 5, z(95%) ≈ 1.645, √4 = 2 → ceil(16.45) = 17; reorder point = 5×4 + 17
+		s := suggest(p, series, 4, 0, params)
+		if s.SafetyStock != 17 || s.ReorderPoint != 37 || !s.NeedsReorder {
+			t.Fatalf("unexpected suggestion: %+v", s)
+		}
+		// Target 5 × (4 + 14) + 17 = 107, less the 10 on hand
+		if s.SuggestedQuantity != 97 {
+			t.Fatalf("expected 97 to order, got %d", s.SuggestedQuantity)
+		}
+	})
+
+	t.Run("stock on order counts toward the position", func(t *testing.T) {
+		p := product.Product{StockQuantity: 10}
+		s := suggest(p, []float64{5, 5}, 3, 10, params)
+		if s.NeedsReorder {
+			t.Fatalf("expected no reorder with 20 in position, got %+v", s)
+		}
+	})
+
+	t.Run("the low-stock threshold is a floor", func(t *testing.T) {
+		p := product.Product{StockQuantity: 4, LowStockThresold: 5}
+		s := suggest(p, []float64{0, 0}, 7, 0, params)
+		if s.ReorderPoint != 5 || !s.NeedsReorder || s.SuggestedQuantity != 2 {
+			t.Fatalf("unexpected suggestion: %+v", s)
+		}
+	})
+}
+
+func TestService_GetSuggestions(t *testing.T) {
+	supplierID := uuid.New()
+	fast, slow := product.Product{Name: "Fast", StockQuantity: 30, SupplierID: &supplierID}, product.Product{Name: "Slow", StockQuantity: 100}
+	fast.ID, slow.ID = uuid.New(), uuid.New()
+
+	products := &stubProducts{
+		products: []product.Product{fast, slow},
+		// 10 a day over the whole window, plus an old sale outside it and an adjustment
+		movements: append(decrements(fast.ID, 30, 10),
+			product.StockMovement{ProductID: fast.ID, Quantity: -500, Reason: product.ReasonDecrement, OccurredAt: today.AddDate(0, -3, 0)},
+			product.StockMovement{ProductID: fast.ID, Quantity: -50, Reason: product.ReasonAdjustment, OccurredAt: today},
+		),
+	}
+	orders := &stubOrders{}
+	suppliers := stubSuppliers{suppliers: []supplier.Supplier{{LeadTimeDays: 5}}}
+	suppliers.suppliers[0].ID = supplierID
+
+	svc := NewService(products, orders, suppliers).(*service)
+	svc.now = func() time.Time { return today }
+	ctx := context.Background()
+
+	t.Run("suggests products below their reorder point", func(t *testing.T) {
+		suggestions, err := svc.GetSuggestions(ctx, DefaultParams())
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if len(suggestions) != 1 || suggestions[0].ProductID != fast.ID {
+			t.Fatalf("expected only the fast mover, got %+v", suggestions)
+		}
+
+		s := suggestions[0]
+		if s.AverageDailyConsumption != 10 || s.LeadTimeDays != 5 || s.ReorderPoint != 50 || s.SuggestedQuantity != 160 {
+			t.Fatalf("unexpected suggestion: %+v", s)
+		}
+	})
+
+	t.Run("all includes products that are fine", func(t *testing.T) {
+		params := DefaultParams()
+		params.All = true
+
+		suggestions, _ := svc.GetSuggestions(ctx, params)
+		if len(suggestions) != 2 {
+			t.Fatalf("expected 2 suggestions, got %d", len(suggestions))
+		}
+	})
+
+	t.Run("generates one draft per supplier and skips the rest", func(t *testing.T) {
+		products.products[1].StockQuantity = 0
+
+		result, err := svc.CreateDraftOrders(ctx, DefaultParams())
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if len(result.PurchaseOrders) != 1 || result.PurchaseOrders[0].SupplierID != supplierID {
+			t.Fatalf("expected one order to the supplier, got %+v", result.PurchaseOrders)
+		}
+		if line := result.PurchaseOrders[0].Lines[0]; line.ProductID != fast.ID || line.QuantityOrdered != 160 {
+			t.Fatalf("unexpected line: %+v", line)
+		}
+		if len(result.Skipped) != 1 || result.Skipped[0].ProductID != slow.ID {
+			t.Fatalf("expected the slow mover to be skipped, got %+v", result.Skipped)
+		}
+	})
+
+	t.Run("open orders are not ordered again", func(t *testing.T) {
+		orders.open = orders.created
+
+		suggestions, _ := svc.GetSuggestions(ctx, DefaultParams())
+		for _, s := range suggestions {
+			if s.ProductID == fast.ID {
+				t.Fatalf("expected the fast mover to be covered by the draft, got %+v", s)
+			}
+		}
+	})
+
+	t.Run("error on invalid service level", func(t *testing.T) {
+		params := DefaultParams()
+		params.ServiceLevel = 1
+
+		_, err := svc.GetSuggestions(ctx, params)
+		if appErr, ok := err.(*apperrors.AppError); !ok || appErr.Code != apperrors.InvalidInput {
+			t.Fatalf("expected INVALID_INPUT, got %v", err)
+		}
+	})
+}
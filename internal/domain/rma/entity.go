@@ -0,0 +1,82 @@
+package rma
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+)
+
+// ReferenceType identifies returns as the reference of stock movements.
+const ReferenceType = "return"
+
+// Status is the stage of a return. Returns stay open while their lines are inspected and
+// complete once every line has a disposition.
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Disposition is what happens to the returned units of a line after inspection.
+type Disposition string
+
+const (
+	DispositionPending    Disposition = "pending"
+	DispositionRestock    Disposition = "restock"
+	DispositionQuarantine Disposition = "quarantine"
+	DispositionScrap      Disposition = "scrap"
+)
+
+// Return is goods coming back from a customer, optionally against a sales order.
+type Return struct {
+	model.BaseModel
+	Customer     string     `json:"customer" gorm:"not null" validate:"required"`
+	SalesOrderID *uuid.UUID `json:"sales_order_id,omitempty" gorm:"type:uuid;index" doc:"Sales order the goods were shipped on; lines are checked against what it fulfilled"`
+	Status       Status     `json:"status" gorm:"not null;index" enum:"open,completed,cancelled" openapi:"readonly"`
+	Reason       string     `json:"reason" doc:"Why the customer returned the goods"`
+	Note         string     `json:"note"`
+	Lines        []Line     `json:"lines" gorm:"foreignKey:ReturnID" validate:"required"`
+}
+
+// Line is the quantity of one product, or one of its variants, coming back.
+type Line struct {
+	model.BaseModel
+	ReturnID    uuid.UUID   `json:"return_id" gorm:"type:uuid;not null;index" openapi:"readonly"`
+	ProductID   uuid.UUID   `json:"product_id" gorm:"type:uuid;not null;index" validate:"required"`
+	VariantID   *uuid.UUID  `json:"variant_id,omitempty" gorm:"type:uuid" doc:"Required for products with variants"`
+	Quantity    int         `json:"quantity" gorm:"not null" validate:"required,min=1" doc:"Units returned, kept in the product's base unit"`
+	Unit        string      `json:"unit,omitempty" gorm:"-" doc:"Unit quantity is given in, converted to the base unit; defaults to the base unit"`
+	Disposition Disposition `json:"disposition" gorm:"not null;default:pending" enum:"pending,restock,quarantine,scrap" openapi:"readonly"`
+	InspectedBy string      `json:"inspected_by,omitempty" openapi:"readonly"`
+	InspectedAt *time.Time  `json:"inspected_at,omitempty" openapi:"readonly"`
+	Note        string      `json:"note,omitempty" openapi:"readonly" doc:"Inspector's findings"`
+}
+
+func (Line) TableName() string {
+	return "return_lines"
+}
+
+// Inspection records the disposition of some or all of a return's lines.
+type Inspection struct {
+	InspectedBy string           `json:"inspected_by" validate:"required"`
+	Lines       []InspectionLine `json:"lines" validate:"required"`
+}
+
+// InspectionLine is the disposition chosen for one line. A line is inspected once.
+type InspectionLine struct {
+	LineID      uuid.UUID   `json:"line_id" validate:"required"`
+	Disposition Disposition `json:"disposition" validate:"required" enum:"restock,quarantine,scrap"`
+	LotNumber   string      `json:"lot_number,omitempty" doc:"Lot the units came from; required for lot-tracked products"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty" doc:"Expiry date of the lot, if it no longer exists"`
+	Serials     []string    `json:"serials,omitempty" doc:"Serial numbers returned; required for serialized products, one per unit"`
+	Note        string      `json:"note"`
+}
+
+// Filter narrows a return listing. The zero value matches every return.
+type Filter struct {
+	SalesOrderID string
+	Status       Status
+}
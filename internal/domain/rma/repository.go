@@ -0,0 +1,19 @@
+package rma
+
+import "context"
+
+type Repository interface {
+	// Create inserts the return together with its lines.
+	Create(context.Context, *Return) error
+	GetAll(context.Context, Filter) ([]Return, error)
+	GetByID(context.Context, string) (*Return, error)
+
+	// Lock locks the return row until the surrounding transaction ends.
+	Lock(ctx context.Context, id string) error
+	// LockSalesOrder locks the row of the sales order returns are made against until the
+	// surrounding transaction ends.
+	LockSalesOrder(ctx context.Context, salesOrderID string) error
+	UpdateStatus(ctx context.Context, id string, status Status) error
+	// UpdateLineDisposition saves the line's disposition and inspection details.
+	UpdateLineDisposition(context.Context, *Line) error
+}
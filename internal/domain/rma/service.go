@@ -0,0 +1,403 @@
+package rma
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	// CreateReturn opens a return. Returns against a sales order cannot bring back more
+	// of a product than the order fulfilled, less what earlier returns brought back.
+	CreateReturn(context.Context, *Return) error
+	GetAllReturns(context.Context, Filter) ([]Return, error)
+	GetReturnByID(context.Context, string) (*Return, error)
+
+	// InspectReturn records the disposition of the given lines and posts them to the stock ledger.
+	InspectReturn(ctx context.Context, id string, inspection Inspection) error
+	CancelReturn(ctx context.Context, id string) error
+	GetDispositionMovements(ctx context.Context, id string) ([]product.StockMovement, error)
+}
+
+// Transactor runs fn inside one database transaction, passing a Repository and a
+// product.Service that both take part in it.
+type Transactor func(ctx context.Context, fn func(Repository, product.Service) error) error
+
+type service struct {
+	repo     Repository
+	products product.Service
+	orders   salesorder.Service
+	tx       Transactor
+}
+
+func NewService(repo Repository, products product.Service, orders salesorder.Service, tx Transactor) Service {
+	return &service{
+		repo:     repo,
+		products: products,
+		orders:   orders,
+		tx:       tx,
+	}
+}
+
+// CreateReturn implements Service. Every line starts pending inspection. A return against
+// a sales order holds the order's lock while it is checked and created, so concurrent
+// returns cannot together bring back more than the order fulfilled.
+func (s *service) CreateReturn(ctx context.Context, r *Return) error {
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		if r.SalesOrderID != nil {
+			if err := lockSalesOrder(ctx, repo, r.SalesOrderID.String()); err != nil {
+				return err
+			}
+		}
+
+		if err := s.validate(ctx, repo, products, r); err != nil {
+			return err
+		}
+
+		r.Status = StatusOpen
+
+		if err := repo.Create(ctx, r); err != nil {
+			return apperrors.NewDatabaseError("failed to create return: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// GetAllReturns implements Service.
+func (s *service) GetAllReturns(ctx context.Context, filter Filter) ([]Return, error) {
+	returns, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve returns: " + err.Error())
+	}
+
+	return returns, nil
+}
+
+// GetReturnByID implements Service.
+func (s *service) GetReturnByID(ctx context.Context, id string) (*Return, error) {
+	return getReturn(ctx, s.repo, id)
+}
+
+// InspectReturn implements Service. Every line is posted as a `return` movement. Restocked
+// units stay available; quarantined units then move to the quarantined bucket with a
+// `quarantine` movement, and scrapped units are written off with a `scrap` movement, so
+// neither increases the available stock. The movements, dispositions and status change
+// commit together.
+func (s *service) InspectReturn(ctx context.Context, id string, inspection Inspection) error {
+	if inspection.InspectedBy == "" {
+		return apperrors.NewMissingRequiredDataError("inspected_by")
+	}
+
+	if len(inspection.Lines) == 0 {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		r, err := lockReturn(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if r.Status != StatusOpen {
+			return apperrors.NewBusinessLogicError("only open returns can be inspected")
+		}
+
+		lines := make(map[string]*Line, len(r.Lines))
+		for i := range r.Lines {
+			lines[r.Lines[i].ID.String()] = &r.Lines[i]
+		}
+
+		now := time.Now().UTC()
+		changes := make([]product.StockChange, 0, len(inspection.Lines))
+		var quarantined []product.StockChange
+		for _, il := range inspection.Lines {
+			line, ok := lines[il.LineID.String()]
+			if !ok {
+				return apperrors.NewInvalidInputError("line " + il.LineID.String() + " is not on this return")
+			}
+			if line.Disposition != DispositionPending {
+				return apperrors.NewBusinessLogicError("line " + line.ID.String() + " has already been inspected")
+			}
+
+			if !validDisposition(il.Disposition) {
+				return apperrors.NewInvalidInputError("disposition must be restock, quarantine or scrap")
+			}
+
+			line.Disposition = il.Disposition
+			line.InspectedBy = inspection.InspectedBy
+			line.InspectedAt = &now
+			line.Note = il.Note
+			if err := repo.UpdateLineDisposition(ctx, line); err != nil {
+				return apperrors.NewDatabaseError("failed to update return line: " + err.Error())
+			}
+
+			in := product.StockChange{
+				ProductID:     line.ProductID.String(),
+				Quantity:      line.Quantity,
+				Reason:        product.ReasonReturn,
+				LotNumber:     il.LotNumber,
+				ExpiresAt:     il.ExpiresAt,
+				Serials:       il.Serials,
+				ReferenceType: ReferenceType,
+				ReferenceID:   r.ID.String(),
+				Note:          il.Note,
+			}
+			if line.VariantID != nil {
+				in.VariantID = line.VariantID.String()
+			}
+			changes = append(changes, in)
+
+			switch il.Disposition {
+			case DispositionQuarantine:
+				quarantined = append(quarantined, in)
+			case DispositionScrap:
+				out := in
+				out.Quantity = -line.Quantity
+				out.Reason = product.ReasonScrap
+				out.ExpiresAt = nil
+				changes = append(changes, out)
+			}
+		}
+
+		if _, err := products.ApplyStockChanges(ctx, changes...); err != nil {
+			return err
+		}
+
+		for _, in := range quarantined {
+			err := products.MoveStock(ctx, in.ProductID, product.BucketMove{
+				VariantID:     in.VariantID,
+				From:          product.BucketAvailable,
+				To:            product.BucketQuarantined,
+				Quantity:      in.Quantity,
+				LotNumber:     in.LotNumber,
+				Serials:       in.Serials,
+				Note:          in.Note,
+				Reason:        product.ReasonQuarantine,
+				ReferenceType: ReferenceType,
+				ReferenceID:   r.ID.String(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !fullyInspected(r.Lines) {
+			return nil
+		}
+
+		if err := repo.UpdateStatus(ctx, id, StatusCompleted); err != nil {
+			return apperrors.NewDatabaseError("failed to update return status: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// CancelReturn implements Service. Returns with inspected lines have already moved stock
+// and cannot be cancelled.
+func (s *service) CancelReturn(ctx context.Context, id string) error {
+	return s.tx(ctx, func(repo Repository, _ product.Service) error {
+		r, err := lockReturn(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if r.Status != StatusOpen {
+			return apperrors.NewBusinessLogicError("only open returns can be cancelled")
+		}
+
+		for _, line := range r.Lines {
+			if line.Disposition != DispositionPending {
+				return apperrors.NewBusinessLogicError("returns with inspected lines cannot be cancelled")
+			}
+		}
+
+		if err := repo.UpdateStatus(ctx, id, StatusCancelled); err != nil {
+			return apperrors.NewDatabaseError("failed to update return status: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// GetDispositionMovements implements Service.
+func (s *service) GetDispositionMovements(ctx context.Context, id string) ([]product.StockMovement, error) {
+	r, err := s.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.products.ListMovements(ctx, product.MovementFilter{
+		ReferenceType: ReferenceType,
+		ReferenceID:   r.ID.String(),
+	})
+}
+
+// validate checks the customer and every line, and for returns against a sales order
+// that no line brings back more than is still returnable.
+func (s *service) validate(ctx context.Context, repo Repository, products product.Service, r *Return) error {
+	if r.Customer == "" {
+		return apperrors.NewMissingRequiredDataError("customer")
+	}
+
+	if len(r.Lines) == 0 {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	var returnable map[string]int
+	if r.SalesOrderID != nil {
+		var err error
+		if returnable, err = s.returnable(ctx, repo, r.SalesOrderID.String()); err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[string]bool, len(r.Lines))
+	for i := range r.Lines {
+		line := &r.Lines[i]
+
+		if line.Quantity <= 0 {
+			return apperrors.NewInvalidInputError("returned quantity must be greater than 0")
+		}
+
+		p, err := products.GetProductByID(ctx, line.ProductID.String())
+		if err != nil {
+			return err
+		}
+
+		if err := p.CheckVariant(line.VariantID); err != nil {
+			return err
+		}
+
+		if line.Unit != "" {
+			if line.Quantity, err = p.ToBaseUnits(quantity.FromInt(line.Quantity), line.Unit); err != nil {
+				return err
+			}
+			line.Unit = ""
+		}
+
+		key := lineKey(line.ProductID.String(), line.VariantID)
+		if seen[key] {
+			return apperrors.NewInvalidInputError("product " + p.ID.String() + " is returned on more than one line")
+		}
+		seen[key] = true
+
+		if returnable != nil && line.Quantity > returnable[key] {
+			return apperrors.NewBusinessLogicError("only " + strconv.Itoa(returnable[key]) + " units of product " + p.ID.String() + " can be returned against this order")
+		}
+
+		line.Disposition = DispositionPending
+		line.InspectedBy = ""
+		line.InspectedAt = nil
+		line.Note = ""
+	}
+
+	return nil
+}
+
+// returnable works out, per product or variant, how many units the sales order fulfilled
+// that no earlier return has brought back.
+func (s *service) returnable(ctx context.Context, repo Repository, salesOrderID string) (map[string]int, error) {
+	so, err := s.orders.GetSalesOrderByID(ctx, salesOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	returnable := make(map[string]int, len(so.Lines))
+	for _, line := range so.Lines {
+		returnable[lineKey(line.ProductID.String(), line.VariantID)] += line.QuantityFulfilled
+	}
+
+	earlier, err := repo.GetAll(ctx, Filter{SalesOrderID: so.ID.String()})
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve returns: " + err.Error())
+	}
+
+	for _, r := range earlier {
+		if r.Status == StatusCancelled {
+			continue
+		}
+		for _, line := range r.Lines {
+			returnable[lineKey(line.ProductID.String(), line.VariantID)] -= line.Quantity
+		}
+	}
+
+	return returnable, nil
+}
+
+func validDisposition(d Disposition) bool {
+	switch d {
+	case DispositionRestock, DispositionQuarantine, DispositionScrap:
+		return true
+	}
+	return false
+}
+
+func fullyInspected(lines []Line) bool {
+	for _, line := range lines {
+		if line.Disposition == DispositionPending {
+			return false
+		}
+	}
+	return true
+}
+
+func lineKey(productID string, variantID *uuid.UUID) string {
+	if variantID == nil {
+		return productID
+	}
+	return productID + "/" + variantID.String()
+}
+
+func getReturn(ctx context.Context, repo Repository, id string) (*Return, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	r, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewReturnNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve return: " + err.Error())
+	}
+
+	return r, nil
+}
+
+// lockReturn locks the return row for the rest of the transaction and returns the return.
+func lockReturn(ctx context.Context, repo Repository, id string) (*Return, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	if err := repo.Lock(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewReturnNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to lock return: " + err.Error())
+	}
+
+	return getReturn(ctx, repo, id)
+}
+
+// lockSalesOrder locks the sales order a return is made against.
+func lockSalesOrder(ctx context.Context, repo Repository, id string) error {
+	if err := repo.LockSalesOrder(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewSalesOrderNotFoundError(id)
+		}
+		return apperrors.NewDatabaseError("failed to lock sales order: " + err.Error())
+	}
+
+	return nil
+}
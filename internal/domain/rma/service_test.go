@@ -0,0 +1,354 @@
+package rma
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+type mockRepo struct {
+	returns map[string]*Return
+	// lockedOrders are the sales orders locked inside a transaction
+	lockedOrders  []string
+	inTransaction bool
+}
+
+func newMockRepo() *mockRepo {
+	return &mockRepo{returns: make(map[string]*Return)}
+}
+
+func (m *mockRepo) Create(_ context.Context, r *Return) error {
+	r.ID = uuid.New()
+	for i := range r.Lines {
+		r.Lines[i].ID = uuid.New()
+		r.Lines[i].ReturnID = r.ID
+	}
+	stored := *r
+	stored.Lines = append([]Line(nil), r.Lines...)
+	m.returns[r.ID.String()] = &stored
+	return nil
+}
+
+func (m *mockRepo) GetAll(_ context.Context, filter Filter) ([]Return, error) {
+	out := make([]Return, 0, len(m.returns))
+	for _, r := range m.returns {
+		if filter.SalesOrderID != "" && (r.SalesOrderID == nil || r.SalesOrderID.String() != filter.SalesOrderID) {
+			continue
+		}
+		out = append(out, *r)
+	}
+	return out, nil
+}
+
+func (m *mockRepo) GetByID(_ context.Context, id string) (*Return, error) {
+	r, ok := m.returns[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *r
+	copied.Lines = append([]Line(nil), r.Lines...)
+	return &copied, nil
+}
+
+func (m *mockRepo) Lock(_ context.Context, id string) error {
+	if _, ok := m.returns[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (m *mockRepo) LockSalesOrder(_ context.Context, salesOrderID string) error {
+	if m.inTransaction {
+		m.lockedOrders = append(m.lockedOrders, salesOrderID)
+	}
+	return nil
+}
+
+func (m *mockRepo) UpdateStatus(_ context.Context, id string, status Status) error {
+	m.returns[id].Status = status
+	return nil
+}
+
+func (m *mockRepo) UpdateLineDisposition(_ context.Context, line *Line) error {
+	r := m.returns[line.ReturnID.String()]
+	for i := range r.Lines {
+		if r.Lines[i].ID == line.ID {
+			r.Lines[i] = *line
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// stubOrders serves canned sales orders.
+type stubOrders struct {
+	salesorder.Service
+
+	orders map[string]*salesorder.SalesOrder
+}
+
+func (s *stubOrders) GetSalesOrderByID(_ context.Context, id string) (*salesorder.SalesOrder, error) {
+	so, ok := s.orders[id]
+	if !ok {
+		return nil, apperrors.NewSalesOrderNotFoundError(id)
+	}
+	return so, nil
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+// fixture posts dispositions through a real product service over the product package's
+// in-memory repository, so inspections are held to the ledger's rules.
+type fixture struct {
+	svc      Service
+	repo     *mockRepo
+	products product.Service
+	widget   *product.Product
+	order    *salesorder.SalesOrder
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{repo: newMockRepo(), products: product.NewService(product.NewMemoryRepository())}
+
+	f.widget = f.create(t, &product.Product{Name: "Widget", StockQuantity: 10})
+
+	f.order = &salesorder.SalesOrder{Customer: "Acme", Lines: []salesorder.Line{{ProductID: f.widget.ID, QuantityOrdered: 5, QuantityFulfilled: 4}}}
+	f.order.ID = uuid.New()
+	orders := &stubOrders{orders: map[string]*salesorder.SalesOrder{f.order.ID.String(): f.order}}
+
+	tx := func(_ context.Context, fn func(Repository, product.Service) error) error {
+		f.repo.inTransaction = true
+		defer func() { f.repo.inTransaction = false }()
+		return fn(f.repo, f.products)
+	}
+	f.svc = NewService(f.repo, f.products, orders, tx)
+
+	return f
+}
+
+// create adds the product to the product service.
+func (f *fixture) create(t *testing.T, p *product.Product) *product.Product {
+	t.Helper()
+
+	assertNoError(t, f.products.CreateProduct(context.Background(), p))
+	return p
+}
+
+// movements returns the stock movements posted for the return.
+func (f *fixture) movements(t *testing.T, id string) []product.StockMovement {
+	t.Helper()
+
+	movements, err := f.products.ListMovements(context.Background(), product.MovementFilter{ReferenceType: ReferenceType, ReferenceID: id})
+	assertNoError(t, err)
+	return movements
+}
+
+func TestService_CreateReturn(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	against := func(quantity int) *Return {
+		return &Return{Customer: "Acme", SalesOrderID: &f.order.ID, Lines: []Line{{ProductID: f.widget.ID, Quantity: quantity}}}
+	}
+
+	t.Run("opens a return with every line pending", func(t *testing.T) {
+		r := against(3)
+		assertNoError(t, f.svc.CreateReturn(ctx, r))
+
+		if r.Status != StatusOpen || r.Lines[0].Disposition != DispositionPending {
+			t.Fatalf("expected an open return with a pending line, got %s with %s", r.Status, r.Lines[0].Disposition)
+		}
+		if !slices.Equal(f.repo.lockedOrders, []string{f.order.ID.String()}) {
+			t.Fatalf("expected the sales order locked in the creating transaction, got %v", f.repo.lockedOrders)
+		}
+	})
+
+	t.Run("error returning more than the order has left to return", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CreateReturn(ctx, against(2)), apperrors.BusinessLogicError)
+	})
+
+	t.Run("error returning a product the order did not ship", func(t *testing.T) {
+		other := f.create(t, &product.Product{Name: "Gadget"})
+
+		r := &Return{Customer: "Acme", SalesOrderID: &f.order.ID, Lines: []Line{{ProductID: other.ID, Quantity: 1}}}
+		assertAppErrorCode(t, f.svc.CreateReturn(ctx, r), apperrors.BusinessLogicError)
+	})
+
+	t.Run("returns without an order are not limited", func(t *testing.T) {
+		r := &Return{Customer: "Walk-in", Lines: []Line{{ProductID: f.widget.ID, Quantity: 20}}}
+		assertNoError(t, f.svc.CreateReturn(ctx, r))
+	})
+
+	t.Run("error on unknown order", func(t *testing.T) {
+		missing := uuid.New()
+		r := &Return{Customer: "Acme", SalesOrderID: &missing, Lines: []Line{{ProductID: f.widget.ID, Quantity: 1}}}
+		assertAppErrorCode(t, f.svc.CreateReturn(ctx, r), apperrors.SalesOrderNotFound)
+	})
+
+	t.Run("error without customer", func(t *testing.T) {
+		r := &Return{Lines: []Line{{ProductID: f.widget.ID, Quantity: 1}}}
+		assertAppErrorCode(t, f.svc.CreateReturn(ctx, r), apperrors.MissingRequiredData)
+	})
+}
+
+func TestService_ReturnUnits(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	assertNoError(t, f.products.SetUnits(ctx, f.widget.ID.String(), []product.UnitOfMeasure{{Name: "pair", Factor: "2"}}))
+
+	against := func(pairs int) *Return {
+		return &Return{Customer: "Acme", SalesOrderID: &f.order.ID, Lines: []Line{{ProductID: f.widget.ID, Quantity: pairs, Unit: "pair"}}}
+	}
+
+	t.Run("error returning more than the order shipped in base units", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CreateReturn(ctx, against(3)), apperrors.BusinessLogicError)
+	})
+
+	t.Run("lines are kept in base units", func(t *testing.T) {
+		r := against(2)
+		assertNoError(t, f.svc.CreateReturn(ctx, r))
+
+		if line := r.Lines[0]; line.Quantity != 4 || line.Unit != "" {
+			t.Fatalf("expected 4 widgets returned in the base unit, got %d %s", line.Quantity, line.Unit)
+		}
+	})
+}
+
+func TestService_InspectReturn(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	gadget := f.create(t, &product.Product{Name: "Gadget"})
+
+	r := &Return{Customer: "Acme", Lines: []Line{
+		{ProductID: f.widget.ID, Quantity: 2},
+		{ProductID: gadget.ID, Quantity: 1},
+	}}
+	assertNoError(t, f.svc.CreateReturn(ctx, r))
+	id := r.ID.String()
+	widgetLine, gadgetLine := r.Lines[0].ID, r.Lines[1].ID
+
+	t.Run("error on an unknown disposition", func(t *testing.T) {
+		err := f.svc.InspectReturn(ctx, id, Inspection{InspectedBy: "carol", Lines: []InspectionLine{{LineID: widgetLine, Disposition: "resell"}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("restocked units increase the stock", func(t *testing.T) {
+		err := f.svc.InspectReturn(ctx, id, Inspection{InspectedBy: "carol", Lines: []InspectionLine{{LineID: widgetLine, Disposition: DispositionRestock, Note: "unopened"}}})
+		assertNoError(t, err)
+
+		if f.widget.StockQuantity != 12 {
+			t.Fatalf("expected stock 12, got %d", f.widget.StockQuantity)
+		}
+		movements := f.movements(t, id)
+		if len(movements) != 1 {
+			t.Fatalf("expected one movement, got %+v", movements)
+		}
+		if m := movements[0]; m.Quantity != 2 || m.Reason != product.ReasonReturn || m.Note != "unopened" {
+			t.Fatalf("unexpected return movement: %+v", m)
+		}
+		if got := f.repo.returns[id]; got.Status != StatusOpen || got.Lines[0].InspectedBy != "carol" {
+			t.Fatalf("expected the return to stay open with the line inspected, got %+v", got)
+		}
+	})
+
+	t.Run("error inspecting a line twice", func(t *testing.T) {
+		err := f.svc.InspectReturn(ctx, id, Inspection{InspectedBy: "carol", Lines: []InspectionLine{{LineID: widgetLine, Disposition: DispositionScrap}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error cancelling a partly inspected return", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CancelReturn(ctx, id), apperrors.BusinessLogicError)
+	})
+
+	t.Run("scrapped units are recorded without increasing the stock", func(t *testing.T) {
+		err := f.svc.InspectReturn(ctx, id, Inspection{InspectedBy: "carol", Lines: []InspectionLine{{LineID: gadgetLine, Disposition: DispositionScrap}}})
+		assertNoError(t, err)
+
+		if gadget.StockQuantity != 0 {
+			t.Fatalf("expected stock 0, got %d", gadget.StockQuantity)
+		}
+		if got := f.repo.returns[id].Status; got != StatusCompleted {
+			t.Fatalf("expected completed, got %s", got)
+		}
+		movements := f.movements(t, id)
+		if len(movements) != 3 {
+			t.Fatalf("expected 3 movements, got %+v", movements)
+		}
+		in, out := movements[1], movements[2]
+		if in.Quantity != 1 || in.Reason != product.ReasonReturn || out.Quantity != -1 || out.Reason != product.ReasonScrap {
+			t.Fatalf("expected a return and a scrap movement, got %+v and %+v", in, out)
+		}
+	})
+
+	t.Run("quarantined units move to the quarantined bucket", func(t *testing.T) {
+		r := &Return{Customer: "Acme", Lines: []Line{{ProductID: f.widget.ID, Quantity: 1}}}
+		assertNoError(t, f.svc.CreateReturn(ctx, r))
+
+		err := f.svc.InspectReturn(ctx, r.ID.String(), Inspection{InspectedBy: "carol", Lines: []InspectionLine{{LineID: r.Lines[0].ID, Disposition: DispositionQuarantine}}})
+		assertNoError(t, err)
+
+		if f.widget.StockQuantity != 12 {
+			t.Fatalf("expected stock to stay 12, got %d", f.widget.StockQuantity)
+		}
+		movements := f.movements(t, r.ID.String())
+		if len(movements) != 2 {
+			t.Fatalf("expected a return and a bucket move, got %+v", movements)
+		}
+		if move := movements[1]; move.Quantity != -1 || move.Bucket != product.BucketQuarantined || move.Reason != product.ReasonQuarantine {
+			t.Fatalf("unexpected bucket move: %+v", move)
+		}
+		levels, err := f.products.GetStockBuckets(ctx, f.widget.ID.String())
+		assertNoError(t, err)
+		if levels[0].Quarantined != 1 {
+			t.Fatalf("expected 1 widget quarantined, got %+v", levels)
+		}
+		if got := f.repo.returns[r.ID.String()].Status; got != StatusCompleted {
+			t.Fatalf("expected completed, got %s", got)
+		}
+	})
+
+	t.Run("error on unknown return", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CancelReturn(ctx, uuid.NewString()), apperrors.ReturnNotFound)
+	})
+}
+
+func TestService_CancelReturn(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := &Return{Customer: "Acme", SalesOrderID: &f.order.ID, Lines: []Line{{ProductID: f.widget.ID, Quantity: 4}}}
+	assertNoError(t, f.svc.CreateReturn(ctx, r))
+	assertNoError(t, f.svc.CancelReturn(ctx, r.ID.String()))
+
+	// The cancelled return no longer counts against the order
+	again := &Return{Customer: "Acme", SalesOrderID: &f.order.ID, Lines: []Line{{ProductID: f.widget.ID, Quantity: 4}}}
+	assertNoError(t, f.svc.CreateReturn(ctx, again))
+
+	assertAppErrorCode(t, f.svc.CancelReturn(ctx, r.ID.String()), apperrors.BusinessLogicError)
+}
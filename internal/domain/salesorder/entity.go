@@ -0,0 +1,73 @@
+package salesorder
+
+import (
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+)
+
+// ReferenceType identifies sales orders as the reference of stock movements.
+const ReferenceType = "sales_order"
+
+// Status is the stage of a sales order. It follows from the quantities allocated and
+// fulfilled on the lines, except for cancelled orders.
+type Status string
+
+const (
+	StatusPending            Status = "pending"
+	StatusPartiallyAllocated Status = "partially_allocated"
+	StatusAllocated          Status = "allocated"
+	StatusPartiallyFulfilled Status = "partially_fulfilled"
+	StatusFulfilled          Status = "fulfilled"
+	StatusCancelled          Status = "cancelled"
+)
+
+type SalesOrder struct {
+	model.BaseModel
+	Customer  string `json:"customer" gorm:"not null" validate:"required"`
+	Reference string `json:"reference" gorm:"index" doc:"The customer's own order reference"`
+	Status    Status `json:"status" gorm:"not null;index" enum:"pending,partially_allocated,allocated,partially_fulfilled,fulfilled,cancelled" openapi:"readonly"`
+	Currency  string `json:"currency" gorm:"size:3;not null" doc:"ISO 4217 code of the line prices; defaults to the currency of the products"`
+	Note      string `json:"note"`
+	Lines     []Line `json:"lines" gorm:"foreignKey:SalesOrderID" validate:"required"`
+}
+
+// Line is the quantity of one product, or one of its variants, ordered by the customer.
+// Allocated units are reserved for the order until they are fulfilled or the order is
+// cancelled.
+type Line struct {
+	model.BaseModel
+	SalesOrderID      uuid.UUID  `json:"sales_order_id" gorm:"type:uuid;not null;index" openapi:"readonly"`
+	ProductID         uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index" validate:"required"`
+	VariantID         *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid" doc:"Required for products with variants"`
+	QuantityOrdered   int        `json:"quantity_ordered" gorm:"not null" validate:"required,min=1" doc:"Units ordered, kept in the product's base unit"`
+	Unit              string     `json:"unit,omitempty" gorm:"-" doc:"Unit quantity_ordered is given in, converted to the base unit; defaults to the base unit"`
+	QuantityAllocated int        `json:"quantity_allocated" gorm:"not null;default:0" openapi:"readonly" doc:"Units reserved for this line, including those already fulfilled"`
+	QuantityFulfilled int        `json:"quantity_fulfilled" gorm:"not null;default:0" openapi:"readonly"`
+	UnitPrice         int64      `json:"unit_price" gorm:"not null;default:0" validate:"min=0" doc:"Price per base unit in minor units; defaults to the product's sale price"`
+}
+
+func (Line) TableName() string {
+	return "sales_order_lines"
+}
+
+// Fulfilment records units shipped against the lines of a sales order.
+type Fulfilment struct {
+	Lines []FulfilmentLine `json:"lines" validate:"required"`
+	Note  string           `json:"note"`
+}
+
+// FulfilmentLine is the quantity shipped for one order line. It cannot exceed what is
+// allocated to the line and not yet fulfilled.
+type FulfilmentLine struct {
+	LineID    uuid.UUID `json:"line_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1"`
+	Unit      string    `json:"unit,omitempty" doc:"Unit of quantity; defaults to the base unit"`
+	LotNumber string    `json:"lot_number,omitempty" doc:"Lot to ship from; by default lot-tracked products ship first-expired-first-out"`
+	Serials   []string  `json:"serials,omitempty" doc:"Serial numbers shipped; required for serialized products, one per unit"`
+}
+
+// Filter narrows a sales order listing. The zero value matches every order.
+type Filter struct {
+	Customer string
+	Status   Status
+}
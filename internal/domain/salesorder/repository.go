@@ -0,0 +1,16 @@
+package salesorder
+
+import "context"
+
+type Repository interface {
+	// Create inserts the order together with its lines.
+	Create(context.Context, *SalesOrder) error
+	GetAll(context.Context, Filter) ([]SalesOrder, error)
+	GetByID(context.Context, string) (*SalesOrder, error)
+
+	// Lock locks the order row until the surrounding transaction ends.
+	Lock(ctx context.Context, id string) error
+	UpdateStatus(ctx context.Context, id string, status Status) error
+	// UpdateLine saves the line's allocated and fulfilled quantities.
+	UpdateLine(context.Context, *Line) error
+}
@@ -0,0 +1,377 @@
+package salesorder
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreateSalesOrder(context.Context, *SalesOrder) error
+	GetAllSalesOrders(context.Context, Filter) ([]SalesOrder, error)
+	GetSalesOrderByID(context.Context, string) (*SalesOrder, error)
+
+	// AllocateSalesOrder reserves available stock for the outstanding quantity of every
+	// line. Lines that cannot be covered in full stay partially allocated and can be
+	// allocated again once stock arrives.
+	AllocateSalesOrder(ctx context.Context, id string) error
+	// FulfilSalesOrder ships allocated units, posting them as sale movements linked to the order.
+	FulfilSalesOrder(ctx context.Context, id string, fulfilment Fulfilment) error
+	// CancelSalesOrder releases every allocated unit that has not been fulfilled.
+	CancelSalesOrder(ctx context.Context, id string) error
+	GetFulfilmentMovements(ctx context.Context, id string) ([]product.StockMovement, error)
+}
+
+// Transactor runs fn inside one database transaction, passing a Repository and a
+// product.Service that both take part in it.
+type Transactor func(ctx context.Context, fn func(Repository, product.Service) error) error
+
+type service struct {
+	repo     Repository
+	products product.Service
+	tx       Transactor
+}
+
+func NewService(repo Repository, products product.Service, tx Transactor) Service {
+	return &service{
+		repo:     repo,
+		products: products,
+		tx:       tx,
+	}
+}
+
+// CreateSalesOrder implements Service. New orders start pending, with nothing allocated.
+func (s *service) CreateSalesOrder(ctx context.Context, so *SalesOrder) error {
+	if err := s.validate(ctx, so); err != nil {
+		return err
+	}
+
+	so.Status = StatusPending
+
+	if err := s.repo.Create(ctx, so); err != nil {
+		return apperrors.NewDatabaseError("failed to create sales order: " + err.Error())
+	}
+
+	return nil
+}
+
+// GetAllSalesOrders implements Service.
+func (s *service) GetAllSalesOrders(ctx context.Context, filter Filter) ([]SalesOrder, error) {
+	orders, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve sales orders: " + err.Error())
+	}
+
+	return orders, nil
+}
+
+// GetSalesOrderByID implements Service.
+func (s *service) GetSalesOrderByID(ctx context.Context, id string) (*SalesOrder, error) {
+	return getSalesOrder(ctx, s.repo, id)
+}
+
+// AllocateSalesOrder implements Service. The reservations, line quantities and status
+// change commit together.
+func (s *service) AllocateSalesOrder(ctx context.Context, id string) error {
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		so, err := lockSalesOrder(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if so.Status == StatusFulfilled || so.Status == StatusCancelled {
+			return apperrors.NewBusinessLogicError("only open sales orders can be allocated")
+		}
+
+		for i := range so.Lines {
+			line := &so.Lines[i]
+
+			outstanding := line.QuantityOrdered - line.QuantityAllocated
+			if outstanding <= 0 {
+				continue
+			}
+
+			reserved, err := products.ReserveStock(ctx, line.ProductID.String(), variantID(line), outstanding)
+			if err != nil {
+				return err
+			}
+			if reserved == 0 {
+				continue
+			}
+
+			line.QuantityAllocated += reserved
+			if err := updateLine(ctx, repo, line); err != nil {
+				return err
+			}
+		}
+
+		return updateStatus(ctx, repo, so.ID.String(), deriveStatus(so.Lines))
+	})
+}
+
+// FulfilSalesOrder implements Service. Each line releases its reservation for the units
+// shipped and posts them as a sale; the stock changes, line quantities and status change
+// commit together.
+func (s *service) FulfilSalesOrder(ctx context.Context, id string, fulfilment Fulfilment) error {
+	if len(fulfilment.Lines) == 0 {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		so, err := lockSalesOrder(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if so.Status == StatusFulfilled || so.Status == StatusCancelled {
+			return apperrors.NewBusinessLogicError("only open sales orders can be fulfilled")
+		}
+
+		lines := make(map[string]*Line, len(so.Lines))
+		for i := range so.Lines {
+			lines[so.Lines[i].ID.String()] = &so.Lines[i]
+		}
+
+		changes := make([]product.StockChange, 0, len(fulfilment.Lines))
+		for _, fl := range fulfilment.Lines {
+			line, ok := lines[fl.LineID.String()]
+			if !ok {
+				return apperrors.NewInvalidInputError("line " + fl.LineID.String() + " is not on this sales order")
+			}
+			if fl.Quantity <= 0 {
+				return apperrors.NewInvalidInputError("fulfilled quantity must be greater than 0")
+			}
+			if fl.Unit != "" {
+				if fl.Quantity, err = products.ToBaseUnits(ctx, line.ProductID.String(), quantity.FromInt(fl.Quantity), fl.Unit); err != nil {
+					return err
+				}
+			}
+
+			if allocated := line.QuantityAllocated - line.QuantityFulfilled; fl.Quantity > allocated {
+				return apperrors.NewBusinessLogicError("line " + line.ID.String() + " has only " + strconv.Itoa(allocated) + " allocated units left to fulfil")
+			}
+
+			if err := products.ReleaseStock(ctx, line.ProductID.String(), variantID(line), fl.Quantity); err != nil {
+				return err
+			}
+
+			line.QuantityFulfilled += fl.Quantity
+			if err := updateLine(ctx, repo, line); err != nil {
+				return err
+			}
+
+			changes = append(changes, product.StockChange{
+				ProductID:     line.ProductID.String(),
+				VariantID:     variantID(line),
+				Quantity:      -fl.Quantity,
+				Reason:        product.ReasonSale,
+				LotNumber:     fl.LotNumber,
+				Serials:       fl.Serials,
+				ReferenceType: ReferenceType,
+				ReferenceID:   so.ID.String(),
+				Note:          fulfilment.Note,
+			})
+		}
+
+		if _, err := products.ApplyStockChanges(ctx, changes...); err != nil {
+			return err
+		}
+
+		return updateStatus(ctx, repo, so.ID.String(), deriveStatus(so.Lines))
+	})
+}
+
+// CancelSalesOrder implements Service. Units already fulfilled stay shipped; returning
+// them is a separate flow.
+func (s *service) CancelSalesOrder(ctx context.Context, id string) error {
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		so, err := lockSalesOrder(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if so.Status == StatusFulfilled || so.Status == StatusCancelled {
+			return apperrors.NewBusinessLogicError("only open sales orders can be cancelled")
+		}
+
+		for i := range so.Lines {
+			line := &so.Lines[i]
+
+			unfulfilled := line.QuantityAllocated - line.QuantityFulfilled
+			if unfulfilled <= 0 {
+				continue
+			}
+
+			if err := products.ReleaseStock(ctx, line.ProductID.String(), variantID(line), unfulfilled); err != nil {
+				return err
+			}
+
+			line.QuantityAllocated = line.QuantityFulfilled
+			if err := updateLine(ctx, repo, line); err != nil {
+				return err
+			}
+		}
+
+		return updateStatus(ctx, repo, so.ID.String(), StatusCancelled)
+	})
+}
+
+// GetFulfilmentMovements implements Service.
+func (s *service) GetFulfilmentMovements(ctx context.Context, id string) ([]product.StockMovement, error) {
+	so, err := s.GetSalesOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.products.ListMovements(ctx, product.MovementFilter{
+		ReferenceType: ReferenceType,
+		ReferenceID:   so.ID.String(),
+	})
+}
+
+// validate checks the customer and every line, defaulting the currency and line prices
+// from the products ordered.
+func (s *service) validate(ctx context.Context, so *SalesOrder) error {
+	if so.Customer == "" {
+		return apperrors.NewMissingRequiredDataError("customer")
+	}
+
+	if len(so.Lines) == 0 {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	seen := make(map[string]bool, len(so.Lines))
+	for i := range so.Lines {
+		line := &so.Lines[i]
+
+		if line.QuantityOrdered <= 0 {
+			return apperrors.NewInvalidInputError("ordered quantity must be greater than 0")
+		}
+		if line.UnitPrice < 0 {
+			return apperrors.NewInvalidInputError("unit price cannot be negative")
+		}
+
+		p, err := s.products.GetProductByID(ctx, line.ProductID.String())
+		if err != nil {
+			return err
+		}
+
+		if err := p.CheckVariant(line.VariantID); err != nil {
+			return err
+		}
+
+		if line.Unit != "" {
+			if line.QuantityOrdered, err = p.ToBaseUnits(quantity.FromInt(line.QuantityOrdered), line.Unit); err != nil {
+				return err
+			}
+			line.Unit = ""
+		}
+
+		key := line.ProductID.String() + "/" + variantID(line)
+		if seen[key] {
+			return apperrors.NewInvalidInputError("product " + p.ID.String() + " is ordered on more than one line")
+		}
+		seen[key] = true
+
+		if so.Currency == "" {
+			so.Currency = p.Currency
+		}
+		if p.Currency != so.Currency {
+			return apperrors.NewInvalidInputError("product " + p.ID.String() + " is priced in " + p.Currency + ", not " + so.Currency)
+		}
+
+		if line.UnitPrice == 0 {
+			line.UnitPrice = p.SalePrice
+		}
+		line.QuantityAllocated = 0
+		line.QuantityFulfilled = 0
+	}
+
+	return nil
+}
+
+// deriveStatus works out the status of an open order from its line quantities.
+func deriveStatus(lines []Line) Status {
+	allAllocated, allFulfilled := true, true
+	anyAllocated, anyFulfilled := false, false
+
+	for _, line := range lines {
+		allAllocated = allAllocated && line.QuantityAllocated >= line.QuantityOrdered
+		allFulfilled = allFulfilled && line.QuantityFulfilled >= line.QuantityOrdered
+		anyAllocated = anyAllocated || line.QuantityAllocated > 0
+		anyFulfilled = anyFulfilled || line.QuantityFulfilled > 0
+	}
+
+	switch {
+	case allFulfilled:
+		return StatusFulfilled
+	case anyFulfilled:
+		return StatusPartiallyFulfilled
+	case allAllocated:
+		return StatusAllocated
+	case anyAllocated:
+		return StatusPartiallyAllocated
+	default:
+		return StatusPending
+	}
+}
+
+func variantID(line *Line) string {
+	if line.VariantID == nil {
+		return ""
+	}
+	return line.VariantID.String()
+}
+
+func updateLine(ctx context.Context, repo Repository, line *Line) error {
+	if err := repo.UpdateLine(ctx, line); err != nil {
+		return apperrors.NewDatabaseError("failed to update sales order line: " + err.Error())
+	}
+
+	return nil
+}
+
+func updateStatus(ctx context.Context, repo Repository, id string, status Status) error {
+	if err := repo.UpdateStatus(ctx, id, status); err != nil {
+		return apperrors.NewDatabaseError("failed to update sales order status: " + err.Error())
+	}
+
+	return nil
+}
+
+func getSalesOrder(ctx context.Context, repo Repository, id string) (*SalesOrder, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	so, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewSalesOrderNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve sales order: " + err.Error())
+	}
+
+	return so, nil
+}
+
+// lockSalesOrder locks the order row for the rest of the transaction and returns the order.
+func lockSalesOrder(ctx context.Context, repo Repository, id string) (*SalesOrder, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	if err := repo.Lock(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewSalesOrderNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to lock sales order: " + err.Error())
+	}
+
+	return getSalesOrder(ctx, repo, id)
+}
@@ -0,0 +1,317 @@
+package salesorder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+type mockRepo struct {
+	orders map[string]*SalesOrder
+}
+
+func newMockRepo() *mockRepo {
+	return &mockRepo{orders: make(map[string]*SalesOrder)}
+}
+
+func (m *mockRepo) Create(_ context.Context, so *SalesOrder) error {
+	so.ID = uuid.New()
+	for i := range so.Lines {
+		so.Lines[i].ID = uuid.New()
+		so.Lines[i].SalesOrderID = so.ID
+	}
+	stored := *so
+	stored.Lines = append([]Line(nil), so.Lines...)
+	m.orders[so.ID.String()] = &stored
+	return nil
+}
+
+func (m *mockRepo) GetAll(context.Context, Filter) ([]SalesOrder, error) {
+	out := make([]SalesOrder, 0, len(m.orders))
+	for _, so := range m.orders {
+		out = append(out, *so)
+	}
+	return out, nil
+}
+
+func (m *mockRepo) GetByID(_ context.Context, id string) (*SalesOrder, error) {
+	so, ok := m.orders[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *so
+	copied.Lines = append([]Line(nil), so.Lines...)
+	return &copied, nil
+}
+
+func (m *mockRepo) Lock(_ context.Context, id string) error {
+	if _, ok := m.orders[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (m *mockRepo) UpdateStatus(_ context.Context, id string, status Status) error {
+	m.orders[id].Status = status
+	return nil
+}
+
+func (m *mockRepo) UpdateLine(_ context.Context, line *Line) error {
+	so := m.orders[line.SalesOrderID.String()]
+	for i := range so.Lines {
+		if so.Lines[i].ID == line.ID {
+			so.Lines[i] = *line
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+// fixture reserves and posts stock through a real product service over the product
+// package's in-memory repository.
+type fixture struct {
+	svc      Service
+	repo     *mockRepo
+	products product.Service
+	widget   *product.Product
+	gadget   *product.Product
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{repo: newMockRepo(), products: product.NewService(product.NewMemoryRepository())}
+
+	f.widget = &product.Product{Name: "Widget", StockQuantity: 10, Currency: "USD", SalePrice: 500}
+	f.gadget = &product.Product{Name: "Gadget", StockQuantity: 2, Currency: "USD", SalePrice: 1200}
+	for _, p := range []*product.Product{f.widget, f.gadget} {
+		if err := f.products.CreateProduct(context.Background(), p); err != nil {
+			t.Fatalf("failed to create %s: %v", p.Name, err)
+		}
+	}
+
+	tx := func(_ context.Context, fn func(Repository, product.Service) error) error {
+		return fn(f.repo, f.products)
+	}
+	f.svc = NewService(f.repo, f.products, tx)
+
+	return f
+}
+
+func (f *fixture) create(t *testing.T, widgets int, gadgets int) *SalesOrder {
+	t.Helper()
+
+	so := &SalesOrder{Customer: "Acme", Lines: []Line{
+		{ProductID: f.widget.ID, QuantityOrdered: widgets},
+		{ProductID: f.gadget.ID, QuantityOrdered: gadgets},
+	}}
+	assertNoError(t, f.svc.CreateSalesOrder(context.Background(), so))
+
+	return so
+}
+
+func TestService_CreateSalesOrder(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	t.Run("defaults currency and prices from the products", func(t *testing.T) {
+		so := f.create(t, 3, 1)
+
+		if so.Status != StatusPending || so.Currency != "USD" {
+			t.Fatalf("expected a pending USD order, got %s in %s", so.Status, so.Currency)
+		}
+		if so.Lines[0].UnitPrice != 500 || so.Lines[1].UnitPrice != 1200 {
+			t.Fatalf("unexpected line prices: %+v", so.Lines)
+		}
+	})
+
+	t.Run("error on a product ordered twice", func(t *testing.T) {
+		err := f.svc.CreateSalesOrder(ctx, &SalesOrder{Customer: "Acme", Lines: []Line{
+			{ProductID: f.widget.ID, QuantityOrdered: 1},
+			{ProductID: f.widget.ID, QuantityOrdered: 2},
+		}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error on unknown product", func(t *testing.T) {
+		err := f.svc.CreateSalesOrder(ctx, &SalesOrder{Customer: "Acme", Lines: []Line{{ProductID: uuid.New(), QuantityOrdered: 1}}})
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+	})
+
+	t.Run("error without customer", func(t *testing.T) {
+		err := f.svc.CreateSalesOrder(ctx, &SalesOrder{Lines: []Line{{ProductID: f.widget.ID, QuantityOrdered: 1}}})
+		assertAppErrorCode(t, err, apperrors.MissingRequiredData)
+	})
+}
+
+func TestService_SalesOrderLifecycle(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	so := f.create(t, 4, 3)
+	id := so.ID.String()
+	widgetLine, gadgetLine := so.Lines[0].ID, so.Lines[1].ID
+
+	t.Run("allocation reserves what is available", func(t *testing.T) {
+		assertNoError(t, f.svc.AllocateSalesOrder(ctx, id))
+
+		stored := f.repo.orders[id]
+		if stored.Status != StatusPartiallyAllocated {
+			t.Fatalf("expected partially allocated, got %s", stored.Status)
+		}
+		if stored.Lines[0].QuantityAllocated != 4 || stored.Lines[1].QuantityAllocated != 2 {
+			t.Fatalf("unexpected allocations: %+v", stored.Lines)
+		}
+		if f.widget.ReservedQuantity != 4 || f.gadget.ReservedQuantity != 2 {
+			t.Fatalf("expected 4 widgets and 2 gadgets reserved, got %d and %d", f.widget.ReservedQuantity, f.gadget.ReservedQuantity)
+		}
+	})
+
+	t.Run("allocating again picks up stock that arrived", func(t *testing.T) {
+		_, err := f.products.ApplyStockChanges(ctx, product.StockChange{ProductID: f.gadget.ID.String(), Quantity: 3, Reason: product.ReasonReceipt})
+		assertNoError(t, err)
+		assertNoError(t, f.svc.AllocateSalesOrder(ctx, id))
+
+		if got := f.repo.orders[id]; got.Status != StatusAllocated || got.Lines[1].QuantityAllocated != 3 {
+			t.Fatalf("expected the order fully allocated, got %s with %+v", got.Status, got.Lines[1])
+		}
+	})
+
+	t.Run("error fulfilling more than is allocated", func(t *testing.T) {
+		err := f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{{LineID: widgetLine, Quantity: 5}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error on a line from another order", func(t *testing.T) {
+		err := f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{{LineID: uuid.New(), Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("partial fulfilment posts sales linked to the order", func(t *testing.T) {
+		assertNoError(t, f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{{LineID: widgetLine, Quantity: 3}}, Note: "first parcel"}))
+
+		if got := f.repo.orders[id].Status; got != StatusPartiallyFulfilled {
+			t.Fatalf("expected partially fulfilled, got %s", got)
+		}
+		if f.widget.StockQuantity != 7 || f.widget.ReservedQuantity != 1 {
+			t.Fatalf("expected 7 widgets with 1 reserved, got %d with %d", f.widget.StockQuantity, f.widget.ReservedQuantity)
+		}
+
+		movements, err := f.products.ListMovements(ctx, product.MovementFilter{ReferenceType: ReferenceType, ReferenceID: id})
+		assertNoError(t, err)
+		if len(movements) != 1 {
+			t.Fatalf("expected one sale movement, got %+v", movements)
+		}
+		if m := movements[0]; m.Quantity != -3 || m.Reason != product.ReasonSale || m.ProductID != f.widget.ID || m.Note != "first parcel" {
+			t.Fatalf("unexpected sale movement: %+v", m)
+		}
+	})
+
+	t.Run("cancelling releases what was not fulfilled", func(t *testing.T) {
+		assertNoError(t, f.svc.CancelSalesOrder(ctx, id))
+
+		stored := f.repo.orders[id]
+		if stored.Status != StatusCancelled {
+			t.Fatalf("expected cancelled, got %s", stored.Status)
+		}
+		if f.widget.ReservedQuantity != 0 || f.gadget.ReservedQuantity != 0 {
+			t.Fatalf("expected no reservations left, got %d and %d", f.widget.ReservedQuantity, f.gadget.ReservedQuantity)
+		}
+		if stored.Lines[0].QuantityAllocated != 3 || stored.Lines[1].QuantityAllocated != 0 {
+			t.Fatalf("expected allocations cut back to what was fulfilled, got %+v", stored.Lines)
+		}
+		movements, _ := f.products.ListMovements(ctx, product.MovementFilter{ReferenceType: ReferenceType, ReferenceID: id})
+		if f.widget.StockQuantity != 7 || len(movements) != 1 {
+			t.Fatalf("expected fulfilled units to stay shipped, got stock %d", f.widget.StockQuantity)
+		}
+	})
+
+	t.Run("error on a cancelled order", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.AllocateSalesOrder(ctx, id), apperrors.BusinessLogicError)
+		err := f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{{LineID: gadgetLine, Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error on unknown order", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CancelSalesOrder(ctx, uuid.NewString()), apperrors.SalesOrderNotFound)
+	})
+}
+
+func TestService_FulfilToCompletion(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	so := f.create(t, 2, 1)
+	id := so.ID.String()
+	assertNoError(t, f.svc.AllocateSalesOrder(ctx, id))
+
+	err := f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{
+		{LineID: so.Lines[0].ID, Quantity: 2},
+		{LineID: so.Lines[1].ID, Quantity: 1},
+	}})
+	assertNoError(t, err)
+
+	if got := f.repo.orders[id].Status; got != StatusFulfilled {
+		t.Fatalf("expected fulfilled, got %s", got)
+	}
+	assertAppErrorCode(t, f.svc.CancelSalesOrder(ctx, id), apperrors.BusinessLogicError)
+}
+
+func TestService_SalesOrderUnits(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	assertNoError(t, f.products.SetUnits(ctx, f.widget.ID.String(), []product.UnitOfMeasure{{Name: "pair", Factor: "2"}}))
+
+	so := &SalesOrder{Customer: "Acme", Lines: []Line{{ProductID: f.widget.ID, QuantityOrdered: 3, Unit: "pair"}}}
+	assertNoError(t, f.svc.CreateSalesOrder(ctx, so))
+	if line := so.Lines[0]; line.QuantityOrdered != 6 || line.Unit != "" {
+		t.Fatalf("expected 6 widgets ordered in the base unit, got %d %s", line.QuantityOrdered, line.Unit)
+	}
+	id := so.ID.String()
+	assertNoError(t, f.svc.AllocateSalesOrder(ctx, id))
+
+	t.Run("fulfilments convert to base units", func(t *testing.T) {
+		err := f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{{LineID: so.Lines[0].ID, Quantity: 2, Unit: "pair"}}})
+		assertNoError(t, err)
+
+		if got := f.repo.orders[id].Lines[0].QuantityFulfilled; got != 4 {
+			t.Fatalf("expected 4 widgets fulfilled, got %d", got)
+		}
+		if f.widget.StockQuantity != 6 {
+			t.Fatalf("expected 6 widgets left, got %d", f.widget.StockQuantity)
+		}
+	})
+
+	t.Run("error fulfilling more than is allocated", func(t *testing.T) {
+		err := f.svc.FulfilSalesOrder(ctx, id, Fulfilment{Lines: []FulfilmentLine{{LineID: so.Lines[0].ID, Quantity: 2, Unit: "pair"}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("error on a unit the product does not have", func(t *testing.T) {
+		so := &SalesOrder{Customer: "Acme", Lines: []Line{{ProductID: f.widget.ID, QuantityOrdered: 1, Unit: "dozen"}}}
+		assertAppErrorCode(t, f.svc.CreateSalesOrder(ctx, so), apperrors.InvalidInput)
+	})
+}
@@ -0,0 +1,91 @@
+package stocktake
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+)
+
+// ReferenceType identifies stock takes as the reference of stock movements.
+const ReferenceType = "stock_take"
+
+// Status is the stage of a stock take. Sessions are counted while open, submitted for
+// review once every line is counted, then approved or cancelled.
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusSubmitted Status = "submitted"
+	StatusApproved  Status = "approved"
+	StatusCancelled Status = "cancelled"
+)
+
+// Session is one physical count of a set of products.
+type Session struct {
+	model.BaseModel
+	Name       string     `json:"name" gorm:"not null" validate:"required"`
+	Note       string     `json:"note"`
+	Status     Status     `json:"status" gorm:"not null;index" enum:"open,submitted,approved,cancelled" openapi:"readonly"`
+	ApprovedBy string     `json:"approved_by,omitempty" openapi:"readonly"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty" openapi:"readonly"`
+	Lines      []Line     `json:"lines" gorm:"foreignKey:SessionID" openapi:"readonly"`
+}
+
+func (Session) TableName() string {
+	return "stock_takes"
+}
+
+// Line is the count of one product, one of its variants, or one lot of a lot-tracked
+// product. The expected quantity is snapshotted when the session opens and again when the
+// count is recorded, so stock moving while the warehouse is counted does not show up as
+// variance.
+type Line struct {
+	model.BaseModel
+	SessionID        uuid.UUID  `json:"session_id" gorm:"type:uuid;not null;index"`
+	ProductID        uuid.UUID  `json:"product_id" gorm:"type:uuid;not null"`
+	VariantID        *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid"`
+	LotNumber        string     `json:"lot_number,omitempty" doc:"Lot counted, for lot-tracked products"`
+	ExpectedQuantity int        `json:"expected_quantity" gorm:"not null"`
+	CountedQuantity  *int       `json:"counted_quantity"`
+	Variance         *int       `json:"variance" doc:"Counted less expected quantity; null until counted"`
+	CountedBy        string     `json:"counted_by,omitempty"`
+	CountedAt        *time.Time `json:"counted_at,omitempty"`
+	Note             string     `json:"note,omitempty"`
+}
+
+func (Line) TableName() string {
+	return "stock_take_lines"
+}
+
+// Scope selects the products a new session counts. The zero value counts every product.
+type Scope struct {
+	Name       string      `json:"name" validate:"required"`
+	Note       string      `json:"note"`
+	ProductIDs []uuid.UUID `json:"product_ids" doc:"Products to count; empty counts every product (in the category, if given)"`
+	CategoryID string      `json:"category_id" doc:"Count the products in this category and its sub-categories"`
+}
+
+// Counts records counted quantities against the lines of a session.
+type Counts struct {
+	CountedBy string  `json:"counted_by" validate:"required"`
+	Lines     []Count `json:"lines" validate:"required"`
+}
+
+// Count is the quantity counted for one line. Counting a line again replaces its count.
+type Count struct {
+	LineID   uuid.UUID `json:"line_id" validate:"required"`
+	Quantity int       `json:"quantity" validate:"min=0"`
+	Unit     string    `json:"unit,omitempty" doc:"Unit of quantity; defaults to the base unit"`
+	Note     string    `json:"note" doc:"Explanation of the variance, recorded on the adjustment"`
+}
+
+// Approval is a supervisor's sign-off on a submitted session.
+type Approval struct {
+	ApprovedBy string `json:"approved_by" validate:"required"`
+}
+
+// Filter narrows a session listing. The zero value matches every session.
+type Filter struct {
+	Status Status
+}
@@ -0,0 +1,21 @@
+package stocktake
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Create inserts the session together with its lines.
+	Create(context.Context, *Session) error
+	GetAll(context.Context, Filter) ([]Session, error)
+	GetByID(context.Context, string) (*Session, error)
+
+	// Lock locks the session row until the surrounding transaction ends.
+	Lock(ctx context.Context, id string) error
+	UpdateStatus(ctx context.Context, id string, status Status) error
+	// Approve marks the session approved by the given supervisor.
+	Approve(ctx context.Context, id string, approvedBy string, at time.Time) error
+	// UpdateLineCount saves the line's expected, counted and variance quantities.
+	UpdateLineCount(context.Context, *Line) error
+}
@@ -0,0 +1,417 @@
+package stocktake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	// CreateStockTake opens a session with a line per product, per variant, or per lot of
+	// a lot-tracked product in scope. Kits and serialized products are not counted by
+	// quantity and are left out.
+	CreateStockTake(context.Context, Scope) (*Session, error)
+	GetAllStockTakes(context.Context, Filter) ([]Session, error)
+	GetStockTakeByID(context.Context, string) (*Session, error)
+
+	RecordCounts(ctx context.Context, id string, counts Counts) error
+	SubmitStockTake(ctx context.Context, id string) error
+	// ApproveStockTake posts every variance as a stock movement linked to the session.
+	ApproveStockTake(ctx context.Context, id string, approval Approval) error
+	CancelStockTake(ctx context.Context, id string) error
+	GetAdjustmentMovements(ctx context.Context, id string) ([]product.StockMovement, error)
+}
+
+// Transactor runs fn inside one database transaction, passing a Repository and a
+// product.Service that both take part in it.
+type Transactor func(ctx context.Context, fn func(Repository, product.Service) error) error
+
+type service struct {
+	repo     Repository
+	products product.Service
+	tx       Transactor
+}
+
+func NewService(repo Repository, products product.Service, tx Transactor) Service {
+	return &service{
+		repo:     repo,
+		products: products,
+		tx:       tx,
+	}
+}
+
+// CreateStockTake implements Service. Expected quantities are snapshotted from current stock.
+func (s *service) CreateStockTake(ctx context.Context, scope Scope) (*Session, error) {
+	if scope.Name == "" {
+		return nil, apperrors.NewMissingRequiredDataError("name")
+	}
+
+	products, err := s.productsInScope(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{
+		Name:   scope.Name,
+		Note:   scope.Note,
+		Status: StatusOpen,
+	}
+
+	for _, p := range products {
+		if p.LotTracked {
+			lots, err := s.products.GetLots(ctx, p.ID.String())
+			if err != nil {
+				return nil, err
+			}
+			for _, lot := range lots {
+				if lot.Quantity > 0 {
+					session.Lines = append(session.Lines, Line{ProductID: p.ID, LotNumber: lot.LotNumber, ExpectedQuantity: lot.Quantity})
+				}
+			}
+			continue
+		}
+
+		if len(p.Variants) == 0 {
+			session.Lines = append(session.Lines, Line{ProductID: p.ID, ExpectedQuantity: p.StockQuantity})
+			continue
+		}
+
+		for _, v := range p.Variants {
+			session.Lines = append(session.Lines, Line{ProductID: p.ID, VariantID: &v.ID, ExpectedQuantity: v.StockQuantity})
+		}
+	}
+
+	if len(session.Lines) == 0 {
+		return nil, apperrors.NewInvalidInputError("no products to count")
+	}
+
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, apperrors.NewDatabaseError("failed to create stock take: " + err.Error())
+	}
+
+	return session, nil
+}
+
+// GetAllStockTakes implements Service.
+func (s *service) GetAllStockTakes(ctx context.Context, filter Filter) ([]Session, error) {
+	sessions, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve stock takes: " + err.Error())
+	}
+
+	return sessions, nil
+}
+
+// GetStockTakeByID implements Service.
+func (s *service) GetStockTakeByID(ctx context.Context, id string) (*Session, error) {
+	return getSession(ctx, s.repo, id)
+}
+
+// RecordCounts implements Service. Each count re-snapshots the line's expected quantity
+// from current stock, so the variance reflects the stock at the time of counting.
+func (s *service) RecordCounts(ctx context.Context, id string, counts Counts) error {
+	if counts.CountedBy == "" {
+		return apperrors.NewMissingRequiredDataError("counted_by")
+	}
+	if len(counts.Lines) == 0 {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		session, err := lockSession(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if session.Status != StatusOpen {
+			return apperrors.NewBusinessLogicError("counts can only be recorded on open stock takes")
+		}
+
+		lines := make(map[uuid.UUID]*Line, len(session.Lines))
+		for i := range session.Lines {
+			lines[session.Lines[i].ID] = &session.Lines[i]
+		}
+
+		now := time.Now().UTC()
+
+		for _, count := range counts.Lines {
+			line, ok := lines[count.LineID]
+			if !ok {
+				return apperrors.NewInvalidInputError("line " + count.LineID.String() + " is not on this stock take")
+			}
+			if count.Quantity < 0 {
+				return apperrors.NewInvalidInputError("counted quantity cannot be negative")
+			}
+			// Nothing counted is nothing in any unit
+			if count.Unit != "" && count.Quantity > 0 {
+				if count.Quantity, err = products.ToBaseUnits(ctx, line.ProductID.String(), quantity.FromInt(count.Quantity), count.Unit); err != nil {
+					return err
+				}
+			}
+
+			expected, err := currentStock(ctx, products, line)
+			if err != nil {
+				return err
+			}
+
+			variance := count.Quantity - expected
+			line.ExpectedQuantity = expected
+			line.CountedQuantity = &count.Quantity
+			line.Variance = &variance
+			line.CountedBy = counts.CountedBy
+			line.CountedAt = &now
+			line.Note = count.Note
+
+			if err := repo.UpdateLineCount(ctx, line); err != nil {
+				return apperrors.NewDatabaseError("failed to record count: " + err.Error())
+			}
+		}
+
+		return nil
+	})
+}
+
+// SubmitStockTake implements Service. Every line must have been counted.
+func (s *service) SubmitStockTake(ctx context.Context, id string) error {
+	return s.tx(ctx, func(repo Repository, _ product.Service) error {
+		session, err := lockSession(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if session.Status != StatusOpen {
+			return apperrors.NewBusinessLogicError("only open stock takes can be submitted")
+		}
+
+		uncounted := 0
+		for _, line := range session.Lines {
+			if line.CountedQuantity == nil {
+				uncounted++
+			}
+		}
+		if uncounted > 0 {
+			return apperrors.NewBusinessLogicError(fmt.Sprintf("%d of %d lines have not been counted", uncounted, len(session.Lines)))
+		}
+
+		if err := repo.UpdateStatus(ctx, id, StatusSubmitted); err != nil {
+			return apperrors.NewDatabaseError("failed to update stock take status: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// ApproveStockTake implements Service. The adjustments and the approval commit together;
+// if any adjustment would take stock below zero, nothing is posted.
+func (s *service) ApproveStockTake(ctx context.Context, id string, approval Approval) error {
+	if approval.ApprovedBy == "" {
+		return apperrors.NewMissingRequiredDataError("approved_by")
+	}
+
+	return s.tx(ctx, func(repo Repository, products product.Service) error {
+		session, err := lockSession(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if session.Status != StatusSubmitted {
+			return apperrors.NewBusinessLogicError("only submitted stock takes can be approved")
+		}
+
+		var changes []product.StockChange
+		for _, line := range session.Lines {
+			if line.Variance == nil || *line.Variance == 0 {
+				continue
+			}
+
+			change := product.StockChange{
+				ProductID:     line.ProductID.String(),
+				Quantity:      *line.Variance,
+				Reason:        product.ReasonCount,
+				LotNumber:     line.LotNumber,
+				ReferenceType: ReferenceType,
+				ReferenceID:   session.ID.String(),
+				Note:          line.Note,
+			}
+			if line.VariantID != nil {
+				change.VariantID = line.VariantID.String()
+			}
+			changes = append(changes, change)
+		}
+
+		if len(changes) > 0 {
+			if _, err := products.ApplyStockChanges(ctx, changes...); err != nil {
+				return err
+			}
+		}
+
+		if err := repo.Approve(ctx, id, approval.ApprovedBy, time.Now().UTC()); err != nil {
+			return apperrors.NewDatabaseError("failed to approve stock take: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// CancelStockTake implements Service. Nothing is posted for a cancelled session.
+func (s *service) CancelStockTake(ctx context.Context, id string) error {
+	return s.tx(ctx, func(repo Repository, _ product.Service) error {
+		session, err := lockSession(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+
+		if session.Status != StatusOpen && session.Status != StatusSubmitted {
+			return apperrors.NewBusinessLogicError("only open or submitted stock takes can be cancelled")
+		}
+
+		if err := repo.UpdateStatus(ctx, id, StatusCancelled); err != nil {
+			return apperrors.NewDatabaseError("failed to update stock take status: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// GetAdjustmentMovements implements Service.
+func (s *service) GetAdjustmentMovements(ctx context.Context, id string) ([]product.StockMovement, error) {
+	session, err := s.GetStockTakeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.products.ListMovements(ctx, product.MovementFilter{
+		ReferenceType: ReferenceType,
+		ReferenceID:   session.ID.String(),
+	})
+}
+
+// productsInScope returns the products the scope counts. Products listed by ID must be
+// countable; a category or full count skips those that are not.
+func (s *service) productsInScope(ctx context.Context, scope Scope) ([]product.Product, error) {
+	if len(scope.ProductIDs) == 0 {
+		all, err := s.products.GetAllProducts(ctx, product.Filter{
+			CategoryID:         scope.CategoryID,
+			IncludeDescendants: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		products := all[:0]
+		for _, p := range all {
+			if checkCountable(&p) == nil {
+				products = append(products, p)
+			}
+		}
+		return products, nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(scope.ProductIDs))
+	products := make([]product.Product, 0, len(scope.ProductIDs))
+
+	for _, id := range scope.ProductIDs {
+		if seen[id] {
+			return nil, apperrors.NewInvalidInputError("product " + id.String() + " is listed more than once")
+		}
+		seen[id] = true
+
+		p, err := s.products.GetProductByID(ctx, id.String())
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCountable(p); err != nil {
+			return nil, err
+		}
+		products = append(products, *p)
+	}
+
+	return products, nil
+}
+
+// checkCountable rejects products a session cannot count. Kits hold no stock of their
+// own, and serialized units are adjusted by serial number, which a count line does not
+// record.
+func checkCountable(p *product.Product) error {
+	if p.IsKit() {
+		return apperrors.NewBusinessLogicError("product " + p.ID.String() + " is a kit; count its components instead")
+	}
+
+	if p.Serialized {
+		return apperrors.NewBusinessLogicError("product " + p.ID.String() + " is serialized; adjust its stock by serial number instead")
+	}
+
+	return nil
+}
+
+// currentStock returns the stock of the line's product, variant or lot.
+func currentStock(ctx context.Context, products product.Service, line *Line) (int, error) {
+	if line.LotNumber != "" {
+		lots, err := products.GetLots(ctx, line.ProductID.String())
+		if err != nil {
+			return 0, err
+		}
+		for _, lot := range lots {
+			if lot.LotNumber == line.LotNumber {
+				return lot.Quantity, nil
+			}
+		}
+		return 0, nil
+	}
+
+	p, err := products.GetProductByID(ctx, line.ProductID.String())
+	if err != nil {
+		return 0, err
+	}
+
+	if line.VariantID == nil {
+		return p.StockQuantity, nil
+	}
+
+	for _, v := range p.Variants {
+		if v.ID == *line.VariantID {
+			return v.StockQuantity, nil
+		}
+	}
+
+	return 0, apperrors.NewVariantNotFoundError(line.VariantID.String())
+}
+
+func getSession(ctx context.Context, repo Repository, id string) (*Session, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	session, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewStockTakeNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve stock take: " + err.Error())
+	}
+
+	return session, nil
+}
+
+// lockSession locks the session row for the rest of the transaction and returns the session.
+func lockSession(ctx context.Context, repo Repository, id string) (*Session, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	if err := repo.Lock(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewStockTakeNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to lock stock take: " + err.Error())
+	}
+
+	return getSession(ctx, repo, id)
+}
@@ -0,0 +1,467 @@
+package stocktake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// mockRepo is an in-memory implementation of the Repository interface for testing.
+type mockRepo struct {
+	sessions map[string]*Session
+}
+
+func newMockRepo() *mockRepo {
+	return &mockRepo{sessions: make(map[string]*Session)}
+}
+
+func (m *mockRepo) Create(_ context.Context, session *Session) error {
+	session.ID = uuid.New()
+	for i := range session.Lines {
+		session.Lines[i].ID = uuid.New()
+		session.Lines[i].SessionID = session.ID
+	}
+	stored := *session
+	stored.Lines = append([]Line(nil), session.Lines...)
+	m.sessions[session.ID.String()] = &stored
+	return nil
+}
+
+func (m *mockRepo) GetAll(context.Context, Filter) ([]Session, error) {
+	out := make([]Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		out = append(out, *session)
+	}
+	return out, nil
+}
+
+func (m *mockRepo) GetByID(_ context.Context, id string) (*Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *session
+	copied.Lines = append([]Line(nil), session.Lines...)
+	return &copied, nil
+}
+
+func (m *mockRepo) Lock(_ context.Context, id string) error {
+	if _, ok := m.sessions[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (m *mockRepo) UpdateStatus(_ context.Context, id string, status Status) error {
+	m.sessions[id].Status = status
+	return nil
+}
+
+func (m *mockRepo) Approve(_ context.Context, id string, approvedBy string, at time.Time) error {
+	m.sessions[id].Status = StatusApproved
+	m.sessions[id].ApprovedBy = approvedBy
+	m.sessions[id].ApprovedAt = &at
+	return nil
+}
+
+func (m *mockRepo) UpdateLineCount(_ context.Context, line *Line) error {
+	session := m.sessions[line.SessionID.String()]
+	for i := range session.Lines {
+		if session.Lines[i].ID == line.ID {
+			session.Lines[i] = *line
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperrors.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		t.Fatalf("expected *AppError with code %s, got %T (%v)", code, err, err)
+	}
+	if appErr.Code != code {
+		t.Fatalf("expected error code %s, got %s (message: %s)", code, appErr.Code, appErr.Message)
+	}
+}
+
+// fixture posts adjustments through a real product service over the product package's
+// in-memory repository, so approvals are held to the ledger's rules.
+type fixture struct {
+	svc      Service
+	repo     *mockRepo
+	products product.Service
+	widget   *product.Product
+	shirt    *product.Product
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &fixture{repo: newMockRepo(), products: product.NewService(product.NewMemoryRepository())}
+
+	f.widget = &product.Product{Name: "Widget", StockQuantity: 10}
+	f.shirt = &product.Product{Name: "Shirt"}
+	f.create(t, f.widget, f.shirt)
+
+	shirtID := f.shirt.ID.String()
+	if err := f.products.SetOptions(ctx, shirtID, []product.OptionAxis{{Name: "size", Values: []string{"S", "L"}}}); err != nil {
+		t.Fatalf("failed to set the shirt's options: %v", err)
+	}
+	for _, v := range []product.Variant{{Options: map[string]string{"size": "S"}, StockQuantity: 3}, {Options: map[string]string{"size": "L"}, StockQuantity: 4}} {
+		if err := f.products.CreateVariant(ctx, shirtID, &v); err != nil {
+			t.Fatalf("failed to create the %s shirt: %v", v.Options["size"], err)
+		}
+	}
+
+	tx := func(_ context.Context, fn func(Repository, product.Service) error) error {
+		return fn(f.repo, f.products)
+	}
+	f.svc = NewService(f.repo, f.products, tx)
+
+	return f
+}
+
+// create adds the products to the product service.
+func (f *fixture) create(t *testing.T, products ...*product.Product) {
+	t.Helper()
+
+	for _, p := range products {
+		if err := f.products.CreateProduct(context.Background(), p); err != nil {
+			t.Fatalf("failed to create %s: %v", p.Name, err)
+		}
+	}
+}
+
+// sell posts a sale of quantity units of the product, as happens while a session is open.
+func (f *fixture) sell(t *testing.T, p *product.Product, quantity int) {
+	t.Helper()
+
+	_, err := f.products.ApplyStockChanges(context.Background(), product.StockChange{ProductID: p.ID.String(), Quantity: -quantity, Reason: product.ReasonSale})
+	if err != nil {
+		t.Fatalf("failed to sell %s: %v", p.Name, err)
+	}
+}
+
+// countAll records a count for every line of the session, looked up by product or variant.
+func (f *fixture) countAll(t *testing.T, session *Session, counted map[uuid.UUID]int) {
+	t.Helper()
+
+	counts := Counts{CountedBy: "alice"}
+	for _, line := range session.Lines {
+		key := line.ProductID
+		if line.VariantID != nil {
+			key = *line.VariantID
+		}
+		counts.Lines = append(counts.Lines, Count{LineID: line.ID, Quantity: counted[key], Note: "shelf count"})
+	}
+
+	if err := f.svc.RecordCounts(context.Background(), session.ID.String(), counts); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestService_CreateStockTake(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	t.Run("snapshots a line per product or variant", func(t *testing.T) {
+		session, err := f.svc.CreateStockTake(ctx, Scope{Name: "March", ProductIDs: []uuid.UUID{f.widget.ID, f.shirt.ID}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if session.Status != StatusOpen || len(session.Lines) != 3 {
+			t.Fatalf("expected an open session with 3 lines, got %s with %d", session.Status, len(session.Lines))
+		}
+		if line := session.Lines[0]; line.ExpectedQuantity != 10 || line.VariantID != nil {
+			t.Fatalf("unexpected widget line: %+v", line)
+		}
+		if line := session.Lines[2]; line.ExpectedQuantity != 4 || *line.VariantID != f.shirt.Variants[1].ID {
+			t.Fatalf("unexpected variant line: %+v", line)
+		}
+	})
+
+	t.Run("counts every product when none are listed", func(t *testing.T) {
+		session, err := f.svc.CreateStockTake(ctx, Scope{Name: "Full"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(session.Lines) != 3 {
+			t.Fatalf("expected 3 lines, got %d", len(session.Lines))
+		}
+	})
+
+	t.Run("error on unknown product", func(t *testing.T) {
+		_, err := f.svc.CreateStockTake(ctx, Scope{Name: "March", ProductIDs: []uuid.UUID{uuid.New()}})
+		assertAppErrorCode(t, err, apperrors.ProductNotFound)
+	})
+
+	t.Run("error on duplicate product", func(t *testing.T) {
+		_, err := f.svc.CreateStockTake(ctx, Scope{Name: "March", ProductIDs: []uuid.UUID{f.widget.ID, f.widget.ID}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	t.Run("error without name", func(t *testing.T) {
+		_, err := f.svc.CreateStockTake(ctx, Scope{})
+		assertAppErrorCode(t, err, apperrors.MissingRequiredData)
+	})
+}
+
+func TestService_StockTakeLifecycle(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	session, _ := f.svc.CreateStockTake(ctx, Scope{Name: "March", ProductIDs: []uuid.UUID{f.widget.ID, f.shirt.ID}})
+	id := session.ID.String()
+	small, large := f.shirt.Variants[0].ID, f.shirt.Variants[1].ID
+
+	t.Run("error submitting before every line is counted", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.SubmitStockTake(ctx, id), apperrors.BusinessLogicError)
+	})
+
+	t.Run("error approving an open session", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.ApproveStockTake(ctx, id, Approval{ApprovedBy: "bob"}), apperrors.BusinessLogicError)
+	})
+
+	t.Run("variance is measured against stock at the time of counting", func(t *testing.T) {
+		// Two widgets sold after the session opened
+		f.sell(t, f.widget, 2)
+
+		f.countAll(t, session, map[uuid.UUID]int{f.widget.ID: 7, small: 3, large: 6})
+
+		stored := f.repo.sessions[id]
+		if line := stored.Lines[0]; line.ExpectedQuantity != 8 || *line.Variance != -1 || line.CountedBy != "alice" {
+			t.Fatalf("unexpected widget line: %+v", line)
+		}
+		if line := stored.Lines[2]; *line.Variance != 2 {
+			t.Fatalf("expected a variance of 2, got %d", *line.Variance)
+		}
+	})
+
+	t.Run("error on a line from another session", func(t *testing.T) {
+		err := f.svc.RecordCounts(ctx, id, Counts{CountedBy: "alice", Lines: []Count{{LineID: uuid.New(), Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+
+	if err := f.svc.SubmitStockTake(ctx, id); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	t.Run("error counting a submitted session", func(t *testing.T) {
+		err := f.svc.RecordCounts(ctx, id, Counts{CountedBy: "alice", Lines: []Count{{LineID: session.Lines[0].ID, Quantity: 1}}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("failed adjustments leave the session submitted", func(t *testing.T) {
+		// The remaining widgets sold before approval, so the shortfall cannot be written off
+		f.sell(t, f.widget, 8)
+
+		assertAppErrorCode(t, f.svc.ApproveStockTake(ctx, id, Approval{ApprovedBy: "bob"}), apperrors.InsufficientStock)
+		if got := f.repo.sessions[id].Status; got != StatusSubmitted {
+			t.Fatalf("expected submitted, got %s", got)
+		}
+
+		_, err := f.products.ApplyStockChanges(ctx, product.StockChange{ProductID: f.widget.ID.String(), Quantity: 8, Reason: product.ReasonReturn})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("approval posts the non-zero variances", func(t *testing.T) {
+		if err := f.svc.ApproveStockTake(ctx, id, Approval{ApprovedBy: "bob"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		stored := f.repo.sessions[id]
+		if stored.Status != StatusApproved || stored.ApprovedBy != "bob" {
+			t.Fatalf("expected approved by bob, got %s by %q", stored.Status, stored.ApprovedBy)
+		}
+
+		movements, err := f.products.ListMovements(ctx, product.MovementFilter{ReferenceType: ReferenceType, ReferenceID: id})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(movements) != 2 {
+			t.Fatalf("expected 2 adjustments, got %+v", movements)
+		}
+		widget, shirt := movements[0], movements[1]
+		if widget.ProductID == f.shirt.ID {
+			widget, shirt = shirt, widget
+		}
+		if widget.Quantity != -1 || widget.Reason != product.ReasonCount || widget.Note != "shelf count" {
+			t.Fatalf("unexpected widget adjustment: %+v", widget)
+		}
+		if shirt.Quantity != 2 || shirt.VariantID == nil || *shirt.VariantID != large {
+			t.Fatalf("unexpected shirt adjustment: %+v", shirt)
+		}
+		if f.widget.StockQuantity != 7 || f.shirt.Variants[1].StockQuantity != 6 {
+			t.Fatalf("expected stock to match the counts, got %d widgets and %d large shirts", f.widget.StockQuantity, f.shirt.Variants[1].StockQuantity)
+		}
+	})
+
+	t.Run("error cancelling an approved session", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.CancelStockTake(ctx, id), apperrors.BusinessLogicError)
+	})
+
+	t.Run("error on unknown session", func(t *testing.T) {
+		assertAppErrorCode(t, f.svc.SubmitStockTake(ctx, uuid.NewString()), apperrors.StockTakeNotFound)
+	})
+}
+
+func TestService_StockTakeSerialized(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	laptop := &product.Product{Name: "Laptop", Serialized: true}
+	f.create(t, laptop)
+	if err := f.products.IncrementSerials(ctx, laptop.ID.String(), []string{"SN-1", "SN-2"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	t.Run("error listing a serialized product", func(t *testing.T) {
+		_, err := f.svc.CreateStockTake(ctx, Scope{Name: "March", ProductIDs: []uuid.UUID{laptop.ID}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("a full count leaves serialized products out", func(t *testing.T) {
+		session, err := f.svc.CreateStockTake(ctx, Scope{Name: "Full"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		for _, line := range session.Lines {
+			if line.ProductID == laptop.ID {
+				t.Fatalf("expected no line for the serialized product, got %+v", line)
+			}
+		}
+
+		id := session.ID.String()
+		f.countAll(t, session, map[uuid.UUID]int{f.widget.ID: 9})
+		if err := f.svc.SubmitStockTake(ctx, id); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if err := f.svc.ApproveStockTake(ctx, id, Approval{ApprovedBy: "bob"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if laptop.StockQuantity != 2 || f.widget.StockQuantity != 9 {
+			t.Fatalf("expected 2 laptops and 9 widgets, got %d and %d", laptop.StockQuantity, f.widget.StockQuantity)
+		}
+	})
+}
+
+func TestService_StockTakeLotsAndKits(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	milk := &product.Product{Name: "Milk", LotTracked: true}
+	kit := &product.Product{Name: "Widget pair"}
+	f.create(t, milk, kit)
+
+	soon, later := time.Now().AddDate(0, 0, 30), time.Now().AddDate(0, 0, 60)
+	for _, receipt := range []product.LotReceipt{{LotNumber: "A", ExpiresAt: &soon, Quantity: 5}, {LotNumber: "B", ExpiresAt: &later, Quantity: 3}} {
+		if err := f.products.ReceiveLot(ctx, milk.ID.String(), receipt); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	if err := f.products.SetKitComponents(ctx, kit.ID.String(), []product.KitComponent{{ComponentID: f.widget.ID, Quantity: 2}}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	t.Run("error listing a kit", func(t *testing.T) {
+		_, err := f.svc.CreateStockTake(ctx, Scope{Name: "March", ProductIDs: []uuid.UUID{kit.ID}})
+		assertAppErrorCode(t, err, apperrors.BusinessLogicError)
+	})
+
+	t.Run("a full count leaves kits out", func(t *testing.T) {
+		session, err := f.svc.CreateStockTake(ctx, Scope{Name: "Full"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		for _, line := range session.Lines {
+			if line.ProductID == kit.ID {
+				t.Fatalf("expected no line for the kit, got %+v", line)
+			}
+		}
+	})
+
+	t.Run("lot-tracked products are counted and adjusted per lot", func(t *testing.T) {
+		session, err := f.svc.CreateStockTake(ctx, Scope{Name: "Dairy", ProductIDs: []uuid.UUID{milk.ID}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(session.Lines) != 2 {
+			t.Fatalf("expected a line per lot, got %+v", session.Lines)
+		}
+
+		counted := map[string]int{"A": 6, "B": 2}
+		counts := Counts{CountedBy: "alice"}
+		for _, line := range session.Lines {
+			counts.Lines = append(counts.Lines, Count{LineID: line.ID, Quantity: counted[line.LotNumber]})
+		}
+		id := session.ID.String()
+		if err := f.svc.RecordCounts(ctx, id, counts); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if err := f.svc.SubmitStockTake(ctx, id); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if err := f.svc.ApproveStockTake(ctx, id, Approval{ApprovedBy: "bob"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		lots, err := f.products.GetLots(ctx, milk.ID.String())
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		for _, lot := range lots {
+			if lot.Quantity != counted[lot.LotNumber] {
+				t.Fatalf("expected lot %s to hold %d, got %d", lot.LotNumber, counted[lot.LotNumber], lot.Quantity)
+			}
+		}
+		if milk.StockQuantity != 8 {
+			t.Fatalf("expected 8 units of milk, got %d", milk.StockQuantity)
+		}
+	})
+}
+
+func TestService_StockTakeUnits(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	if err := f.products.SetUnits(ctx, f.widget.ID.String(), []product.UnitOfMeasure{{Name: "box", Factor: "4"}}); err != nil {
+		t.Fatalf("failed to set the widget's units: %v", err)
+	}
+
+	session, _ := f.svc.CreateStockTake(ctx, Scope{Name: "Boxes", ProductIDs: []uuid.UUID{f.widget.ID}})
+	id, lineID := session.ID.String(), session.Lines[0].ID
+
+	t.Run("counts convert to base units", func(t *testing.T) {
+		err := f.svc.RecordCounts(ctx, id, Counts{CountedBy: "alice", Lines: []Count{{LineID: lineID, Quantity: 3, Unit: "box"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if line := f.repo.sessions[id].Lines[0]; *line.CountedQuantity != 12 || *line.Variance != 2 {
+			t.Fatalf("expected 12 counted with a variance of 2, got %+v", line)
+		}
+	})
+
+	t.Run("an empty count needs no conversion", func(t *testing.T) {
+		err := f.svc.RecordCounts(ctx, id, Counts{CountedBy: "alice", Lines: []Count{{LineID: lineID, Quantity: 0, Unit: "box"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("error on a unit the product does not have", func(t *testing.T) {
+		err := f.svc.RecordCounts(ctx, id, Counts{CountedBy: "alice", Lines: []Count{{LineID: lineID, Quantity: 1, Unit: "crate"}}})
+		assertAppErrorCode(t, err, apperrors.InvalidInput)
+	})
+}
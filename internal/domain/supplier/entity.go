@@ -0,0 +1,13 @@
+package supplier
+
+import "github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+
+type Supplier struct {
+	model.BaseModel
+	Name         string `json:"name" gorm:"not null" validate:"required"`
+	ContactName  string `json:"contact_name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Address      string `json:"address"`
+	LeadTimeDays int    `json:"lead_time_days" gorm:"not null;default:0" validate:"min=0" doc:"Typical days between sending an order and receiving it"`
+}
@@ -0,0 +1,11 @@
+package supplier
+
+import "context"
+
+type Repository interface {
+	Create(context.Context, *Supplier) error
+	GetAll(context.Context) ([]Supplier, error)
+	GetByID(context.Context, string) (*Supplier, error)
+	Update(context.Context, *Supplier) error
+	Delete(context.Context, string) error
+}
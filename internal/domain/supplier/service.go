@@ -0,0 +1,111 @@
+package supplier
+
+import (
+	"context"
+	"errors"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreateSupplier(context.Context, *Supplier) error
+	GetAllSuppliers(context.Context) ([]Supplier, error)
+	GetSupplierByID(context.Context, string) (*Supplier, error)
+	UpdateSupplier(context.Context, string, *Supplier) error
+	DeleteSupplier(context.Context, string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+// CreateSupplier implements Service.
+func (s *service) CreateSupplier(ctx context.Context, supplier *Supplier) error {
+	if err := validate(supplier); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, supplier); err != nil {
+		return apperrors.NewDatabaseError("failed to create supplier: " + err.Error())
+	}
+
+	return nil
+}
+
+// GetAllSuppliers implements Service.
+func (s *service) GetAllSuppliers(ctx context.Context) ([]Supplier, error) {
+	suppliers, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to retrieve suppliers: " + err.Error())
+	}
+
+	return suppliers, nil
+}
+
+// GetSupplierByID implements Service.
+func (s *service) GetSupplierByID(ctx context.Context, id string) (*Supplier, error) {
+	if id == "" {
+		return nil, apperrors.NewMissingRequiredDataError("id")
+	}
+
+	supplier, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewSupplierNotFoundError(id)
+		}
+		return nil, apperrors.NewDatabaseError("failed to retrieve supplier: " + err.Error())
+	}
+
+	return supplier, nil
+}
+
+// UpdateSupplier implements Service.
+func (s *service) UpdateSupplier(ctx context.Context, id string, supplier *Supplier) error {
+	if err := validate(supplier); err != nil {
+		return err
+	}
+
+	existing, err := s.GetSupplierByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	supplier.ID = existing.ID
+	if err := s.repo.Update(ctx, supplier); err != nil {
+		return apperrors.NewDatabaseError("failed to update supplier: " + err.Error())
+	}
+
+	return nil
+}
+
+// DeleteSupplier implements Service.
+func (s *service) DeleteSupplier(ctx context.Context, id string) error {
+	if _, err := s.GetSupplierByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return apperrors.NewDatabaseError("failed to delete supplier: " + err.Error())
+	}
+
+	return nil
+}
+
+func validate(supplier *Supplier) error {
+	if supplier.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+
+	if supplier.LeadTimeDays < 0 {
+		return apperrors.NewInvalidInputError("lead time cannot be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package valuation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/money"
+)
+
+// Method selects how stock on hand is costed.
+type Method string
+
+const (
+	// FIFO values stock at the cost of the most recent receipts, consuming the oldest first.
+	FIFO Method = "fifo"
+	// WeightedAverage values stock at the moving average cost of everything received.
+	WeightedAverage Method = "weighted_average"
+)
+
+// ProductValuation is the stock on hand of one product and its value, including the units
+// held in the quarantined, damaged and in-transit buckets.
+type ProductValuation struct {
+	ProductID uuid.UUID   `json:"product_id"`
+	Name      string      `json:"name"`
+	Quantity  int         `json:"quantity"`
+	Value     money.Money `json:"value"`
+	Held      int         `json:"held" doc:"Units of the quantity held outside the available bucket"`
+	HeldValue money.Money `json:"held_value" doc:"Value of the held units, at the cost they left the available bucket at"`
+}
+
+// Report is the value of the inventory at a point in time.
+type Report struct {
+	AsOf     time.Time          `json:"as_of"`
+	Method   Method             `json:"method" enum:"fifo,weighted_average"`
+	Products []ProductValuation `json:"products"`
+	Totals   []money.Money      `json:"totals" doc:"Total inventory value per currency"`
+}
+
+// StockValue is the quantity of a product available and its value in minor units, and the
+// quantity held in other buckets and its value.
+type StockValue struct {
+	Quantity  int
+	Value     int64
+	Held      int
+	HeldValue int64
+}
@@ -0,0 +1,16 @@
+package valuation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// StockValues replays the stock ledger up to and including asOf and returns, per
+	// product, the quantity available and its value in minor units under the given method,
+	// and the quantity held in other buckets at the cost it left the available bucket at.
+	// Products without movements by asOf are left out.
+	StockValues(ctx context.Context, asOf time.Time, method Method) (map[uuid.UUID]StockValue, error)
+}
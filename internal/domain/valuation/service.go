@@ -0,0 +1,82 @@
+package valuation
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/money"
+)
+
+type Service interface {
+	// GetValuation values the stock on hand at asOf by replaying the stock ledger up to it,
+	// listing the products as they were at asOf.
+	GetValuation(ctx context.Context, asOf time.Time, method Method) (*Report, error)
+}
+
+type service struct {
+	products product.Service
+	repo     Repository
+}
+
+func NewService(products product.Service, repo Repository) Service {
+	return &service{
+		products: products,
+		repo:     repo,
+	}
+}
+
+// GetValuation implements Service.
+func (s *service) GetValuation(ctx context.Context, asOf time.Time, method Method) (*Report, error) {
+	if method != FIFO && method != WeightedAverage {
+		return nil, apperrors.NewInvalidInputError("method must be one of fifo, weighted_average")
+	}
+
+	// Products deleted since asOf still held the stock being valued
+	products, err := s.products.GetAllProducts(ctx, product.Filter{AsOf: asOf})
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := s.repo.StockValues(ctx, asOf, method)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to value stock: " + err.Error())
+	}
+
+	report := &Report{
+		AsOf:     asOf,
+		Method:   method,
+		Products: []ProductValuation{},
+		Totals:   []money.Money{},
+	}
+	totals := make(map[string]int64)
+
+	for _, p := range products {
+		v, ok := values[p.ID]
+		if !ok {
+			continue
+		}
+
+		report.Products = append(report.Products, ProductValuation{
+			ProductID: p.ID,
+			Name:      p.Name,
+			Quantity:  v.Quantity + v.Held,
+			Value:     money.Money{Amount: v.Value + v.HeldValue, Currency: p.Currency},
+			Held:      v.Held,
+			HeldValue: money.Money{Amount: v.HeldValue, Currency: p.Currency},
+		})
+		totals[p.Currency] += v.Value + v.HeldValue
+	}
+
+	for currency, amount := range totals {
+		report.Totals = append(report.Totals, money.Money{Amount: amount, Currency: currency})
+	}
+	slices.SortFunc(report.Totals, func(a, b money.Money) int {
+		return strings.Compare(a.Currency, b.Currency)
+	})
+
+	return report, nil
+}
@@ -0,0 +1,116 @@
+package valuation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// stubProductService serves canned products and records the filter they were listed with.
+type stubProductService struct {
+	product.Service
+
+	products []product.Product
+	filter   product.Filter
+}
+
+func (s *stubProductService) GetAllProducts(_ context.Context, filter product.Filter) ([]product.Product, error) {
+	s.filter = filter
+	return s.products, nil
+}
+
+// stubRepository serves canned stock values and records what it was asked for.
+type stubRepository struct {
+	values map[uuid.UUID]StockValue
+	asOf   time.Time
+	method Method
+}
+
+func (r *stubRepository) StockValues(_ context.Context, asOf time.Time, method Method) (map[uuid.UUID]StockValue, error) {
+	r.asOf, r.method = asOf, method
+	return r.values, nil
+}
+
+func TestService_GetValuation(t *testing.T) {
+	shirt, mug, yen, unused := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	products := &stubProductService{
+		products: []product.Product{
+			{Name: "Shirt", Currency: "USD"},
+			{Name: "Mug", Currency: "USD"},
+			{Name: "Fan", Currency: "JPY"},
+			{Name: "Lamp", Currency: "USD"},
+		},
+	}
+	products.products[0].ID, products.products[1].ID = shirt, mug
+	products.products[2].ID, products.products[3].ID = yen, unused
+
+	repo := &stubRepository{
+		values: map[uuid.UUID]StockValue{
+			shirt: {Quantity: 10, Value: 5000},
+			mug:   {Quantity: 4, Value: 1000, Held: 2, HeldValue: 300},
+			yen:   {Quantity: 2, Value: 6000},
+		},
+	}
+
+	svc := NewService(products, repo)
+	ctx := context.Background()
+	asOf := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("values the products as they were at as_of", func(t *testing.T) {
+		report, err := svc.GetValuation(ctx, asOf, WeightedAverage)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if !products.filter.AsOf.Equal(asOf) {
+			t.Fatalf("expected products as of %v, got %v", asOf, products.filter.AsOf)
+		}
+		if !repo.asOf.Equal(asOf) || repo.method != WeightedAverage {
+			t.Fatalf("expected stock valued as of %v by weighted average, got %v by %s", asOf, repo.asOf, repo.method)
+		}
+
+		if len(report.Products) != 3 {
+			t.Fatalf("expected the 3 products with stock movements, got %+v", report.Products)
+		}
+		if report.Products[0].Name != "Shirt" || report.Products[0].Quantity != 10 || report.Products[0].Value.Amount != 5000 || report.Products[0].Value.Currency != "USD" {
+			t.Fatalf("unexpected shirt valuation: %+v", report.Products[0])
+		}
+	})
+
+	t.Run("held units count toward the stock and its value", func(t *testing.T) {
+		report, err := svc.GetValuation(ctx, asOf, WeightedAverage)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if m := report.Products[1]; m.Quantity != 6 || m.Value.Amount != 1300 || m.Held != 2 || m.HeldValue.Amount != 300 {
+			t.Fatalf("unexpected mug valuation: %+v", m)
+		}
+	})
+
+	t.Run("totals per currency", func(t *testing.T) {
+		report, err := svc.GetValuation(ctx, asOf, FIFO)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if len(report.Totals) != 2 ||
+			report.Totals[0].Currency != "JPY" || report.Totals[0].Amount != 6000 ||
+			report.Totals[1].Currency != "USD" || report.Totals[1].Amount != 6300 {
+			t.Fatalf("unexpected totals: %+v", report.Totals)
+		}
+	})
+
+	t.Run("error on unknown method", func(t *testing.T) {
+		_, err := svc.GetValuation(ctx, asOf, Method("lifo"))
+		appErr, ok := err.(*apperrors.AppError)
+		if !ok || appErr.Code != apperrors.InvalidInput {
+			t.Fatalf("expected INVALID_INPUT, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+// Package eventbus fans stock events out to in-process subscribers, keeping the most
+// recent ones so that subscribers can resume where they left off after reconnecting.
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+)
+
+// subscriberBuffer is the number of messages a subscriber may fall behind before it is dropped.
+const subscriberBuffer = 64
+
+// Message is an event as delivered to subscribers. IDs increase in the order events are
+// published, starting again from 1 when the process restarts.
+type Message struct {
+	ID       uint64
+	TenantID string
+	Event    product.Event
+}
+
+// Filter selects the messages a subscription receives. Messages of other tenants are
+// never delivered.
+type Filter struct {
+	TenantID string
+	// ProductIDs limits messages to events of these products when set
+	ProductIDs []uuid.UUID
+	// LowStock limits messages to product.EventLowStock events
+	LowStock bool
+}
+
+func (f Filter) matches(m Message) bool {
+	if m.TenantID != f.TenantID {
+		return false
+	}
+
+	if len(f.ProductIDs) > 0 && !slices.Contains(f.ProductIDs, m.Event.ProductID) {
+		return false
+	}
+
+	return !f.LowStock || m.Event.Type == product.EventLowStock
+}
+
+// Subscription receives the messages matching its filter until it is closed.
+type Subscription struct {
+	bus    *Bus
+	filter Filter
+	ch     chan Message
+}
+
+// Messages returns the channel messages are delivered on. It is closed when the
+// subscription is closed, when the bus is closed, or when the subscriber falls too far
+// behind; the subscriber may then subscribe again from the last message it received.
+func (s *Subscription) Messages() <-chan Message {
+	return s.ch
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
+
+// Bus is an in-process product.Publisher that delivers each event to the subscriptions
+// whose filter it matches, keeping the last events published for subscriptions resuming
+// from an earlier message.
+type Bus struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []Message
+	size    int
+	subs    map[*Subscription]struct{}
+	closed  bool
+}
+
+// New returns a Bus that keeps the last historySize events for resuming subscriptions.
+func New(historySize int) *Bus {
+	return &Bus{
+		size: max(historySize, 0),
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish implements product.Publisher. The event belongs to the tenant of ctx.
+func (b *Bus) Publish(ctx context.Context, e product.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.lastID++
+	m := Message{ID: b.lastID, TenantID: tenant.FromContext(ctx), Event: e}
+
+	if b.size > 0 {
+		if len(b.history) == b.size {
+			b.history = slices.Delete(b.history, 0, 1)
+		}
+		b.history = append(b.history, m)
+	}
+
+	for s := range b.subs {
+		if !s.filter.matches(m) {
+			continue
+		}
+
+		select {
+		case s.ch <- m:
+		default:
+			// Never block publishers on a slow subscriber; it can resume from history
+			b.remove(s)
+		}
+	}
+}
+
+// LastID returns the ID of the last message published, or zero before the first.
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.lastID
+}
+
+// Subscribe starts a subscription to the messages matching filter. When lastID is not
+// zero, the matching messages published after it that are still kept are returned to be
+// delivered first, and complete reports whether they are all of them: it is false when
+// lastID is older than the kept messages or was issued before the process restarted.
+func (b *Bus) Subscribe(filter Filter, lastID uint64) (sub *Subscription, replay []Message, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{bus: b, filter: filter, ch: make(chan Message, subscriberBuffer)}
+	if b.closed {
+		close(sub.ch)
+		return sub, nil, false
+	}
+	b.subs[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+
+	for _, m := range b.history {
+		if m.ID > lastID && filter.matches(m) {
+			replay = append(replay, m)
+		}
+	}
+
+	oldest := b.lastID + 1
+	if len(b.history) > 0 {
+		oldest = b.history[0].ID
+	}
+
+	return sub, replay, lastID <= b.lastID && lastID+1 >= oldest
+}
+
+// Close ends every subscription and stops accepting new ones, so that open streams finish
+// before the server shuts down.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subs {
+		b.remove(s)
+	}
+}
+
+// remove ends the subscription if it is still open. The caller must hold b.mu.
+func (b *Bus) remove(s *Subscription) {
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+
+	delete(b.subs, s)
+	close(s.ch)
+}
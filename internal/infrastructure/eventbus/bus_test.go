@@ -0,0 +1,139 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+)
+
+func receive(t *testing.T, sub *Subscription) []Message {
+	t.Helper()
+
+	var got []Message
+	for {
+		select {
+		case m, ok := <-sub.Messages():
+			if !ok {
+				return got
+			}
+			got = append(got, m)
+		default:
+			return got
+		}
+	}
+}
+
+func TestBus_Filters(t *testing.T) {
+	bus := New(10)
+	acme := tenant.WithTenant(context.Background(), "acme")
+	widget, gadget := uuid.New(), uuid.New()
+
+	all := subscribe(t, bus, Filter{TenantID: "acme"})
+	one := subscribe(t, bus, Filter{TenantID: "acme", ProductIDs: []uuid.UUID{widget}})
+	low := subscribe(t, bus, Filter{TenantID: "acme", LowStock: true})
+	other := subscribe(t, bus, Filter{TenantID: "globex"})
+
+	bus.Publish(acme, product.Event{Type: product.EventStockChanged, ProductID: widget})
+	bus.Publish(acme, product.Event{Type: product.EventStockChanged, ProductID: gadget})
+	bus.Publish(acme, product.Event{Type: product.EventLowStock, ProductID: gadget})
+
+	if got := receive(t, all); len(got) != 3 || got[0].ID != 1 || got[2].ID != 3 || got[0].TenantID != "acme" {
+		t.Fatalf("expected every event of the tenant in order, got %+v", got)
+	}
+	if got := receive(t, one); len(got) != 1 || got[0].Event.ProductID != widget {
+		t.Fatalf("expected only the widget's event, got %+v", got)
+	}
+	if got := receive(t, low); len(got) != 1 || got[0].Event.Type != product.EventLowStock {
+		t.Fatalf("expected only the low-stock event, got %+v", got)
+	}
+	if got := receive(t, other); len(got) != 0 {
+		t.Fatalf("expected no events of another tenant, got %+v", got)
+	}
+}
+
+// subscribe starts a subscription from now on, closed when the test ends.
+func subscribe(t *testing.T, b *Bus, filter Filter) *Subscription {
+	sub, _, _ := b.Subscribe(filter, 0)
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+func TestBus_Resume(t *testing.T) {
+	bus := New(3)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	filter := Filter{TenantID: "acme"}
+
+	for range 5 {
+		bus.Publish(ctx, product.Event{Type: product.EventStockChanged, ProductID: uuid.New()})
+	}
+
+	t.Run("reports the last event published", func(t *testing.T) {
+		if id := bus.LastID(); id != 5 {
+			t.Fatalf("expected event 5, got %d", id)
+		}
+	})
+
+	t.Run("replays the events after the last one received", func(t *testing.T) {
+		sub, replay, complete := bus.Subscribe(filter, 3)
+		defer sub.Close()
+
+		if !complete || len(replay) != 2 || replay[0].ID != 4 || replay[1].ID != 5 {
+			t.Fatalf("expected events 4 and 5, got %+v (complete: %v)", replay, complete)
+		}
+	})
+
+	t.Run("reports events no longer kept", func(t *testing.T) {
+		sub, replay, complete := bus.Subscribe(filter, 1)
+		defer sub.Close()
+
+		if complete || len(replay) != 3 {
+			t.Fatalf("expected the 3 kept events and an incomplete replay, got %+v (complete: %v)", replay, complete)
+		}
+	})
+
+	t.Run("reports IDs from before a restart", func(t *testing.T) {
+		sub, replay, complete := bus.Subscribe(filter, 42)
+		defer sub.Close()
+
+		if complete || len(replay) != 0 {
+			t.Fatalf("expected nothing to replay and an incomplete replay, got %+v (complete: %v)", replay, complete)
+		}
+	})
+}
+
+func TestBus_DropsSlowSubscribers(t *testing.T) {
+	bus := New(0)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	sub := subscribe(t, bus, Filter{TenantID: "acme"})
+
+	for range subscriberBuffer + 1 {
+		bus.Publish(ctx, product.Event{Type: product.EventStockChanged})
+	}
+
+	if got := receive(t, sub); len(got) != subscriberBuffer {
+		t.Fatalf("expected the buffered %d events before the subscription closed, got %d", subscriberBuffer, len(got))
+	}
+	if _, open := <-sub.Messages(); open {
+		t.Fatal("expected the subscription of a slow subscriber to be closed")
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := New(10)
+	sub := subscribe(t, bus, Filter{TenantID: "acme"})
+
+	bus.Close()
+
+	if _, open := <-sub.Messages(); open {
+		t.Fatal("expected open subscriptions to be closed")
+	}
+
+	late, _, _ := bus.Subscribe(Filter{TenantID: "acme"}, 0)
+	if _, open := <-late.Messages(); open {
+		t.Fatal("expected subscriptions after Close to be closed")
+	}
+	late.Close()
+}
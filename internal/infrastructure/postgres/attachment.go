@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/attachment"
+)
+
+type attachmentRepository struct {
+	conn *ConnectionManager
+}
+
+func NewAttachmentRepository(conn *ConnectionManager) attachment.Repository {
+	return &attachmentRepository{
+		conn: conn,
+	}
+}
+
+// Create implements attachment.Repository.
+func (r *attachmentRepository) Create(ctx context.Context, a *attachment.Attachment) error {
+	if err := r.conn.Writer(ctx).Create(a).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements attachment.Repository.
+func (r *attachmentRepository) GetAll(ctx context.Context, productID string) ([]attachment.Attachment, error) {
+	attachments := []attachment.Attachment{}
+
+	if err := r.conn.Reader(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at").
+		Find(&attachments).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetByID implements attachment.Repository.
+func (r *attachmentRepository) GetByID(ctx context.Context, productID string, id string) (*attachment.Attachment, error) {
+	var a attachment.Attachment
+
+	if err := r.conn.Reader(ctx).First(&a, "id = ? AND product_id = ?", id, productID).Error; err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// Delete implements attachment.Repository.
+func (r *attachmentRepository) Delete(ctx context.Context, id string) error {
+	if err := r.conn.Writer(ctx).Unscoped().Delete(&attachment.Attachment{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListOrphaned implements attachment.Repository.
+func (r *attachmentRepository) ListOrphaned(ctx context.Context, deletedBefore time.Time) ([]attachment.Attachment, error) {
+	var attachments []attachment.Attachment
+
+	// Soft-deleted products can be restored, so their attachments are kept until the
+	// deletion is older than deletedBefore
+	if err := r.conn.Writer(ctx).
+		Where("NOT EXISTS (SELECT 1 FROM products p WHERE p.id = attachments.product_id AND (p.deleted_at IS NULL OR p.deleted_at >= ?))", deletedBefore).
+		Find(&attachments).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
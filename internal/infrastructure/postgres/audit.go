@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+)
+
+// AuditTriggers make audit_entries append-only in the database: updates, deletes and
+// truncation are rejected whichever client attempts them. The statements are idempotent
+// and run after the schema is migrated.
+var AuditTriggers = []string{
+	`CREATE OR REPLACE FUNCTION audit_entries_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit entries are append-only';
+	END;
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS audit_entries_no_change ON audit_entries`,
+	`CREATE TRIGGER audit_entries_no_change BEFORE UPDATE OR DELETE ON audit_entries
+		FOR EACH ROW EXECUTE FUNCTION audit_entries_append_only()`,
+	`DROP TRIGGER IF EXISTS audit_entries_no_truncate ON audit_entries`,
+	`CREATE TRIGGER audit_entries_no_truncate BEFORE TRUNCATE ON audit_entries
+		FOR EACH STATEMENT EXECUTE FUNCTION audit_entries_append_only()`,
+}
+
+type auditRepository struct {
+	conn *ConnectionManager
+}
+
+func NewAuditRepository(conn *ConnectionManager) audit.Repository {
+	return &auditRepository{
+		conn: conn,
+	}
+}
+
+// Create implements audit.Repository.
+func (r *auditRepository) Create(ctx context.Context, e *audit.Entry) error {
+	if err := r.conn.Writer(ctx).Create(e).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List implements audit.Repository.
+func (r *auditRepository) List(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	entries := []audit.Entry{}
+
+	q := r.conn.Reader(ctx).Where("entity_type = ?", filter.EntityType)
+
+	if filter.EntityID != "" {
+		q = q.Where("entity_id = ?", filter.EntityID)
+	}
+
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	if err := q.Order("occurred_at DESC, id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"gorm.io/gorm/clause"
+)
+
+// categoryTreeCTE selects the given category (first argument) and all of its live descendants as "tree".
+// UNION drops categories already selected, so the recursion ends even if the hierarchy holds a cycle.
+const categoryTreeCTE = `WITH RECURSIVE tree AS (
+	SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+)`
+
+// categoryAncestorsCTE selects the given category (first argument) and all of its live ancestors
+// as "ancestors", ending like categoryTreeCTE even if the hierarchy holds a cycle.
+const categoryAncestorsCTE = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id WHERE c.deleted_at IS NULL
+)`
+
+type categoryRepository struct {
+	conn *ConnectionManager
+}
+
+func NewCategoryRepository(conn *ConnectionManager) category.Repository {
+	return &categoryRepository{
+		conn: conn,
+	}
+}
+
+// Create implements category.Repository.
+func (r *categoryRepository) Create(ctx context.Context, c *category.Category) error {
+	if err := r.conn.Writer(ctx).Omit(clause.Associations).Create(c).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements category.Repository.
+func (r *categoryRepository) GetAll(ctx context.Context) ([]category.Category, error) {
+	var categories []category.Category
+
+	if err := r.conn.Reader(ctx).Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// GetByID implements category.Repository.
+func (r *categoryRepository) GetByID(ctx context.Context, id string) (*category.Category, error) {
+	var c category.Category
+
+	if err := r.conn.Reader(ctx).Preload("Children").First(&c, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Update implements category.Repository.
+func (r *categoryRepository) Update(ctx context.Context, c *category.Category) error {
+	// Select the columns explicitly so that a nil parent moves the category to the root
+	if err := r.conn.Writer(ctx).
+		Model(c).
+		Select("name", "description", "parent_id", "attribute_schema").
+		Updates(c).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements category.Repository.
+func (r *categoryRepository) Delete(ctx context.Context, id string) error {
+	if err := r.conn.Writer(ctx).Delete(&category.Category{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Transaction implements category.Repository.
+func (r *categoryRepository) Transaction(ctx context.Context, fn func(category.Repository) error) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		return fn(NewCategoryRepository(tx))
+	})
+}
+
+// LockAncestors implements category.Repository.
+func (r *categoryRepository) LockAncestors(ctx context.Context, id string, parentID string) ([]uuid.UUID, error) {
+	var locked []uuid.UUID
+
+	// Lock in ID order, so that concurrent moves wait for each other instead of deadlocking
+	if err := r.conn.Writer(ctx).
+		Raw(categoryAncestorsCTE+` SELECT id FROM categories
+WHERE id = ? OR id IN (SELECT id FROM ancestors)
+ORDER BY id
+FOR UPDATE`, parentID, id).
+		Scan(&locked).
+		Error; err != nil {
+		return nil, err
+	}
+
+	// Read the chain again, since a move committed while waiting for the locks is only
+	// visible to a later statement
+	var ids []uuid.UUID
+
+	if err := r.conn.Writer(ctx).
+		Raw(categoryAncestorsCTE+" SELECT id FROM ancestors", parentID).
+		Scan(&ids).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// DescendantIDs implements category.Repository.
+func (r *categoryRepository) DescendantIDs(ctx context.Context, id string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	if err := r.conn.Reader(ctx).
+		Raw(categoryTreeCTE+" SELECT id FROM tree WHERE id <> ?", id, id).
+		Scan(&ids).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// LockProducts implements category.Repository.
+func (r *categoryRepository) LockProducts(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var found []uuid.UUID
+
+	// Lock in ID order, so that concurrent assignments wait for each other instead of deadlocking
+	if err := r.conn.Writer(ctx).
+		Model(&product.Product{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Pluck("id", &found).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// LockAssignedProducts implements category.Repository.
+func (r *categoryRepository) LockAssignedProducts(ctx context.Context, categoryID string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	if err := r.conn.Writer(ctx).
+		Raw(`SELECT p.id FROM products p
+WHERE p.deleted_at IS NULL
+	AND p.id IN (SELECT product_id FROM product_categories WHERE category_id = ?)
+ORDER BY p.id
+FOR UPDATE OF p`, categoryID).
+		Scan(&ids).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ProductAttributes implements category.Repository.
+func (r *categoryRepository) ProductAttributes(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]map[string]any, error) {
+	var products []product.Product
+
+	if err := r.conn.Reader(ctx).
+		Select("id", "attributes").
+		Where("id IN ?", ids).
+		Find(&products).
+		Error; err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[uuid.UUID]map[string]any, len(products))
+	for _, p := range products {
+		attributes[p.ID] = p.Attributes
+	}
+
+	return attributes, nil
+}
+
+// AssignProducts implements category.Repository.
+func (r *categoryRepository) AssignProducts(ctx context.Context, categoryID string, productIDs []uuid.UUID) error {
+	rows := make([]map[string]any, 0, len(productIDs))
+	for _, id := range productIDs {
+		rows = append(rows, map[string]any{
+			"category_id": categoryID,
+			"product_id":  id,
+		})
+	}
+
+	if err := r.conn.Writer(ctx).
+		Table("product_categories").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnassignProduct implements category.Repository.
+func (r *categoryRepository) UnassignProduct(ctx context.Context, categoryID string, productID string) error {
+	if err := r.conn.Writer(ctx).
+		Exec("DELETE FROM product_categories WHERE category_id = ? AND product_id = ?", categoryID, productID).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StockSummary implements category.Repository.
+func (r *categoryRepository) StockSummary(ctx context.Context, id string) (*category.StockSummary, error) {
+	var summary category.StockSummary
+
+	if err := r.conn.Reader(ctx).
+		Raw(categoryTreeCTE+`
+SELECT
+	COUNT(*) AS product_count,
+	COALESCE(SUM(p.stock_quantity), 0) AS total_units,
+	COUNT(*) FILTER (WHERE p.stock_quantity <= p.low_stock_thresold) AS low_stock_count
+FROM products p
+WHERE p.deleted_at IS NULL
+	AND p.id IN (SELECT product_id FROM product_categories WHERE category_id IN (SELECT id FROM tree))`, id).
+		Scan(&summary).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
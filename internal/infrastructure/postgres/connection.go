@@ -1,34 +1,301 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/xxthunderblastxx/ase-challenge/internal/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// maxConnectBackoff caps the exponential backoff between startup connection attempts.
+const maxConnectBackoff = 30 * time.Second
+
+// healthCheckTimeout bounds a single health-check ping.
+const healthCheckTimeout = 3 * time.Second
+
+type readOnlyKey struct{}
+
+// WithReadOnly marks the context as read-only so that repository reads may be served by the replica.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+func isReadOnly(ctx context.Context) bool {
+	v, _ := ctx.Value(readOnlyKey{}).(bool)
+	return v
+}
+
+// ConnectionManager owns the primary (and optional replica) connection pools
+// and tracks database readiness through a periodic health check.
 type ConnectionManager struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Replica *gorm.DB
+
+	ready        atomic.Bool
+	replicaReady atomic.Bool
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+
+	// commitHooks are run once the outermost transaction commits; nil outside of one
+	commitHooks *[]func()
 }
 
+// MustConnect connects to the database and exits the process if every attempt fails.
 func MustConnect(cfg *config.PostgresConfig) *ConnectionManager {
+	cm, err := Connect(cfg)
+	if err != nil {
+		log.Fatalf("☹️ failed to connect database: %v", err)
+	}
+
+	return cm
+}
+
+// Connect opens the primary pool (and replica pool, when configured), retrying with
+// exponential backoff, and starts the background health check.
+func Connect(cfg *config.PostgresConfig) (*ConnectionManager, error) {
+	db, err := openWithRetry(cfg, cfg.Host, cfg.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("🚀 Connected to the database successfully")
+
+	cm := &ConnectionManager{
+		DB:   db,
+		stop: make(chan struct{}),
+	}
+	cm.ready.Store(true)
+
+	if cfg.ReplicaHost != "" {
+		replica, err := openWithRetry(cfg, cfg.ReplicaHost, cfg.ReplicaPort)
+		if err != nil {
+			// The replica is optional; reads fall back to the primary.
+			log.Printf("⚠️ failed to connect read replica, reads will use the primary: %v", err)
+		} else {
+			log.Println("🚀 Connected to the read replica successfully")
+			cm.Replica = replica
+			cm.replicaReady.Store(true)
+		}
+	}
+
+	if cfg.HealthCheckInterval > 0 {
+		cm.wg.Add(1)
+		go cm.monitor(cfg.HealthCheckInterval)
+	}
+
+	return cm, nil
+}
+
+// openWithRetry opens a pooled connection to host:port and pings it, retrying with backoff.
+func openWithRetry(cfg *config.PostgresConfig, host, port string) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
-		cfg.Host, cfg.User, cfg.Password, cfg.DB, cfg.Port,
+		host, cfg.User, cfg.Password, cfg.DB, port,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	attempts := cfg.ConnectRetries + 1
+	backoff := cfg.ConnectBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		db, err := open(dsn, cfg)
+		if err == nil {
+			return db, nil
+		}
+		lastErr = err
+
+		if attempt == attempts {
+			break
+		}
+
+		log.Printf("⏳ database connection attempt %d/%d to %s failed: %v (retrying in %s)", attempt, attempts, host, err, backoff)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, lastErr)
+}
+
+func open(dsn string, cfg *config.PostgresConfig) (*gorm.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
 	if err != nil {
-		log.Fatalf("☹️ failed to connect database: %v", err)
+		return nil, err
 	}
 
-	log.Println("🚀 Connected to the database successfully")
+	// Every connection carries the tenant of the statement it runs for row-level security
+	sqlDB := sql.OpenDB(tenantConnector{stdlib.GetConnector(*connConfig)})
 
-	cm := &ConnectionManager{
-		DB: db,
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, err
 	}
 
-	return cm
+	if err := registerTenantScope(db); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// monitor pings the pools on every tick and flips readiness on state changes.
+func (cm *ConnectionManager) monitor(interval time.Duration) {
+	defer cm.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cm.stop:
+			return
+		case <-ticker.C:
+			cm.check(cm.DB, &cm.ready, "database")
+			if cm.Replica != nil {
+				cm.check(cm.Replica, &cm.replicaReady, "read replica")
+			}
+		}
+	}
+}
+
+func (cm *ConnectionManager) check(db *gorm.DB, flag *atomic.Bool, name string) {
+	err := ping(db)
+	healthy := err == nil
+
+	if flag.Swap(healthy) != healthy {
+		if healthy {
+			log.Printf("✅ %s is healthy again", name)
+		} else {
+			log.Printf("☹️ %s health check failed: %v", name, err)
+		}
+	}
+}
+
+func ping(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	return sqlDB.PingContext(ctx)
+}
+
+// IsReady reports whether the last health check against the primary succeeded.
+func (cm *ConnectionManager) IsReady() bool {
+	return cm.ready.Load()
+}
+
+// Writer returns the primary pool bound to ctx.
+func (cm *ConnectionManager) Writer(ctx context.Context) *gorm.DB {
+	return cm.DB.WithContext(ctx)
+}
+
+// Reader returns the pool that should serve a read bound to ctx. The replica is used
+// only when it is healthy and the context has been marked with WithReadOnly.
+func (cm *ConnectionManager) Reader(ctx context.Context) *gorm.DB {
+	if cm.Replica != nil && cm.replicaReady.Load() && isReadOnly(ctx) {
+		return cm.Replica.WithContext(ctx)
+	}
+
+	return cm.DB.WithContext(ctx)
+}
+
+// Transaction runs fn inside a database transaction on the primary. The ConnectionManager
+// passed to fn routes every read and write through the transaction, so repositories built
+// from it take part in the same unit of work. Called on a transaction's ConnectionManager,
+// it nests a savepoint inside that transaction.
+func (cm *ConnectionManager) Transaction(ctx context.Context, fn func(*ConnectionManager) error) error {
+	hooks := cm.commitHooks
+	outermost := hooks == nil
+	if outermost {
+		hooks = new([]func())
+	}
+	registered := len(*hooks)
+
+	err := cm.Writer(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&ConnectionManager{DB: tx, commitHooks: hooks})
+	})
+	if err != nil {
+		// Work rolled back to a savepoint must not be announced when the outer transaction commits
+		*hooks = (*hooks)[:registered]
+		return err
+	}
+
+	if outermost {
+		for _, hook := range *hooks {
+			hook()
+		}
+	}
+
+	return nil
+}
+
+// AfterCommit runs fn once the transaction of cm commits, or at once outside of a transaction.
+func (cm *ConnectionManager) AfterCommit(fn func()) {
+	if cm.commitHooks == nil {
+		fn()
+		return
+	}
+
+	*cm.commitHooks = append(*cm.commitHooks, fn)
+}
+
+// Close stops the health check and closes every pool. It is safe to call more than once.
+func (cm *ConnectionManager) Close() error {
+	var errs []error
+
+	cm.closeOnce.Do(func() {
+		close(cm.stop)
+		cm.wg.Wait()
+		cm.ready.Store(false)
+
+		for _, db := range []*gorm.DB{cm.DB, cm.Replica} {
+			if db == nil {
+				continue
+			}
+			sqlDB, err := db.DB()
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+
+	return errors.Join(errs...)
 }
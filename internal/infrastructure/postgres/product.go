@@ -1,7 +1,17 @@
 package postgres
 
 import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
 	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -15,8 +25,8 @@ func NewProductRepository(conn *ConnectionManager) product.Repository {
 }
 
 // Create implements product.Repository.
-func (r *productRepository) Create(product *product.Product) error {
-	if err := r.conn.DB.Create(product).Error; err != nil {
+func (r *productRepository) Create(ctx context.Context, product *product.Product) error {
+	if err := r.conn.Writer(ctx).Omit(clause.Associations).Create(product).Error; err != nil {
 		return err
 	}
 
@@ -24,30 +34,189 @@ func (r *productRepository) Create(product *product.Product) error {
 }
 
 // Delete implements product.Repository.
-func (r *productRepository) Delete(id string) error {
-	if err := r.conn.DB.Delete(&product.Product{}, "id = ?", id).Error; err != nil {
+func (r *productRepository) Delete(ctx context.Context, id string) error {
+	if err := r.conn.Writer(ctx).Delete(&product.Product{}, "id = ?", id).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// GetDeletedByID implements product.Repository.
+func (r *productRepository) GetDeletedByID(ctx context.Context, id string) (*product.Product, error) {
+	var p product.Product
+
+	if err := r.conn.Writer(ctx).
+		Unscoped().
+		Preload("Categories").Preload("Options").Preload("Variants").Preload("Units").Preload("Components.Component").
+		Where("deleted_at IS NOT NULL").
+		First(&p, "id = ?", id).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Restore implements product.Repository.
+func (r *productRepository) Restore(ctx context.Context, id string) error {
+	if err := r.conn.Writer(ctx).
+		Unscoped().
+		Model(&product.Product{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{"deleted_at": nil, "updated_at": time.Now()}).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateAuditEntry implements product.Repository.
+func (r *productRepository) CreateAuditEntry(ctx context.Context, e *audit.Entry) error {
+	return NewAuditRepository(r.conn).Create(ctx, e)
+}
+
+// kitStockSQL is the stock of "products": the kits its live components make up, as in
+// the service, or its own stock for a product that is not a kit.
+const kitStockSQL = `COALESCE((
+	SELECT MIN(GREATEST(COALESCE(c.stock_quantity - c.reserved_quantity, 0), 0) / kc.quantity)
+	FROM kit_components kc
+	LEFT JOIN products c ON c.id = kc.component_id AND c.deleted_at IS NULL
+	WHERE kc.kit_id = products.id AND kc.deleted_at IS NULL
+), products.stock_quantity)`
+
+// ledgerStockSQL is the stock of "products" replayed from the ledger up to and including
+// the time given as its argument, as in StockAsOf.
+const ledgerStockSQL = `COALESCE((
+	SELECT SUM(m.quantity) FROM stock_movements m
+	WHERE m.product_id = products.id AND m.deleted_at IS NULL AND m.occurred_at <= ?
+), 0)`
+
 // GetAll implements product.Repository.
-func (r *productRepository) GetAll() ([]product.Product, error) {
+func (r *productRepository) GetAll(ctx context.Context, filter product.Filter) ([]product.Product, error) {
 	var products []product.Product
 
-	if err := r.conn.DB.Find(&products).Error; err != nil {
+	q, err := r.filtered(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.AsOf.IsZero() {
+		q = q.Preload("Categories").Preload("Options").Preload("Variants").Preload("Units").Preload("Components.Component")
+	}
+
+	// Order the listing so that pages do not overlap
+	q = q.Order("created_at, id")
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		q = q.Offset(filter.Offset)
+	}
+
+	if err := q.Find(&products).Error; err != nil {
 		return nil, err
 	}
 
 	return products, nil
 }
 
+// Count implements product.Repository.
+func (r *productRepository) Count(ctx context.Context, filter product.Filter) (int64, error) {
+	var count int64
+
+	q, err := r.filtered(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := q.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// filtered selects the products matching the filter, ignoring its paging.
+func (r *productRepository) filtered(ctx context.Context, filter product.Filter) (*gorm.DB, error) {
+	q := r.conn.Reader(ctx).Model(&product.Product{})
+	if !filter.AsOf.IsZero() {
+		// Versions stand in for the table; those of deleted products are left out by the
+		// soft-delete condition on the alias
+		q = q.Table("(?) AS products", r.conn.Reader(ctx).Raw(productVersionsAt, filter.AsOf, filter.AsOf))
+	}
+
+	if filter.CategoryID != "" {
+		if filter.IncludeDescendants {
+			q = q.Where("id IN (SELECT product_id FROM product_categories WHERE category_id IN ("+categoryTreeCTE+" SELECT id FROM tree))", filter.CategoryID)
+		} else {
+			q = q.Where("id IN (SELECT product_id FROM product_categories WHERE category_id = ?)", filter.CategoryID)
+		}
+	}
+
+	if len(filter.IDs) > 0 {
+		q = q.Where("id IN ?", filter.IDs)
+	}
+
+	for name, value := range filter.Attributes {
+		conditions, args, err := attributeConditions(name, value)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where(conditions, args...)
+	}
+
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where("tags @> ?::jsonb", string(tags))
+	}
+
+	if filter.LowStock {
+		if filter.AsOf.IsZero() {
+			q = q.Where(kitStockSQL + " <= products.low_stock_thresold")
+		} else {
+			q = q.Where(ledgerStockSQL+" <= products.low_stock_thresold", filter.AsOf)
+		}
+	}
+
+	return q, nil
+}
+
+// attributeConditions matches products whose attribute has the value given in a query
+// string. The value also matches a number or boolean attribute it parses as, so
+// attr.size=10 finds {"size": 10} as well as {"size": "10"}.
+func attributeConditions(name, value string) (string, []any, error) {
+	candidates := []any{value}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		candidates = append(candidates, f)
+	}
+	if value == "true" || value == "false" {
+		candidates = append(candidates, value == "true")
+	}
+
+	conditions := make([]string, 0, len(candidates))
+	args := make([]any, 0, len(candidates))
+	for _, v := range candidates {
+		doc, err := json.Marshal(map[string]any{name: v})
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "attributes @> ?::jsonb")
+		args = append(args, string(doc))
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+}
+
 // GetByID implements product.Repository.
-func (r *productRepository) GetByID(id string) (*product.Product, error) {
+func (r *productRepository) GetByID(ctx context.Context, id string) (*product.Product, error) {
 	var p product.Product
 
-	if err := r.conn.DB.First(&p, "id = ?", id).Error; err != nil {
+	if err := r.conn.Reader(ctx).Preload("Categories").Preload("Options").Preload("Variants").Preload("Units").Preload("Components.Component").First(&p, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -55,16 +224,22 @@ func (r *productRepository) GetByID(id string) (*product.Product, error) {
 }
 
 // UpdateAllColumn implements product.Repository.
-func (r *productRepository) UpdateAllColumn(id string, product *product.Product) error {
-	if err := r.conn.DB.Updates(product).Error; err != nil {
+func (r *productRepository) UpdateAllColumn(ctx context.Context, id string, p *product.Product) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.Product{}).
+		Where("id = ?", id).
+		Select("*").
+		Omit(clause.Associations, "id", "tenant_id", "created_at", "deleted_at").
+		Updates(p).
+		Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *productRepository) UpdateSingleColumn(id string, column string, value any) error {
-	if err := r.conn.DB.
+func (r *productRepository) UpdateSingleColumn(ctx context.Context, id string, column string, value any) error {
+	if err := r.conn.Writer(ctx).
 		Model(&product.Product{}).
 		Where("id = ?", id).
 		Update(column, value).
@@ -74,3 +249,452 @@ func (r *productRepository) UpdateSingleColumn(id string, column string, value a
 
 	return nil
 }
+
+// Transaction implements product.Repository.
+func (r *productRepository) Transaction(ctx context.Context, fn func(product.Repository) error) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		return fn(NewProductRepository(tx))
+	})
+}
+
+// AfterCommit implements product.Repository.
+func (r *productRepository) AfterCommit(fn func()) {
+	r.conn.AfterCommit(fn)
+}
+
+// LockProduct implements product.Repository.
+func (r *productRepository) LockProduct(ctx context.Context, id string) error {
+	var p product.Product
+
+	if err := r.conn.Writer(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&p, "id = ?", id).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceOptions implements product.Repository.
+func (r *productRepository) ReplaceOptions(ctx context.Context, productID string, options []product.OptionAxis) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		if err := tx.Writer(ctx).
+			Unscoped().
+			Delete(&product.OptionAxis{}, "product_id = ?", productID).
+			Error; err != nil {
+			return err
+		}
+
+		if len(options) == 0 {
+			return nil
+		}
+
+		return tx.Writer(ctx).Create(&options).Error
+	})
+}
+
+// ReplaceUnits implements product.Repository.
+func (r *productRepository) ReplaceUnits(ctx context.Context, productID string, units []product.UnitOfMeasure) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		if err := tx.Writer(ctx).
+			Unscoped().
+			Delete(&product.UnitOfMeasure{}, "product_id = ?", productID).
+			Error; err != nil {
+			return err
+		}
+
+		if len(units) == 0 {
+			return nil
+		}
+
+		return tx.Writer(ctx).Create(&units).Error
+	})
+}
+
+// ReplaceKitComponents implements product.Repository.
+func (r *productRepository) ReplaceKitComponents(ctx context.Context, kitID string, components []product.KitComponent) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		if err := tx.Writer(ctx).
+			Unscoped().
+			Delete(&product.KitComponent{}, "kit_id = ?", kitID).
+			Error; err != nil {
+			return err
+		}
+
+		if len(components) == 0 {
+			return nil
+		}
+
+		return tx.Writer(ctx).Omit("Component").Create(&components).Error
+	})
+}
+
+// IsKitComponent implements product.Repository.
+func (r *productRepository) IsKitComponent(ctx context.Context, productID string) (bool, error) {
+	var count int64
+
+	if err := r.conn.Reader(ctx).
+		Model(&product.KitComponent{}).
+		Where("component_id = ? AND kit_id IN (SELECT id FROM products WHERE deleted_at IS NULL)", productID).
+		Count(&count).
+		Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetKitIDs implements product.Repository.
+func (r *productRepository) GetKitIDs(ctx context.Context, productID string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	if err := r.conn.Reader(ctx).
+		Model(&product.KitComponent{}).
+		Where("component_id = ? AND kit_id IN (SELECT id FROM products WHERE deleted_at IS NULL)", productID).
+		Order("kit_id").
+		Pluck("kit_id", &ids).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetKitComponents implements product.Repository.
+func (r *productRepository) GetKitComponents(ctx context.Context, kitIDs []string) ([]product.KitComponent, error) {
+	var components []product.KitComponent
+
+	if err := r.conn.Reader(ctx).
+		Where("kit_id IN ?", kitIDs).
+		Find(&components).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// CreateVariant implements product.Repository.
+func (r *productRepository) CreateVariant(ctx context.Context, v *product.Variant) error {
+	if err := r.conn.Writer(ctx).Create(v).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetVariantByID implements product.Repository.
+func (r *productRepository) GetVariantByID(ctx context.Context, productID string, variantID string) (*product.Variant, error) {
+	var v product.Variant
+
+	if err := r.conn.Reader(ctx).First(&v, "id = ? AND product_id = ?", variantID, productID).Error; err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// UpdateVariant implements product.Repository.
+func (r *productRepository) UpdateVariant(ctx context.Context, v *product.Variant) error {
+	if err := r.conn.Writer(ctx).
+		Model(v).
+		Select("sku", "options", "low_stock_threshold").
+		Updates(v).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateVariantStock implements product.Repository.
+func (r *productRepository) UpdateVariantStock(ctx context.Context, variantID string, quantity int) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.Variant{}).
+		Where("id = ?", variantID).
+		Update("stock_quantity", quantity).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateVariantReserved implements product.Repository.
+func (r *productRepository) UpdateVariantReserved(ctx context.Context, variantID string, quantity int) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.Variant{}).
+		Where("id = ?", variantID).
+		Update("reserved_quantity", quantity).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteVariant implements product.Repository.
+func (r *productRepository) DeleteVariant(ctx context.Context, variantID string) error {
+	if err := r.conn.Writer(ctx).Delete(&product.Variant{}, "id = ?", variantID).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SyncVariantStock implements product.Repository.
+func (r *productRepository) SyncVariantStock(ctx context.Context, productID string) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.Product{}).
+		Where("id = ?", productID).
+		Update("stock_quantity", gorm.Expr(
+			"(SELECT COALESCE(SUM(stock_quantity), 0) FROM variants WHERE product_id = ? AND deleted_at IS NULL)",
+			productID,
+		)).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateLot implements product.Repository.
+func (r *productRepository) CreateLot(ctx context.Context, lot *product.Lot) error {
+	if err := r.conn.Writer(ctx).Create(lot).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLots implements product.Repository.
+func (r *productRepository) GetLots(ctx context.Context, productID string) ([]product.Lot, error) {
+	var lots []product.Lot
+
+	if err := r.conn.Reader(ctx).
+		Where("product_id = ?", productID).
+		Order("expires_at ASC NULLS LAST, created_at").
+		Find(&lots).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+// UpdateLotQuantity implements product.Repository.
+func (r *productRepository) UpdateLotQuantity(ctx context.Context, lotID string, quantity int) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.Lot{}).
+		Where("id = ?", lotID).
+		Update("quantity", quantity).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListExpiringLots implements product.Repository.
+func (r *productRepository) ListExpiringLots(ctx context.Context, before time.Time) ([]product.Lot, error) {
+	lots := []product.Lot{}
+
+	if err := r.conn.Reader(ctx).
+		Where("quantity > 0 AND expires_at < ?", before).
+		Order("expires_at, created_at").
+		Find(&lots).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+// CreateSerial implements product.Repository.
+func (r *productRepository) CreateSerial(ctx context.Context, serial *product.Serial) error {
+	if err := r.conn.Writer(ctx).Create(serial).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetSerials implements product.Repository.
+func (r *productRepository) GetSerials(ctx context.Context, productID string, numbers []string) ([]product.Serial, error) {
+	serials := []product.Serial{}
+
+	q := r.conn.Reader(ctx).Where("product_id = ?", productID)
+
+	if numbers != nil {
+		q = q.Where("serial_number IN ?", numbers)
+	}
+
+	if err := q.Order("serial_number").Find(&serials).Error; err != nil {
+		return nil, err
+	}
+
+	return serials, nil
+}
+
+// SetSerialsInStock implements product.Repository.
+func (r *productRepository) SetSerialsInStock(ctx context.Context, productID string, numbers []string, inStock bool) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.Serial{}).
+		Where("product_id = ? AND serial_number IN ?", productID, numbers).
+		Update("in_stock", inStock).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetBuckets implements product.Repository.
+func (r *productRepository) GetBuckets(ctx context.Context, productID string) ([]product.StockBucket, error) {
+	var buckets []product.StockBucket
+
+	if err := r.conn.Reader(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at").
+		Find(&buckets).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// CreateBucket implements product.Repository.
+func (r *productRepository) CreateBucket(ctx context.Context, bucket *product.StockBucket) error {
+	if err := r.conn.Writer(ctx).Create(bucket).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateBucket implements product.Repository.
+func (r *productRepository) UpdateBucket(ctx context.Context, bucketID string, quantity int, value int64) error {
+	if err := r.conn.Writer(ctx).
+		Model(&product.StockBucket{}).
+		Where("id = ?", bucketID).
+		Updates(map[string]any{"quantity": quantity, "value": value}).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateMovement implements product.Repository.
+func (r *productRepository) CreateMovement(ctx context.Context, m *product.StockMovement) error {
+	if err := r.conn.Writer(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// issueCostQuery costs the next @quantity units to leave a product's stock. The stock on
+// hand is the newest received, so the units leaving are the oldest of those: each receipt
+// counts for the part of it that lies among them.
+const issueCostQuery = `WITH ledger AS (
+	SELECT id, quantity, unit_cost, occurred_at, created_at
+	FROM stock_movements
+	WHERE deleted_at IS NULL
+		AND product_id = @product
+		AND variant_id IS NOT DISTINCT FROM @variant::uuid
+		AND (@tenant = '' OR tenant_id = @tenant)
+),
+on_hand AS (
+	SELECT coalesce((stock_value(quantity, unit_cost ORDER BY occurred_at, created_at, id))[1], 0) AS quantity
+	FROM ledger
+),
+receipts AS (
+	SELECT quantity, unit_cost,
+		sum(quantity) OVER (ORDER BY occurred_at DESC, created_at DESC, id DESC) - quantity AS newer
+	FROM ledger
+	WHERE quantity > 0
+)
+SELECT coalesce(sum(greatest(least(r.newer + r.quantity, o.quantity) - greatest(r.newer, o.quantity - @quantity), 0) * r.unit_cost), 0)::bigint
+FROM receipts r
+CROSS JOIN on_hand o`
+
+// IssueCost implements product.Repository.
+func (r *productRepository) IssueCost(ctx context.Context, productID string, variantID *uuid.UUID, quantity int) (int64, error) {
+	var cost int64
+
+	if err := r.conn.Writer(ctx).
+		Raw(issueCostQuery, map[string]any{
+			"product":  productID,
+			"variant":  variantID,
+			"quantity": quantity,
+			"tenant":   rawQueryTenant(ctx),
+		}).
+		Scan(&cost).
+		Error; err != nil {
+		return 0, err
+	}
+
+	return cost, nil
+}
+
+// ListMovements implements product.Repository. The latest movements of each product are
+// picked in the database by ranking them with a window function.
+func (r *productRepository) ListMovements(ctx context.Context, filter product.MovementFilter) ([]product.StockMovement, error) {
+	var movements []product.StockMovement
+
+	q, err := filterMovements(r.conn.Reader(ctx).Model(&product.StockMovement{}), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.LastPerProduct > 0 {
+		ranked, err := filterMovements(r.conn.Reader(ctx).Model(&product.StockMovement{}), filter)
+		if err != nil {
+			return nil, err
+		}
+		ranked = ranked.Select("id, row_number() OVER (PARTITION BY product_id ORDER BY occurred_at DESC, created_at DESC, id DESC) AS position")
+
+		q = q.Where("id IN (?)", r.conn.Reader(ctx).Table("(?) AS ranked", ranked).Select("id").Where("position <= ?", filter.LastPerProduct))
+	}
+
+	if err := q.Order("occurred_at, created_at").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// filterMovements narrows q to the movements matching the filter, before any limit.
+func filterMovements(q *gorm.DB, filter product.MovementFilter) (*gorm.DB, error) {
+	if filter.ProductID != "" {
+		q = q.Where("product_id = ?", filter.ProductID)
+	}
+	if len(filter.ProductIDs) > 0 {
+		q = q.Where("product_id IN ?", filter.ProductIDs)
+	}
+	if filter.ReferenceType != "" {
+		q = q.Where("reference_type = ?", filter.ReferenceType)
+	}
+	if filter.ReferenceID != "" {
+		q = q.Where("reference_id = ?", filter.ReferenceID)
+	}
+	if filter.Serial != "" {
+		serials, err := json.Marshal([]string{filter.Serial})
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where("serials @> ?::jsonb", string(serials))
+	}
+	if !filter.Since.IsZero() {
+		q = q.Where("occurred_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		q = q.Where("occurred_at < ?", filter.Until)
+	}
+
+	return q, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+)
+
+// ProductVersioning keeps product_versions in step with products: every insert or update
+// of a product, including a soft delete or restore, closes the current version and opens
+// a new one holding the row as written. Versions run on transaction time, so changes made
+// in one transaction leave a single version. Products that predate versioning get an
+// initial version from their creation time, and versions written before products had a
+// tenant are given the tenant of their row. The statements are idempotent and run after
+// the schema is migrated.
+var ProductVersioning = []string{
+	`CREATE OR REPLACE FUNCTION products_version() RETURNS trigger AS $$
+	BEGIN
+		UPDATE product_versions SET valid_to = now() WHERE product_id = NEW.id AND valid_to IS NULL;
+		INSERT INTO product_versions (id, tenant_id, product_id, valid_from, snapshot)
+			VALUES (gen_random_uuid(), NEW.tenant_id, NEW.id, now(), to_jsonb(NEW));
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS products_versioning ON products`,
+	`CREATE TRIGGER products_versioning AFTER INSERT OR UPDATE ON products
+		FOR EACH ROW EXECUTE FUNCTION products_version()`,
+	`INSERT INTO product_versions (id, tenant_id, product_id, valid_from, snapshot)
+	SELECT gen_random_uuid(), p.tenant_id, p.id, p.created_at, to_jsonb(p)
+	FROM products p
+	WHERE NOT EXISTS (SELECT 1 FROM product_versions v WHERE v.product_id = p.id)`,
+	`UPDATE product_versions SET snapshot = snapshot || jsonb_build_object('tenant_id', tenant_id)
+	WHERE snapshot->>'tenant_id' IS NULL`,
+}
+
+// productVersionsAt selects the version of every product that was current at the given
+// time (first and second arguments), decoded back into product rows.
+const productVersionsAt = `SELECT (jsonb_populate_record(NULL::products, snapshot)).*
+FROM product_versions
+WHERE valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)`
+
+// GetVersion implements product.Repository.
+func (r *productRepository) GetVersion(ctx context.Context, id string, asOf time.Time) (*product.Product, error) {
+	var p product.Product
+
+	versions := r.conn.Reader(ctx).Raw(productVersionsAt+" AND product_id = ?", asOf, asOf, id)
+
+	if err := r.conn.Reader(ctx).
+		Unscoped().
+		Table("(?) AS products", versions).
+		First(&p).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// StockAsOf implements product.Repository.
+func (r *productRepository) StockAsOf(ctx context.Context, productIDs []string, asOf time.Time) (map[string]int, error) {
+	var rows []struct {
+		ProductID string
+		Quantity  int
+	}
+
+	if err := r.conn.Reader(ctx).
+		Model(&product.StockMovement{}).
+		Select("product_id, SUM(quantity) AS quantity").
+		Where("product_id IN ? AND occurred_at <= ?", productIDs, asOf).
+		Group("product_id").
+		Scan(&rows).
+		Error; err != nil {
+		return nil, err
+	}
+
+	stock := make(map[string]int, len(rows))
+	for _, row := range rows {
+		stock[row.ProductID] = row.Quantity
+	}
+
+	return stock, nil
+}
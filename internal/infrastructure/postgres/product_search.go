@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+)
+
+// productDocument is the weighted full-text document of a product: its name ranks above
+// its description. SearchIndexes indexes this exact expression, so the two must match.
+const productDocument = `(setweight(to_tsvector('english', coalesce(name, '')), 'A') || ` +
+	`setweight(to_tsvector('english', coalesce(description, '')), 'B'))`
+
+// SearchIndexes enables trigram matching and creates the indexes used by product search.
+// The statements are idempotent and run after the schema is migrated.
+var SearchIndexes = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_products_search ON products USING GIN (` + productDocument + `)`,
+	`CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_products_description_trgm ON products USING GIN (description gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_variants_sku_trgm ON variants USING GIN (sku gin_trgm_ops)`,
+}
+
+// Highlighted words are delimited by control characters that ts_headline cannot mistake for
+// markup, so that the text can be HTML-escaped before they are turned into <mark> tags.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// searchQuery matches products whose document contains the search terms, or whose name,
+// description or a variant SKU is similar to the search text, so that misspelt words
+// still match. Full-text matches rank by ts_rank_cd; similarity adds to the rank, and an
+// exact SKU prefix ranks first. Matches are confined to @tenant unless it is empty.
+const searchQuery = `
+WITH query AS (
+	SELECT websearch_to_tsquery('english', @text) AS tsq
+),
+skus AS (
+	SELECT DISTINCT ON (v.product_id) v.product_id, v.sku,
+		CASE WHEN v.sku ILIKE @prefix THEN 1.0 ELSE similarity(v.sku, @text) END AS score
+	FROM variants v
+	WHERE v.deleted_at IS NULL AND (@tenant = '' OR v.tenant_id = @tenant)
+		AND v.sku <> '' AND (v.sku ILIKE @prefix OR v.sku % @text)
+	ORDER BY v.product_id, score DESC
+),
+matches AS (
+	SELECT p.id, p.name, p.description, s.sku,
+		ts_rank_cd(` + productDocument + `, query.tsq)
+			+ greatest(word_similarity(@text, p.name), similarity(p.name, @text), word_similarity(@text, p.description) / 2)
+			+ coalesce(s.score, 0) AS rank
+	FROM products p
+	CROSS JOIN query
+	LEFT JOIN skus s ON s.product_id = p.id
+	WHERE p.deleted_at IS NULL AND (@tenant = '' OR p.tenant_id = @tenant) AND (
+		` + productDocument + ` @@ query.tsq
+		OR p.name % @text
+		OR @text <% p.name
+		OR @text <% p.description
+		OR s.sku IS NOT NULL
+	)
+)
+SELECT t.total, page.product_id,
+	coalesce(page.rank, 0) AS rank,
+	coalesce(page.name_highlight, '') AS name_highlight,
+	coalesce(page.description_highlight, '') AS description_highlight,
+	coalesce(page.sku, '') AS sku
+FROM (SELECT count(*) AS total FROM matches) t
+LEFT JOIN LATERAL (
+	SELECT m.id AS product_id, m.rank, m.name, m.sku,
+		ts_headline('english', m.name, query.tsq, 'StartSel=` + highlightStart + `, StopSel=` + highlightStop + `, HighlightAll=true') AS name_highlight,
+		ts_headline('english', coalesce(m.description, ''), query.tsq, 'StartSel=` + highlightStart + `, StopSel=` + highlightStop + `, MaxFragments=2, MaxWords=20, MinWords=5') AS description_highlight
+	FROM matches m
+	CROSS JOIN query
+	ORDER BY m.rank DESC, m.name, m.id
+	LIMIT @limit OFFSET @offset
+) page ON true
+ORDER BY page.rank DESC, page.name, page.product_id`
+
+// searchRow is a row of searchQuery. A page past the last match is a single row with no
+// product, carrying only the total.
+type searchRow struct {
+	ProductID            *uuid.UUID
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+	SKU                  string
+	Total                int64
+}
+
+// Search implements product.Repository.
+func (r *productRepository) Search(ctx context.Context, query product.SearchQuery) ([]product.SearchHit, int64, error) {
+	var rows []searchRow
+
+	if err := r.conn.Reader(ctx).
+		Raw(searchQuery, map[string]any{
+			"text":   query.Text,
+			"prefix": escapeLike(query.Text) + "%",
+			"limit":  query.Limit,
+			"offset": query.Offset,
+			"tenant": rawQueryTenant(ctx),
+		}).
+		Scan(&rows).
+		Error; err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	hits := make([]product.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		total = row.Total
+		if row.ProductID == nil {
+			continue
+		}
+
+		hits = append(hits, product.SearchHit{
+			ProductID:            *row.ProductID,
+			Rank:                 row.Rank,
+			NameHighlight:        markHighlights(row.NameHighlight),
+			DescriptionHighlight: markHighlights(row.DescriptionHighlight),
+			SKU:                  row.SKU,
+		})
+	}
+
+	return hits, total, nil
+}
+
+// markHighlights HTML-escapes a ts_headline result, since names and descriptions are
+// free text that clients render as markup, and wraps its highlighted words in <mark> tags.
+func markHighlights(s string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(s))
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
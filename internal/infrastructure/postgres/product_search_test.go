@@ -0,0 +1,21 @@
+package postgres
+
+import "testing"
+
+func TestMarkHighlights(t *testing.T) {
+	tests := []struct {
+		name, headline, want string
+	}{
+		{"wraps highlighted words", "Red \x02Mug\x03", "Red <mark>Mug</mark>"},
+		{"escapes markup in the text", "\x02<script>alert(1)</script>\x03 Mug", "<mark>&lt;script&gt;alert(1)&lt;/script&gt;</mark> Mug"},
+		{"escapes attributes", `<img src=x onerror="alert('x')">`, "&lt;img src=x onerror=&#34;alert(&#39;x&#39;)&#34;&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markHighlights(tt.headline); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
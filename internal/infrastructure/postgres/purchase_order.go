@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type purchaseOrderRepository struct {
+	conn *ConnectionManager
+}
+
+func NewPurchaseOrderRepository(conn *ConnectionManager) purchaseorder.Repository {
+	return &purchaseOrderRepository{
+		conn: conn,
+	}
+}
+
+// Create implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) Create(ctx context.Context, po *purchaseorder.PurchaseOrder) error {
+	if err := r.conn.Writer(ctx).Omit("Supplier").Create(po).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) GetAll(ctx context.Context, filter purchaseorder.Filter) ([]purchaseorder.PurchaseOrder, error) {
+	var orders []purchaseorder.PurchaseOrder
+
+	q := r.conn.Reader(ctx).Preload("Supplier").Preload("Lines")
+
+	if filter.SupplierID != "" {
+		q = q.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if err := q.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// GetByID implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) GetByID(ctx context.Context, id string) (*purchaseorder.PurchaseOrder, error) {
+	var po purchaseorder.PurchaseOrder
+
+	if err := r.conn.Reader(ctx).
+		Preload("Supplier").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		First(&po, "id = ?", id).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &po, nil
+}
+
+// Update implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) Update(ctx context.Context, po *purchaseorder.PurchaseOrder) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		if err := tx.Writer(ctx).
+			Model(po).
+			Select("supplier_id", "currency", "expected_at", "note").
+			Updates(po).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.Writer(ctx).
+			Unscoped().
+			Delete(&purchaseorder.Line{}, "purchase_order_id = ?", po.ID).
+			Error; err != nil {
+			return err
+		}
+
+		for i := range po.Lines {
+			po.Lines[i].ID = uuid.Nil
+			po.Lines[i].PurchaseOrderID = po.ID
+		}
+
+		return tx.Writer(ctx).Create(&po.Lines).Error
+	})
+}
+
+// Delete implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) Delete(ctx context.Context, id string) error {
+	return r.conn.Transaction(ctx, func(tx *ConnectionManager) error {
+		if err := tx.Writer(ctx).Delete(&purchaseorder.Line{}, "purchase_order_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Writer(ctx).Delete(&purchaseorder.PurchaseOrder{}, "id = ?", id).Error
+	})
+}
+
+// Lock implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) Lock(ctx context.Context, id string) error {
+	var po purchaseorder.PurchaseOrder
+
+	if err := r.conn.Writer(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&po, "id = ?", id).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) UpdateStatus(ctx context.Context, id string, status purchaseorder.Status) error {
+	if err := r.conn.Writer(ctx).
+		Model(&purchaseorder.PurchaseOrder{}).
+		Where("id = ?", id).
+		Update("status", status).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLineReceived implements purchaseorder.Repository.
+func (r *purchaseOrderRepository) UpdateLineReceived(ctx context.Context, lineID string, quantity int) error {
+	if err := r.conn.Writer(ctx).
+		Model(&purchaseorder.Line{}).
+		Where("id = ?", lineID).
+		Update("quantity_received", quantity).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/rma"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type returnRepository struct {
+	conn *ConnectionManager
+}
+
+func NewReturnRepository(conn *ConnectionManager) rma.Repository {
+	return &returnRepository{
+		conn: conn,
+	}
+}
+
+// Create implements rma.Repository.
+func (r *returnRepository) Create(ctx context.Context, ret *rma.Return) error {
+	if err := r.conn.Writer(ctx).Create(ret).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements rma.Repository.
+func (r *returnRepository) GetAll(ctx context.Context, filter rma.Filter) ([]rma.Return, error) {
+	var returns []rma.Return
+
+	q := r.conn.Reader(ctx).Preload("Lines")
+
+	if filter.SalesOrderID != "" {
+		q = q.Where("sales_order_id = ?", filter.SalesOrderID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if err := q.Order("created_at DESC").Find(&returns).Error; err != nil {
+		return nil, err
+	}
+
+	return returns, nil
+}
+
+// GetByID implements rma.Repository.
+func (r *returnRepository) GetByID(ctx context.Context, id string) (*rma.Return, error) {
+	var ret rma.Return
+
+	if err := r.conn.Reader(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		First(&ret, "id = ?", id).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+// Lock implements rma.Repository.
+func (r *returnRepository) Lock(ctx context.Context, id string) error {
+	var ret rma.Return
+
+	if err := r.conn.Writer(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&ret, "id = ?", id).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LockSalesOrder implements rma.Repository.
+func (r *returnRepository) LockSalesOrder(ctx context.Context, salesOrderID string) error {
+	var so salesorder.SalesOrder
+
+	if err := r.conn.Writer(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&so, "id = ?", salesOrderID).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus implements rma.Repository.
+func (r *returnRepository) UpdateStatus(ctx context.Context, id string, status rma.Status) error {
+	if err := r.conn.Writer(ctx).
+		Model(&rma.Return{}).
+		Where("id = ?", id).
+		Update("status", status).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLineDisposition implements rma.Repository.
+func (r *returnRepository) UpdateLineDisposition(ctx context.Context, line *rma.Line) error {
+	if err := r.conn.Writer(ctx).
+		Model(line).
+		Select("disposition", "inspected_by", "inspected_at", "note").
+		Updates(line).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type salesOrderRepository struct {
+	conn *ConnectionManager
+}
+
+func NewSalesOrderRepository(conn *ConnectionManager) salesorder.Repository {
+	return &salesOrderRepository{
+		conn: conn,
+	}
+}
+
+// Create implements salesorder.Repository.
+func (r *salesOrderRepository) Create(ctx context.Context, so *salesorder.SalesOrder) error {
+	if err := r.conn.Writer(ctx).Create(so).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements salesorder.Repository.
+func (r *salesOrderRepository) GetAll(ctx context.Context, filter salesorder.Filter) ([]salesorder.SalesOrder, error) {
+	var orders []salesorder.SalesOrder
+
+	q := r.conn.Reader(ctx).Preload("Lines")
+
+	if filter.Customer != "" {
+		q = q.Where("customer = ?", filter.Customer)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if err := q.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// GetByID implements salesorder.Repository.
+func (r *salesOrderRepository) GetByID(ctx context.Context, id string) (*salesorder.SalesOrder, error) {
+	var so salesorder.SalesOrder
+
+	if err := r.conn.Reader(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		First(&so, "id = ?", id).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &so, nil
+}
+
+// Lock implements salesorder.Repository.
+func (r *salesOrderRepository) Lock(ctx context.Context, id string) error {
+	var so salesorder.SalesOrder
+
+	if err := r.conn.Writer(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&so, "id = ?", id).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus implements salesorder.Repository.
+func (r *salesOrderRepository) UpdateStatus(ctx context.Context, id string, status salesorder.Status) error {
+	if err := r.conn.Writer(ctx).
+		Model(&salesorder.SalesOrder{}).
+		Where("id = ?", id).
+		Update("status", status).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLine implements salesorder.Repository.
+func (r *salesOrderRepository) UpdateLine(ctx context.Context, line *salesorder.Line) error {
+	if err := r.conn.Writer(ctx).
+		Model(line).
+		Select("quantity_allocated", "quantity_fulfilled").
+		Updates(line).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
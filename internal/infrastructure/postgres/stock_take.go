@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/stocktake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockTakeRepository struct {
+	conn *ConnectionManager
+}
+
+func NewStockTakeRepository(conn *ConnectionManager) stocktake.Repository {
+	return &stockTakeRepository{
+		conn: conn,
+	}
+}
+
+// Create implements stocktake.Repository.
+func (r *stockTakeRepository) Create(ctx context.Context, session *stocktake.Session) error {
+	if err := r.conn.Writer(ctx).Create(session).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements stocktake.Repository. Lines are left out of the listing.
+func (r *stockTakeRepository) GetAll(ctx context.Context, filter stocktake.Filter) ([]stocktake.Session, error) {
+	var sessions []stocktake.Session
+
+	q := r.conn.Reader(ctx)
+
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if err := q.Order("created_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// GetByID implements stocktake.Repository.
+func (r *stockTakeRepository) GetByID(ctx context.Context, id string) (*stocktake.Session, error) {
+	var session stocktake.Session
+
+	if err := r.conn.Reader(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		First(&session, "id = ?", id).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Lock implements stocktake.Repository.
+func (r *stockTakeRepository) Lock(ctx context.Context, id string) error {
+	var session stocktake.Session
+
+	if err := r.conn.Writer(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&session, "id = ?", id).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus implements stocktake.Repository.
+func (r *stockTakeRepository) UpdateStatus(ctx context.Context, id string, status stocktake.Status) error {
+	if err := r.conn.Writer(ctx).
+		Model(&stocktake.Session{}).
+		Where("id = ?", id).
+		Update("status", status).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Approve implements stocktake.Repository.
+func (r *stockTakeRepository) Approve(ctx context.Context, id string, approvedBy string, at time.Time) error {
+	if err := r.conn.Writer(ctx).
+		Model(&stocktake.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":      stocktake.StatusApproved,
+			"approved_by": approvedBy,
+			"approved_at": at,
+		}).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLineCount implements stocktake.Repository.
+func (r *stockTakeRepository) UpdateLineCount(ctx context.Context, line *stocktake.Line) error {
+	if err := r.conn.Writer(ctx).
+		Model(line).
+		Select("expected_quantity", "counted_quantity", "variance", "counted_by", "counted_at", "note").
+		Updates(line).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
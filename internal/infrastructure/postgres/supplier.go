@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+)
+
+type supplierRepository struct {
+	conn *ConnectionManager
+}
+
+func NewSupplierRepository(conn *ConnectionManager) supplier.Repository {
+	return &supplierRepository{
+		conn: conn,
+	}
+}
+
+// Create implements supplier.Repository.
+func (r *supplierRepository) Create(ctx context.Context, s *supplier.Supplier) error {
+	if err := r.conn.Writer(ctx).Create(s).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAll implements supplier.Repository.
+func (r *supplierRepository) GetAll(ctx context.Context) ([]supplier.Supplier, error) {
+	var suppliers []supplier.Supplier
+
+	if err := r.conn.Reader(ctx).Order("name").Find(&suppliers).Error; err != nil {
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+// GetByID implements supplier.Repository.
+func (r *supplierRepository) GetByID(ctx context.Context, id string) (*supplier.Supplier, error) {
+	var s supplier.Supplier
+
+	if err := r.conn.Reader(ctx).First(&s, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Update implements supplier.Repository.
+func (r *supplierRepository) Update(ctx context.Context, s *supplier.Supplier) error {
+	if err := r.conn.Writer(ctx).
+		Model(s).
+		Select("name", "contact_name", "email", "phone", "address", "lead_time_days").
+		Updates(s).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements supplier.Repository.
+func (r *supplierRepository) Delete(ctx context.Context, id string) error {
+	if err := r.conn.Writer(ctx).Delete(&supplier.Supplier{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
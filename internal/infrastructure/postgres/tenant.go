@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantColumn holds the tenant that owns a row.
+const tenantColumn = "tenant_id"
+
+// tenantSetting is the session setting row-level security compares tenant_id against.
+const tenantSetting = "app.tenant_id"
+
+// tenantTables are the tables whose rows belong to a tenant.
+var tenantTables = []string{
+	"categories",
+	"products",
+	"product_versions",
+	"option_axis",
+	"variants",
+	"product_units",
+	"lots",
+	"serials",
+	"kit_components",
+	"stock_buckets",
+	"stock_movements",
+	"attachments",
+	"audit_entries",
+	"suppliers",
+	"purchase_orders",
+	"purchase_order_lines",
+	"stock_takes",
+	"stock_take_lines",
+	"sales_orders",
+	"sales_order_lines",
+	"returns",
+	"return_lines",
+}
+
+// TenantIsolation enforces in the database what the repositories' tenant scope does in
+// the application: with row-level security forced on every tenant table, a session sees
+// and writes only rows of the tenant in app.tenant_id, even through raw SQL. A session
+// with no tenant sees nothing; the tenant.All marker sees everything and is used for
+// migrations. product_categories rows follow their product. Variant SKUs are unique per
+// tenant rather than globally. The statements are idempotent and run after the schema is
+// migrated.
+var TenantIsolation = tenantIsolation()
+
+func tenantIsolation() []string {
+	check := fmt.Sprintf("tenant_id = current_setting('%[1]s', true) OR current_setting('%[1]s', true) = '%[2]s'", tenantSetting, tenant.All)
+
+	stmts := []string{
+		`DROP INDEX IF EXISTS idx_variants_sku`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_variants_tenant_sku ON variants (tenant_id, sku) WHERE sku <> ''`,
+	}
+
+	for _, table := range tenantTables {
+		stmts = append(stmts,
+			fmt.Sprintf(`ALTER TABLE %s ENABLE ROW LEVEL SECURITY`, table),
+			fmt.Sprintf(`ALTER TABLE %s FORCE ROW LEVEL SECURITY`, table),
+			fmt.Sprintf(`DROP POLICY IF EXISTS tenant_isolation ON %s`, table),
+			fmt.Sprintf(`CREATE POLICY tenant_isolation ON %s USING (%s) WITH CHECK (%s)`, table, check, check),
+		)
+	}
+
+	owned := `EXISTS (SELECT 1 FROM products p WHERE p.id = product_categories.product_id)`
+
+	return append(stmts,
+		`ALTER TABLE product_categories ENABLE ROW LEVEL SECURITY`,
+		`ALTER TABLE product_categories FORCE ROW LEVEL SECURITY`,
+		`DROP POLICY IF EXISTS tenant_isolation ON product_categories`,
+		fmt.Sprintf(`CREATE POLICY tenant_isolation ON product_categories USING (%s) WITH CHECK (%s)`, owned, owned),
+	)
+}
+
+// registerTenantScope confines every query, update and delete on a model with a tenant
+// to the tenant in the statement's context, and stamps created rows with it. Statements
+// without a tenant, or with tenant.All, are left alone; row-level security still applies.
+func registerTenantScope(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("tenant:assign", assignTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("tenant:scope", scopeTenant)
+}
+
+// statementTenant returns the tenant to scope the statement to, if it should be scoped.
+func statementTenant(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.LookUpField(tenantColumn) == nil {
+		return "", false
+	}
+
+	id := tenant.FromContext(db.Statement.Context)
+	if id == "" || id == tenant.All {
+		return "", false
+	}
+
+	return id, true
+}
+
+// rawQueryTenant returns the tenant raw SQL should be confined to, or "" when it should
+// not be, matching the scope applied to model statements.
+func rawQueryTenant(ctx context.Context) string {
+	if id := tenant.FromContext(ctx); id != tenant.All {
+		return id
+	}
+	return ""
+}
+
+func scopeTenant(db *gorm.DB) {
+	id, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tenantColumn}, Value: id},
+	}})
+}
+
+func assignTenant(db *gorm.DB) {
+	id, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(tenantColumn)
+	set := func(v reflect.Value) {
+		if err := field.Set(db.Statement.Context, reflect.Indirect(v), id); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			set(rv.Index(i))
+		}
+	case reflect.Struct:
+		set(rv)
+	}
+}
+
+// tenantConnector hands out connections that keep app.tenant_id in step with the
+// tenant of each statement's context, for row-level security.
+type tenantConnector struct {
+	driver.Connector
+}
+
+// Connect implements driver.Connector.
+func (c tenantConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantConn{Conn: conn.(*stdlib.Conn)}, nil
+}
+
+// tenantConn sets app.tenant_id before a statement whenever the statement's tenant
+// differs from the one last set on the session. Rolling back a transaction also undoes
+// a setting made inside it, so the setting is re-applied after a rollback.
+type tenantConn struct {
+	*stdlib.Conn
+
+	// tenant last set on the session, valid while applied is true
+	tenant  string
+	applied bool
+}
+
+func (c *tenantConn) setTenant(ctx context.Context) error {
+	id := tenant.FromContext(ctx)
+	if c.applied && c.tenant == id {
+		return nil
+	}
+
+	c.applied = false
+	if _, err := c.Conn.Conn().Exec(ctx, "SELECT set_config($1, $2, false)", tenantSetting, id); err != nil {
+		return err
+	}
+
+	c.tenant, c.applied = id, true
+	return nil
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *tenantConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return c.Conn.ExecContext(ctx, query, args)
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *tenantConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return c.Conn.QueryContext(ctx, query, args)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *tenantConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return c.Conn.PrepareContext(ctx, query)
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *tenantConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.Conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantTx{Tx: tx, conn: c}, nil
+}
+
+type tenantTx struct {
+	driver.Tx
+	conn *tenantConn
+}
+
+// Rollback implements driver.Tx.
+func (t *tenantTx) Rollback() error {
+	t.conn.applied = false
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB returns a DB that builds statements without a database to run them on.
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := registerTenantScope(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestTenantScope(t *testing.T) {
+	db := newDryRunDB(t)
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	scoped := func(stmt *gorm.Statement) bool {
+		if !strings.Contains(stmt.SQL.String(), `"products"."tenant_id" = `) {
+			return false
+		}
+		for _, v := range stmt.Vars {
+			if v == "acme" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("scopes reads, updates and deletes to the context's tenant", func(t *testing.T) {
+		stmts := []*gorm.Statement{
+			db.WithContext(acme).First(&product.Product{}, "id = ?", "p1").Statement,
+			db.WithContext(acme).Model(&product.Product{}).Where("id = ?", "p1").Update("name", "x").Statement,
+			db.WithContext(acme).Delete(&product.Product{}, "id = ?", "p1").Statement,
+			db.WithContext(acme).Table("(?) AS products", db.Raw("SELECT 1")).Find(&[]product.Product{}).Statement,
+		}
+
+		for _, stmt := range stmts {
+			if !scoped(stmt) {
+				t.Fatalf("expected the statement to be scoped to acme, got %s", stmt.SQL.String())
+			}
+		}
+	})
+
+	t.Run("stamps created rows with the context's tenant", func(t *testing.T) {
+		p := product.Product{Name: "Widget"}
+		db.WithContext(acme).Create(&p)
+
+		if p.TenantID != "acme" {
+			t.Fatalf("expected the product to belong to acme, got %q", p.TenantID)
+		}
+	})
+
+	t.Run("leaves statements without a tenant to row-level security", func(t *testing.T) {
+		for _, ctx := range []context.Context{context.Background(), tenant.WithAll(context.Background())} {
+			stmt := db.WithContext(ctx).Find(&[]product.Product{}).Statement
+			if strings.Contains(stmt.SQL.String(), "tenant_id") {
+				t.Fatalf("expected no tenant scope, got %s", stmt.SQL.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/valuation"
+)
+
+// StockValuation creates stock_value, an ordered aggregate that replays a product's ledger
+// and returns {quantity on hand, weighted-average value}. Issues never take more than is on
+// hand, and each takes its share of the value rounded half away from zero, so the remainder
+// keeps the rounding. The statements are idempotent and run after the schema is migrated.
+var StockValuation = []string{
+	`CREATE OR REPLACE FUNCTION stock_value_step(state numeric[], quantity bigint, unit_cost bigint) RETURNS numeric[] AS $$
+	SELECT CASE
+		WHEN quantity > 0 THEN ARRAY[state[1] + quantity, state[2] + quantity::numeric * unit_cost]
+		WHEN state[1] = 0 THEN state
+		ELSE ARRAY[
+			state[1] - least(-quantity, state[1]),
+			state[2] - round(state[2] * least(-quantity, state[1]) / state[1])
+		]
+	END
+$$ LANGUAGE sql IMMUTABLE`,
+	`CREATE OR REPLACE AGGREGATE stock_value(bigint, bigint) (
+	SFUNC = stock_value_step,
+	STYPE = numeric[],
+	INITCOND = '{0,0}'
+)`,
+}
+
+// stockValuesQuery values the stock on hand of each product from the movements up to and
+// including @as_of, the bound StockAsOf uses. Under FIFO the stock left is the newest received, so each receipt counts for the
+// part of it that the receipts after it do not cover. Units held in the other buckets left
+// the available stock with transfers recording their cost, so they are added at that cost.
+const stockValuesQuery = `WITH ledger AS (
+	SELECT id, product_id, quantity, unit_cost, coalesce(bucket, '') AS bucket, occurred_at, created_at
+	FROM stock_movements
+	WHERE deleted_at IS NULL
+		AND occurred_at <= @as_of
+		AND (@tenant = '' OR tenant_id = @tenant)
+),
+on_hand AS (
+	SELECT product_id, stock_value(quantity, unit_cost ORDER BY occurred_at, created_at, id) AS state
+	FROM ledger
+	GROUP BY product_id
+),
+receipts AS (
+	SELECT product_id, quantity, unit_cost,
+		sum(quantity) OVER (PARTITION BY product_id ORDER BY occurred_at DESC, created_at DESC, id DESC) - quantity AS newer
+	FROM ledger
+	WHERE quantity > 0
+),
+held AS (
+	SELECT product_id, -sum(quantity) AS quantity, -sum(quantity * unit_cost) AS value
+	FROM ledger
+	WHERE bucket <> ''
+	GROUP BY product_id
+)
+SELECT o.product_id,
+	o.state[1]::bigint AS quantity,
+	CASE WHEN @method = 'fifo' THEN coalesce((
+		SELECT sum(least(r.quantity, greatest(o.state[1] - r.newer, 0)) * r.unit_cost)
+		FROM receipts r
+		WHERE r.product_id = o.product_id
+	), 0) ELSE o.state[2] END::bigint AS value,
+	coalesce(h.quantity, 0)::bigint AS held,
+	coalesce(h.value, 0)::bigint AS held_value
+FROM on_hand o
+LEFT JOIN held h ON h.product_id = o.product_id`
+
+type valuationRepository struct {
+	conn *ConnectionManager
+}
+
+func NewValuationRepository(conn *ConnectionManager) valuation.Repository {
+	return &valuationRepository{
+		conn: conn,
+	}
+}
+
+// StockValues implements valuation.Repository.
+func (r *valuationRepository) StockValues(ctx context.Context, asOf time.Time, method valuation.Method) (map[uuid.UUID]valuation.StockValue, error) {
+	var rows []struct {
+		ProductID uuid.UUID
+		Quantity  int
+		Value     int64
+		Held      int
+		HeldValue int64
+	}
+
+	if err := r.conn.Reader(ctx).
+		Raw(stockValuesQuery, map[string]any{
+			"as_of":  asOf,
+			"method": string(method),
+			"tenant": rawQueryTenant(ctx),
+		}).
+		Scan(&rows).
+		Error; err != nil {
+		return nil, err
+	}
+
+	values := make(map[uuid.UUID]valuation.StockValue, len(rows))
+	for _, row := range rows {
+		values[row.ProductID] = valuation.StockValue{Quantity: row.Quantity, Value: row.Value, Held: row.Held, HeldValue: row.HeldValue}
+	}
+
+	return values, nil
+}
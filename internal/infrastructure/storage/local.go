@@ -0,0 +1,94 @@
+// Package storage provides the backends that attachment files are kept in.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/attachment"
+)
+
+type localStorage struct {
+	root string
+}
+
+// NewLocal returns a Storage that keeps each object as a file under root.
+func NewLocal(root string) attachment.Storage {
+	return &localStorage{
+		root: root,
+	}
+}
+
+// Put implements attachment.Storage. The file is written under a temporary name and
+// renamed into place, so readers never see a partial file.
+func (s *localStorage) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open implements attachment.Storage.
+func (s *localStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// Delete implements attachment.Storage. Directories left empty are removed up to the root.
+func (s *localStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for dir := filepath.Dir(path); dir != filepath.Clean(s.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// path maps a key to a file under the root, rejecting keys that would escape it.
+func (s *localStorage) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(key) || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
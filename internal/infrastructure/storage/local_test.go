@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	s := NewLocal(root)
+	ctx := context.Background()
+
+	key := "products/p1/a1/original"
+
+	if err := s.Put(ctx, key, []byte("contents")); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "contents" {
+		t.Fatalf("expected contents, got %q", data)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "products")); !os.IsNotExist(err) {
+		t.Fatalf("expected empty directories to be removed, got %v", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("expected deleting a missing key to succeed, got %v", err)
+	}
+
+	for _, bad := range []string{"", "../escape", "/abs/path", `products\p1`} {
+		if err := s.Put(ctx, bad, nil); err == nil {
+			t.Errorf("expected key %q to be rejected", bad)
+		}
+	}
+}
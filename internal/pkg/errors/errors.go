@@ -2,7 +2,9 @@ package errors
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,9 +20,17 @@ const (
 	InvalidFormat       ErrorCode = "INVALID_FORMAT"
 
 	// Not found errors
-	NotFoundError   ErrorCode = "NOT_FOUND"
-	ProductNotFound ErrorCode = "PRODUCT_NOT_FOUND"
-	UserNotFound    ErrorCode = "USER_NOT_FOUND"
+	NotFoundError         ErrorCode = "NOT_FOUND"
+	ProductNotFound       ErrorCode = "PRODUCT_NOT_FOUND"
+	CategoryNotFound      ErrorCode = "CATEGORY_NOT_FOUND"
+	VariantNotFound       ErrorCode = "VARIANT_NOT_FOUND"
+	SupplierNotFound      ErrorCode = "SUPPLIER_NOT_FOUND"
+	PurchaseOrderNotFound ErrorCode = "PURCHASE_ORDER_NOT_FOUND"
+	StockTakeNotFound     ErrorCode = "STOCK_TAKE_NOT_FOUND"
+	SalesOrderNotFound    ErrorCode = "SALES_ORDER_NOT_FOUND"
+	ReturnNotFound        ErrorCode = "RETURN_NOT_FOUND"
+	AttachmentNotFound    ErrorCode = "ATTACHMENT_NOT_FOUND"
+	UserNotFound          ErrorCode = "USER_NOT_FOUND"
 
 	// Business logic errors
 	BusinessLogicError ErrorCode = "BUSINESS_LOGIC_ERROR"
@@ -40,6 +50,10 @@ const (
 	UnauthorizedError ErrorCode = "UNAUTHORIZED"
 	ForbiddenError    ErrorCode = "FORBIDDEN"
 	TokenExpiredError ErrorCode = "TOKEN_EXPIRED"
+
+	// Request limiting errors
+	RateLimited     ErrorCode = "RATE_LIMITED"
+	PayloadTooLarge ErrorCode = "PAYLOAD_TOO_LARGE"
 )
 
 // AppError represents a custom application error
@@ -96,6 +110,38 @@ func NewProductNotFoundError(id string) *AppError {
 	return NewAppError(ProductNotFound, fmt.Sprintf("Product with ID %s not found", id), fiber.StatusNotFound)
 }
 
+func NewCategoryNotFoundError(id string) *AppError {
+	return NewAppError(CategoryNotFound, fmt.Sprintf("Category with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewVariantNotFoundError(id string) *AppError {
+	return NewAppError(VariantNotFound, fmt.Sprintf("Variant with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewSupplierNotFoundError(id string) *AppError {
+	return NewAppError(SupplierNotFound, fmt.Sprintf("Supplier with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewPurchaseOrderNotFoundError(id string) *AppError {
+	return NewAppError(PurchaseOrderNotFound, fmt.Sprintf("Purchase order with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewStockTakeNotFoundError(id string) *AppError {
+	return NewAppError(StockTakeNotFound, fmt.Sprintf("Stock take with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewSalesOrderNotFoundError(id string) *AppError {
+	return NewAppError(SalesOrderNotFound, fmt.Sprintf("Sales order with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewReturnNotFoundError(id string) *AppError {
+	return NewAppError(ReturnNotFound, fmt.Sprintf("Return with ID %s not found", id), fiber.StatusNotFound)
+}
+
+func NewAttachmentNotFoundError(id string) *AppError {
+	return NewAppError(AttachmentNotFound, fmt.Sprintf("Attachment with ID %s not found", id), fiber.StatusNotFound)
+}
+
 func NewUserNotFoundError(id string) *AppError {
 	return NewAppError(UserNotFound, fmt.Sprintf("User with ID %s not found", id), fiber.StatusNotFound)
 }
@@ -114,6 +160,19 @@ func NewInsufficientStockError(available, required int) *AppError {
 	})
 }
 
+// NewComponentInsufficientStockError reports the kit component that is short when a kit
+// cannot be decremented.
+func NewComponentInsufficientStockError(componentID, name string, available, required int) *AppError {
+	return NewAppError(InsufficientStock,
+		fmt.Sprintf("Insufficient stock of component %s (%s). Available: %d, Required: %d", name, componentID, available, required),
+		fiber.StatusConflict).WithDetails(map[string]any{
+		"component_id":   componentID,
+		"component_name": name,
+		"available":      available,
+		"required":       required,
+	})
+}
+
 func NewDuplicateEntryError(field, value string) *AppError {
 	return NewAppError(DuplicateEntry,
 		fmt.Sprintf("Duplicate entry for %s: %s", field, value),
@@ -161,6 +220,29 @@ func NewTokenExpiredError() *AppError {
 	return NewAppError(TokenExpiredError, "Token has expired", fiber.StatusUnauthorized)
 }
 
+// Request Limiting Error Creators
+func NewRateLimitedError(retryAfter time.Duration) *AppError {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	return NewAppError(RateLimited,
+		fmt.Sprintf("Rate limit exceeded. Retry in %d seconds", seconds),
+		fiber.StatusTooManyRequests).WithDetails(map[string]int{
+		"retry_after": seconds,
+	})
+}
+
+func NewPayloadTooLargeError(limit int) *AppError {
+	return NewAppError(PayloadTooLarge,
+		fmt.Sprintf("Request body exceeds the limit of %d bytes", limit),
+		fiber.StatusRequestEntityTooLarge)
+}
+
+// NewAttachmentTooLargeError reports an uploaded file over the attachment size limit.
+func NewAttachmentTooLargeError(limit int64) *AppError {
+	return NewAppError(PayloadTooLarge,
+		fmt.Sprintf("Attachment exceeds the limit of %d bytes", limit),
+		fiber.StatusRequestEntityTooLarge)
+}
+
 // FromError converts a standard error to AppError
 func FromError(err error) *AppError {
 	if appErr, ok := err.(*AppError); ok {
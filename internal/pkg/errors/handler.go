@@ -27,6 +27,11 @@ func ErrorHandler() fiber.ErrorHandler {
 
 		// Check for Fiber errors
 		if fiberErr, ok := err.(*fiber.Error); ok {
+			// Raised by fasthttp before routing when the body exceeds fiber.Config.BodyLimit
+			if fiberErr.Code == fiber.StatusRequestEntityTooLarge {
+				return HandleError(c, NewPayloadTooLargeError(c.App().Config().BodyLimit))
+			}
+
 			return c.Status(fiberErr.Code).JSON(response.ErrorResponseWithCode{
 				BaseResponse: response.BaseResponse{
 					Success: false,
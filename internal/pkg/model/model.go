@@ -8,8 +8,9 @@ import (
 )
 
 type BaseModel struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id" openapi:"readonly"`
+	TenantID  string         `gorm:"not null;default:'default';index" json:"-"`
+	CreatedAt time.Time      `json:"created_at" openapi:"readonly"`
+	UpdatedAt time.Time      `json:"updated_at" openapi:"readonly"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at" openapi:"readonly"`
 }
@@ -0,0 +1,98 @@
+// Package money provides exact monetary arithmetic on amounts held as integer minor
+// units (e.g. cents) alongside an ISO 4217 currency code.
+package money
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for products created without a currency code.
+const DefaultCurrency = "USD"
+
+// exponents lists the currencies whose minor unit is not 1/100 of the major unit.
+var exponents = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "PYG": 0, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+}
+
+// Money is an amount in minor units of a currency.
+type Money struct {
+	Amount   int64  `json:"amount" doc:"Amount in minor units of the currency, e.g. cents"`
+	Currency string `json:"currency" doc:"ISO 4217 currency code"`
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 code: three upper-case letters.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Exponent returns the number of decimal places in the currency's minor unit.
+func Exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+// String formats the amount in major units, e.g. "12.34 USD".
+func (m Money) String() string {
+	return Format(m.Amount, m.Currency) + " " + m.Currency
+}
+
+// Format renders an amount of minor units as a decimal string in major units.
+func Format(amount int64, currency string) string {
+	exp := Exponent(currency)
+
+	neg := amount < 0
+	digits := strconv.FormatUint(abs(amount), 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MulDiv returns a*b/c rounded half away from zero, without intermediate overflow.
+// It is used to price a share of a stock layer, e.g. value*quantity/onHand.
+// The result is assumed to fit in an int64; c must not be zero.
+func MulDiv(a, b, c int64) int64 {
+	n := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	d := big.NewInt(c)
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+
+	// Round half away from zero: compare 2|r| with |c|.
+	r.Abs(r).Lsh(r, 1)
+	if r.Cmp(new(big.Int).Abs(d)) >= 0 {
+		if n.Sign()*d.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q.Int64()
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
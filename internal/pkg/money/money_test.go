@@ -0,0 +1,51 @@
+package money
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{1234, "USD", "12.34"},
+		{5, "EUR", "0.05"},
+		{-250, "USD", "-2.50"},
+		{0, "USD", "0.00"},
+		{1500, "JPY", "1500"},
+		{1234, "KWD", "1.234"},
+	}
+
+	for _, tc := range cases {
+		if got := Format(tc.amount, tc.currency); got != tc.want {
+			t.Errorf("Format(%d, %s) = %q, want %q", tc.amount, tc.currency, got, tc.want)
+		}
+	}
+}
+
+func TestMulDiv(t *testing.T) {
+	cases := []struct {
+		a, b, c int64
+		want    int64
+	}{
+		{1000, 1, 3, 333},
+		{1000, 2, 3, 667},
+		{5, 1, 2, 3},
+		{-5, 1, 2, -3},
+		{1 << 62, 4, 8, 1 << 61},
+	}
+
+	for _, tc := range cases {
+		if got := MulDiv(tc.a, tc.b, tc.c); got != tc.want {
+			t.Errorf("MulDiv(%d, %d, %d) = %d, want %d", tc.a, tc.b, tc.c, got, tc.want)
+		}
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	for code, want := range map[string]bool{"USD": true, "usd": false, "US": false, "EURO": false} {
+		if got := ValidCurrency(code); got != want {
+			t.Errorf("ValidCurrency(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package quantity provides exact decimal quantities and their conversion between units
+// of measure. Quantities are parsed as decimal strings rather than floats, so 0.1 kg is
+// exactly 100 g.
+package quantity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// BaseFactor is the conversion factor of a product's base unit.
+const BaseFactor Decimal = "1"
+
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Decimal is a decimal number such as "2", "0.25" or "453.59237". It is held as its
+// string form and accepted in JSON as either a string or a number.
+type Decimal string
+
+// UnmarshalJSON implements json.Unmarshaler. Numbers are taken from their literal text,
+// never through a float.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*d = Decimal(s)
+	} else {
+		*d = Decimal(data)
+	}
+
+	if _, err := d.rat(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FromInt returns the whole number n as a Decimal.
+func FromInt(n int) Decimal {
+	return Decimal(strconv.Itoa(n))
+}
+
+// Valid reports whether d is a well-formed decimal.
+func (d Decimal) Valid() bool {
+	return decimalPattern.MatchString(string(d))
+}
+
+// Positive reports whether d is a well-formed decimal greater than zero.
+func (d Decimal) Positive() bool {
+	r, err := d.rat()
+	return err == nil && r.Sign() > 0
+}
+
+func (d Decimal) rat() (*big.Rat, error) {
+	if !d.Valid() {
+		return nil, fmt.Errorf("%q is not a decimal number", string(d))
+	}
+
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil, fmt.Errorf("%q is not a decimal number", string(d))
+	}
+	return r, nil
+}
+
+// ErrNotWhole is returned when a quantity does not convert to a whole number of base units.
+var ErrNotWhole = errors.New("quantity is not a whole number of base units")
+
+// ToBase converts q units, each worth factor base units, to a whole number of base units.
+// The result must be exact: 1.5 cases of 24 is 36, but 0.3 of a unit with factor 1 fails
+// with ErrNotWhole.
+func ToBase(q Decimal, factor Decimal) (int, error) {
+	qr, err := q.rat()
+	if err != nil {
+		return 0, err
+	}
+
+	fr, err := factor.rat()
+	if err != nil {
+		return 0, err
+	}
+
+	base := new(big.Rat).Mul(qr, fr)
+	if !base.IsInt() {
+		return 0, ErrNotWhole
+	}
+
+	n := base.Num()
+	if !n.IsInt64() || n.Int64() > math.MaxInt32 || n.Int64() < math.MinInt32 {
+		return 0, fmt.Errorf("quantity %s is out of range", string(q))
+	}
+
+	return int(n.Int64()), nil
+}
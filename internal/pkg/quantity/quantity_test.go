@@ -0,0 +1,59 @@
+package quantity
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestToBase(t *testing.T) {
+	cases := []struct {
+		q, factor Decimal
+		want      int
+	}{
+		{"2", "24", 48},
+		{"1.5", "24", 36},
+		{"0.1", "1000", 100},
+		{"0.25", "453.59237", 0},
+		{"3", "1", 3},
+	}
+
+	for _, tc := range cases {
+		got, err := ToBase(tc.q, tc.factor)
+		if tc.want == 0 {
+			if !errors.Is(err, ErrNotWhole) {
+				t.Errorf("ToBase(%s, %s) error = %v, want ErrNotWhole", tc.q, tc.factor, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("ToBase(%s, %s) = %d, %v, want %d", tc.q, tc.factor, got, err, tc.want)
+		}
+	}
+}
+
+func TestToBase_Malformed(t *testing.T) {
+	for _, q := range []Decimal{"", "1e3", "1/3", "abc", ".5"} {
+		if _, err := ToBase(q, BaseFactor); err == nil {
+			t.Errorf("ToBase(%q) expected an error", q)
+		}
+	}
+}
+
+func TestDecimal_UnmarshalJSON(t *testing.T) {
+	var body struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"a": 0.1, "b": "2.50"}`), &body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.A != "0.1" || body.B != "2.50" {
+		t.Fatalf("expected literal decimals, got %q and %q", body.A, body.B)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a": "ten"}`), &body); err == nil {
+		t.Fatal("expected an error for a non-decimal string")
+	}
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval controls how often idle buckets are evicted.
+const sweepInterval = time.Minute
+
+// DefaultMaxClients is the number of client buckets a Limiter keeps when given no maximum.
+const DefaultMaxClients = 10000
+
+// Result describes the outcome of a single Allow call.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token is available (zero when allowed).
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// Limiter is an in-memory token-bucket rate limiter keyed by client.
+// Each key gets a bucket holding up to Burst tokens, refilled at Rate tokens per second.
+// At most maxClients buckets are kept; the least recently seen client is evicted first.
+type Limiter struct {
+	rate       float64
+	burst      int
+	maxClients int
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	// recent orders buckets from most to least recently seen
+	recent    *list.List
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+// New creates a Limiter that allows perMinute requests per minute with bursts of up to burst
+// requests, keeping buckets for at most maxClients clients (DefaultMaxClients when not positive).
+func New(perMinute, burst, maxClients int) *Limiter {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	if maxClients <= 0 {
+		maxClients = DefaultMaxClients
+	}
+
+	return &Limiter{
+		rate:       float64(perMinute) / 60,
+		burst:      burst,
+		maxClients: maxClients,
+		buckets:    make(map[string]*list.Element),
+		recent:     list.New(),
+		now:        time.Now,
+	}
+}
+
+// Allow takes a token from the bucket for key, if one is available.
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	var b *bucket
+	if e, ok := l.buckets[key]; ok {
+		l.recent.MoveToFront(e)
+		b = e.Value.(*bucket)
+	} else {
+		// Make room by forgetting the least recently seen client, whose bucket is the
+		// likeliest to have refilled anyway
+		for len(l.buckets) >= l.maxClients {
+			l.evict(l.recent.Back())
+		}
+
+		b = &bucket{key: key, tokens: float64(l.burst), last: now}
+		l.buckets[key] = l.recent.PushFront(b)
+	}
+
+	// Refill for the time elapsed since the last request
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	res := Result{Limit: l.burst}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = l.timeFor(1 - b.tokens)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = l.timeFor(float64(l.burst) - b.tokens)
+
+	return res
+}
+
+// timeFor returns how long it takes to refill the given number of tokens.
+func (l *Limiter) timeFor(tokens float64) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / l.rate * float64(time.Second)))
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+// Callers must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	// Buckets are ordered by last use, so the idle ones are all at the back
+	full := l.timeFor(float64(l.burst))
+	for e := l.recent.Back(); e != nil && now.Sub(e.Value.(*bucket).last) >= full; e = l.recent.Back() {
+		l.evict(e)
+	}
+}
+
+// evict drops the bucket of e. Callers must hold l.mu.
+func (l *Limiter) evict(e *list.Element) {
+	delete(l.buckets, e.Value.(*bucket).key)
+	l.recent.Remove(e)
+}
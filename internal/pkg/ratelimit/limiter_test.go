@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(perMinute, burst int) (*Limiter, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(perMinute, burst, 0)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Run("allows up to the burst and then throttles", func(t *testing.T) {
+		l, _ := newTestLimiter(60, 3)
+
+		for i := 0; i < 3; i++ {
+			res := l.Allow("client")
+			if !res.Allowed {
+				t.Fatalf("request %d should be allowed", i+1)
+			}
+			if res.Remaining != 2-i {
+				t.Fatalf("expected remaining %d, got %d", 2-i, res.Remaining)
+			}
+		}
+
+		res := l.Allow("client")
+		if res.Allowed {
+			t.Fatal("expected request to be throttled")
+		}
+		if res.RetryAfter != time.Second {
+			t.Fatalf("expected retry after 1s, got %s", res.RetryAfter)
+		}
+	})
+
+	t.Run("refills tokens over time", func(t *testing.T) {
+		l, now := newTestLimiter(60, 1)
+
+		if !l.Allow("client").Allowed {
+			t.Fatal("first request should be allowed")
+		}
+		if l.Allow("client").Allowed {
+			t.Fatal("second request should be throttled")
+		}
+
+		*now = now.Add(time.Second)
+		if !l.Allow("client").Allowed {
+			t.Fatal("request after refill should be allowed")
+		}
+	})
+
+	t.Run("keeps separate buckets per key", func(t *testing.T) {
+		l, _ := newTestLimiter(60, 1)
+
+		if !l.Allow("a").Allowed || !l.Allow("b").Allowed {
+			t.Fatal("each key should have its own budget")
+		}
+		if l.Allow("a").Allowed {
+			t.Fatal("key a should be throttled")
+		}
+	})
+
+	t.Run("evicts idle buckets", func(t *testing.T) {
+		l, now := newTestLimiter(60, 2)
+
+		l.Allow("client")
+		*now = now.Add(2 * sweepInterval)
+		l.Allow("other")
+
+		if _, ok := l.buckets["client"]; ok {
+			t.Fatal("expected idle bucket to be evicted")
+		}
+	})
+	t.Run("evicts the least recently seen client when full", func(t *testing.T) {
+		l, now := newTestLimiter(60, 1)
+		l.maxClients = 2
+
+		l.Allow("a")
+		*now = now.Add(time.Millisecond)
+		l.Allow("b")
+		*now = now.Add(time.Millisecond)
+		l.Allow("a")
+		*now = now.Add(time.Millisecond)
+		l.Allow("c")
+
+		if len(l.buckets) != 2 {
+			t.Fatalf("expected 2 buckets, got %d", len(l.buckets))
+		}
+		if _, ok := l.buckets["b"]; ok {
+			t.Fatal("expected the least recently seen bucket to be evicted")
+		}
+		if l.Allow("a").Allowed {
+			t.Fatal("expected the recently seen client to keep its spent budget")
+		}
+	})
+}
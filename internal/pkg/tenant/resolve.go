@@ -0,0 +1,81 @@
+package tenant
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// Header names the tenant a request acts for.
+const Header = "X-Tenant-ID"
+
+// Resolver works out the tenant a request acts for from its credentials.
+type Resolver struct {
+	secret   []byte
+	fallback string
+	required bool
+	now      func() time.Time
+}
+
+// NewResolver returns a Resolver. When secret is set, requests must carry a bearer token
+// signed with it, and the tenant header may only repeat the token's tenant. Otherwise the
+// tenant header is used, falling back to fallback unless required is set.
+func NewResolver(secret, fallback string, required bool) *Resolver {
+	return &Resolver{
+		secret:   []byte(secret),
+		fallback: fallback,
+		required: required,
+		now:      time.Now,
+	}
+}
+
+// Identity is who a request acts as.
+type Identity struct {
+	// Tenant is empty only when no tenant is required and there is no fallback
+	Tenant string
+	// Subject of the bearer token, if any
+	Subject string
+}
+
+// Resolve returns the identity named by an Authorization value and a tenant header value.
+func (r *Resolver) Resolve(authorization, header string) (Identity, error) {
+	var id Identity
+
+	if len(r.secret) > 0 {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok || token == "" {
+			return id, apperrors.NewUnauthorizedError("a bearer token naming the tenant is required")
+		}
+
+		claims, err := ParseToken(token, r.secret, r.now())
+		if errors.Is(err, ErrTokenExpired) {
+			return id, apperrors.NewTokenExpiredError()
+		}
+		if err != nil || claims.Tenant == "" {
+			return id, apperrors.NewUnauthorizedError("the bearer token is invalid")
+		}
+
+		if header != "" && header != claims.Tenant {
+			return id, apperrors.NewForbiddenError("the " + Header + " header does not match the token's tenant")
+		}
+
+		id = Identity{Tenant: claims.Tenant, Subject: claims.Subject}
+	} else {
+		id.Tenant = header
+	}
+
+	if id.Tenant == "" {
+		if r.required {
+			return id, apperrors.NewUnauthorizedError("the " + Header + " header is required")
+		}
+		id.Tenant = r.fallback
+	}
+
+	if id.Tenant != "" && !Valid(id.Tenant) {
+		return id, apperrors.NewInvalidFormatError(Header)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// All is the tenant of system work that must see every tenant's rows, such as migrations.
+// It cannot be requested by clients, since it is not a valid tenant ID.
+const All = "*"
+
+// validID limits tenant IDs to short identifiers that are safe in headers and logs.
+var validID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+type contextKey struct{}
+
+// Valid reports whether id can name a tenant.
+func Valid(id string) bool {
+	return validID.MatchString(id)
+}
+
+// WithTenant returns a context whose reads and writes are confined to the tenant id.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// WithAll returns a context that sees every tenant's rows.
+func WithAll(ctx context.Context) context.Context {
+	return WithTenant(ctx, All)
+}
+
+// FromContext returns the tenant set by WithTenant or WithAll, or "" when there is none.
+// Without a tenant, the database shows no tenant's rows.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
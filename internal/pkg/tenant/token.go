@@ -0,0 +1,94 @@
+package tenant
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed, unsigned or signed with another key.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrTokenExpired is returned for correctly signed tokens past their expiry.
+	ErrTokenExpired = errors.New("token has expired")
+)
+
+// Claims are the parts of a token's payload the service reads.
+type Claims struct {
+	// Tenant the bearer acts for
+	Tenant string `json:"tenant"`
+
+	// Subject names the bearer; it is recorded as the actor in the audit trail
+	Subject string `json:"sub,omitempty"`
+
+	// ExpiresAt is the expiry as a Unix time; zero means the token does not expire
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+// ParseToken verifies an HS256-signed JWT against secret and returns its claims.
+func ParseToken(token string, secret []byte, now time.Time) (Claims, error) {
+	var claims Claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return claims, ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, sign(parts[0]+"."+parts[1], secret)) {
+		return claims, ErrInvalidToken
+	}
+
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return claims, ErrInvalidToken
+	}
+
+	if claims.ExpiresAt != 0 && !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return claims, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+// NewToken returns an HS256-signed JWT carrying claims.
+func NewToken(claims Claims, secret []byte) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sign(unsigned, secret)), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func sign(unsigned string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,80 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("returns the claims of a valid token", func(t *testing.T) {
+		token, err := NewToken(Claims{Tenant: "acme", Subject: "alice", ExpiresAt: now.Add(time.Hour).Unix()}, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := ParseToken(token, secret, now)
+		if err != nil {
+			t.Fatalf("expected the token to verify, got %v", err)
+		}
+		if claims.Tenant != "acme" || claims.Subject != "alice" {
+			t.Fatalf("expected acme and alice, got %+v", claims)
+		}
+	})
+
+	t.Run("rejects a token signed with another key", func(t *testing.T) {
+		token, _ := NewToken(Claims{Tenant: "acme"}, []byte("other"))
+
+		if _, err := ParseToken(token, secret, now); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("rejects a tampered payload", func(t *testing.T) {
+		token, _ := NewToken(Claims{Tenant: "acme"}, secret)
+		other, _ := NewToken(Claims{Tenant: "globex"}, secret)
+
+		a, b := strings.Split(token, "."), strings.Split(other, ".")
+		if _, err := ParseToken(a[0]+"."+b[1]+"."+a[2], secret, now); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("rejects malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"", "abc", "a.b.c", "a.b"} {
+			if _, err := ParseToken(token, secret, now); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken for %q, got %v", token, err)
+			}
+		}
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		token, _ := NewToken(Claims{Tenant: "acme", ExpiresAt: now.Unix()}, secret)
+
+		if _, err := ParseToken(token, secret, now); !errors.Is(err, ErrTokenExpired) {
+			t.Fatalf("expected ErrTokenExpired, got %v", err)
+		}
+	})
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+
+	if got := FromContext(ctx); got != "" {
+		t.Fatalf("expected no tenant, got %q", got)
+	}
+	if got := FromContext(WithTenant(ctx, "acme")); got != "acme" {
+		t.Fatalf("expected acme, got %q", got)
+	}
+	if got := FromContext(WithAll(ctx)); got != All {
+		t.Fatalf("expected %q, got %q", All, got)
+	}
+	if Valid(All) || Valid("") || !Valid("acme-eu_1") {
+		t.Fatal("expected only identifiers to be valid tenant IDs")
+	}
+}
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/eventbus"
 	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
-	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
 )
 
 // App struct holds the web-server configuration
@@ -13,6 +16,9 @@ type App struct {
 
 	Appconfig    *config.AppConfig
 	PostgresConn *postgres.ConnectionManager
+
+	// Events carries the stock changes committed by product services to stream clients
+	Events *eventbus.Bus
 }
 
 // New initializes and returns a new Server instance
@@ -23,9 +29,26 @@ func New() *App {
 
 	return &App{
 		App: fiber.New(fiber.Config{
-			ErrorHandler: errors.ErrorHandler(),
+			ErrorHandler: apperrors.ErrorHandler(),
+			// Bodies are streamed rather than buffered up front, so that the body limits the
+			// middleware applies per route reject an oversized body before it is read, and
+			// attachment uploads spill to temporary files
+			StreamRequestBody:            true,
+			DisablePreParseMultipartForm: true,
 		}),
-		Appconfig:    config.New(),
+		Appconfig:    cfg,
 		PostgresConn: pConn,
+		Events:       eventbus.New(cfg.StreamConfig.HistorySize),
 	}
 }
+
+// Shutdown ends the event streams, gracefully stops the HTTP server and then closes the
+// database pools.
+func (a *App) Shutdown() error {
+	a.Events.Close()
+
+	return errors.Join(
+		a.App.ShutdownWithTimeout(a.Appconfig.ShutdownTimeout),
+		a.PostgresConn.Close(),
+	)
+}
@@ -0,0 +1,164 @@
+// Package gql serves the product catalogue over GraphQL, resolving through the same
+// product.Service as the REST API.
+package gql
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// Handler serves GraphQL requests.
+type Handler struct {
+	schema  graphql.Schema
+	service product.Service
+	limits  config.GraphQLConfig
+}
+
+// NewHandler returns a Handler resolving through s and rejecting queries beyond the
+// limits in cfg.
+func NewHandler(s product.Service, cfg config.GraphQLConfig) *Handler {
+	schema, err := newSchema(s)
+	if err != nil {
+		panic("gql: invalid schema: " + err.Error())
+	}
+
+	return &Handler{
+		schema:  schema,
+		service: s,
+		limits:  cfg,
+	}
+}
+
+// request is a GraphQL request as posted by clients.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Query executes a GraphQL request. Requests that cannot be parsed, fail validation or
+// exceed the depth and complexity limits are rejected with 400 before anything is
+// resolved; errors raised while resolving are reported in the response's errors, with the
+// AppError code as the extensions.code of each.
+func (h *Handler) Query() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req request
+		if err := c.BodyParser(&req); err != nil {
+			return reject(c, apperrors.NewInvalidFormatError("request body"))
+		}
+		if req.Query == "" {
+			return reject(c, apperrors.NewMissingRequiredDataError("query"))
+		}
+
+		doc, err := parser.Parse(parser.ParseParams{
+			Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+		})
+		if err != nil {
+			return rejectFormatted(c, gqlerrors.FormatErrors(err))
+		}
+
+		if res := graphql.ValidateDocument(&h.schema, doc, nil); !res.IsValid {
+			return rejectFormatted(c, res.Errors)
+		}
+
+		op := operation(doc, req.OperationName)
+		if op == nil {
+			return reject(c, apperrors.NewInvalidInputError("the request names no operation to execute"))
+		}
+
+		if err := checkLimits(&h.schema, doc, op, req.Variables, h.limits); err != nil {
+			return reject(c, err)
+		}
+
+		ctx := withLoaders(c.UserContext(), newLoaders(h.service))
+		if op.Operation == ast.OperationTypeQuery {
+			ctx = postgres.WithReadOnly(ctx)
+		}
+
+		res := graphql.Execute(graphql.ExecuteParams{
+			Schema:        h.schema,
+			AST:           doc,
+			OperationName: req.OperationName,
+			Args:          req.Variables,
+			Context:       ctx,
+		})
+		res.Errors = withCodes(res.Errors)
+
+		return c.JSON(res)
+	}
+}
+
+// operation returns the operation a request executes: the one named, or the only one.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+// withCodes sets extensions.code on errors raised by the service layer. The executor
+// wraps resolver errors, so each is unwrapped down to the AppError, if any.
+func withCodes(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, e := range errs {
+		if appErr := appError(e); appErr != nil {
+			errs[i].Message = appErr.Message
+			errs[i].Extensions = map[string]any{"code": appErr.Code}
+		}
+	}
+	return errs
+}
+
+func appError(err error) *apperrors.AppError {
+	for err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// reject answers a request that cannot be executed with 400 and the error's code.
+func reject(c *fiber.Ctx, err error) error {
+	return rejectFormatted(c, withCodes(gqlerrors.FormatErrors(err)))
+}
+
+func rejectFormatted(c *fiber.Ctx, errs []gqlerrors.FormattedError) error {
+	for i := range errs {
+		if errs[i].Extensions == nil {
+			errs[i].Extensions = map[string]any{"code": apperrors.ValidationError}
+		}
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"errors": errs})
+}
@@ -0,0 +1,311 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+)
+
+// gqlProductService is a product.Service over a fixed set of products that counts the
+// calls made to it. Operations not covered by the tests fall through to the nil embedded
+// Service.
+type gqlProductService struct {
+	product.Service
+
+	products  []product.Product
+	movements []product.StockMovement
+
+	listCalls      int
+	idListCalls    int
+	movementCalls  int
+	pageFilter     product.Filter
+	movementFilter product.MovementFilter
+}
+
+func (m *gqlProductService) GetAllProducts(_ context.Context, filter product.Filter) ([]product.Product, error) {
+	m.listCalls++
+	if len(filter.IDs) > 0 {
+		m.idListCalls++
+	} else {
+		m.pageFilter = filter
+	}
+
+	out := m.matching(filter)
+	out = out[min(filter.Offset, len(out)):]
+	if filter.Limit > 0 {
+		out = out[:min(filter.Limit, len(out))]
+	}
+	return out, nil
+}
+
+func (m *gqlProductService) CountProducts(_ context.Context, filter product.Filter) (int, error) {
+	return len(m.matching(filter)), nil
+}
+
+// matching returns the products the filter selects, in the order of the catalogue.
+func (m *gqlProductService) matching(filter product.Filter) []product.Product {
+	var out []product.Product
+	for _, p := range m.products {
+		if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, p.ID.String()) {
+			continue
+		}
+		if filter.LowStock && p.StockQuantity > p.LowStockThresold {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func (m *gqlProductService) GetProductByID(_ context.Context, id string) (*product.Product, error) {
+	for i := range m.products {
+		if m.products[i].ID.String() == id {
+			p := m.products[i]
+			return &p, nil
+		}
+	}
+	return nil, apperrors.NewProductNotFoundError(id)
+}
+
+func (m *gqlProductService) ListMovements(_ context.Context, filter product.MovementFilter) ([]product.StockMovement, error) {
+	m.movementCalls++
+	m.movementFilter = filter
+	var out []product.StockMovement
+	kept := map[uuid.UUID]int{}
+	for _, mv := range slices.Backward(m.movements) {
+		if slices.Contains(filter.ProductIDs, mv.ProductID.String()) && kept[mv.ProductID] < filter.LastPerProduct {
+			kept[mv.ProductID]++
+			out = append([]product.StockMovement{mv}, out...)
+		}
+	}
+	return out, nil
+}
+
+func (m *gqlProductService) IncermentStock(_ context.Context, id string, quantity int) error {
+	for i := range m.products {
+		if m.products[i].ID.String() == id {
+			m.products[i].StockQuantity += quantity
+			return nil
+		}
+	}
+	return apperrors.NewProductNotFoundError(id)
+}
+
+// ToBaseUnits knows a single unit, boxes of 10.
+func (m *gqlProductService) ToBaseUnits(_ context.Context, _ string, q quantity.Decimal, unit string) (int, error) {
+	if unit != "box" {
+		return 0, apperrors.NewInvalidInputError("unit " + unit + " is not defined for this product")
+	}
+	n, err := strconv.Atoi(string(q))
+	return n * 10, err
+}
+
+// newCatalogue returns two components and a kit made of both, each with two movements.
+func newCatalogue() *gqlProductService {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newProduct := func(name string, stock, threshold int, age int) product.Product {
+		return product.Product{
+			BaseModel:        model.BaseModel{ID: uuid.New(), CreatedAt: created.Add(time.Duration(age) * time.Hour)},
+			Name:             name,
+			StockQuantity:    stock,
+			LowStockThresold: threshold,
+		}
+	}
+
+	bolt, nut := newProduct("Bolt", 100, 10, 0), newProduct("Nut", 5, 10, 1)
+	kit := newProduct("Fixing kit", 5, 2, 2)
+	kit.Components = []product.KitComponent{
+		{ComponentID: bolt.ID, Quantity: 4},
+		{ComponentID: nut.ID, Quantity: 4},
+	}
+
+	m := &gqlProductService{products: []product.Product{bolt, nut, kit}}
+	for _, p := range m.products {
+		for i := range 2 {
+			m.movements = append(m.movements, product.StockMovement{
+				BaseModel:  model.BaseModel{ID: uuid.New()},
+				ProductID:  p.ID,
+				Quantity:   i + 1,
+				Reason:     product.ReasonIncrement,
+				OccurredAt: created.Add(time.Duration(i) * time.Hour),
+			})
+		}
+	}
+	return m
+}
+
+type response struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h *Handler, query string, variables map[string]any) (int, response) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Post("/graphql", h.Query())
+
+	body, _ := json.Marshal(request{Query: query, Variables: variables})
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	var out response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp.StatusCode, out
+}
+
+var testLimits = config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 5000}
+
+func TestQueryBatchesRelations(t *testing.T) {
+	svc := newCatalogue()
+	h := NewHandler(svc, testLimits)
+
+	status, resp := post(t, h, `{
+		products(limit: 10) {
+			total
+			items {
+				name
+				components { quantity product { name stockQuantity } }
+				movements(last: 1) { quantity product { name } }
+			}
+		}
+	}`, nil)
+	if status != fiber.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("expected 200 without errors, got %d: %+v", status, resp.Errors)
+	}
+
+	page := resp.Data["products"].(map[string]any)
+	items := page["items"].([]any)
+	if page["total"].(float64) != 3 || len(items) != 3 {
+		t.Fatalf("expected 3 products, got %v", page)
+	}
+
+	kit := items[2].(map[string]any)
+	components := kit["components"].([]any)
+	if len(components) != 2 || components[0].(map[string]any)["product"].(map[string]any)["name"] != "Bolt" {
+		t.Fatalf("expected the kit's components to resolve, got %v", components)
+	}
+
+	movements := kit["movements"].([]any)
+	if len(movements) != 1 || movements[0].(map[string]any)["quantity"].(float64) != 2 {
+		t.Fatalf("expected only the latest movement, got %v", movements)
+	}
+
+	// One listing for the page, one for every product referenced by components and
+	// movements, and one ledger query for the movements of all three products
+	if svc.listCalls != 2 || svc.idListCalls != 1 || svc.movementCalls != 1 {
+		t.Fatalf("expected batched loads, got %d listings (%d by ID) and %d ledger queries",
+			svc.listCalls, svc.idListCalls, svc.movementCalls)
+	}
+	if svc.movementFilter.LastPerProduct != 1 {
+		t.Fatalf("expected the ledger query to be limited per product, got %+v", svc.movementFilter)
+	}
+}
+
+func TestQueryFiltersAndPages(t *testing.T) {
+	svc := newCatalogue()
+	h := NewHandler(svc, testLimits)
+
+	_, resp := post(t, h, `{ products(limit: 1, offset: 1) { total items { name } } }`, nil)
+	page := resp.Data["products"].(map[string]any)
+	if items := page["items"].([]any); page["total"].(float64) != 3 || len(items) != 1 || items[0].(map[string]any)["name"] != "Nut" {
+		t.Fatalf("expected the second of 3 products, got %v", page)
+	}
+	if svc.pageFilter.Limit != 1 || svc.pageFilter.Offset != 1 {
+		t.Fatalf("expected the page to be loaded by the service, got %+v", svc.pageFilter)
+	}
+
+	_, resp = post(t, h, `query($low: Boolean) { products(filter: {lowStock: $low}) { items { name lowStock } } }`, map[string]any{"low": true})
+	items := resp.Data["products"].(map[string]any)["items"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["name"] != "Nut" {
+		t.Fatalf("expected only the low-stock product, got %v", items)
+	}
+	if !svc.pageFilter.LowStock {
+		t.Fatalf("expected low stock to be filtered by the service, got %+v", svc.pageFilter)
+	}
+
+	_, resp = post(t, h, `{ products(limit: 500) { total } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(apperrors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for an oversized page, got %+v", resp.Errors)
+	}
+}
+
+func TestQueryReportsErrorCodes(t *testing.T) {
+	h := NewHandler(newCatalogue(), testLimits)
+
+	_, resp := post(t, h, `{ product(id: "00000000-0000-0000-0000-000000000000") { name } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(apperrors.ProductNotFound) {
+		t.Fatalf("expected PRODUCT_NOT_FOUND, got %+v", resp.Errors)
+	}
+
+	status, resp := post(t, h, `{ products { items { colour } } }`, nil)
+	if status != fiber.StatusBadRequest || len(resp.Errors) == 0 || resp.Errors[0].Extensions["code"] != string(apperrors.ValidationError) {
+		t.Fatalf("expected 400 with VALIDATION_ERROR for an unknown field, got %d: %+v", status, resp.Errors)
+	}
+}
+
+func TestMutationChangesStock(t *testing.T) {
+	svc := newCatalogue()
+	h := NewHandler(svc, testLimits)
+	id := svc.products[0].ID.String()
+
+	mutation := `mutation($input: StockChangeInput!) { incrementStock(input: $input) { stockQuantity } }`
+
+	_, resp := post(t, h, mutation, map[string]any{"input": map[string]any{"productId": id, "quantity": 5}})
+	if len(resp.Errors) > 0 || resp.Data["incrementStock"].(map[string]any)["stockQuantity"].(float64) != 105 {
+		t.Fatalf("expected stock of 105, got %+v %+v", resp.Data, resp.Errors)
+	}
+
+	_, resp = post(t, h, mutation, map[string]any{"input": map[string]any{"productId": id, "quantity": 2, "unit": "box"}})
+	if len(resp.Errors) > 0 || resp.Data["incrementStock"].(map[string]any)["stockQuantity"].(float64) != 125 {
+		t.Fatalf("expected stock of 125 after adding two boxes of 10, got %+v %+v", resp.Data, resp.Errors)
+	}
+
+	_, resp = post(t, h, mutation, map[string]any{"input": map[string]any{"productId": id, "quantity": 0}})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(apperrors.InvalidInput) {
+		t.Fatalf("expected INVALID_INPUT for a zero quantity, got %+v", resp.Errors)
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	h := NewHandler(newCatalogue(), config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 500})
+
+	status, resp := post(t, h, `{ products { items { components { product { components { quantity } } } } } }`, nil)
+	if status != fiber.StatusBadRequest || len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(apperrors.InvalidInput) {
+		t.Fatalf("expected 400 for a query too deep, got %d: %+v", status, resp.Errors)
+	}
+
+	status, _ = post(t, h, `{ products(limit: 100) { items { movements(last: 100) { quantity } } } }`, nil)
+	if status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a query too complex, got %d", status)
+	}
+
+	status, resp = post(t, h, `{ products(limit: 10) { items { name movements(last: 5) { quantity } } } }`, nil)
+	if status != fiber.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("expected a query within the limits to run, got %d: %+v", status, resp.Errors)
+	}
+}
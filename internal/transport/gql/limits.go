@@ -0,0 +1,189 @@
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// defaultListSize is the number of items assumed for lists whose length a query cannot bound.
+const defaultListSize = 10
+
+// sizeArgs names the argument bounding the length of paged fields, by type and field.
+var sizeArgs = map[string]string{
+	"Query.products":       "limit",
+	"Query.searchProducts": "limit",
+	"Product.movements":    "last",
+}
+
+// pageItems are the lists of a page, whose length is already counted by the paged field.
+var pageItems = map[string]bool{
+	"ProductPage.items":  true,
+	"SearchPage.results": true,
+}
+
+// checkLimits rejects an operation that nests fields deeper than cfg.MaxDepth or costs
+// more than cfg.MaxComplexity. Every field costs one, and the fields selected under a list
+// are counted once per item it can return. Introspection fields are free.
+func checkLimits(schema *graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, vars map[string]any, cfg config.GraphQLConfig) error {
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	a := &analysis{fragments: map[string]*ast.FragmentDefinition{}, vars: vars, schema: schema}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[f.Name.Value] = f
+		}
+	}
+
+	cost, depth := a.selectionSet(op.SelectionSet, root, 0)
+
+	if cfg.MaxDepth > 0 && depth > cfg.MaxDepth {
+		return apperrors.NewInvalidInputError(fmt.Sprintf("query depth %d exceeds the limit of %d", depth, cfg.MaxDepth))
+	}
+	if cfg.MaxComplexity > 0 && cost > cfg.MaxComplexity {
+		return apperrors.NewInvalidInputError(fmt.Sprintf("query complexity %d exceeds the limit of %d", cost, cfg.MaxComplexity))
+	}
+
+	return nil
+}
+
+type analysis struct {
+	fragments map[string]*ast.FragmentDefinition
+	vars      map[string]any
+	schema    *graphql.Schema
+
+	// visiting guards against fragment cycles, which validation reports on its own
+	visiting []string
+}
+
+// selectionSet returns the cost of the selections on parent and the depth of their deepest field.
+func (a *analysis) selectionSet(set *ast.SelectionSet, parent *graphql.Object, depth int) (cost, maxDepth int) {
+	if set == nil || parent == nil {
+		return 0, depth
+	}
+	maxDepth = depth
+
+	for _, sel := range set.Selections {
+		var c, d int
+
+		switch sel := sel.(type) {
+		case *ast.Field:
+			c, d = a.field(sel, parent, depth)
+		case *ast.InlineFragment:
+			c, d = a.selectionSet(sel.SelectionSet, a.condition(sel.TypeCondition, parent), depth)
+		case *ast.FragmentSpread:
+			f := a.fragments[sel.Name.Value]
+			if f == nil || slices.Contains(a.visiting, f.Name.Value) {
+				continue
+			}
+			a.visiting = append(a.visiting, f.Name.Value)
+			c, d = a.selectionSet(f.SelectionSet, a.condition(f.TypeCondition, parent), depth)
+			a.visiting = a.visiting[:len(a.visiting)-1]
+		}
+
+		cost += c
+		maxDepth = max(maxDepth, d)
+	}
+
+	return cost, maxDepth
+}
+
+func (a *analysis) field(f *ast.Field, parent *graphql.Object, depth int) (cost, maxDepth int) {
+	name := f.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return 0, depth
+	}
+
+	def := parent.Fields()[name]
+	if def == nil {
+		return 0, depth
+	}
+
+	key := parent.Name() + "." + name
+	named, isList := unwrap(def.Type)
+
+	child, _ := named.(*graphql.Object)
+	childCost, maxDepth := a.selectionSet(f.SelectionSet, child, depth+1)
+
+	size := 1
+	switch {
+	case sizeArgs[key] != "":
+		size = a.intArgument(f, def, sizeArgs[key])
+	case isList && !pageItems[key]:
+		size = defaultListSize
+	}
+
+	return 1 + max(size, 1)*childCost, max(maxDepth, depth+1)
+}
+
+// condition returns the object type a fragment applies to, or parent when it names none.
+func (a *analysis) condition(typ *ast.Named, parent *graphql.Object) *graphql.Object {
+	if typ == nil {
+		return parent
+	}
+	obj, _ := a.schema.Type(typ.Name.Value).(*graphql.Object)
+	return obj
+}
+
+// intArgument returns the value of an Int argument given as a literal or variable, or its default.
+func (a *analysis) intArgument(f *ast.Field, def *graphql.FieldDefinition, name string) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			n, _ := strconv.Atoi(v.Value)
+			return n
+		case *ast.Variable:
+			if value, ok := a.vars[v.Name.Value]; ok {
+				return toInt(value)
+			}
+		}
+	}
+
+	for _, arg := range def.Args {
+		if arg.Name() == name {
+			return toInt(arg.DefaultValue)
+		}
+	}
+	return 1
+}
+
+func toInt(v any) int {
+	switch v := v.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return int(n)
+	}
+	return 0
+}
+
+// unwrap returns the named type under any non-null and list wrappers, and whether it is a list.
+func unwrap(t graphql.Type) (graphql.Type, bool) {
+	isList := false
+	for {
+		switch w := t.(type) {
+		case *graphql.NonNull:
+			t = w.OfType
+		case *graphql.List:
+			t, isList = w.OfType, true
+		default:
+			return t, isList
+		}
+	}
+}
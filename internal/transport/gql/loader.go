@@ -0,0 +1,68 @@
+package gql
+
+import (
+	"context"
+	"sync"
+)
+
+// loader batches the loads made while a request is resolved. The executor calls every
+// resolver of one level of the query before it calls any of the thunks they return, so the
+// keys queued by a level are fetched together when the first of their thunks runs. Results
+// are kept for the rest of the request.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	done    map[K]loaded[V]
+}
+
+type loaded[V any] struct {
+	value V
+	err   error
+}
+
+// newLoader returns a loader fetching batches of keys with fetch. Keys missing from the
+// map fetch returns load as the zero value.
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: map[K]bool{},
+		done:   map[K]loaded[V]{},
+	}
+}
+
+// load queues key for the next batch and returns a thunk resolving to its value.
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (any, error) {
+	l.mu.Lock()
+	if _, ok := l.done[key]; !ok && !l.queued[key] {
+		l.pending = append(l.pending, key)
+		l.queued[key] = true
+	}
+	l.mu.Unlock()
+
+	return func() (any, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, ok := l.done[key]; !ok {
+			l.dispatch(ctx)
+		}
+
+		r := l.done[key]
+		return r.value, r.err
+	}
+}
+
+// dispatch fetches the pending keys. It must be called with l.mu held.
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	clear(l.queued)
+
+	values, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		l.done[k] = loaded[V]{value: values[k], err: err}
+	}
+}
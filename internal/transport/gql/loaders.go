@@ -0,0 +1,130 @@
+package gql
+
+import (
+	"context"
+	"time"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+)
+
+// loaders are the batch loaders of one request.
+type loaders struct {
+	// products by ID, with their associations
+	products *loader[string, *product.Product]
+	// latest movements of a product in a time range, oldest first
+	movements *loader[movementKey, []product.StockMovement]
+	// lots of a product expiring within a number of days
+	expiringLots *loader[lotKey, []product.Lot]
+}
+
+type movementKey struct {
+	productID    string
+	since, until time.Time
+	last         int
+}
+
+type lotKey struct {
+	productID string
+	days      int
+}
+
+func newLoaders(s product.Service) *loaders {
+	return &loaders{
+		products:     newLoader(productsFetcher(s)),
+		movements:    newLoader(movementsFetcher(s)),
+		expiringLots: newLoader(expiringLotsFetcher(s)),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
+
+// productsFetcher loads a batch of products with one listing. Products that do not exist
+// are left out, so they resolve to null.
+func productsFetcher(s product.Service) func(context.Context, []string) (map[string]*product.Product, error) {
+	return func(ctx context.Context, ids []string) (map[string]*product.Product, error) {
+		products, err := s.GetAllProducts(ctx, product.Filter{IDs: ids})
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[string]*product.Product, len(products))
+		for i := range products {
+			byID[products[i].ID.String()] = &products[i]
+		}
+		return byID, nil
+	}
+}
+
+// movementsFetcher loads the latest movements of a batch of products with one ledger query
+// per distinct time range and count, which the database limits per product.
+func movementsFetcher(s product.Service) func(context.Context, []movementKey) (map[movementKey][]product.StockMovement, error) {
+	return func(ctx context.Context, keys []movementKey) (map[movementKey][]product.StockMovement, error) {
+		type window struct {
+			since, until time.Time
+			last         int
+		}
+
+		ids := map[window][]string{}
+		for _, k := range keys {
+			w := window{k.since, k.until, k.last}
+			ids[w] = append(ids[w], k.productID)
+		}
+
+		out := make(map[movementKey][]product.StockMovement, len(keys))
+		for w, productIDs := range ids {
+			movements, err := s.ListMovements(ctx, product.MovementFilter{
+				ProductIDs:     productIDs,
+				Since:          w.since,
+				Until:          w.until,
+				LastPerProduct: w.last,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, mv := range movements {
+				k := movementKey{productID: mv.ProductID.String(), since: w.since, until: w.until, last: w.last}
+				out[k] = append(out[k], mv)
+			}
+		}
+		return out, nil
+	}
+}
+
+// expiringLotsFetcher loads the expiring lots of a batch of products with one query per
+// distinct number of days.
+func expiringLotsFetcher(s product.Service) func(context.Context, []lotKey) (map[lotKey][]product.Lot, error) {
+	return func(ctx context.Context, keys []lotKey) (map[lotKey][]product.Lot, error) {
+		wanted := map[lotKey]bool{}
+		days := map[int]bool{}
+		for _, k := range keys {
+			wanted[k] = true
+			days[k.days] = true
+		}
+
+		out := make(map[lotKey][]product.Lot, len(keys))
+		for d := range days {
+			lots, err := s.GetExpiringLots(ctx, d)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, lot := range lots {
+				k := lotKey{productID: lot.ProductID.String(), days: d}
+				if wanted[k] {
+					out[k] = append(out[k], lot)
+				}
+			}
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,360 @@
+package gql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+)
+
+// resolver resolves the root and relation fields through the product service.
+type resolver struct {
+	service product.Service
+}
+
+// productPage is a page of a product listing.
+type productPage struct {
+	Total  int
+	Limit  int
+	Offset int
+	Items  []*product.Product
+}
+
+// loaders returns the batch loaders of the request, or new ones outside of a request.
+func (r *resolver) loaders(ctx context.Context) *loaders {
+	if l := loadersFrom(ctx); l != nil {
+		return l
+	}
+	return newLoaders(r.service)
+}
+
+func (r *resolver) products(p graphql.ResolveParams) (any, error) {
+	limit, offset := intArg(p.Args, "limit"), intArg(p.Args, "offset")
+	if limit < 1 || limit > maxPageSize {
+		return nil, apperrors.NewInvalidInputError("limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return nil, apperrors.NewInvalidInputError("offset cannot be negative")
+	}
+
+	in, _ := p.Args["filter"].(map[string]any)
+	filter, err := productFilter(in)
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit, filter.Offset = limit, offset
+
+	products, err := r.service.GetAllProducts(p.Context, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := r.service.CountProducts(p.Context, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &productPage{Total: total, Limit: limit, Offset: offset, Items: []*product.Product{}}
+	for i := range products {
+		page.Items = append(page.Items, &products[i])
+	}
+
+	return page, nil
+}
+
+// productFilter converts a ProductFilter input to a product.Filter.
+func productFilter(in map[string]any) (product.Filter, error) {
+	filter := product.Filter{
+		CategoryID:         stringArg(in, "categoryId"),
+		IncludeDescendants: true,
+		IDs:                stringsArg(in, "ids"),
+		Tags:               stringsArg(in, "tags"),
+		AsOf:               timeArg(in, "asOf"),
+	}
+	filter.LowStock, _ = in["lowStock"].(bool)
+
+	if v, ok := in["includeDescendants"].(bool); ok {
+		filter.IncludeDescendants = v
+	}
+
+	for _, id := range filter.IDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return filter, apperrors.NewInvalidFormatError("ids")
+		}
+	}
+	if filter.CategoryID != "" {
+		if _, err := uuid.Parse(filter.CategoryID); err != nil {
+			return filter, apperrors.NewInvalidFormatError("categoryId")
+		}
+	}
+
+	if attrs, ok := in["attributes"].([]any); ok {
+		filter.Attributes = map[string]string{}
+		for _, a := range attrs {
+			attr, _ := a.(map[string]any)
+			filter.Attributes[stringArg(attr, "name")] = stringArg(attr, "value")
+		}
+	}
+
+	return filter, nil
+}
+
+func (r *resolver) product(p graphql.ResolveParams) (any, error) {
+	id := stringArg(p.Args, "id")
+
+	if asOf := timeArg(p.Args, "asOf"); !asOf.IsZero() {
+		return r.service.GetProductAsOf(p.Context, id, asOf)
+	}
+
+	return r.service.GetProductByID(p.Context, id)
+}
+
+func (r *resolver) searchProducts(p graphql.ResolveParams) (any, error) {
+	return r.service.SearchProducts(p.Context, product.SearchQuery{
+		Text:   stringArg(p.Args, "query"),
+		Limit:  intArg(p.Args, "limit"),
+		Offset: intArg(p.Args, "offset"),
+	})
+}
+
+// movements loads the latest movements of the product, batched with those of the other
+// products in the query.
+func (r *resolver) movements(p graphql.ResolveParams) (any, error) {
+	src, _ := p.Source.(*product.Product)
+
+	last := intArg(p.Args, "last")
+	if last < 1 || last > maxPageSize {
+		return nil, apperrors.NewInvalidInputError("last must be between 1 and 100")
+	}
+
+	thunk := r.loaders(p.Context).movements.load(p.Context, movementKey{
+		productID: src.ID.String(),
+		since:     timeArg(p.Args, "since"),
+		until:     timeArg(p.Args, "until"),
+		last:      last,
+	})
+
+	return func() (any, error) {
+		v, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+
+		movements, _ := v.([]product.StockMovement)
+		return nonNil(movements), nil
+	}, nil
+}
+
+// expiringLots loads the product's expiring lots, batched with those of the other
+// products in the query.
+func (r *resolver) expiringLots(p graphql.ResolveParams) (any, error) {
+	src, _ := p.Source.(*product.Product)
+
+	days := intArg(p.Args, "days")
+	if days < 0 {
+		return nil, apperrors.NewInvalidInputError("days cannot be negative")
+	}
+
+	thunk := r.loaders(p.Context).expiringLots.load(p.Context, lotKey{productID: src.ID.String(), days: days})
+
+	return func() (any, error) {
+		v, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+
+		lots, _ := v.([]product.Lot)
+		return nonNil(lots), nil
+	}, nil
+}
+
+// componentProduct loads a kit component's product, batched with the other products
+// referenced by the query.
+func (r *resolver) componentProduct(p graphql.ResolveParams) (any, error) {
+	src, _ := p.Source.(product.KitComponent)
+	return r.loaders(p.Context).products.load(p.Context, src.ComponentID.String()), nil
+}
+
+// movementProduct loads a movement's product, batched with the other products
+// referenced by the query.
+func (r *resolver) movementProduct(p graphql.ResolveParams) (any, error) {
+	src, _ := p.Source.(product.StockMovement)
+	return r.loaders(p.Context).products.load(p.Context, src.ProductID.String()), nil
+}
+
+func (r *resolver) incrementStock(p graphql.ResolveParams) (any, error) {
+	in, _ := p.Args["input"].(map[string]any)
+	id, variantID, serials := stringArg(in, "productId"), stringArg(in, "variantId"), stringsArg(in, "serials")
+
+	quantity, err := r.baseQuantity(p.Context, id, intArg(in, "quantity"), stringArg(in, "unit"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateStockChange(quantity, serials); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(serials) > 0:
+		err = r.service.IncrementSerials(p.Context, id, serials)
+	case variantID != "":
+		err = r.service.IncrementVariantStock(p.Context, id, variantID, quantity)
+	default:
+		err = r.service.IncermentStock(p.Context, id, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.service.GetProductByID(p.Context, id)
+}
+
+func (r *resolver) decrementStock(p graphql.ResolveParams) (any, error) {
+	in, _ := p.Args["input"].(map[string]any)
+	id, variantID, serials := stringArg(in, "productId"), stringArg(in, "variantId"), stringsArg(in, "serials")
+
+	quantity, err := r.baseQuantity(p.Context, id, intArg(in, "quantity"), stringArg(in, "unit"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateStockChange(quantity, serials); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(serials) > 0:
+		err = r.service.DecrementSerials(p.Context, id, serials)
+	case variantID != "":
+		err = r.service.DecrementVariantStock(p.Context, id, variantID, quantity)
+	default:
+		err = r.service.DecrementStock(p.Context, id, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.service.GetProductByID(p.Context, id)
+}
+
+// baseQuantity converts a quantity in unit to the product's base unit.
+func (r *resolver) baseQuantity(ctx context.Context, id string, n int, unit string) (int, error) {
+	if unit == "" {
+		return n, nil
+	}
+
+	return r.service.ToBaseUnits(ctx, id, quantity.FromInt(n), unit)
+}
+
+// validateStockChange checks the quantity of a stock change, which must match the number
+// of serials when they are given.
+func validateStockChange(quantity int, serials []string) error {
+	if quantity <= 0 {
+		return apperrors.NewInvalidInputError("quantity must be greater than 0")
+	}
+
+	if len(serials) > 0 && quantity != len(serials) {
+		return apperrors.NewInvalidInputError("quantity must equal the number of serials")
+	}
+
+	return nil
+}
+
+func (r *resolver) reserveStock(p graphql.ResolveParams) (any, error) {
+	in, _ := p.Args["input"].(map[string]any)
+	id := stringArg(in, "productId")
+
+	reserved, err := r.service.ReserveStock(p.Context, id, stringArg(in, "variantId"), intArg(in, "quantity"))
+	if err != nil {
+		return nil, err
+	}
+
+	prod, err := r.service.GetProductByID(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"product": prod, "reserved": reserved}, nil
+}
+
+func (r *resolver) releaseStock(p graphql.ResolveParams) (any, error) {
+	in, _ := p.Args["input"].(map[string]any)
+	id := stringArg(in, "productId")
+
+	if err := r.service.ReleaseStock(p.Context, id, stringArg(in, "variantId"), intArg(in, "quantity")); err != nil {
+		return nil, err
+	}
+
+	return r.service.GetProductByID(p.Context, id)
+}
+
+func (r *resolver) moveStock(p graphql.ResolveParams) (any, error) {
+	in, _ := p.Args["input"].(map[string]any)
+	id := stringArg(in, "productId")
+
+	from, _ := in["from"].(product.Bucket)
+	to, _ := in["to"].(product.Bucket)
+
+	err := r.service.MoveStock(p.Context, id, product.BucketMove{
+		VariantID: stringArg(in, "variantId"),
+		From:      from,
+		To:        to,
+		Quantity:  intArg(in, "quantity"),
+		Unit:      stringArg(in, "unit"),
+		LotNumber: stringArg(in, "lotNumber"),
+		Serials:   stringsArg(in, "serials"),
+		Note:      stringArg(in, "note"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.service.GetProductByID(p.Context, id)
+}
+
+func stringArg(args map[string]any, name string) string {
+	v, _ := args[name].(string)
+	return v
+}
+
+func intArg(args map[string]any, name string) int {
+	v, _ := args[name].(int)
+	return v
+}
+
+func timeArg(args map[string]any, name string) time.Time {
+	v, _ := args[name].(time.Time)
+	return v
+}
+
+func stringsArg(args map[string]any, name string) []string {
+	items, _ := args[name].([]any)
+
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func optionalID(id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// nonNil returns s, or an empty slice when s is nil, for fields typed as non-null lists.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,377 @@
+package gql
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+)
+
+// Defaults and caps for the lists a query can page through.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	defaultMovements = 20
+	defaultLotDays   = 30
+)
+
+// jsonScalar carries free-form values such as product attributes as they are.
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "An arbitrary JSON value",
+	Serialize:   func(v any) any { return v },
+})
+
+var bucketEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Bucket",
+	Description: "A stock status; only available units can be sold",
+	Values: graphql.EnumValueConfigMap{
+		"AVAILABLE":   {Value: product.BucketAvailable},
+		"QUARANTINED": {Value: product.BucketQuarantined},
+		"DAMAGED":     {Value: product.BucketDamaged},
+		"IN_TRANSIT":  {Value: product.BucketInTransit},
+	},
+})
+
+// field resolves a field from the source value with fn.
+func field[T any](fn func(T) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		src, _ := p.Source.(T)
+		return fn(src), nil
+	}
+}
+
+// nonNull wraps a type as non-null.
+func nonNull(t graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(t)
+}
+
+// listOf is a non-null list of non-null items.
+func listOf(t graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+// newSchema builds the schema, resolving through s.
+func newSchema(s product.Service) (graphql.Schema, error) {
+	r := &resolver{service: s}
+
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":          {Type: nonNull(graphql.ID), Resolve: field(func(c category.Category) any { return c.ID.String() })},
+			"name":        {Type: nonNull(graphql.String)},
+			"description": {Type: nonNull(graphql.String)},
+			"parentId": {Type: graphql.ID, Resolve: field(func(c category.Category) any {
+				if c.ParentID == nil {
+					return nil
+				}
+				return c.ParentID.String()
+			})},
+		},
+	})
+
+	optionAxisType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OptionAxis",
+		Fields: graphql.Fields{
+			"name":   {Type: nonNull(graphql.String)},
+			"values": {Type: listOf(graphql.String)},
+		},
+	})
+
+	variantType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Variant",
+		Fields: graphql.Fields{
+			"id":                {Type: nonNull(graphql.ID), Resolve: field(func(v product.Variant) any { return v.ID.String() })},
+			"sku":               {Type: nonNull(graphql.String)},
+			"options":           {Type: nonNull(jsonScalar), Description: "Value per option axis"},
+			"stockQuantity":     {Type: nonNull(graphql.Int)},
+			"reservedQuantity":  {Type: nonNull(graphql.Int)},
+			"lowStockThreshold": {Type: nonNull(graphql.Int)},
+			"lowStock": {Type: nonNull(graphql.Boolean), Resolve: field(func(v product.Variant) any {
+				return v.StockQuantity <= v.LowStockThreshold
+			})},
+		},
+	})
+
+	unitType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "UnitOfMeasure",
+		Fields: graphql.Fields{
+			"name":   {Type: nonNull(graphql.String)},
+			"factor": {Type: nonNull(graphql.String), Description: "Base units in one of this unit, as an exact decimal", Resolve: field(func(u product.UnitOfMeasure) any { return string(u.Factor) })},
+		},
+	})
+
+	lotType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Lot",
+		Fields: graphql.Fields{
+			"id":        {Type: nonNull(graphql.ID), Resolve: field(func(l product.Lot) any { return l.ID.String() })},
+			"lotNumber": {Type: nonNull(graphql.String)},
+			"expiresAt": {Type: graphql.DateTime},
+			"quantity":  {Type: nonNull(graphql.Int)},
+		},
+	})
+
+	// Product refers to itself through kit components and movements, so its fields are
+	// added once the types referring back to it exist
+	productType := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Product",
+		Fields: graphql.Fields{},
+	})
+
+	kitComponentType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "KitComponent",
+		Fields: graphql.Fields{
+			"quantity": {Type: nonNull(graphql.Int), Description: "Units of the component per kit"},
+			"product":  {Type: productType, Description: "The component; null if it has since been deleted", Resolve: r.componentProduct},
+		},
+	})
+
+	movementType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "StockMovement",
+		Description: "An entry in the stock ledger",
+		Fields: graphql.Fields{
+			"id":        {Type: nonNull(graphql.ID), Resolve: field(func(m product.StockMovement) any { return m.ID.String() })},
+			"product":   {Type: productType, Resolve: r.movementProduct},
+			"variantId": {Type: graphql.ID, Resolve: field(func(m product.StockMovement) any { return optionalID(m.VariantID) })},
+			"lotId":     {Type: graphql.ID, Resolve: field(func(m product.StockMovement) any { return optionalID(m.LotID) })},
+			"serials":   {Type: listOf(graphql.String), Resolve: field(func(m product.StockMovement) any { return nonNil(m.Serials) })},
+			"quantity":  {Type: nonNull(graphql.Int), Description: "Signed change in units; negative for stock leaving"},
+			"bucket": {Type: bucketEnum, Description: "For moves between buckets, the bucket the units left for or came from", Resolve: field(func(m product.StockMovement) any {
+				if m.Bucket == "" {
+					return nil
+				}
+				return m.Bucket
+			})},
+			"reason":        {Type: nonNull(graphql.String), Resolve: field(func(m product.StockMovement) any { return string(m.Reason) })},
+			"unitCost":      {Type: nonNull(graphql.Int), Description: "Cost per unit in minor units for stock entering"},
+			"currency":      {Type: nonNull(graphql.String)},
+			"referenceType": {Type: nonNull(graphql.String)},
+			"referenceId":   {Type: nonNull(graphql.String), Resolve: field(func(m product.StockMovement) any { return m.ReferenceID })},
+			"note":          {Type: nonNull(graphql.String)},
+			"occurredAt":    {Type: nonNull(graphql.DateTime)},
+		},
+	})
+
+	productType.AddFieldConfig("id", &graphql.Field{Type: nonNull(graphql.ID), Resolve: field(func(p *product.Product) any { return p.ID.String() })})
+	productType.AddFieldConfig("name", &graphql.Field{Type: nonNull(graphql.String)})
+	productType.AddFieldConfig("description", &graphql.Field{Type: nonNull(graphql.String)})
+	productType.AddFieldConfig("attributes", &graphql.Field{Type: jsonScalar, Description: "Structured details such as color or size"})
+	productType.AddFieldConfig("tags", &graphql.Field{Type: listOf(graphql.String), Resolve: field(func(p *product.Product) any { return nonNil(p.Tags) })})
+	productType.AddFieldConfig("stockQuantity", &graphql.Field{Type: nonNull(graphql.Int), Description: "Units available on hand"})
+	productType.AddFieldConfig("reservedQuantity", &graphql.Field{Type: nonNull(graphql.Int), Description: "Units allocated to open sales orders"})
+	productType.AddFieldConfig("lowStockThreshold", &graphql.Field{Type: nonNull(graphql.Int), Resolve: field(func(p *product.Product) any { return p.LowStockThresold })})
+	productType.AddFieldConfig("lowStock", &graphql.Field{
+		Type:        nonNull(graphql.Boolean),
+		Description: "Whether stock is at or below the low-stock threshold",
+		Resolve:     field(func(p *product.Product) any { return p.StockQuantity <= p.LowStockThresold }),
+	})
+	productType.AddFieldConfig("baseUnit", &graphql.Field{Type: nonNull(graphql.String)})
+	productType.AddFieldConfig("currency", &graphql.Field{Type: nonNull(graphql.String)})
+	productType.AddFieldConfig("unitCost", &graphql.Field{Type: nonNull(graphql.Int), Description: "Cost per unit in minor units"})
+	productType.AddFieldConfig("salePrice", &graphql.Field{Type: nonNull(graphql.Int), Description: "Sale price per unit in minor units"})
+	productType.AddFieldConfig("supplierId", &graphql.Field{Type: graphql.ID, Resolve: field(func(p *product.Product) any { return optionalID(p.SupplierID) })})
+	productType.AddFieldConfig("leadTimeDays", &graphql.Field{Type: nonNull(graphql.Int)})
+	productType.AddFieldConfig("lotTracked", &graphql.Field{Type: nonNull(graphql.Boolean)})
+	productType.AddFieldConfig("serialized", &graphql.Field{Type: nonNull(graphql.Boolean)})
+	productType.AddFieldConfig("isKit", &graphql.Field{Type: nonNull(graphql.Boolean), Resolve: field(func(p *product.Product) any { return p.IsKit() })})
+	productType.AddFieldConfig("createdAt", &graphql.Field{Type: graphql.DateTime, Resolve: field(func(p *product.Product) any { return p.CreatedAt })})
+	productType.AddFieldConfig("updatedAt", &graphql.Field{Type: graphql.DateTime, Resolve: field(func(p *product.Product) any { return p.UpdatedAt })})
+	productType.AddFieldConfig("categories", &graphql.Field{Type: listOf(categoryType), Resolve: field(func(p *product.Product) any { return nonNil(p.Categories) })})
+	productType.AddFieldConfig("options", &graphql.Field{Type: listOf(optionAxisType), Resolve: field(func(p *product.Product) any { return nonNil(p.Options) })})
+	productType.AddFieldConfig("variants", &graphql.Field{Type: listOf(variantType), Resolve: field(func(p *product.Product) any { return nonNil(p.Variants) })})
+	productType.AddFieldConfig("units", &graphql.Field{Type: listOf(unitType), Resolve: field(func(p *product.Product) any { return nonNil(p.Units) })})
+	productType.AddFieldConfig("components", &graphql.Field{
+		Type:        listOf(kitComponentType),
+		Description: "Products a kit is made of",
+		Resolve:     field(func(p *product.Product) any { return nonNil(p.Components) }),
+	})
+	productType.AddFieldConfig("movements", &graphql.Field{
+		Type:        listOf(movementType),
+		Description: "The latest stock movements, oldest first",
+		Args: graphql.FieldConfigArgument{
+			"since": {Type: graphql.DateTime, Description: "Leave out movements before this time"},
+			"until": {Type: graphql.DateTime, Description: "Leave out movements at or after this time"},
+			"last":  {Type: graphql.Int, DefaultValue: defaultMovements, Description: "Number of movements, 1 to 100"},
+		},
+		Resolve: r.movements,
+	})
+	productType.AddFieldConfig("expiringLots", &graphql.Field{
+		Type:        listOf(lotType),
+		Description: "Lots still holding stock that expire within the given number of days, including expired ones",
+		Args: graphql.FieldConfigArgument{
+			"days": {Type: graphql.Int, DefaultValue: defaultLotDays},
+		},
+		Resolve: r.expiringLots,
+	})
+
+	productPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductPage",
+		Fields: graphql.Fields{
+			"total":  {Type: nonNull(graphql.Int), Description: "Products matching the filter"},
+			"limit":  {Type: nonNull(graphql.Int)},
+			"offset": {Type: nonNull(graphql.Int)},
+			"items":  {Type: listOf(productType)},
+		},
+	})
+
+	searchResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.Fields{
+			"product":              {Type: nonNull(productType), Resolve: field(func(sr product.SearchResult) any { return &sr.Product })},
+			"rank":                 {Type: nonNull(graphql.Float), Description: "Relevance; higher is a better match"},
+			"nameHighlight":        {Type: nonNull(graphql.String), Resolve: field(func(sr product.SearchResult) any { return sr.Highlights.Name })},
+			"descriptionHighlight": {Type: nonNull(graphql.String), Resolve: field(func(sr product.SearchResult) any { return sr.Highlights.Description })},
+			"matchedSku":           {Type: graphql.String, Description: "The variant SKU that matched, if any", Resolve: field(func(sr product.SearchResult) any { return sr.MatchedSKU })},
+		},
+	})
+
+	searchPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchPage",
+		Fields: graphql.Fields{
+			"total":   {Type: nonNull(graphql.Int)},
+			"limit":   {Type: nonNull(graphql.Int)},
+			"offset":  {Type: nonNull(graphql.Int)},
+			"results": {Type: listOf(searchResultType), Resolve: field(func(sp *product.SearchPage) any { return nonNil(sp.Results) })},
+		},
+	})
+
+	attributeFilterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AttributeFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":  {Type: nonNull(graphql.String)},
+			"value": {Type: nonNull(graphql.String), Description: "Also matches a number or boolean attribute it parses as"},
+		},
+	})
+
+	productFilterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ProductFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"ids":                {Type: graphql.NewList(nonNull(graphql.ID))},
+			"categoryId":         {Type: graphql.ID},
+			"includeDescendants": {Type: graphql.Boolean, DefaultValue: true, Description: "Also match products in sub-categories of categoryId"},
+			"attributes":         {Type: graphql.NewList(nonNull(attributeFilterInput)), Description: "Products having every one of these attribute values"},
+			"tags":               {Type: graphql.NewList(nonNull(graphql.String)), Description: "Products carrying every one of these tags"},
+			"lowStock":           {Type: graphql.Boolean, Description: "Only products at or below their low-stock threshold"},
+			"asOf":               {Type: graphql.DateTime, Description: "Products as they were at this time, with stock replayed from the ledger"},
+		},
+	})
+
+	stockChangeInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "StockChangeInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"productId": {Type: nonNull(graphql.ID)},
+			"variantId": {Type: graphql.ID, Description: "Required for products with variants"},
+			"quantity":  {Type: nonNull(graphql.Int), Description: "Units to change, in unit"},
+			"unit":      {Type: graphql.String, Description: "Unit of quantity; defaults to the product's base unit"},
+			"serials":   {Type: graphql.NewList(nonNull(graphql.String)), Description: "Serial numbers of the units, one per unit, for serialized products"},
+		},
+	})
+
+	reservationInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ReservationInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"productId": {Type: nonNull(graphql.ID)},
+			"variantId": {Type: graphql.ID},
+			"quantity":  {Type: nonNull(graphql.Int)},
+		},
+	})
+
+	moveStockInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "MoveStockInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"productId": {Type: nonNull(graphql.ID)},
+			"variantId": {Type: graphql.ID},
+			"from":      {Type: nonNull(bucketEnum)},
+			"to":        {Type: nonNull(bucketEnum)},
+			"quantity":  {Type: nonNull(graphql.Int), Description: "Units to move, in unit"},
+			"unit":      {Type: graphql.String, Description: "Unit of quantity; defaults to the product's base unit"},
+			"lotNumber": {Type: graphql.String},
+			"serials":   {Type: graphql.NewList(nonNull(graphql.String))},
+			"note":      {Type: graphql.String},
+		},
+	})
+
+	reservationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Reservation",
+		Fields: graphql.Fields{
+			"product":  {Type: nonNull(productType)},
+			"reserved": {Type: nonNull(graphql.Int), Description: "Units reserved, which may be fewer than requested"},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"products": {
+				Type:        nonNull(productPageType),
+				Description: "A page of products matching the filter, oldest first",
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: productFilterInput},
+					"limit":  {Type: graphql.Int, DefaultValue: defaultPageSize, Description: "Page size, 1 to 100"},
+					"offset": {Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: r.products,
+			},
+			"product": {
+				Type:        productType,
+				Description: "A product by ID, or as it was at asOf",
+				Args: graphql.FieldConfigArgument{
+					"id":   {Type: nonNull(graphql.ID)},
+					"asOf": {Type: graphql.DateTime},
+				},
+				Resolve: r.product,
+			},
+			"searchProducts": {
+				Type:        nonNull(searchPageType),
+				Description: "Products matching free text by name, description or variant SKU, best first",
+				Args: graphql.FieldConfigArgument{
+					"query":  {Type: nonNull(graphql.String)},
+					"limit":  {Type: graphql.Int, DefaultValue: defaultPageSize, Description: "Page size, 1 to 100"},
+					"offset": {Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: r.searchProducts,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"incrementStock": {
+				Type:        nonNull(productType),
+				Description: "Add units to a product, one of its variants, or by serial number",
+				Args:        graphql.FieldConfigArgument{"input": {Type: nonNull(stockChangeInput)}},
+				Resolve:     r.incrementStock,
+			},
+			"decrementStock": {
+				Type:        nonNull(productType),
+				Description: "Remove units from a product, one of its variants, or by serial number",
+				Args:        graphql.FieldConfigArgument{"input": {Type: nonNull(stockChangeInput)}},
+				Resolve:     r.decrementStock,
+			},
+			"reserveStock": {
+				Type:        nonNull(reservationType),
+				Description: "Allocate up to quantity available units",
+				Args:        graphql.FieldConfigArgument{"input": {Type: nonNull(reservationInput)}},
+				Resolve:     r.reserveStock,
+			},
+			"releaseStock": {
+				Type:        nonNull(productType),
+				Description: "Return reserved units to available stock",
+				Args:        graphql.FieldConfigArgument{"input": {Type: nonNull(reservationInput)}},
+				Resolve:     r.releaseStock,
+			},
+			"moveStock": {
+				Type:        nonNull(productType),
+				Description: "Move units between stock buckets",
+				Args:        graphql.FieldConfigArgument{"input": {Type: nonNull(moveStockInput)}},
+				Resolve:     r.moveStock,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/attachment"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// AttachmentField is the multipart form field UploadAttachment reads the file from.
+const AttachmentField = "file"
+
+type AttachmentHandler struct {
+	service attachment.Service
+}
+
+func NewAttachmentHandler(s attachment.Service) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: s,
+	}
+}
+
+// PurgeAttachmentsResponse reports how many orphaned attachments were removed.
+type PurgeAttachmentsResponse struct {
+	Removed int `json:"removed"`
+}
+
+func (h *AttachmentHandler) UploadAttachment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Parse multipart form
+		fh, err := c.FormFile(AttachmentField)
+		if err != nil {
+			return errors.HandleError(c, errors.NewMissingRequiredDataError(AttachmentField))
+		}
+
+		f, err := fh.Open()
+		if err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid file upload: "+err.Error()))
+		}
+		defer f.Close()
+
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid file upload: "+err.Error()))
+		}
+
+		// Call service layer
+		a, err := h.service.UploadAttachment(c.UserContext(), c.Params("id"), attachment.Upload{
+			Filename:    fh.Filename,
+			ContentType: fh.Header.Get(fiber.HeaderContentType),
+			Data:        data,
+		})
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, a)
+	}
+}
+
+func (h *AttachmentHandler) GetAttachments() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		attachments, err := h.service.GetAttachments(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, attachments)
+	}
+}
+
+func (h *AttachmentHandler) GetAttachmentByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		a, err := h.service.GetAttachmentByID(c.UserContext(), c.Params("id"), c.Params("attachmentId"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, a)
+	}
+}
+
+// DownloadAttachment sends the attachment's file, or its thumbnail when thumbnail is set.
+func (h *AttachmentHandler) DownloadAttachment(thumbnail bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		a, r, err := h.service.OpenAttachment(c.UserContext(), c.Params("id"), c.Params("attachmentId"), thumbnail)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		if thumbnail {
+			c.Set(fiber.HeaderContentType, "image/png")
+		} else {
+			c.Set(fiber.HeaderContentType, a.ContentType)
+			c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": a.Filename}))
+		}
+		c.Set("X-Content-Type-Options", "nosniff")
+
+		// The stream is closed once it has been sent
+		return c.SendStream(r)
+	}
+}
+
+func (h *AttachmentHandler) DeleteAttachment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := h.service.DeleteAttachment(c.UserContext(), c.Params("id"), c.Params("attachmentId")); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
+
+func (h *AttachmentHandler) PurgeOrphanedAttachments() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		removed, err := h.service.PurgeOrphaned(c.UserContext())
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, PurgeAttachmentsResponse{Removed: removed})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type AuditHandler struct {
+	service audit.Service
+}
+
+func NewAuditHandler(s audit.Service) *AuditHandler {
+	return &AuditHandler{
+		service: s,
+	}
+}
+
+func (h *AuditHandler) GetEntries() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := audit.Filter{
+			EntityType: c.Query("entity"),
+			EntityID:   c.Query("id"),
+		}
+
+		if v := c.Query("limit"); v != "" {
+			var err error
+			if filter.Limit, err = strconv.Atoi(v); err != nil {
+				return errors.HandleError(c, errors.NewInvalidFormatError("limit"))
+			}
+		}
+
+		// Call service layer
+		entries, err := h.service.GetEntries(c.UserContext(), filter)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, entries)
+	}
+}
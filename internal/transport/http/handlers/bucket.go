@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func (h *ProductHandler) GetStockBuckets() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		levels, err := h.service.GetStockBuckets(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, levels)
+	}
+}
+
+func (h *ProductHandler) MoveStock() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var move product.BucketMove
+
+		// Parse request body
+		if err := c.BodyParser(&move); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.MoveStock(c.UserContext(), id, move); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		levels, err := h.service.GetStockBuckets(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, levels)
+	}
+}
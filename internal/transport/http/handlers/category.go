@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// AssignProductsRequest is the body accepted by AssignProducts.
+type AssignProductsRequest struct {
+	ProductIDs []uuid.UUID `json:"product_ids" validate:"required"`
+}
+
+type CategoryHandler struct {
+	service category.Service
+}
+
+func NewCategoryHandler(s category.Service) *CategoryHandler {
+	return &CategoryHandler{
+		service: s,
+	}
+}
+
+func (h *CategoryHandler) CreateCategory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var cat category.Category
+
+		// Parse request body
+		if err := c.BodyParser(&cat); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.CreateCategory(c.UserContext(), &cat); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, cat)
+	}
+}
+
+func (h *CategoryHandler) GetAllCategories() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var (
+			categories []category.Category
+			err        error
+		)
+
+		// Return the nested hierarchy when tree=true
+		if c.Query("tree") == "true" {
+			categories, err = h.service.GetCategoryTree(c.UserContext())
+		} else {
+			categories, err = h.service.GetAllCategories(c.UserContext())
+		}
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, categories)
+	}
+}
+
+func (h *CategoryHandler) GetCategoryByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		cat, err := h.service.GetCategoryByID(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, cat)
+	}
+}
+
+func (h *CategoryHandler) UpdateCategory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var cat category.Category
+
+		// Parse request body
+		if err := c.BodyParser(&cat); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.UpdateCategory(c.UserContext(), id, &cat); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Get updated category to return
+		updated, err := h.service.GetCategoryByID(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, updated)
+	}
+}
+
+func (h *CategoryHandler) DeleteCategory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := h.service.DeleteCategory(c.UserContext(), c.Params("id")); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
+
+func (h *CategoryHandler) AssignProducts() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req AssignProductsRequest
+
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		if err := h.service.AssignProducts(c.UserContext(), c.Params("id"), req.ProductIDs); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
+
+func (h *CategoryHandler) UnassignProduct() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := h.service.UnassignProduct(c.UserContext(), c.Params("id"), c.Params("productId")); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
+
+func (h *CategoryHandler) GetStockSummary() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		summary, err := h.service.GetStockSummary(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, summary)
+	}
+}
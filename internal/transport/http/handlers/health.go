@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// HealthStatus is returned by the liveness and readiness probes.
+type HealthStatus struct {
+	Status string `json:"status"`
+	Uptime string `json:"uptime,omitempty"`
+}
+
+type HealthHandler struct {
+	conn   *postgres.ConnectionManager
+	uptime time.Time
+}
+
+func NewHealthHandler(conn *postgres.ConnectionManager, uptime time.Time) *HealthHandler {
+	return &HealthHandler{
+		conn:   conn,
+		uptime: uptime,
+	}
+}
+
+// Liveness reports that the process is up, regardless of database state.
+func (h *HealthHandler) Liveness() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return errors.HandleSuccess(c, HealthStatus{
+			Status: "ok",
+			Uptime: time.Since(h.uptime).Round(time.Second).String(),
+		})
+	}
+}
+
+// Readiness reports whether the service can serve traffic, based on the last database health check.
+func (h *HealthHandler) Readiness() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.conn == nil || !h.conn.IsReady() {
+			return errors.HandleError(c, errors.NewConnectionError("database is not ready"))
+		}
+
+		return errors.HandleSuccess(c, HealthStatus{
+			Status: "ready",
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// SetKitComponentsRequest is the body accepted by SetKitComponents.
+type SetKitComponentsRequest struct {
+	Components []product.KitComponent `json:"components" validate:"required"`
+}
+
+func (h *ProductHandler) SetKitComponents() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var req SetKitComponentsRequest
+
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.SetKitComponents(c.UserContext(), id, req.Components); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithProduct(c, id)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func (h *ProductHandler) ReceiveLot() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var receipt product.LotReceipt
+
+		// Parse request body
+		if err := c.BodyParser(&receipt); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.ReceiveLot(c.UserContext(), id, receipt); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		lots, err := h.service.GetLots(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, lots)
+	}
+}
+
+func (h *ProductHandler) GetLots() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		lots, err := h.service.GetLots(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, lots)
+	}
+}
+
+func (h *ProductHandler) GetExpiringLots() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		days := 30
+		if v := c.Query("days"); v != "" {
+			var err error
+			if days, err = strconv.Atoi(v); err != nil {
+				return errors.HandleError(c, errors.NewInvalidFormatError("days"))
+			}
+		}
+
+		// Call service layer
+		lots, err := h.service.GetExpiringLots(c.UserContext(), days)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, lots)
+	}
+}
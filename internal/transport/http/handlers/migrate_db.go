@@ -2,11 +2,36 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/attachment"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
 	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/rma"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/stocktake"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
 	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
 	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
 )
 
+// openingMovements records stock that predates the ledger as opening movements, so that
+// valuation reports include it. Products and variants that already have movements are skipped.
+var openingMovements = []string{
+	`INSERT INTO stock_movements (id, tenant_id, product_id, quantity, reason, unit_cost, currency, occurred_at, created_at, updated_at)
+	SELECT gen_random_uuid(), p.tenant_id, p.id, p.stock_quantity, 'opening', p.unit_cost, p.currency, now(), now(), now()
+	FROM products p
+	WHERE p.deleted_at IS NULL AND p.stock_quantity > 0
+		AND NOT EXISTS (SELECT 1 FROM variants v WHERE v.product_id = p.id AND v.deleted_at IS NULL)
+		AND NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.product_id = p.id)`,
+	`INSERT INTO stock_movements (id, tenant_id, product_id, variant_id, quantity, reason, unit_cost, currency, occurred_at, created_at, updated_at)
+	SELECT gen_random_uuid(), p.tenant_id, p.id, v.id, v.stock_quantity, 'opening', p.unit_cost, p.currency, now(), now(), now()
+	FROM variants v JOIN products p ON p.id = v.product_id
+	WHERE v.deleted_at IS NULL AND p.deleted_at IS NULL AND v.stock_quantity > 0
+		AND NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.variant_id = v.id)`,
+}
+
 type MigrateDBHandler struct {
 	conn *postgres.ConnectionManager
 }
@@ -29,11 +54,73 @@ func (h *MigrateDBHandler) MigrateDB() fiber.Handler {
 			return errors.HandleError(c, errors.NewConnectionError("database connection is not established"))
 		}
 
+		// Migrations work across every tenant's rows
+		db := h.conn.DB.WithContext(tenant.WithAll(c.UserContext()))
+
 		// Perform migration
-		if err := h.conn.DB.AutoMigrate(product.Product{}); err != nil {
+		if err := db.AutoMigrate(
+			category.Category{},
+			product.Product{},
+			product.ProductVersion{},
+			product.OptionAxis{},
+			product.Variant{},
+			product.UnitOfMeasure{},
+			product.Lot{},
+			product.Serial{},
+			product.KitComponent{},
+			product.StockBucket{},
+			product.StockMovement{},
+			attachment.Attachment{},
+			audit.Entry{},
+			supplier.Supplier{},
+			purchaseorder.PurchaseOrder{},
+			purchaseorder.Line{},
+			stocktake.Session{},
+			stocktake.Line{},
+			salesorder.SalesOrder{},
+			salesorder.Line{},
+			rma.Return{},
+			rma.Line{},
+		); err != nil {
 			return errors.HandleError(c, errors.NewMigrationError("failed to migrate database: "+err.Error()))
 		}
 
+		for _, stmt := range postgres.SearchIndexes {
+			if err := db.Exec(stmt).Error; err != nil {
+				return errors.HandleError(c, errors.NewMigrationError("failed to create search indexes: "+err.Error()))
+			}
+		}
+
+		for _, stmt := range postgres.ProductVersioning {
+			if err := db.Exec(stmt).Error; err != nil {
+				return errors.HandleError(c, errors.NewMigrationError("failed to set up product versioning: "+err.Error()))
+			}
+		}
+
+		for _, stmt := range postgres.StockValuation {
+			if err := db.Exec(stmt).Error; err != nil {
+				return errors.HandleError(c, errors.NewMigrationError("failed to create stock valuation functions: "+err.Error()))
+			}
+		}
+
+		for _, stmt := range postgres.AuditTriggers {
+			if err := db.Exec(stmt).Error; err != nil {
+				return errors.HandleError(c, errors.NewMigrationError("failed to create audit triggers: "+err.Error()))
+			}
+		}
+
+		for _, stmt := range postgres.TenantIsolation {
+			if err := db.Exec(stmt).Error; err != nil {
+				return errors.HandleError(c, errors.NewMigrationError("failed to set up tenant isolation: "+err.Error()))
+			}
+		}
+
+		for _, stmt := range openingMovements {
+			if err := db.Exec(stmt).Error; err != nil {
+				return errors.HandleError(c, errors.NewMigrationError("failed to backfill the stock ledger: "+err.Error()))
+			}
+		}
+
 		return errors.HandleSuccess(c, "Database migrated successfully", fiber.StatusOK)
 	}
 }
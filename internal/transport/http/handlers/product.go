@@ -1,11 +1,45 @@
 package handlers
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
 	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
 )
 
+// StockIncrementRequest is the body accepted by IncrementStock.
+type StockIncrementRequest struct {
+	StockIncrement int              `json:"stock_increment" validate:"min=0" doc:"Units to add in the product's base unit; omit when giving quantity and unit"`
+	Quantity       quantity.Decimal `json:"quantity,omitempty" doc:"Exact decimal quantity in unit, converted to the base unit, e.g. 1.5 cases or 0.25 kg"`
+	Unit           string           `json:"unit,omitempty" doc:"Unit of quantity; defaults to the base unit"`
+	Serials        []string         `json:"serials,omitempty" doc:"Required for serialized products: one unique serial number per unit"`
+}
+
+// StockDecrementRequest is the body accepted by DecrementStock.
+type StockDecrementRequest struct {
+	StockDecrement int              `json:"stock_decrement" validate:"min=0" doc:"Units to remove in the product's base unit; omit when giving quantity and unit"`
+	Quantity       quantity.Decimal `json:"quantity,omitempty" doc:"Exact decimal quantity in unit, converted to the base unit, e.g. 1.5 cases or 0.25 kg"`
+	Unit           string           `json:"unit,omitempty" doc:"Unit of quantity; defaults to the base unit"`
+	Serials        []string         `json:"serials,omitempty" doc:"Required for serialized products: the serial numbers leaving stock"`
+}
+
+// StockIncrementResponse is returned by IncrementStock.
+type StockIncrementResponse struct {
+	Message         string           `json:"message"`
+	Product         *product.Product `json:"product"`
+	IncrementAmount int              `json:"increment_amount"`
+}
+
+// StockDecrementResponse is returned by DecrementStock.
+type StockDecrementResponse struct {
+	Message         string           `json:"message"`
+	Product         *product.Product `json:"product"`
+	DecrementAmount int              `json:"decrement_amount"`
+}
+
 type ProductHandler struct {
 	service product.Service
 }
@@ -26,7 +60,7 @@ func (h *ProductHandler) CreateProduct() fiber.Handler {
 		}
 
 		// Call service layer
-		if err := h.service.CreateProduct(&p); err != nil {
+		if err := h.service.CreateProduct(c.UserContext(), &p); err != nil {
 			return errors.HandleError(c, err)
 		}
 
@@ -43,8 +77,20 @@ func (h *ProductHandler) GetProductByID() fiber.Handler {
 			return errors.HandleError(c, errors.NewMissingRequiredDataError("id"))
 		}
 
-		// Call service layer
-		p, err := h.service.GetProductByID(id)
+		// Call service layer, for the product's state at as_of when it is given
+		var (
+			p   *product.Product
+			err error
+		)
+		if v := c.Query("as_of"); v != "" {
+			asOf, perr := parseAsOf(v)
+			if perr != nil {
+				return errors.HandleError(c, perr)
+			}
+			p, err = h.service.GetProductAsOf(c.UserContext(), id, asOf)
+		} else {
+			p, err = h.service.GetProductByID(c.UserContext(), id)
+		}
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
@@ -55,8 +101,37 @@ func (h *ProductHandler) GetProductByID() fiber.Handler {
 
 func (h *ProductHandler) GetAllProducts() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// Category filter includes sub-categories unless include-descendants=false
+		filter := product.Filter{
+			CategoryID:         c.Query("category"),
+			IncludeDescendants: c.Query("include-descendants") != "false",
+		}
+
+		// Attribute filters are given as attr.<name>=<value>; tags as a comma-separated list
+		for key, value := range c.Queries() {
+			if name, ok := strings.CutPrefix(key, "attr."); ok && name != "" {
+				if filter.Attributes == nil {
+					filter.Attributes = map[string]string{}
+				}
+				filter.Attributes[name] = value
+			}
+		}
+		for _, tag := range strings.Split(c.Query("tag"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+
+		if v := c.Query("as_of"); v != "" {
+			asOf, err := parseAsOf(v)
+			if err != nil {
+				return errors.HandleError(c, err)
+			}
+			filter.AsOf = asOf
+		}
+
 		// Call service layer
-		products, err := h.service.GetAllProducts()
+		products, err := h.service.GetAllProducts(c.UserContext(), filter)
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
@@ -65,7 +140,7 @@ func (h *ProductHandler) GetAllProducts() fiber.Handler {
 		lowStock := c.Query("low-stock")
 		if lowStock == "true" {
 			// Filter products with stock below their individual threshold
-			var lowStockProducts []product.Product
+			lowStockProducts := []product.Product{}
 			for _, p := range products {
 				if p.StockQuantity <= p.LowStockThresold {
 					lowStockProducts = append(lowStockProducts, p)
@@ -87,20 +162,33 @@ func (h *ProductHandler) UpdateProduct() fiber.Handler {
 			return errors.HandleError(c, errors.NewMissingRequiredDataError("id"))
 		}
 
-		var p product.Product
+		current, err := h.service.GetProductByID(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Parse the request body over the current values, so that fields it omits keep them
+		p := updatableFields(current)
+
+		// Attributes in the body replace the current ones rather than merging into them
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(c.Body(), &fields) == nil {
+			if _, ok := fields["attributes"]; ok {
+				p.Attributes = nil
+			}
+		}
 
-		// Parse request body
 		if err := c.BodyParser(&p); err != nil {
 			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
 		}
 
 		// Call service layer
-		if err := h.service.UpdateProduct(id, &p); err != nil {
+		if err := h.service.UpdateProduct(c.UserContext(), id, &p); err != nil {
 			return errors.HandleError(c, err)
 		}
 
 		// Get updated product to return
-		updatedProduct, err := h.service.GetProductByID(id)
+		updatedProduct, err := h.service.GetProductByID(c.UserContext(), id)
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
@@ -109,6 +197,25 @@ func (h *ProductHandler) UpdateProduct() fiber.Handler {
 	}
 }
 
+// updatableFields returns the fields of p that UpdateProduct sets.
+func updatableFields(p *product.Product) product.Product {
+	return product.Product{
+		Name:             p.Name,
+		Description:      p.Description,
+		Attributes:       p.Attributes,
+		Tags:             p.Tags,
+		LowStockThresold: p.LowStockThresold,
+		BaseUnit:         p.BaseUnit,
+		Currency:         p.Currency,
+		UnitCost:         p.UnitCost,
+		SalePrice:        p.SalePrice,
+		SupplierID:       p.SupplierID,
+		LeadTimeDays:     p.LeadTimeDays,
+		LotTracked:       p.LotTracked,
+		Serialized:       p.Serialized,
+	}
+}
+
 func (h *ProductHandler) DeleteProduct() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("id")
@@ -119,7 +226,7 @@ func (h *ProductHandler) DeleteProduct() fiber.Handler {
 		}
 
 		// Call service layer
-		if err := h.service.DeleteProduct(id); err != nil {
+		if err := h.service.DeleteProduct(c.UserContext(), id); err != nil {
 			return errors.HandleError(c, err)
 		}
 
@@ -127,6 +234,19 @@ func (h *ProductHandler) DeleteProduct() fiber.Handler {
 	}
 }
 
+func (h *ProductHandler) RestoreProduct() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.RestoreProduct(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithProduct(c, id)
+	}
+}
+
 func (h *ProductHandler) IncrementStock() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("id")
@@ -136,36 +256,44 @@ func (h *ProductHandler) IncrementStock() fiber.Handler {
 			return errors.HandleError(c, errors.NewMissingRequiredDataError("id"))
 		}
 
-		var req struct {
-			StockIncrement int `json:"stock_increment" validate:"required,min=1"`
-		}
+		var req StockIncrementRequest
 
 		// Parse request body
 		if err := c.BodyParser(&req); err != nil {
 			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
 		}
 
+		// Convert a quantity given in a unit of measure to base units
+		var err error
+		if req.StockIncrement, err = h.baseQuantity(c, id, req.StockIncrement, "stock_increment", req.Quantity, req.Unit); err != nil {
+			return errors.HandleError(c, err)
+		}
+
 		// Validate increment value
 		if req.StockIncrement <= 0 {
 			return errors.HandleError(c, errors.NewInvalidInputError("stock_increment must be greater than 0"))
 		}
 
 		// Call service layer
-		err := h.service.IncermentStock(id, req.StockIncrement)
+		if len(req.Serials) > 0 {
+			err = h.incrementSerials(c, id, req.StockIncrement, req.Serials)
+		} else {
+			err = h.service.IncermentStock(c.UserContext(), id, req.StockIncrement)
+		}
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
 
 		// Get updated product to return current stock
-		updatedProduct, err := h.service.GetProductByID(id)
+		updatedProduct, err := h.service.GetProductByID(c.UserContext(), id)
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
 
-		return errors.HandleSuccess(c, map[string]any{
-			"message":          "Stock incremented successfully",
-			"product":          updatedProduct,
-			"increment_amount": req.StockIncrement,
+		return errors.HandleSuccess(c, StockIncrementResponse{
+			Message:         "Stock incremented successfully",
+			Product:         updatedProduct,
+			IncrementAmount: req.StockIncrement,
 		})
 	}
 }
@@ -179,36 +307,63 @@ func (h *ProductHandler) DecrementStock() fiber.Handler {
 			return errors.HandleError(c, errors.NewMissingRequiredDataError("id"))
 		}
 
-		var req struct {
-			StockDecrement int `json:"stock_decrement" validate:"required,min=1"`
-		}
+		var req StockDecrementRequest
 
 		// Parse request body
 		if err := c.BodyParser(&req); err != nil {
 			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
 		}
 
+		// Convert a quantity given in a unit of measure to base units
+		var err error
+		if req.StockDecrement, err = h.baseQuantity(c, id, req.StockDecrement, "stock_decrement", req.Quantity, req.Unit); err != nil {
+			return errors.HandleError(c, err)
+		}
+
 		// Validate decrement value
 		if req.StockDecrement <= 0 {
 			return errors.HandleError(c, errors.NewInvalidInputError("stock_decrement must be greater than 0"))
 		}
 
 		// Call service layer
-		err := h.service.DecrementStock(id, req.StockDecrement)
+		if len(req.Serials) > 0 {
+			err = h.decrementSerials(c, id, req.StockDecrement, req.Serials)
+		} else {
+			err = h.service.DecrementStock(c.UserContext(), id, req.StockDecrement)
+		}
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
 
 		// Get updated product to return current stock
-		updatedProduct, err := h.service.GetProductByID(id)
+		updatedProduct, err := h.service.GetProductByID(c.UserContext(), id)
 		if err != nil {
 			return errors.HandleError(c, err)
 		}
 
-		return errors.HandleSuccess(c, map[string]any{
-			"message":          "Stock decremented successfully",
-			"product":          updatedProduct,
-			"decrement_amount": req.StockDecrement,
+		return errors.HandleSuccess(c, StockDecrementResponse{
+			Message:         "Stock decremented successfully",
+			Product:         updatedProduct,
+			DecrementAmount: req.StockDecrement,
 		})
 	}
 }
+
+func (h *ProductHandler) ListMovements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Check the product exists so an unknown ID is a 404 rather than an empty ledger
+		if _, err := h.service.GetProductByID(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Call service layer
+		movements, err := h.service.ListMovements(c.UserContext(), product.MovementFilter{ProductID: id})
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, movements)
+	}
+}
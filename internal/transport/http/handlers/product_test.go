@@ -1,49 +1,162 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
 	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
 )
 
 // mockProductService implements the product.Service interface for testing
 type mockProductService struct {
 	products []product.Product
 	getError error
+	// product is returned by GetProductByID; updated records what UpdateProduct was given
+	product *product.Product
+	updated *product.Product
 }
 
-func (m *mockProductService) CreateProduct(*product.Product) error {
+func (m *mockProductService) CreateProduct(context.Context, *product.Product) error {
 	return nil
 }
 
-func (m *mockProductService) GetAllProducts() ([]product.Product, error) {
+func (m *mockProductService) GetAllProducts(context.Context, product.Filter) ([]product.Product, error) {
 	if m.getError != nil {
 		return nil, m.getError
 	}
 	return m.products, nil
 }
 
-func (m *mockProductService) GetProductByID(string) (*product.Product, error) {
+func (m *mockProductService) CountProducts(context.Context, product.Filter) (int, error) {
+	return len(m.products), m.getError
+}
+
+func (m *mockProductService) GetProductByID(context.Context, string) (*product.Product, error) {
+	return m.product, nil
+}
+
+func (m *mockProductService) UpdateProduct(_ context.Context, _ string, p *product.Product) error {
+	m.updated = p
+	return nil
+}
+
+func (m *mockProductService) DeleteProduct(context.Context, string) error {
+	return nil
+}
+
+func (m *mockProductService) GetProductAsOf(context.Context, string, time.Time) (*product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) RestoreProduct(context.Context, string) error {
+	return nil
+}
+
+func (m *mockProductService) IncermentStock(context.Context, string, int) error {
+	return nil
+}
+
+func (m *mockProductService) DecrementStock(context.Context, string, int) error {
+	return nil
+}
+
+func (m *mockProductService) ApplyStockChanges(context.Context, ...product.StockChange) ([]product.StockMovement, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) ListMovements(context.Context, product.MovementFilter) ([]product.StockMovement, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) ReceiveLot(context.Context, string, product.LotReceipt) error {
+	return nil
+}
+
+func (m *mockProductService) GetLots(context.Context, string) ([]product.Lot, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) GetExpiringLots(context.Context, int) ([]product.Lot, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) IncrementSerials(context.Context, string, []string) error {
+	return nil
+}
+
+func (m *mockProductService) DecrementSerials(context.Context, string, []string) error {
+	return nil
+}
+
+func (m *mockProductService) GetSerials(context.Context, string, bool) ([]product.Serial, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) GetSerialHistory(context.Context, string, string) (*product.SerialHistory, error) {
+	return nil, nil
+}
+
+func (m *mockProductService) ReserveStock(context.Context, string, string, int) (int, error) {
+	return 0, nil
+}
+
+func (m *mockProductService) ReleaseStock(context.Context, string, string, int) error {
+	return nil
+}
+
+func (m *mockProductService) MoveStock(context.Context, string, product.BucketMove) error {
+	return nil
+}
+
+func (m *mockProductService) GetStockBuckets(context.Context, string) ([]product.BucketLevels, error) {
 	return nil, nil
 }
 
-func (m *mockProductService) UpdateProduct(string, *product.Product) error {
+func (m *mockProductService) SetOptions(context.Context, string, []product.OptionAxis) error {
+	return nil
+}
+
+func (m *mockProductService) SearchProducts(context.Context, product.SearchQuery) (*product.SearchPage, error) {
+	return &product.SearchPage{}, nil
+}
+
+func (m *mockProductService) SetUnits(context.Context, string, []product.UnitOfMeasure) error {
+	return nil
+}
+
+func (m *mockProductService) ToBaseUnits(_ context.Context, _ string, q quantity.Decimal, _ string) (int, error) {
+	return quantity.ToBase(q, quantity.BaseFactor)
+}
+
+func (m *mockProductService) SetKitComponents(context.Context, string, []product.KitComponent) error {
+	return nil
+}
+
+func (m *mockProductService) CreateVariant(context.Context, string, *product.Variant) error {
+	return nil
+}
+
+func (m *mockProductService) UpdateVariant(context.Context, string, string, *product.Variant) error {
 	return nil
 }
 
-func (m *mockProductService) DeleteProduct(string) error {
+func (m *mockProductService) DeleteVariant(context.Context, string, string) error {
 	return nil
 }
 
-func (m *mockProductService) IncermentStock(string, int) error {
+func (m *mockProductService) IncrementVariantStock(context.Context, string, string, int) error {
 	return nil
 }
 
-func (m *mockProductService) DecrementStock(string, int) error {
+func (m *mockProductService) DecrementVariantStock(context.Context, string, string, int) error {
 	return nil
 }
 
@@ -271,3 +384,57 @@ func TestProductHandler_GetAllProducts(t *testing.T) {
 		}
 	})
 }
+
+func TestProductHandler_UpdateProduct(t *testing.T) {
+	supplierID := uuid.New()
+	newService := func() *mockProductService {
+		return &mockProductService{product: &product.Product{
+			Name:          "Widget",
+			Attributes:    map[string]any{"color": "red", "size": "M"},
+			Tags:          []string{"sale"},
+			StockQuantity: 12,
+			Currency:      "EUR",
+			BaseUnit:      "kg",
+			SupplierID:    &supplierID,
+		}}
+	}
+
+	update := func(t *testing.T, svc *mockProductService, body string) {
+		t.Helper()
+
+		app := fiber.New()
+		app.Put("/products/:id", NewProductHandler(svc).UpdateProduct())
+
+		req := httptest.NewRequest("PUT", "/products/p1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected status 200, got %d", resp.StatusCode)
+		}
+	}
+
+	t.Run("keeps the fields the body omits", func(t *testing.T) {
+		svc := newService()
+		update(t, svc, `{"name":"Widget Pro"}`)
+
+		p := svc.updated
+		if p.Name != "Widget Pro" || p.Currency != "EUR" || p.BaseUnit != "kg" || len(p.Tags) != 1 ||
+			p.Attributes["color"] != "red" || p.SupplierID == nil || *p.SupplierID != supplierID {
+			t.Fatalf("expected the omitted fields to be kept, got %+v", p)
+		}
+	})
+
+	t.Run("replaces and clears the fields the body gives", func(t *testing.T) {
+		svc := newService()
+		update(t, svc, `{"name":"Widget","attributes":{"color":"blue"},"tags":[],"supplier_id":null}`)
+
+		p := svc.updated
+		if len(p.Attributes) != 1 || p.Attributes["color"] != "blue" || len(p.Tags) != 0 || p.SupplierID != nil {
+			t.Fatalf("expected the given fields to replace the current ones, got %+v", p)
+		}
+	})
+}
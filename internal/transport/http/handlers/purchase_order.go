@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type PurchaseOrderHandler struct {
+	service purchaseorder.Service
+}
+
+func NewPurchaseOrderHandler(s purchaseorder.Service) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		service: s,
+	}
+}
+
+func (h *PurchaseOrderHandler) CreatePurchaseOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var po purchaseorder.PurchaseOrder
+
+		// Parse request body
+		if err := c.BodyParser(&po); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.CreatePurchaseOrder(c.UserContext(), &po); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, po)
+	}
+}
+
+func (h *PurchaseOrderHandler) GetAllPurchaseOrders() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := purchaseorder.Filter{
+			SupplierID: c.Query("supplier"),
+			Status:     purchaseorder.Status(c.Query("status")),
+		}
+
+		// Call service layer
+		orders, err := h.service.GetAllPurchaseOrders(c.UserContext(), filter)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, orders)
+	}
+}
+
+func (h *PurchaseOrderHandler) GetPurchaseOrderByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.respondWithOrder(c, c.Params("id"))
+	}
+}
+
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var po purchaseorder.PurchaseOrder
+
+		// Parse request body
+		if err := c.BodyParser(&po); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.UpdatePurchaseOrder(c.UserContext(), id, &po); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithOrder(c, id)
+	}
+}
+
+func (h *PurchaseOrderHandler) DeletePurchaseOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		if err := h.service.DeletePurchaseOrder(c.UserContext(), c.Params("id")); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
+
+func (h *PurchaseOrderHandler) SendPurchaseOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.SendPurchaseOrder(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithOrder(c, id)
+	}
+}
+
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var receipt purchaseorder.Receipt
+
+		// Parse request body
+		if err := c.BodyParser(&receipt); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.ReceivePurchaseOrder(c.UserContext(), id, receipt); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithOrder(c, id)
+	}
+}
+
+func (h *PurchaseOrderHandler) GetReceiptMovements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		movements, err := h.service.GetReceiptMovements(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, movements)
+	}
+}
+
+// respondWithOrder writes the current state of the order, e.g. after a status change.
+func (h *PurchaseOrderHandler) respondWithOrder(c *fiber.Ctx, id string) error {
+	po, err := h.service.GetPurchaseOrderByID(c.UserContext(), id)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	return errors.HandleSuccess(c, po)
+}
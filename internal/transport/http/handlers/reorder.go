@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/reorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type ReorderHandler struct {
+	service reorder.Service
+}
+
+func NewReorderHandler(s reorder.Service) *ReorderHandler {
+	return &ReorderHandler{
+		service: s,
+	}
+}
+
+func (h *ReorderHandler) GetSuggestions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params, err := parseReorderParams(c)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Call service layer
+		suggestions, err := h.service.GetSuggestions(c.UserContext(), params)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, suggestions)
+	}
+}
+
+func (h *ReorderHandler) CreateDraftOrders() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params, err := parseReorderParams(c)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Call service layer
+		result, err := h.service.CreateDraftOrders(c.UserContext(), params)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, result)
+	}
+}
+
+// parseReorderParams reads the tuning parameters from the query string, falling back to
+// the defaults for any that are absent.
+func parseReorderParams(c *fiber.Ctx) (reorder.Params, error) {
+	params := reorder.DefaultParams()
+	var err error
+
+	if v := c.Query("days"); v != "" {
+		if params.LookbackDays, err = strconv.Atoi(v); err != nil {
+			return params, errors.NewInvalidFormatError("days")
+		}
+	}
+
+	if v := c.Query("service_level"); v != "" {
+		if params.ServiceLevel, err = strconv.ParseFloat(v, 64); err != nil {
+			return params, errors.NewInvalidFormatError("service_level")
+		}
+	}
+
+	if v := c.Query("cover_days"); v != "" {
+		if params.CoverDays, err = strconv.Atoi(v); err != nil {
+			return params, errors.NewInvalidFormatError("cover_days")
+		}
+	}
+
+	params.All = c.Query("all") == "true"
+
+	return params, nil
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/valuation"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type ReportHandler struct {
+	valuation valuation.Service
+}
+
+func NewReportHandler(v valuation.Service) *ReportHandler {
+	return &ReportHandler{
+		valuation: v,
+	}
+}
+
+func (h *ReportHandler) GetValuation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		asOf, err := parseAsOf(c.Query("as_of"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		method := valuation.Method(c.Query("method", string(valuation.FIFO)))
+
+		// Call service layer
+		report, err := h.valuation.GetValuation(c.UserContext(), asOf, method)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, report)
+	}
+}
+
+// parseAsOf parses an RFC 3339 timestamp, or a date that covers the whole of that day
+// (UTC) and so means its last microsecond, the finest time the database keeps. An empty
+// value means now.
+func parseAsOf(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().UTC(), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.Parse(time.DateOnly, value); err == nil {
+		return d.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+	}
+
+	return time.Time{}, errors.NewInvalidFormatError("as_of")
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	cases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2025-01-31T12:00:00Z", time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{"2025-01-31", time.Date(2025, 1, 31, 23, 59, 59, 999999000, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		got, err := parseAsOf(tc.value)
+		if err != nil || !got.Equal(tc.want) {
+			t.Errorf("parseAsOf(%q) = %v, %v, want %v", tc.value, got, err, tc.want)
+		}
+	}
+
+	if _, err := parseAsOf("yesterday"); err == nil {
+		t.Error("expected an error for a value that is not a time or date")
+	}
+}
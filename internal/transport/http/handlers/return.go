@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/rma"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type ReturnHandler struct {
+	service rma.Service
+}
+
+func NewReturnHandler(s rma.Service) *ReturnHandler {
+	return &ReturnHandler{
+		service: s,
+	}
+}
+
+func (h *ReturnHandler) CreateReturn() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var r rma.Return
+
+		// Parse request body
+		if err := c.BodyParser(&r); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.CreateReturn(c.UserContext(), &r); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, r)
+	}
+}
+
+func (h *ReturnHandler) GetAllReturns() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := rma.Filter{
+			SalesOrderID: c.Query("sales_order_id"),
+			Status:       rma.Status(c.Query("status")),
+		}
+
+		// Call service layer
+		returns, err := h.service.GetAllReturns(c.UserContext(), filter)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, returns)
+	}
+}
+
+func (h *ReturnHandler) GetReturnByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.respondWithReturn(c, c.Params("id"))
+	}
+}
+
+func (h *ReturnHandler) InspectReturn() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var inspection rma.Inspection
+
+		// Parse request body
+		if err := c.BodyParser(&inspection); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.InspectReturn(c.UserContext(), id, inspection); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithReturn(c, id)
+	}
+}
+
+func (h *ReturnHandler) CancelReturn() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.CancelReturn(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithReturn(c, id)
+	}
+}
+
+func (h *ReturnHandler) GetDispositionMovements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		movements, err := h.service.GetDispositionMovements(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, movements)
+	}
+}
+
+// respondWithReturn writes the current state of the return, e.g. after an inspection.
+func (h *ReturnHandler) respondWithReturn(c *fiber.Ctx, id string) error {
+	r, err := h.service.GetReturnByID(c.UserContext(), id)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	return errors.HandleSuccess(c, r)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type SalesOrderHandler struct {
+	service salesorder.Service
+}
+
+func NewSalesOrderHandler(s salesorder.Service) *SalesOrderHandler {
+	return &SalesOrderHandler{
+		service: s,
+	}
+}
+
+func (h *SalesOrderHandler) CreateSalesOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var so salesorder.SalesOrder
+
+		// Parse request body
+		if err := c.BodyParser(&so); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.CreateSalesOrder(c.UserContext(), &so); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, so)
+	}
+}
+
+func (h *SalesOrderHandler) GetAllSalesOrders() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := salesorder.Filter{
+			Customer: c.Query("customer"),
+			Status:   salesorder.Status(c.Query("status")),
+		}
+
+		// Call service layer
+		orders, err := h.service.GetAllSalesOrders(c.UserContext(), filter)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, orders)
+	}
+}
+
+func (h *SalesOrderHandler) GetSalesOrderByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.respondWithSalesOrder(c, c.Params("id"))
+	}
+}
+
+func (h *SalesOrderHandler) AllocateSalesOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.AllocateSalesOrder(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSalesOrder(c, id)
+	}
+}
+
+func (h *SalesOrderHandler) FulfilSalesOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var fulfilment salesorder.Fulfilment
+
+		// Parse request body
+		if err := c.BodyParser(&fulfilment); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.FulfilSalesOrder(c.UserContext(), id, fulfilment); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSalesOrder(c, id)
+	}
+}
+
+func (h *SalesOrderHandler) CancelSalesOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.CancelSalesOrder(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSalesOrder(c, id)
+	}
+}
+
+func (h *SalesOrderHandler) GetFulfilmentMovements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		movements, err := h.service.GetFulfilmentMovements(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, movements)
+	}
+}
+
+// respondWithSalesOrder writes the current state of the order, e.g. after a status change.
+func (h *SalesOrderHandler) respondWithSalesOrder(c *fiber.Ctx, id string) error {
+	so, err := h.service.GetSalesOrderByID(c.UserContext(), id)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	return errors.HandleSuccess(c, so)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func (h *ProductHandler) SearchProducts() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query := product.SearchQuery{Text: c.Query("q")}
+
+		if v := c.Query("limit"); v != "" {
+			var err error
+			if query.Limit, err = strconv.Atoi(v); err != nil {
+				return errors.HandleError(c, errors.NewInvalidFormatError("limit"))
+			}
+		}
+
+		if v := c.Query("offset"); v != "" {
+			var err error
+			if query.Offset, err = strconv.Atoi(v); err != nil {
+				return errors.HandleError(c, errors.NewInvalidFormatError("offset"))
+			}
+		}
+
+		// Call service layer
+		page, err := h.service.SearchProducts(c.UserContext(), query)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, page)
+	}
+}
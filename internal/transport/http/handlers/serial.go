@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+func (h *ProductHandler) GetSerials() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		serials, err := h.service.GetSerials(c.UserContext(), c.Params("id"), c.Query("in-stock") == "true")
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, serials)
+	}
+}
+
+func (h *ProductHandler) GetSerialHistory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		history, err := h.service.GetSerialHistory(c.UserContext(), c.Params("id"), c.Params("serial"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, history)
+	}
+}
+
+// incrementSerials increments a serialized product by the given serials, which must
+// number as many as the requested increment.
+func (h *ProductHandler) incrementSerials(c *fiber.Ctx, id string, quantity int, serials []string) error {
+	if quantity != len(serials) {
+		return errors.NewInvalidInputError("stock_increment must equal the number of serials")
+	}
+
+	return h.service.IncrementSerials(c.UserContext(), id, serials)
+}
+
+// decrementSerials decrements a serialized product by the given serials, which must
+// number as many as the requested decrement.
+func (h *ProductHandler) decrementSerials(c *fiber.Ctx, id string, quantity int, serials []string) error {
+	if quantity != len(serials) {
+		return errors.NewInvalidInputError("stock_decrement must equal the number of serials")
+	}
+
+	return h.service.DecrementSerials(c.UserContext(), id, serials)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/stocktake"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type StockTakeHandler struct {
+	service stocktake.Service
+}
+
+func NewStockTakeHandler(s stocktake.Service) *StockTakeHandler {
+	return &StockTakeHandler{
+		service: s,
+	}
+}
+
+func (h *StockTakeHandler) CreateStockTake() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var scope stocktake.Scope
+
+		// Parse request body
+		if err := c.BodyParser(&scope); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		session, err := h.service.CreateStockTake(c.UserContext(), scope)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, session)
+	}
+}
+
+func (h *StockTakeHandler) GetAllStockTakes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := stocktake.Filter{
+			Status: stocktake.Status(c.Query("status")),
+		}
+
+		// Call service layer
+		sessions, err := h.service.GetAllStockTakes(c.UserContext(), filter)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, sessions)
+	}
+}
+
+func (h *StockTakeHandler) GetStockTakeByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.respondWithSession(c, c.Params("id"))
+	}
+}
+
+func (h *StockTakeHandler) RecordCounts() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var counts stocktake.Counts
+
+		// Parse request body
+		if err := c.BodyParser(&counts); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.RecordCounts(c.UserContext(), id, counts); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSession(c, id)
+	}
+}
+
+func (h *StockTakeHandler) SubmitStockTake() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.SubmitStockTake(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSession(c, id)
+	}
+}
+
+func (h *StockTakeHandler) ApproveStockTake() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var approval stocktake.Approval
+
+		// Parse request body
+		if err := c.BodyParser(&approval); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.ApproveStockTake(c.UserContext(), id, approval); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSession(c, id)
+	}
+}
+
+func (h *StockTakeHandler) CancelStockTake() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Call service layer
+		if err := h.service.CancelStockTake(c.UserContext(), id); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithSession(c, id)
+	}
+}
+
+func (h *StockTakeHandler) GetAdjustmentMovements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		movements, err := h.service.GetAdjustmentMovements(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, movements)
+	}
+}
+
+// respondWithSession writes the current state of the session, e.g. after a status change.
+func (h *StockTakeHandler) respondWithSession(c *fiber.Ctx, id string) error {
+	session, err := h.service.GetStockTakeByID(c.UserContext(), id)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	return errors.HandleSuccess(c, session)
+}
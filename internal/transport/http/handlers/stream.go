@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/eventbus"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+)
+
+// Stream message types besides the event types.
+const (
+	// StreamReset tells a resuming client that events were missed, so it must reload
+	// what it shows before relying on the events that follow
+	StreamReset = "reset"
+	// StreamHeartbeat is sent on idle WebSocket streams
+	StreamHeartbeat = "heartbeat"
+	// StreamSubscribed acknowledges a WebSocket subscription change
+	StreamSubscribed = "subscribed"
+	// StreamError reports a WebSocket message that could not be applied
+	StreamError = "error"
+)
+
+// defaultHeartbeat is the heartbeat interval of handlers given none.
+const defaultHeartbeat = 15 * time.Second
+
+// streamLocal is the key the WebSocket handler receives the stream request under.
+const streamLocal = "stream"
+
+// StreamMessage is a message sent to WebSocket stream clients.
+type StreamMessage struct {
+	// ID numbers event messages; clients resume after it with last-event-id
+	ID    uint64         `json:"id,omitempty"`
+	Type  string         `json:"type"`
+	Event *product.Event `json:"event,omitempty"`
+	Error string         `json:"error,omitempty"`
+}
+
+// StreamSubscription is a message WebSocket clients send to change the events they
+// receive. It replaces the filter given when connecting.
+type StreamSubscription struct {
+	// Products limits events to these product IDs when set
+	Products []string `json:"products"`
+	// LowStock limits events to stock.low events
+	LowStock bool `json:"low_stock"`
+}
+
+// streamRequest is what a stream client asked for when connecting.
+type streamRequest struct {
+	filter eventbus.Filter
+	lastID uint64
+}
+
+type StreamHandler struct {
+	bus       *eventbus.Bus
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(bus *eventbus.Bus, heartbeat time.Duration) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+
+	return &StreamHandler{
+		bus:       bus,
+		heartbeat: heartbeat,
+	}
+}
+
+// Events streams stock events of the request's tenant as Server-Sent Events. Each event
+// carries its ID, so a reconnecting EventSource resumes after the last one it received
+// through the Last-Event-ID header. Idle streams get a comment line every heartbeat.
+func (h *StreamHandler) Events() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req, err := parseStreamRequest(c)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		sub, replay, complete := h.bus.Subscribe(req.filter, req.lastID)
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer sub.Close()
+
+			ticker := time.NewTicker(h.heartbeat)
+			defer ticker.Stop()
+
+			if !complete {
+				fmt.Fprintf(w, "event: %s\ndata: {}\n\n", StreamReset)
+			}
+			for _, m := range replay {
+				writeServerSentEvent(w, m)
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			for {
+				select {
+				case m, ok := <-sub.Messages():
+					if !ok {
+						return
+					}
+					writeServerSentEvent(w, m)
+				case <-ticker.C:
+					fmt.Fprint(w, ": heartbeat\n\n")
+				}
+
+				// A failed flush means the client has gone
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+
+		return nil
+	}
+}
+
+func writeServerSentEvent(w *bufio.Writer, m eventbus.Message) {
+	data, _ := json.Marshal(m.Event)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", m.ID, m.Event.Type, data)
+}
+
+// WebSocket streams stock events of the request's tenant over a WebSocket as
+// StreamMessages, sending a heartbeat message when idle. Clients resume with the
+// last-event-id query parameter, since browsers cannot set headers on WebSockets, and may
+// send a StreamSubscription at any time to change the events they receive.
+func (h *StreamHandler) WebSocket() fiber.Handler {
+	upgrade := websocket.New(h.serveWebSocket)
+
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return errors.HandleError(c, errors.NewAppError(errors.InvalidInput, "this endpoint requires a WebSocket upgrade", fiber.StatusUpgradeRequired))
+		}
+
+		req, err := parseStreamRequest(c)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Subscribe before the handshake completes, so that no event published once the
+		// client is connected is missed
+		s := &socketStream{bus: h.bus, filter: req.filter, lastID: req.lastID}
+		s.sub, s.replay, s.complete = h.bus.Subscribe(req.filter, req.lastID)
+
+		c.Locals(streamLocal, s)
+		if err := upgrade(c); err != nil {
+			s.sub.Close()
+			return err
+		}
+
+		return nil
+	}
+}
+
+// clientMessage is a message read from a WebSocket client, or why it could not be decoded.
+type clientMessage struct {
+	subscription StreamSubscription
+	err          error
+}
+
+// socketStream is the state of one WebSocket stream.
+type socketStream struct {
+	bus    *eventbus.Bus
+	conn   *websocket.Conn
+	sub    *eventbus.Subscription
+	filter eventbus.Filter
+	// lastID is the ID of the last event sent
+	lastID uint64
+
+	// replay and complete are what the bus returned when subscribing on connection
+	replay   []eventbus.Message
+	complete bool
+}
+
+func (h *StreamHandler) serveWebSocket(conn *websocket.Conn) {
+	s, _ := conn.Locals(streamLocal).(*socketStream)
+	s.conn = conn
+	defer func() { s.sub.Close() }()
+
+	// Read client messages on their own goroutine; only this one writes
+	messages := make(chan clientMessage)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		defer close(messages)
+		for {
+			var m clientMessage
+			if err := conn.ReadJSON(&m.subscription); err != nil {
+				if !isJSONError(err) {
+					return
+				}
+				m.err = errors.NewInvalidFormatError("subscription")
+			}
+
+			select {
+			case messages <- m:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	if !s.complete && !s.send(StreamMessage{Type: StreamReset}) {
+		return
+	}
+	if !s.sendEvents(s.replay...) {
+		return
+	}
+	s.replay = nil
+
+	for {
+		var ok bool
+
+		select {
+		case m, open := <-s.sub.Messages():
+			if !open {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			ok = s.sendEvents(m)
+		case m, open := <-messages:
+			if !open {
+				return
+			}
+			ok = s.resubscribe(m)
+		case <-ticker.C:
+			ok = s.send(StreamMessage{Type: StreamHeartbeat})
+		}
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// send writes m to the client and reports whether it could.
+func (s *socketStream) send(m StreamMessage) bool {
+	return s.conn.WriteJSON(m) == nil
+}
+
+func (s *socketStream) sendEvents(messages ...eventbus.Message) bool {
+	for _, m := range messages {
+		if !s.send(StreamMessage{ID: m.ID, Type: string(m.Event.Type), Event: &m.Event}) {
+			return false
+		}
+		s.lastID = m.ID
+	}
+	return true
+}
+
+// resubscribe applies a client's subscription change, picking up from the bus's last event
+// when the change arrived so that events kept by the bus are neither missed nor repeated,
+// even before any event was sent.
+func (s *socketStream) resubscribe(m clientMessage) bool {
+	if m.err != nil {
+		return s.send(StreamMessage{Type: StreamError, Error: m.err.Error()})
+	}
+
+	filter, err := subscriptionFilter(s.filter.TenantID, m.subscription.Products, m.subscription.LowStock)
+	if err != nil {
+		return s.send(StreamMessage{Type: StreamError, Error: err.Error()})
+	}
+
+	from := s.bus.LastID()
+
+	// Deliver what the old subscription already holds before replacing it
+	sent := make(map[uint64]bool)
+	for pending := true; pending; {
+		select {
+		case m, open := <-s.sub.Messages():
+			if pending = open; open && !s.sendEvents(m) {
+				return false
+			}
+			sent[m.ID] = true
+		default:
+			pending = false
+		}
+	}
+
+	// Subscribe before closing the old subscription, so that no event goes to neither
+	sub, replay, _ := s.bus.Subscribe(filter, from)
+	s.sub.Close()
+	s.sub, s.filter = sub, filter
+
+	replay = slices.DeleteFunc(replay, func(m eventbus.Message) bool { return sent[m.ID] })
+
+	return s.send(StreamMessage{Type: StreamSubscribed}) && s.sendEvents(replay...)
+}
+
+func isJSONError(err error) bool {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return true
+	}
+	return false
+}
+
+// parseStreamRequest reads the filter of a stream request from the products and
+// low-stock query parameters, and the event to resume after from the Last-Event-ID header
+// or the last-event-id query parameter.
+func parseStreamRequest(c *fiber.Ctx) (streamRequest, error) {
+	var products []string
+	for _, id := range strings.Split(c.Query("products"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			products = append(products, id)
+		}
+	}
+
+	var lowStock bool
+	if v := c.Query("low-stock"); v != "" {
+		var err error
+		if lowStock, err = strconv.ParseBool(v); err != nil {
+			return streamRequest{}, errors.NewInvalidFormatError("low-stock")
+		}
+	}
+
+	filter, err := subscriptionFilter(tenant.FromContext(c.UserContext()), products, lowStock)
+	if err != nil {
+		return streamRequest{}, err
+	}
+
+	req := streamRequest{filter: filter}
+	if v := c.Get("Last-Event-ID", c.Query("last-event-id")); v != "" {
+		if req.lastID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return streamRequest{}, errors.NewInvalidFormatError("Last-Event-ID")
+		}
+	}
+
+	return req, nil
+}
+
+func subscriptionFilter(tenantID string, products []string, lowStock bool) (eventbus.Filter, error) {
+	filter := eventbus.Filter{TenantID: tenantID, LowStock: lowStock}
+
+	for _, id := range products {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return filter, errors.NewInvalidFormatError("products")
+		}
+		filter.ProductIDs = append(filter.ProductIDs, parsed)
+	}
+
+	return filter, nil
+}
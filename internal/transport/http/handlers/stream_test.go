@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/eventbus"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+)
+
+// streamServer serves the stream endpoints to the acme tenant on a local port, since
+// streams never end for app.Test to return.
+type streamServer struct {
+	bus  *eventbus.Bus
+	addr string
+	ctx  context.Context
+}
+
+func newStreamServer(t *testing.T) *streamServer {
+	t.Helper()
+
+	bus := eventbus.New(10)
+	h := NewStreamHandler(bus, 50*time.Millisecond)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(tenant.WithTenant(c.UserContext(), "acme"))
+		return c.Next()
+	})
+	app.Get("/stream", h.Events())
+	app.Get("/stream/ws", h.WebSocket())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln)
+
+	t.Cleanup(func() {
+		bus.Close()
+		app.ShutdownWithTimeout(time.Second)
+	})
+
+	return &streamServer{bus: bus, addr: ln.Addr().String(), ctx: tenant.WithTenant(context.Background(), "acme")}
+}
+
+func (s *streamServer) publish(typ product.EventType, productID uuid.UUID) {
+	s.bus.Publish(s.ctx, product.Event{Type: typ, ProductID: productID, StockQuantity: 7})
+}
+
+// openEvents opens a Server-Sent Events stream and returns a reader of its events.
+func (s *streamServer) openEvents(t *testing.T, query string, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+s.addr+"/stream"+query, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	return bufio.NewReader(resp.Body)
+}
+
+// nextEvent returns the lines of the next event, skipping heartbeats unless asked for.
+func nextEvent(t *testing.T, r *bufio.Reader, heartbeats bool) []string {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended: %v", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line != "" {
+			lines = append(lines, line)
+			continue
+		}
+
+		if len(lines) == 1 && lines[0] == ": heartbeat" && !heartbeats {
+			lines = nil
+			continue
+		}
+		return lines
+	}
+}
+
+func TestStreamHandler_Events(t *testing.T) {
+	s := newStreamServer(t)
+	widget, gadget := uuid.New(), uuid.New()
+
+	t.Run("streams the events of the subscribed products", func(t *testing.T) {
+		r := s.openEvents(t, "?products="+widget.String(), "")
+
+		s.publish(product.EventStockChanged, gadget)
+		s.publish(product.EventStockChanged, widget)
+
+		lines := nextEvent(t, r, false)
+		if len(lines) != 3 || lines[0] != "id: 2" || lines[1] != "event: stock.changed" || !strings.Contains(lines[2], widget.String()) {
+			t.Fatalf("expected the widget's event, got %q", lines)
+		}
+	})
+
+	t.Run("streams only low-stock events when asked", func(t *testing.T) {
+		r := s.openEvents(t, "?low-stock=true", "")
+
+		s.publish(product.EventStockChanged, gadget)
+		s.publish(product.EventLowStock, gadget)
+
+		if lines := nextEvent(t, r, false); lines[1] != "event: stock.low" {
+			t.Fatalf("expected the low-stock event, got %q", lines)
+		}
+	})
+
+	t.Run("resumes after Last-Event-ID", func(t *testing.T) {
+		r := s.openEvents(t, "", "3")
+
+		if lines := nextEvent(t, r, false); lines[0] != "id: 4" {
+			t.Fatalf("expected to resume from event 4, got %q", lines)
+		}
+	})
+
+	t.Run("tells clients that missed events to reload", func(t *testing.T) {
+		r := s.openEvents(t, "", "99")
+
+		if lines := nextEvent(t, r, false); lines[0] != "event: reset" {
+			t.Fatalf("expected a reset event, got %q", lines)
+		}
+	})
+
+	t.Run("sends heartbeats when idle", func(t *testing.T) {
+		r := s.openEvents(t, "?products="+uuid.NewString(), "")
+
+		if lines := nextEvent(t, r, true); len(lines) != 1 || lines[0] != ": heartbeat" {
+			t.Fatalf("expected a heartbeat, got %q", lines)
+		}
+	})
+
+	t.Run("error on invalid product ID", func(t *testing.T) {
+		resp, err := http.Get("http://" + s.addr + "/stream?products=abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// dial opens a WebSocket stream.
+func (s *streamServer) dial(t *testing.T, query string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+s.addr+"/stream/ws"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// nextMessage returns the next message of a WebSocket stream, skipping heartbeats unless asked for.
+func nextMessage(t *testing.T, conn *websocket.Conn, heartbeats bool) StreamMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var m StreamMessage
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatalf("stream ended: %v", err)
+		}
+		if m.Type != StreamHeartbeat || heartbeats {
+			return m
+		}
+	}
+}
+
+func TestStreamHandler_WebSocket(t *testing.T) {
+	s := newStreamServer(t)
+	widget, gadget := uuid.New(), uuid.New()
+
+	t.Run("streams events and follows subscription changes", func(t *testing.T) {
+		conn := s.dial(t, "?products="+widget.String())
+
+		s.publish(product.EventStockChanged, widget)
+		if m := nextMessage(t, conn, false); m.Type != "stock.changed" || m.ID != 1 || m.Event.ProductID != widget || m.Event.StockQuantity != 7 {
+			t.Fatalf("expected the widget's event, got %+v", m)
+		}
+
+		if err := conn.WriteJSON(StreamSubscription{Products: []string{gadget.String()}, LowStock: true}); err != nil {
+			t.Fatal(err)
+		}
+		if m := nextMessage(t, conn, false); m.Type != StreamSubscribed {
+			t.Fatalf("expected the subscription to be acknowledged, got %+v", m)
+		}
+
+		s.publish(product.EventLowStock, widget)
+		s.publish(product.EventStockChanged, gadget)
+		s.publish(product.EventLowStock, gadget)
+
+		if m := nextMessage(t, conn, false); m.Type != "stock.low" || m.Event.ProductID != gadget {
+			t.Fatalf("expected the gadget's low-stock event, got %+v", m)
+		}
+	})
+
+	t.Run("resumes after last-event-id", func(t *testing.T) {
+		conn := s.dial(t, "?last-event-id=2")
+
+		if m := nextMessage(t, conn, false); m.ID != 3 {
+			t.Fatalf("expected to resume from event 3, got %+v", m)
+		}
+	})
+
+	t.Run("reports invalid subscriptions and sends heartbeats", func(t *testing.T) {
+		conn := s.dial(t, "")
+
+		if err := conn.WriteJSON(StreamSubscription{Products: []string{"abc"}}); err != nil {
+			t.Fatal(err)
+		}
+		if m := nextMessage(t, conn, false); m.Type != StreamError || m.Error == "" {
+			t.Fatalf("expected an error message, got %+v", m)
+		}
+
+		if m := nextMessage(t, conn, true); m.Type != StreamHeartbeat {
+			t.Fatalf("expected a heartbeat, got %+v", m)
+		}
+	})
+
+	t.Run("error without an upgrade", func(t *testing.T) {
+		resp, err := http.Get("http://" + s.addr + "/stream/ws")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUpgradeRequired {
+			t.Fatalf("expected 426, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+type SupplierHandler struct {
+	service supplier.Service
+}
+
+func NewSupplierHandler(s supplier.Service) *SupplierHandler {
+	return &SupplierHandler{
+		service: s,
+	}
+}
+
+func (h *SupplierHandler) CreateSupplier() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var s supplier.Supplier
+
+		// Parse request body
+		if err := c.BodyParser(&s); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.CreateSupplier(c.UserContext(), &s); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, s)
+	}
+}
+
+func (h *SupplierHandler) GetAllSuppliers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		suppliers, err := h.service.GetAllSuppliers(c.UserContext())
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, suppliers)
+	}
+}
+
+func (h *SupplierHandler) GetSupplierByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		s, err := h.service.GetSupplierByID(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, s)
+	}
+}
+
+func (h *SupplierHandler) UpdateSupplier() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var s supplier.Supplier
+
+		// Parse request body
+		if err := c.BodyParser(&s); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.UpdateSupplier(c.UserContext(), id, &s); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		updated, err := h.service.GetSupplierByID(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, updated)
+	}
+}
+
+func (h *SupplierHandler) DeleteSupplier() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Call service layer
+		if err := h.service.DeleteSupplier(c.UserContext(), c.Params("id")); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+)
+
+// SetUnitsRequest is the body accepted by SetUnits.
+type SetUnitsRequest struct {
+	Units []product.UnitOfMeasure `json:"units" validate:"required"`
+}
+
+func (h *ProductHandler) SetUnits() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var req SetUnitsRequest
+
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.SetUnits(c.UserContext(), id, req.Units); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithProduct(c, id)
+	}
+}
+
+// baseQuantity returns a stock request's change in the product's base unit. The request
+// gives either base units in the named field or a quantity and unit, but not both.
+func (h *ProductHandler) baseQuantity(c *fiber.Ctx, id string, base int, field string, q quantity.Decimal, unit string) (int, error) {
+	if q == "" && unit == "" {
+		return base, nil
+	}
+	if base != 0 {
+		return 0, errors.NewInvalidInputError("give either " + field + " or quantity and unit, not both")
+	}
+	if q == "" {
+		return 0, errors.NewMissingRequiredDataError("quantity")
+	}
+
+	return h.service.ToBaseUnits(c.UserContext(), id, q, unit)
+}
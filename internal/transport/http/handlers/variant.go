@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// SetOptionsRequest is the body accepted by SetOptions.
+type SetOptionsRequest struct {
+	Options []product.OptionAxis `json:"options" validate:"required"`
+}
+
+func (h *ProductHandler) SetOptions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var req SetOptionsRequest
+
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.SetOptions(c.UserContext(), id, req.Options); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithProduct(c, id)
+	}
+}
+
+func (h *ProductHandler) CreateVariant() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var v product.Variant
+
+		// Parse request body
+		if err := c.BodyParser(&v); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.CreateVariant(c.UserContext(), c.Params("id"), &v); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleCreatedSuccess(c, v)
+	}
+}
+
+func (h *ProductHandler) UpdateVariant() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var v product.Variant
+
+		// Parse request body
+		if err := c.BodyParser(&v); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Call service layer
+		if err := h.service.UpdateVariant(c.UserContext(), id, c.Params("variantId"), &v); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return h.respondWithProduct(c, id)
+	}
+}
+
+func (h *ProductHandler) DeleteVariant() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := h.service.DeleteVariant(c.UserContext(), c.Params("id"), c.Params("variantId")); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleNoContent(c)
+	}
+}
+
+func (h *ProductHandler) IncrementVariantStock() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var req StockIncrementRequest
+
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Convert a quantity given in a unit of measure to base units
+		var err error
+		if req.StockIncrement, err = h.baseQuantity(c, id, req.StockIncrement, "stock_increment", req.Quantity, req.Unit); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Validate increment value
+		if req.StockIncrement <= 0 {
+			return errors.HandleError(c, errors.NewInvalidInputError("stock_increment must be greater than 0"))
+		}
+
+		// Call service layer
+		if err := h.service.IncrementVariantStock(c.UserContext(), id, c.Params("variantId"), req.StockIncrement); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Get updated product to return per-variant and aggregate stock
+		updatedProduct, err := h.service.GetProductByID(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, StockIncrementResponse{
+			Message:         "Stock incremented successfully",
+			Product:         updatedProduct,
+			IncrementAmount: req.StockIncrement,
+		})
+	}
+}
+
+func (h *ProductHandler) DecrementVariantStock() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var req StockDecrementRequest
+
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errors.HandleError(c, errors.NewInvalidInputError("invalid request body format: "+err.Error()))
+		}
+
+		// Convert a quantity given in a unit of measure to base units
+		var err error
+		if req.StockDecrement, err = h.baseQuantity(c, id, req.StockDecrement, "stock_decrement", req.Quantity, req.Unit); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Validate decrement value
+		if req.StockDecrement <= 0 {
+			return errors.HandleError(c, errors.NewInvalidInputError("stock_decrement must be greater than 0"))
+		}
+
+		// Call service layer
+		if err := h.service.DecrementVariantStock(c.UserContext(), id, c.Params("variantId"), req.StockDecrement); err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		// Get updated product to return per-variant and aggregate stock
+		updatedProduct, err := h.service.GetProductByID(c.UserContext(), id)
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		return errors.HandleSuccess(c, StockDecrementResponse{
+			Message:         "Stock decremented successfully",
+			Product:         updatedProduct,
+			DecrementAmount: req.StockDecrement,
+		})
+	}
+}
+
+// respondWithProduct returns the current state of the product.
+func (h *ProductHandler) respondWithProduct(c *fiber.Ctx, id string) error {
+	p, err := h.service.GetProductByID(c.UserContext(), id)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	return errors.HandleSuccess(c, p)
+}
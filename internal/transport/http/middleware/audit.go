@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+)
+
+// ActorHeader names who is making the request, for the audit trail.
+const ActorHeader = "X-Actor"
+
+// maxHeaderValue bounds the client-supplied values recorded in the audit trail.
+const maxHeaderValue = 128
+
+// AuditContext attributes the changes a request makes to the actor in the X-Actor header
+// and to the request's ID. The ID is taken from the X-Request-ID header, or generated,
+// and echoed in the response.
+func AuditContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if id == "" || len(id) > maxHeaderValue {
+			id = uuid.NewString()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+
+		actor := c.Get(ActorHeader)
+		if len(actor) > maxHeaderValue {
+			actor = actor[:maxHeaderValue]
+		}
+
+		ctx := audit.WithRequestID(c.UserContext(), id)
+		c.SetUserContext(audit.WithActor(ctx, actor))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+)
+
+func TestAuditContext(t *testing.T) {
+	var actor, requestID string
+
+	app := fiber.New()
+	app.Use(AuditContext())
+	app.Get("/", func(c *fiber.Ctx) error {
+		actor = audit.ActorFrom(c.UserContext())
+		requestID = audit.RequestIDFrom(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("uses the request's actor and ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(ActorHeader, "alice")
+		req.Header.Set(fiber.HeaderXRequestID, "req-1")
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actor != "alice" || requestID != "req-1" {
+			t.Fatalf("expected alice and req-1, got %q and %q", actor, requestID)
+		}
+		if got := resp.Header.Get(fiber.HeaderXRequestID); got != "req-1" {
+			t.Fatalf("expected the request ID to be echoed, got %q", got)
+		}
+	})
+
+	t.Run("generates an ID for anonymous requests", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actor != audit.AnonymousActor || requestID == "" {
+			t.Fatalf("expected an anonymous actor and a generated ID, got %q and %q", actor, requestID)
+		}
+		if got := resp.Header.Get(fiber.HeaderXRequestID); got != requestID {
+			t.Fatalf("expected the generated ID %q to be echoed, got %q", requestID, got)
+		}
+	})
+}
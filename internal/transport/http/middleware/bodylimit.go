@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+)
+
+// BodyLimit rejects request bodies over limit bytes, except those of requests exempt
+// reports, such as uploads held to a limit of their own on their route. exempt may be nil.
+// A limit of zero or less disables the check. When the server streams request bodies, a
+// Content-Length over the limit is rejected before the body is read, and a chunked body
+// is read no further than the limit.
+func BodyLimit(limit int, exempt func(*fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if limit <= 0 || (exempt != nil && exempt(c)) {
+			return c.Next()
+		}
+
+		req := c.Request()
+
+		if req.Header.ContentLength() > limit {
+			return tooLarge(c, limit)
+		}
+
+		if !req.IsBodyStream() {
+			if len(req.Body()) > limit {
+				return tooLarge(c, limit)
+			}
+			return c.Next()
+		}
+
+		if req.Header.ContentLength() < 0 {
+			body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(limit)+1))
+			if err != nil {
+				return errors.HandleError(c, errors.NewInvalidInputError("failed to read request body: "+err.Error()))
+			}
+			if len(body) > limit {
+				return tooLarge(c, limit)
+			}
+			req.SetBody(body)
+		}
+
+		return c.Next()
+	}
+}
+
+// tooLarge rejects the request. The rest of the body is left unread, so the connection
+// cannot carry another request.
+func tooLarge(c *fiber.Ctx, limit int) error {
+	c.Context().SetConnectionClose()
+	return errors.HandleError(c, errors.NewPayloadTooLargeError(limit))
+}
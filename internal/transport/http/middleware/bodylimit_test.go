@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBodyLimit(t *testing.T) {
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	fw, _ := mw.CreateFormFile("file", "spec.pdf")
+	fw.Write([]byte(strings.Repeat("x", 32)))
+	mw.Close()
+
+	app := fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	app.Use(BodyLimit(8, func(c *fiber.Ctx) bool { return c.Path() == "/upload" }))
+	app.Post("/", func(c *fiber.Ctx) error {
+		if len(c.Body()) == 0 {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/upload", BodyLimit(1024, nil), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		chunked     bool
+		status      int
+	}{
+		{"small JSON body", "/", fiber.MIMEApplicationJSON, `{}`, false, 200},
+		{"large JSON body", "/", fiber.MIMEApplicationJSON, `{"name":"Widget"}`, false, 413},
+		{"large multipart body", "/", mw.FormDataContentType(), form.String(), false, 413},
+		{"small chunked body", "/", fiber.MIMEApplicationJSON, `{}`, true, 200},
+		{"large chunked body", "/", fiber.MIMEApplicationJSON, `{"name":"Widget"}`, true, 413},
+		{"upload within its own limit", "/upload", mw.FormDataContentType(), form.String(), false, 200},
+		{"upload over its own limit", "/upload", fiber.MIMEApplicationJSON, strings.Repeat("x", 1025), false, 413},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.chunked {
+				req.ContentLength = -1
+				req.TransferEncoding = []string{"chunked"}
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
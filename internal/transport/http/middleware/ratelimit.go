@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/ratelimit"
+)
+
+// APIKeyHeader identifies the calling client. Requests without a known key are keyed by IP.
+const APIKeyHeader = "X-API-Key"
+
+// Clients identifies the callers that budgets are kept for. Only API keys it knows
+// identify a caller, so that inventing a new key per request cannot earn a fresh budget.
+type Clients struct {
+	apiKeys map[string]struct{}
+}
+
+// NewClients returns Clients that trust the given API keys.
+func NewClients(apiKeys []string) Clients {
+	cl := Clients{apiKeys: make(map[string]struct{}, len(apiKeys))}
+	for _, key := range apiKeys {
+		cl.apiKeys[key] = struct{}{}
+	}
+	return cl
+}
+
+// key identifies the caller by API key when it is a known one, falling back to the remote IP.
+func (cl Clients) key(c *fiber.Ctx) string {
+	if key := c.Get(APIKeyHeader); key != "" {
+		if _, ok := cl.apiKeys[key]; ok {
+			return "key:" + key
+		}
+	}
+	return "ip:" + c.IP()
+}
+
+// RateLimit throttles requests per client, drawing safe methods (GET, HEAD, OPTIONS)
+// from the read budget and everything else from the write budget.
+func RateLimit(clients Clients, read, write *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		l := write
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			l = read
+		}
+
+		return take(c, clients, l)
+	}
+}
+
+// Throttle applies a single budget to the routes it is attached to. It is used
+// to give bulk endpoints a stricter limit on top of the read/write budgets.
+func Throttle(clients Clients, l *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return take(c, clients, l)
+	}
+}
+
+func take(c *fiber.Ctx, clients Clients, l *ratelimit.Limiter) error {
+	res := l.Allow(clients.key(c))
+
+	c.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+	if !res.Allowed {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds(res.RetryAfter)))
+		return errors.HandleError(c, errors.NewRateLimitedError(res.RetryAfter))
+	}
+
+	return c.Next()
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	newApp := func() *fiber.App {
+		app := fiber.New()
+		app.Use(RateLimit(NewClients([]string{"client-a", "client-b"}), ratelimit.New(60, 2, 0), ratelimit.New(60, 1, 0)))
+		app.Get("/products", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		app.Post("/products/:id/decrement-stock", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		return app
+	}
+
+	t.Run("sets RateLimit headers on allowed requests", func(t *testing.T) {
+		app := newApp()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/products", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected status 200, got %d", resp.StatusCode)
+		}
+		if got := resp.Header.Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("expected RateLimit-Limit 2, got %q", got)
+		}
+		if got := resp.Header.Get("RateLimit-Remaining"); got != "1" {
+			t.Errorf("expected RateLimit-Remaining 1, got %q", got)
+		}
+	})
+
+	t.Run("returns 429 RATE_LIMITED once the write budget is spent", func(t *testing.T) {
+		app := newApp()
+
+		for i, want := range []int{200, 429} {
+			resp, err := app.Test(httptest.NewRequest("POST", "/products/p1/decrement-stock", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != want {
+				t.Fatalf("request %d: expected status %d, got %d", i+1, want, resp.StatusCode)
+			}
+
+			if want == 429 {
+				if resp.Header.Get("Retry-After") == "" {
+					t.Error("expected Retry-After header")
+				}
+
+				var body struct {
+					Code string `json:"code"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				if body.Code != "RATE_LIMITED" {
+					t.Errorf("expected code RATE_LIMITED, got %s", body.Code)
+				}
+			}
+		}
+	})
+
+	t.Run("keeps separate budgets per API key", func(t *testing.T) {
+		app := newApp()
+
+		for _, key := range []string{"client-a", "client-b"} {
+			req := httptest.NewRequest("POST", "/products/p1/decrement-stock", nil)
+			req.Header.Set(APIKeyHeader, key)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != 200 {
+				t.Fatalf("expected status 200 for %s, got %d", key, resp.StatusCode)
+			}
+		}
+	})
+	t.Run("limits unknown API keys by IP", func(t *testing.T) {
+		app := newApp()
+
+		for i, want := range []int{200, 429} {
+			req := httptest.NewRequest("POST", "/products/p1/decrement-stock", nil)
+			req.Header.Set(APIKeyHeader, uuid.NewString())
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != want {
+				t.Fatalf("request %d: expected status %d, got %d", i+1, want, resp.StatusCode)
+			}
+		}
+	})
+}
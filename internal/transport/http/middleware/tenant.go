@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+)
+
+// TenantHeader names the tenant a request acts for.
+const TenantHeader = tenant.Header
+
+// Tenant confines the request to one tenant's data. The tenant is taken from the bearer
+// token when cfg.TokenSecret is set, and otherwise from the X-Tenant-ID header, falling
+// back to cfg.Default. A token's subject becomes the audit actor.
+func Tenant(cfg config.TenantConfig) fiber.Handler {
+	resolver := tenant.NewResolver(cfg.TokenSecret, cfg.Default, cfg.Required)
+
+	return func(c *fiber.Ctx) error {
+		id, err := resolver.Resolve(c.Get(fiber.HeaderAuthorization), c.Get(TenantHeader))
+		if err != nil {
+			return errors.HandleError(c, err)
+		}
+
+		ctx := c.UserContext()
+		if id.Tenant != "" {
+			ctx = tenant.WithTenant(ctx, id.Tenant)
+		}
+		if id.Subject != "" {
+			ctx = audit.WithActor(ctx, id.Subject)
+		}
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
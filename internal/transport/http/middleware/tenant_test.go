@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+)
+
+func newTenantApp(cfg config.TenantConfig, got *string, actor *string) *fiber.App {
+	app := fiber.New()
+	app.Use(Tenant(cfg))
+	app.Get("/", func(c *fiber.Ctx) error {
+		*got = tenant.FromContext(c.UserContext())
+		*actor = audit.ActorFrom(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestTenant(t *testing.T) {
+	var got, actor string
+
+	t.Run("takes the tenant from the header, or the default", func(t *testing.T) {
+		app := newTenantApp(config.TenantConfig{Default: "default"}, &got, &actor)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(TenantHeader, "acme")
+		if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusOK || got != "acme" {
+			t.Fatalf("expected 200 for acme, got %d for %q", resp.StatusCode, got)
+		}
+
+		if resp, _ := app.Test(httptest.NewRequest("GET", "/", nil)); resp.StatusCode != fiber.StatusOK || got != "default" {
+			t.Fatalf("expected 200 for the default tenant, got %d for %q", resp.StatusCode, got)
+		}
+	})
+
+	t.Run("rejects missing and invalid tenants", func(t *testing.T) {
+		app := newTenantApp(config.TenantConfig{Required: true}, &got, &actor)
+
+		if resp, _ := app.Test(httptest.NewRequest("GET", "/", nil)); resp.StatusCode != fiber.StatusUnauthorized {
+			t.Fatalf("expected 401 without a tenant, got %d", resp.StatusCode)
+		}
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(TenantHeader, tenant.All)
+		if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusBadRequest {
+			t.Fatalf("expected 400 for an invalid tenant, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("takes the tenant and actor from a signed token", func(t *testing.T) {
+		cfg := config.TenantConfig{TokenSecret: "secret", Default: "default"}
+		app := newTenantApp(cfg, &got, &actor)
+
+		token, _ := tenant.NewToken(tenant.Claims{Tenant: "acme", Subject: "alice"}, []byte(cfg.TokenSecret))
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusOK || got != "acme" || actor != "alice" {
+			t.Fatalf("expected 200 for acme as alice, got %d for %q as %q", resp.StatusCode, got, actor)
+		}
+
+		req = httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		req.Header.Set(TenantHeader, "globex")
+		if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusForbidden {
+			t.Fatalf("expected 403 when the header names another tenant, got %d", resp.StatusCode)
+		}
+
+		req = httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(TenantHeader, "acme")
+		if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusUnauthorized {
+			t.Fatalf("expected 401 for the header without a token, got %d", resp.StatusCode)
+		}
+
+		expired, _ := tenant.NewToken(tenant.Claims{Tenant: "acme", ExpiresAt: time.Now().Add(-time.Minute).Unix()}, []byte(cfg.TokenSecret))
+		req = httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+expired)
+		if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusUnauthorized {
+			t.Fatalf("expected 401 for an expired token, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,277 @@
+// Package openapi builds the OpenAPI 3.0 document for the HTTP API from the
+// route registrations and the Go types that handlers read and write.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/response"
+)
+
+// Document is the root OpenAPI 3.0 object.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Servers    []Server                         `json:"servers"`
+	Tags       []Tag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+
+	names     map[reflect.Type]string
+	types     map[string]reflect.Type
+	overrides map[reflect.Type]*Schema
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Op describes a route as it is registered: the request it accepts and the
+// envelope data it returns. It is translated into an Operation.
+type Op struct {
+	Summary     string
+	Description string
+	Tags        []string
+
+	// Query lists the query-string parameters the handler reads
+	Query []Param
+
+	// Body is a value of the type the handler parses from the request body
+	Body any
+	// Upload names the multipart/form-data field the handler reads a file from
+	Upload string
+
+	// Download is the content type of a file the handler sends instead of the
+	// JSON envelope; "*/*" when it varies
+	Download string
+
+	// Status is the success status code; defaults to 200
+	Status int
+	// Response is a value of the type placed in the envelope's data field.
+	// It is ignored for 204 responses and protocol upgrades (101).
+	Response any
+
+	// Errors lists the error status codes the handler may return
+	Errors []int
+}
+
+// Param describes a query-string parameter.
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+	// Type is the JSON schema type; defaults to "string"
+	Type string
+	Enum []any
+}
+
+// New creates an empty document for the API served under serverURL.
+func New(info Info, serverURL string) *Document {
+	d := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Servers: []Server{{URL: serverURL}},
+		Paths:   map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+		names:     map[reflect.Type]string{},
+		types:     map[string]reflect.Type{},
+		overrides: map[reflect.Type]*Schema{},
+	}
+
+	return d
+}
+
+// AddTag declares a tag and its description.
+func (d *Document) AddTag(name, description string) {
+	d.Tags = append(d.Tags, Tag{Name: name, Description: description})
+}
+
+// Override sets the schema used for every occurrence of the type of v.
+// It is meant for types with custom JSON encodings.
+func (d *Document) Override(v any, s Schema) {
+	d.overrides[reflect.TypeOf(v)] = &s
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// Add records the operation for a fiber-style path such as /products/:id.
+func (d *Document) Add(method, path string, op Op) {
+	oapiPath := ToOpenAPIPath(path)
+
+	o := &Operation{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+		s := &Schema{Type: "string"}
+		if name := m[1]; name == "id" || strings.HasSuffix(name, "Id") {
+			s.Format = "uuid"
+		}
+		o.Parameters = append(o.Parameters, &Parameter{Name: m[1], In: "path", Required: true, Schema: s})
+	}
+
+	for _, q := range op.Query {
+		typ := q.Type
+		if typ == "" {
+			typ = "string"
+		}
+		o.Parameters = append(o.Parameters, &Parameter{
+			Name:        q.Name,
+			In:          "query",
+			Description: q.Description,
+			Required:    q.Required,
+			Schema:      &Schema{Type: typ, Enum: q.Enum},
+		})
+	}
+
+	if op.Body != nil {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: d.schemaFor(reflect.TypeOf(op.Body))},
+			},
+		}
+	}
+
+	if op.Upload != "" {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"multipart/form-data": {Schema: &Schema{
+					Type:       "object",
+					Required:   []string{op.Upload},
+					Properties: map[string]*Schema{op.Upload: {Type: "string", Format: "binary"}},
+				}},
+			},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if op.Download != "" {
+		o.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{op.Download: {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	} else {
+		o.Responses[strconv.Itoa(status)] = d.successResponse(status, op.Response)
+	}
+
+	errs := append([]int{http.StatusTooManyRequests}, op.Errors...)
+	for _, code := range errs {
+		o.Responses[strconv.Itoa(code)] = d.errorResponse(code)
+	}
+
+	if d.Paths[oapiPath] == nil {
+		d.Paths[oapiPath] = map[string]*Operation{}
+	}
+	d.Paths[oapiPath][strings.ToLower(method)] = o
+}
+
+// successResponse documents the SuccessResponseWithCode envelope around data.
+func (d *Document) successResponse(status int, data any) *Response {
+	r := &Response{Description: http.StatusText(status)}
+	if status == http.StatusNoContent || status == http.StatusSwitchingProtocols {
+		return r
+	}
+
+	envelope := d.structSchema(reflect.TypeOf(response.BaseResponse{}))
+	envelope.Required = []string{"success"}
+	if data != nil {
+		envelope.Properties["data"] = d.schemaFor(reflect.TypeOf(data))
+	}
+
+	r.Content = map[string]MediaType{"application/json": {Schema: envelope}}
+	return r
+}
+
+// errorResponse documents the ErrorResponseWithCode envelope.
+func (d *Document) errorResponse(status int) *Response {
+	r := &Response{
+		Description: http.StatusText(status),
+		Content: map[string]MediaType{
+			"application/json": {Schema: d.schemaFor(reflect.TypeOf(response.ErrorResponseWithCode{}))},
+		},
+	}
+
+	if status == http.StatusTooManyRequests {
+		r.Headers = map[string]Header{
+			"Retry-After": {Description: "Seconds until the next request is allowed", Schema: &Schema{Type: "integer"}},
+		}
+	}
+
+	return r
+}
+
+// ToOpenAPIPath converts fiber path parameters (/products/:id) to OpenAPI templates (/products/{id}).
+func ToOpenAPIPath(path string) string {
+	path = pathParam.ReplaceAllString(path, "{$1}")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Router wraps a fiber.Router so that every route registered through it is
+// also recorded in the Document.
+type Router struct {
+	router fiber.Router
+	doc    *Document
+	prefix string
+}
+
+// NewRouter documents routes registered on r. Paths are recorded relative to
+// r, which should be mounted at the document's server URL.
+func NewRouter(r fiber.Router, doc *Document) *Router {
+	return &Router{
+		router: r,
+		doc:    doc,
+	}
+}
+
+// Group creates a sub-router under prefix.
+func (r *Router) Group(prefix string, handlers ...fiber.Handler) *Router {
+	return &Router{
+		router: r.router.Group(prefix, handlers...),
+		doc:    r.doc,
+		prefix: r.prefix + prefix,
+	}
+}
+
+// Use registers middleware on the underlying router.
+func (r *Router) Use(args ...any) *Router {
+	r.router.Use(args...)
+	return r
+}
+
+func (r *Router) Get(path string, op Op, handlers ...fiber.Handler) *Router {
+	return r.add(fiber.MethodGet, path, op, handlers)
+}
+
+func (r *Router) Post(path string, op Op, handlers ...fiber.Handler) *Router {
+	return r.add(fiber.MethodPost, path, op, handlers)
+}
+
+func (r *Router) Put(path string, op Op, handlers ...fiber.Handler) *Router {
+	return r.add(fiber.MethodPut, path, op, handlers)
+}
+
+func (r *Router) Patch(path string, op Op, handlers ...fiber.Handler) *Router {
+	return r.add(fiber.MethodPatch, path, op, handlers)
+}
+
+func (r *Router) Delete(path string, op Op, handlers ...fiber.Handler) *Router {
+	return r.add(fiber.MethodDelete, path, op, handlers)
+}
+
+func (r *Router) add(method, path string, op Op, handlers []fiber.Handler) *Router {
+	r.router.Add(method, path, handlers...)
+	r.doc.Add(method, r.prefix+path, op)
+	return r
+}
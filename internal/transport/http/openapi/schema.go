@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Schema is the subset of the OpenAPI 3.0 schema object used by this API.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the schema for t, registering named struct types as components.
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+	if s, ok := d.overrides[t]; ok {
+		copied := *s
+		return &copied
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := wrapRef(d.schemaFor(t.Elem()))
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		return &Schema{Ref: d.component(t)}
+	}
+
+	// interface{} and anything else accepts any value
+	return &Schema{}
+}
+
+// component registers t under components/schemas and returns its reference.
+func (d *Document) component(t reflect.Type) string {
+	name, ok := d.names[t]
+	if !ok {
+		name = t.Name()
+		if other, taken := d.types[name]; taken && other != t {
+			// Disambiguate same-named types from different packages
+			pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+		}
+		d.names[t] = name
+		d.types[name] = t
+
+		// Reserve the name before recursing so self-referencing types terminate
+		d.Components.Schemas[name] = &Schema{}
+		*d.Components.Schemas[name] = *d.structSchema(t)
+	}
+
+	return "#/components/schemas/" + name
+}
+
+// structSchema builds an object schema from the exported, JSON-visible fields of t.
+// Embedded structs without a json tag are flattened, as encoding/json does.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+
+	d.collectFields(t, s)
+
+	return s
+}
+
+func (d *Document) collectFields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				d.collectFields(ft, s)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := d.schemaFor(f.Type)
+		if f.Tag.Get("doc") != "" || f.Tag.Get("openapi") == "readonly" {
+			fs = wrapRef(fs)
+		}
+		fs.Description = f.Tag.Get("doc")
+		if f.Tag.Get("openapi") == "readonly" {
+			fs.ReadOnly = true
+		}
+		if values := f.Tag.Get("enum"); values != "" {
+			for _, v := range strings.Split(values, ",") {
+				fs.Enum = append(fs.Enum, v)
+			}
+		}
+
+		for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+			if rule == "required" {
+				s.Required = append(s.Required, name)
+			}
+			if min, ok := strings.CutPrefix(rule, "min="); ok {
+				var v float64
+				if err := json.Unmarshal([]byte(min), &v); err == nil {
+					fs.Minimum = &v
+				}
+			}
+		}
+
+		s.Properties[name] = fs
+	}
+}
+
+// wrapRef moves a $ref into allOf so that sibling keywords such as nullable
+// and description are honoured; OpenAPI 3.0 ignores siblings of $ref.
+func wrapRef(s *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+	return &Schema{AllOf: []*Schema{{Ref: s.Ref}}}
+}
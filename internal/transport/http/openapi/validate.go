@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ValidateResponse checks that body is a response the document allows for the
+// given method, fiber-style path and status code. It is used by contract tests.
+func (d *Document) ValidateResponse(method, path string, status int, body []byte) error {
+	item, ok := d.Paths[ToOpenAPIPath(path)]
+	if !ok {
+		return fmt.Errorf("path %s is not documented", path)
+	}
+
+	op, ok := item[strings.ToLower(method)]
+	if !ok {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		return fmt.Errorf("%s %s does not document status %d", method, path, status)
+	}
+
+	media, ok := resp.Content["application/json"]
+	if !ok {
+		// File downloads document a binary body that is not validated
+		if len(body) > 0 && len(resp.Content) == 0 {
+			return fmt.Errorf("%s %s documents no body for status %d, got %q", method, path, status, body)
+		}
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	return d.validate(media.Schema, v, "$")
+}
+
+func (d *Document) resolve(ref string) (*Schema, error) {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	s, ok := d.Components.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema reference %s", ref)
+	}
+	return s, nil
+}
+
+func (d *Document) validate(s *Schema, v any, at string) error {
+	if s.Ref != "" {
+		resolved, err := d.resolve(s.Ref)
+		if err != nil {
+			return err
+		}
+		return d.validate(resolved, v, at)
+	}
+
+	if v == nil {
+		if s.Nullable || (s.Type == "" && len(s.AllOf) == 0) {
+			return nil
+		}
+		return fmt.Errorf("%s: unexpected null", at)
+	}
+
+	for _, sub := range s.AllOf {
+		if err := d.validate(sub, v, at); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Enum) > 0 && !contains(s.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", at, v, s.Enum)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", at, v)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		for name, value := range obj {
+			if prop, ok := s.Properties[name]; ok {
+				if err := d.validate(prop, value, at+"."+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s: unexpected property %q", at, name)
+				}
+			case *Schema:
+				if err := d.validate(extra, value, at+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", at, v)
+		}
+		for i, item := range arr {
+			if err := d.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", at, v)
+		}
+		switch s.Format {
+		case "uuid":
+			if _, err := uuid.Parse(str); err != nil {
+				return fmt.Errorf("%s: %q is not a uuid", at, str)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, str)
+			}
+		}
+
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s: expected %s, got %T", at, s.Type, v)
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			return fmt.Errorf("%s: expected integer, got %v", at, n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s: %v is below the minimum %v", at, n, *s.Minimum)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", at, v)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []any, v any) bool {
+	for _, e := range values {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package router
+
+import (
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/attachment"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/storage"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/middleware"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+// attachmentUploadPath matches the attachment upload route, which is held to the upload
+// limit instead of the general body limit.
+var attachmentUploadPath = regexp.MustCompile(`(?i)^/api/v1/products/[^/]+/attachments/?$`)
+
+func isAttachmentUpload(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodPost && attachmentUploadPath.MatchString(c.Path())
+}
+
+func (r *Router) attachmentRouter(grp *openapi.Router) {
+	cfg := r.app.Appconfig.StorageConfig
+
+	products := r.productService(r.app.PostgresConn)
+	repo := postgres.NewAttachmentRepository(r.app.PostgresConn)
+	s := attachment.NewService(repo, storage.NewLocal(cfg.Dir), products, int64(cfg.MaxAttachmentBytes), cfg.OrphanRetention)
+	h := handlers.NewAttachmentHandler(s)
+
+	attachmentRoutes(grp, h, r.bulk, middleware.BodyLimit(cfg.MaxUploadBytes(), nil))
+}
+
+func attachmentRoutes(grp *openapi.Router, h *handlers.AttachmentHandler, bulk fiber.Handler, uploadLimit fiber.Handler) {
+	agrp := grp.Group("/products/:id/attachments")
+
+	{
+		agrp.Post("/", openapi.Op{
+			Summary:     "Upload an attachment",
+			Description: "Upload a JPEG, PNG, GIF or WebP image, or a PDF, as the multipart field `file`. The declared content type must match the file's contents. Images get a PNG thumbnail.",
+			Tags:        []string{"Attachments"},
+			Upload:      handlers.AttachmentField,
+			Status:      201,
+			Response:    attachment.Attachment{},
+			Errors:      []int{400, 404, 413, 500},
+		}, uploadLimit, h.UploadAttachment())
+		agrp.Get("/", openapi.Op{
+			Summary:  "List a product's attachments",
+			Tags:     []string{"Attachments"},
+			Response: []attachment.Attachment{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetAttachments())
+		agrp.Get("/:attachmentId", openapi.Op{
+			Summary:  "Get attachment by ID",
+			Tags:     []string{"Attachments"},
+			Response: attachment.Attachment{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetAttachmentByID())
+		agrp.Get("/:attachmentId/content", openapi.Op{
+			Summary:  "Download an attachment",
+			Tags:     []string{"Attachments"},
+			Download: "*/*",
+			Errors:   []int{400, 404, 500},
+		}, h.DownloadAttachment(false))
+		agrp.Get("/:attachmentId/thumbnail", openapi.Op{
+			Summary:     "Download an image thumbnail",
+			Description: "PNG thumbnail at most 256 pixels on each side; documents have none",
+			Tags:        []string{"Attachments"},
+			Download:    "image/png",
+			Errors:      []int{400, 404, 500},
+		}, h.DownloadAttachment(true))
+		agrp.Delete("/:attachmentId", openapi.Op{
+			Summary: "Delete an attachment",
+			Tags:    []string{"Attachments"},
+			Status:  204,
+			Errors:  []int{400, 404, 500},
+		}, h.DeleteAttachment())
+	}
+
+	grp.Post("/attachments/purge", openapi.Op{
+		Summary:     "Purge orphaned attachments",
+		Description: "Remove the files and metadata of attachments whose product has been deleted for longer than `ATTACHMENT_ORPHAN_RETENTION`",
+		Tags:        []string{"System"},
+		Response:    handlers.PurgeAttachmentsResponse{},
+		Errors:      []int{500},
+	}, bulk, h.PurgeOrphanedAttachments())
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) auditRouter(grp *openapi.Router) {
+	repo := postgres.NewAuditRepository(r.app.PostgresConn)
+	s := audit.NewService(repo)
+	h := handlers.NewAuditHandler(s)
+
+	auditRoutes(grp, h)
+}
+
+func auditRoutes(grp *openapi.Router, h *handlers.AuditHandler) {
+	grp.Get("/audit", openapi.Op{
+		Summary:     "Get the audit trail",
+		Description: "List recorded creates, updates, deletes and restores with field-level diffs, most recent first",
+		Tags:        []string{"Audit"},
+		Query: []openapi.Param{
+			{Name: "entity", Description: "Entity type", Required: true, Enum: []any{audit.EntityProduct}},
+			{Name: "id", Description: "Only entries for the entity with this ID"},
+			{Name: "limit", Description: "Maximum number of entries (default 100, at most 1000)", Type: "integer"},
+		},
+		Response: []audit.Entry{},
+		Errors:   []int{400, 500},
+	}, h.GetEntries())
+}
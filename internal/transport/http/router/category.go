@@ -0,0 +1,87 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) categoryRouter(grp *openapi.Router) {
+	repo := postgres.NewCategoryRepository(r.app.PostgresConn)
+	s := category.NewService(repo)
+	h := handlers.NewCategoryHandler(s)
+
+	categoryRoutes(grp, h, r.bulk)
+}
+
+func categoryRoutes(grp *openapi.Router, h *handlers.CategoryHandler, bulk fiber.Handler) {
+	cgrp := grp.Group("/categories")
+
+	{
+		cgrp.Post("/", openapi.Op{
+			Summary:     "Create a category",
+			Description: "Create a category, optionally under a parent category",
+			Tags:        []string{"Categories"},
+			Body:        category.Category{},
+			Status:      201,
+			Response:    category.Category{},
+			Errors:      []int{400, 404, 500},
+		}, h.CreateCategory())
+		cgrp.Get("/", openapi.Op{
+			Summary:     "Get all categories",
+			Description: "Retrieve every category as a flat list, or as a nested hierarchy when `tree=true`",
+			Tags:        []string{"Categories"},
+			Query: []openapi.Param{
+				{Name: "tree", Description: "Nest sub-categories under their parents", Enum: []any{"true", "false"}},
+			},
+			Response: []category.Category{},
+			Errors:   []int{500},
+		}, h.GetAllCategories())
+		cgrp.Get("/:id", openapi.Op{
+			Summary:     "Get category by ID",
+			Description: "Retrieve a category with its direct sub-categories",
+			Tags:        []string{"Categories"},
+			Response:    category.Category{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetCategoryByID())
+		cgrp.Put("/:id", openapi.Op{
+			Summary:     "Update category",
+			Description: "Rename a category or move it under another parent. A null parent moves it to the root.",
+			Tags:        []string{"Categories"},
+			Body:        category.Category{},
+			Response:    category.Category{},
+			Errors:      []int{400, 404, 500},
+		}, h.UpdateCategory())
+		cgrp.Delete("/:id", openapi.Op{
+			Summary:     "Delete category",
+			Description: "Delete a category that has no sub-categories",
+			Tags:        []string{"Categories"},
+			Status:      204,
+			Errors:      []int{400, 404, 422, 500},
+		}, h.DeleteCategory())
+
+		cgrp.Post("/:id/products", openapi.Op{
+			Summary:     "Assign products to a category",
+			Description: "Add products to the category. Products already assigned are left unchanged.",
+			Tags:        []string{"Categories"},
+			Body:        handlers.AssignProductsRequest{},
+			Status:      204,
+			Errors:      []int{400, 404, 500},
+		}, bulk, h.AssignProducts())
+		cgrp.Delete("/:id/products/:productId", openapi.Op{
+			Summary: "Remove a product from a category",
+			Tags:    []string{"Categories"},
+			Status:  204,
+			Errors:  []int{400, 404, 500},
+		}, h.UnassignProduct())
+		cgrp.Get("/:id/stock-summary", openapi.Op{
+			Summary:     "Get category stock summary",
+			Description: "Total units and low-stock count across every product in the category and its sub-categories",
+			Tags:        []string{"Categories"},
+			Response:    category.StockSummary{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetStockSummary())
+	}
+}
@@ -0,0 +1,916 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/attachment"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/category"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/reorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/rma"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/stocktake"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/valuation"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+	"github.com/xxthunderblastxx/ase-challenge/internal/server"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/middleware"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+const missingID = "00000000-0000-0000-0000-000000000000"
+
+// listedID is the ID of the product GetAllProducts lists.
+const listedID = "11111111-1111-1111-1111-111111111111"
+
+// contractProductService is a product.Service returning canned data, with
+// missingID reported as not found. Operations not covered by the contract
+// tests fall through to the nil embedded Service.
+type contractProductService struct {
+	product.Service
+}
+
+func sampleProduct(id string) *product.Product {
+	return &product.Product{
+		BaseModel:        model.BaseModel{ID: uuid.MustParse(id)},
+		Name:             "Widget",
+		Description:      "Test product",
+		StockQuantity:    10,
+		LowStockThresold: 5,
+	}
+}
+
+func (contractProductService) CreateProduct(_ context.Context, p *product.Product) error {
+	if p.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+	p.ID = uuid.New()
+	return nil
+}
+
+func (contractProductService) GetAllProducts(context.Context, product.Filter) ([]product.Product, error) {
+	return []product.Product{*sampleProduct(listedID)}, nil
+}
+
+func (contractProductService) GetProductByID(_ context.Context, id string) (*product.Product, error) {
+	if id == missingID {
+		return nil, apperrors.NewProductNotFoundError(id)
+	}
+	return sampleProduct(id), nil
+}
+
+func (s contractProductService) GetProductAsOf(ctx context.Context, id string, _ time.Time) (*product.Product, error) {
+	return s.GetProductByID(ctx, id)
+}
+
+func (s contractProductService) UpdateProduct(ctx context.Context, id string, _ *product.Product) error {
+	_, err := s.GetProductByID(ctx, id)
+	return err
+}
+
+func (s contractProductService) DeleteProduct(ctx context.Context, id string) error {
+	_, err := s.GetProductByID(ctx, id)
+	return err
+}
+
+func (s contractProductService) IncermentStock(ctx context.Context, id string, _ int) error {
+	_, err := s.GetProductByID(ctx, id)
+	return err
+}
+
+func (s contractProductService) DecrementStock(ctx context.Context, id string, quantity int) error {
+	p, err := s.GetProductByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if quantity > p.StockQuantity {
+		return apperrors.NewInsufficientStockError(p.StockQuantity, quantity)
+	}
+	return nil
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	app := &server.App{
+		App:       fiber.New(),
+		Appconfig: &config.AppConfig{},
+	}
+	r := NewRouter(app)
+	r.RegisterRoutes()
+
+	registered := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		path, ok := strings.CutPrefix(route.Path, "/api/v1")
+		if !ok || route.Method == fiber.MethodHead {
+			continue
+		}
+		key := route.Method + " " + openapi.ToOpenAPIPath(path)
+		registered[key] = true
+
+		item, ok := r.doc.Paths[openapi.ToOpenAPIPath(path)]
+		if !ok || item[strings.ToLower(route.Method)] == nil {
+			t.Errorf("route %s is registered but not documented", key)
+		}
+	}
+
+	for path, item := range r.doc.Paths {
+		for method := range item {
+			key := strings.ToUpper(method) + " " + path
+			if !registered[key] {
+				t.Errorf("operation %s is documented but not registered", key)
+			}
+		}
+	}
+
+	if _, err := json.Marshal(r.doc); err != nil {
+		t.Fatalf("document does not serialize: %v", err)
+	}
+}
+
+type contractCase struct {
+	name   string
+	method string
+	route  string // documented route pattern
+	target string // concrete request path
+	body   string // sent as JSON, or as a multipart form when built by multipartBody
+	status int
+}
+
+func (contractProductService) ListMovements(_ context.Context, filter product.MovementFilter) ([]product.StockMovement, error) {
+	m := product.StockMovement{
+		Quantity:   10,
+		Reason:     product.ReasonOpening,
+		UnitCost:   250,
+		Currency:   "USD",
+		OccurredAt: time.Now(),
+	}
+	if filter.ProductID != "" {
+		m.ProductID = uuid.MustParse(filter.ProductID)
+	}
+	return []product.StockMovement{m}, nil
+}
+
+const contractBoundary = "contract-boundary"
+
+// multipartBody returns a multipart form holding data as the file field.
+func multipartBody(field, filename string, data []byte) string {
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	mw.SetBoundary(contractBoundary)
+	fw, _ := mw.CreateFormFile(field, filename)
+	fw.Write(data)
+	mw.Close()
+	return form.String()
+}
+
+// runContract issues each request and validates the response against the documented schema.
+func runContract(t *testing.T, register func(*openapi.Router), cases []contractCase) {
+	t.Helper()
+
+	doc := newDocument()
+	app := fiber.New(fiber.Config{ErrorHandler: apperrors.ErrorHandler()})
+	register(openapi.NewRouter(app.Group("/api/v1"), doc))
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			if tc.body != "" {
+				body = bytes.NewBufferString(tc.body)
+			}
+
+			req := httptest.NewRequest(tc.method, "/api/v1"+tc.target, body)
+			req.Header.Set("Content-Type", "application/json")
+			if strings.HasPrefix(tc.body, "--"+contractBoundary) {
+				req.Header.Set("Content-Type", "multipart/form-data; boundary="+contractBoundary)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := doc.ValidateResponse(tc.method, tc.route, resp.StatusCode, raw); err != nil {
+				t.Errorf("response violates the documented schema: %v\nbody: %s", err, raw)
+			}
+		})
+	}
+}
+
+func TestProductRoutesContract(t *testing.T) {
+	id := uuid.NewString()
+	h := handlers.NewProductHandler(contractProductService{})
+
+	runContract(t, func(api *openapi.Router) { productRoutes(api, h) }, []contractCase{
+		{"create", "POST", "/products/", "/products", `{"name":"Widget","stock_quantity":1}`, 201},
+		{"create without name", "POST", "/products/", "/products", `{}`, 400},
+		{"list", "GET", "/products/", "/products", "", 200},
+		{"list low stock", "GET", "/products/", "/products?low-stock=true", "", 200},
+		{"get", "GET", "/products/:id", "/products/" + id, "", 200},
+		{"get missing", "GET", "/products/:id", "/products/" + missingID, "", 404},
+		{"update", "PUT", "/products/:id", "/products/" + id, `{"name":"Widget"}`, 200},
+		{"delete", "DELETE", "/products/:id", "/products/" + id, "", 204},
+		{"increment", "POST", "/products/:id/increment-stock", "/products/" + id + "/increment-stock", `{"stock_increment":2}`, 200},
+		{"increment invalid", "POST", "/products/:id/increment-stock", "/products/" + id + "/increment-stock", `{"stock_increment":0}`, 400},
+		{"increment in base units and a unit", "POST", "/products/:id/increment-stock", "/products/" + id + "/increment-stock", `{"stock_increment":2,"quantity":"1","unit":"case"}`, 400},
+		{"decrement", "POST", "/products/:id/decrement-stock", "/products/" + id + "/decrement-stock", `{"stock_decrement":2}`, 200},
+		{"decrement insufficient", "POST", "/products/:id/decrement-stock", "/products/" + id + "/decrement-stock", `{"stock_decrement":99}`, 409},
+		{"decrement in base units and a unit", "POST", "/products/:id/decrement-stock", "/products/" + id + "/decrement-stock", `{"stock_decrement":2,"quantity":"1"}`, 400},
+		{"movements", "GET", "/products/:id/movements", "/products/" + id + "/movements", "", 200},
+		{"movements missing", "GET", "/products/:id/movements", "/products/" + missingID + "/movements", "", 404},
+		{"get as of", "GET", "/products/:id", "/products/" + id + "?as_of=2025-01-31T12:00:00Z", "", 200},
+		{"get invalid as_of", "GET", "/products/:id", "/products/" + id + "?as_of=yesterday", "", 400},
+		{"list invalid as_of", "GET", "/products/", "/products?as_of=yesterday", "", 400},
+	})
+}
+
+// contractValuationRepository values every product at 10 units of 2.50.
+type contractValuationRepository struct{}
+
+func (contractValuationRepository) StockValues(context.Context, time.Time, valuation.Method) (map[uuid.UUID]valuation.StockValue, error) {
+	return map[uuid.UUID]valuation.StockValue{uuid.MustParse(listedID): {Quantity: 10, Value: 2500}}, nil
+}
+
+func TestReportRoutesContract(t *testing.T) {
+	h := handlers.NewReportHandler(valuation.NewService(contractProductService{}, contractValuationRepository{}))
+
+	runContract(t, func(api *openapi.Router) { reportRoutes(api, h) }, []contractCase{
+		{"valuation", "GET", "/reports/valuation", "/reports/valuation", "", 200},
+		{"valuation as of date", "GET", "/reports/valuation", "/reports/valuation?as_of=2025-01-31&method=weighted_average", "", 200},
+		{"valuation invalid as_of", "GET", "/reports/valuation", "/reports/valuation?as_of=yesterday", "", 400},
+		{"valuation invalid method", "GET", "/reports/valuation", "/reports/valuation?method=lifo", "", 400},
+	})
+}
+
+// lockedID names a document whose state rejects every change, for the documented 422s.
+const lockedID = "22222222-2222-2222-2222-222222222222"
+
+// contractFind reports missingID as not found.
+func contractFind(id string, notFound func(string) *apperrors.AppError) error {
+	if id == missingID {
+		return notFound(id)
+	}
+	return nil
+}
+
+// contractChange reports missingID as not found and rejects changes to lockedID.
+func contractChange(id string, notFound func(string) *apperrors.AppError) error {
+	if id == lockedID {
+		return apperrors.NewBusinessLogicError("the document cannot be changed in its current state")
+	}
+	return contractFind(id, notFound)
+}
+
+// contractCategoryService is a category.Service returning canned data.
+type contractCategoryService struct {
+	category.Service
+}
+
+func sampleCategory(id string) *category.Category {
+	return &category.Category{
+		BaseModel: model.BaseModel{ID: uuid.MustParse(id)},
+		Name:      "Shirts",
+	}
+}
+
+func (contractCategoryService) CreateCategory(_ context.Context, c *category.Category) error {
+	if c.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+	c.ID = uuid.New()
+	return nil
+}
+
+func (contractCategoryService) GetAllCategories(context.Context) ([]category.Category, error) {
+	return []category.Category{*sampleCategory(uuid.NewString())}, nil
+}
+
+func (contractCategoryService) GetCategoryTree(context.Context) ([]category.Category, error) {
+	root := sampleCategory(uuid.NewString())
+	root.Children = []category.Category{*sampleCategory(uuid.NewString())}
+	root.Children[0].ParentID = &root.ID
+	return []category.Category{*root}, nil
+}
+
+func (contractCategoryService) GetCategoryByID(_ context.Context, id string) (*category.Category, error) {
+	if err := contractFind(id, apperrors.NewCategoryNotFoundError); err != nil {
+		return nil, err
+	}
+	return sampleCategory(id), nil
+}
+
+func (contractCategoryService) UpdateCategory(_ context.Context, id string, c *category.Category) error {
+	if c.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+	return contractFind(id, apperrors.NewCategoryNotFoundError)
+}
+
+func (contractCategoryService) DeleteCategory(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewCategoryNotFoundError)
+}
+
+func (contractCategoryService) AssignProducts(_ context.Context, id string, productIDs []uuid.UUID) error {
+	if len(productIDs) == 0 {
+		return apperrors.NewMissingRequiredDataError("product_ids")
+	}
+	return contractFind(id, apperrors.NewCategoryNotFoundError)
+}
+
+func (contractCategoryService) UnassignProduct(_ context.Context, id string, _ string) error {
+	return contractFind(id, apperrors.NewCategoryNotFoundError)
+}
+
+func (contractCategoryService) GetStockSummary(_ context.Context, id string) (*category.StockSummary, error) {
+	if err := contractFind(id, apperrors.NewCategoryNotFoundError); err != nil {
+		return nil, err
+	}
+	return &category.StockSummary{ProductCount: 2, TotalUnits: 15, LowStockCount: 1}, nil
+}
+
+func TestCategoryRoutesContract(t *testing.T) {
+	id, productID := uuid.NewString(), uuid.NewString()
+	h := handlers.NewCategoryHandler(contractCategoryService{})
+	pass := func(c *fiber.Ctx) error { return c.Next() }
+
+	runContract(t, func(api *openapi.Router) { categoryRoutes(api, h, pass) }, []contractCase{
+		{"create", "POST", "/categories/", "/categories", `{"name":"Shirts"}`, 201},
+		{"create without name", "POST", "/categories/", "/categories", `{}`, 400},
+		{"list", "GET", "/categories/", "/categories", "", 200},
+		{"tree", "GET", "/categories/", "/categories?tree=true", "", 200},
+		{"get", "GET", "/categories/:id", "/categories/" + id, "", 200},
+		{"get missing", "GET", "/categories/:id", "/categories/" + missingID, "", 404},
+		{"update", "PUT", "/categories/:id", "/categories/" + id, `{"name":"Tops"}`, 200},
+		{"update malformed", "PUT", "/categories/:id", "/categories/" + id, `{`, 400},
+		{"update missing", "PUT", "/categories/:id", "/categories/" + missingID, `{"name":"Tops"}`, 404},
+		{"delete", "DELETE", "/categories/:id", "/categories/" + id, "", 204},
+		{"delete with sub-categories", "DELETE", "/categories/:id", "/categories/" + lockedID, "", 422},
+		{"assign", "POST", "/categories/:id/products", "/categories/" + id + "/products", `{"product_ids":["` + productID + `"]}`, 204},
+		{"assign nothing", "POST", "/categories/:id/products", "/categories/" + id + "/products", `{"product_ids":[]}`, 400},
+		{"unassign", "DELETE", "/categories/:id/products/:productId", "/categories/" + id + "/products/" + productID, "", 204},
+		{"stock summary", "GET", "/categories/:id/stock-summary", "/categories/" + id + "/stock-summary", "", 200},
+		{"stock summary missing", "GET", "/categories/:id/stock-summary", "/categories/" + missingID + "/stock-summary", "", 404},
+	})
+}
+
+func contractMovements(referenceType, id string) []product.StockMovement {
+	return []product.StockMovement{{
+		ProductID:     uuid.New(),
+		Quantity:      5,
+		Reason:        product.ReasonReceipt,
+		UnitCost:      250,
+		Currency:      "USD",
+		ReferenceType: referenceType,
+		ReferenceID:   id,
+		OccurredAt:    time.Now(),
+	}}
+}
+
+// contractSupplierService is a supplier.Service returning canned data. It lists no
+// suppliers, so that reorder suggestions fall back to product lead times.
+type contractSupplierService struct {
+	supplier.Service
+}
+
+func sampleSupplier(id string) *supplier.Supplier {
+	return &supplier.Supplier{
+		BaseModel:    model.BaseModel{ID: uuid.MustParse(id)},
+		Name:         "Acme",
+		Email:        "orders@acme.test",
+		LeadTimeDays: 5,
+	}
+}
+
+func (contractSupplierService) CreateSupplier(_ context.Context, s *supplier.Supplier) error {
+	if s.Name == "" {
+		return apperrors.NewMissingRequiredDataError("name")
+	}
+	s.ID = uuid.New()
+	return nil
+}
+
+func (contractSupplierService) GetAllSuppliers(context.Context) ([]supplier.Supplier, error) {
+	return []supplier.Supplier{}, nil
+}
+
+func (contractSupplierService) GetSupplierByID(_ context.Context, id string) (*supplier.Supplier, error) {
+	if err := contractFind(id, apperrors.NewSupplierNotFoundError); err != nil {
+		return nil, err
+	}
+	return sampleSupplier(id), nil
+}
+
+func (contractSupplierService) UpdateSupplier(_ context.Context, id string, _ *supplier.Supplier) error {
+	return contractFind(id, apperrors.NewSupplierNotFoundError)
+}
+
+func (contractSupplierService) DeleteSupplier(_ context.Context, id string) error {
+	return contractFind(id, apperrors.NewSupplierNotFoundError)
+}
+
+func TestSupplierRoutesContract(t *testing.T) {
+	id := uuid.NewString()
+	h := handlers.NewSupplierHandler(contractSupplierService{})
+
+	runContract(t, func(api *openapi.Router) { supplierRoutes(api, h) }, []contractCase{
+		{"create", "POST", "/suppliers/", "/suppliers", `{"name":"Acme","lead_time_days":5}`, 201},
+		{"create without name", "POST", "/suppliers/", "/suppliers", `{}`, 400},
+		{"list", "GET", "/suppliers/", "/suppliers", "", 200},
+		{"get", "GET", "/suppliers/:id", "/suppliers/" + id, "", 200},
+		{"get missing", "GET", "/suppliers/:id", "/suppliers/" + missingID, "", 404},
+		{"update", "PUT", "/suppliers/:id", "/suppliers/" + id, `{"name":"Acme Ltd"}`, 200},
+		{"update missing", "PUT", "/suppliers/:id", "/suppliers/" + missingID, `{"name":"Acme Ltd"}`, 404},
+		{"delete", "DELETE", "/suppliers/:id", "/suppliers/" + id, "", 204},
+		{"delete missing", "DELETE", "/suppliers/:id", "/suppliers/" + missingID, "", 404},
+	})
+}
+
+// contractPurchaseOrderService is a purchaseorder.Service returning canned data. It lists
+// no orders, so that reorder suggestions see nothing on order, and accepts every draft.
+type contractPurchaseOrderService struct {
+	purchaseorder.Service
+}
+
+func samplePurchaseOrder(id string) *purchaseorder.PurchaseOrder {
+	return &purchaseorder.PurchaseOrder{
+		BaseModel:  model.BaseModel{ID: uuid.MustParse(id)},
+		SupplierID: uuid.New(),
+		Status:     purchaseorder.StatusSent,
+		Currency:   "USD",
+		Lines: []purchaseorder.Line{{
+			BaseModel:        model.BaseModel{ID: uuid.New()},
+			PurchaseOrderID:  uuid.MustParse(id),
+			ProductID:        uuid.New(),
+			QuantityOrdered:  10,
+			QuantityReceived: 4,
+			UnitCost:         250,
+		}},
+	}
+}
+
+func (contractPurchaseOrderService) CreatePurchaseOrder(_ context.Context, po *purchaseorder.PurchaseOrder) error {
+	po.ID = uuid.New()
+	po.Status = purchaseorder.StatusDraft
+	return nil
+}
+
+func (contractPurchaseOrderService) GetAllPurchaseOrders(context.Context, purchaseorder.Filter) ([]purchaseorder.PurchaseOrder, error) {
+	return []purchaseorder.PurchaseOrder{}, nil
+}
+
+func (contractPurchaseOrderService) GetPurchaseOrderByID(_ context.Context, id string) (*purchaseorder.PurchaseOrder, error) {
+	if err := contractFind(id, apperrors.NewPurchaseOrderNotFoundError); err != nil {
+		return nil, err
+	}
+	return samplePurchaseOrder(id), nil
+}
+
+func (contractPurchaseOrderService) UpdatePurchaseOrder(_ context.Context, id string, _ *purchaseorder.PurchaseOrder) error {
+	return contractChange(id, apperrors.NewPurchaseOrderNotFoundError)
+}
+
+func (contractPurchaseOrderService) DeletePurchaseOrder(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewPurchaseOrderNotFoundError)
+}
+
+func (contractPurchaseOrderService) SendPurchaseOrder(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewPurchaseOrderNotFoundError)
+}
+
+func (contractPurchaseOrderService) ReceivePurchaseOrder(_ context.Context, id string, receipt purchaseorder.Receipt) error {
+	if len(receipt.Lines) == 0 && !receipt.Close {
+		return apperrors.NewMissingRequiredDataError("lines")
+	}
+	return contractChange(id, apperrors.NewPurchaseOrderNotFoundError)
+}
+
+func (contractPurchaseOrderService) GetReceiptMovements(_ context.Context, id string) ([]product.StockMovement, error) {
+	if err := contractFind(id, apperrors.NewPurchaseOrderNotFoundError); err != nil {
+		return nil, err
+	}
+	return contractMovements(purchaseorder.ReferenceType, id), nil
+}
+
+func TestPurchaseOrderRoutesContract(t *testing.T) {
+	id := uuid.NewString()
+	h := handlers.NewPurchaseOrderHandler(contractPurchaseOrderService{})
+	receipt := `{"lines":[{"line_id":"` + uuid.NewString() + `","quantity":4}]}`
+	pass := func(c *fiber.Ctx) error { return c.Next() }
+
+	runContract(t, func(api *openapi.Router) { purchaseOrderRoutes(api, h, pass) }, []contractCase{
+		{"create", "POST", "/purchase-orders/", "/purchase-orders", `{"supplier_id":"` + uuid.NewString() + `","lines":[{"product_id":"` + uuid.NewString() + `","quantity_ordered":10}]}`, 201},
+		{"create malformed", "POST", "/purchase-orders/", "/purchase-orders", `{`, 400},
+		{"list", "GET", "/purchase-orders/", "/purchase-orders?status=sent", "", 200},
+		{"get", "GET", "/purchase-orders/:id", "/purchase-orders/" + id, "", 200},
+		{"get missing", "GET", "/purchase-orders/:id", "/purchase-orders/" + missingID, "", 404},
+		{"update", "PUT", "/purchase-orders/:id", "/purchase-orders/" + id, `{"note":"urgent"}`, 200},
+		{"update sent", "PUT", "/purchase-orders/:id", "/purchase-orders/" + lockedID, `{"note":"urgent"}`, 422},
+		{"delete", "DELETE", "/purchase-orders/:id", "/purchase-orders/" + id, "", 204},
+		{"delete sent", "DELETE", "/purchase-orders/:id", "/purchase-orders/" + lockedID, "", 422},
+		{"send", "POST", "/purchase-orders/:id/send", "/purchase-orders/" + id + "/send", "", 200},
+		{"send missing", "POST", "/purchase-orders/:id/send", "/purchase-orders/" + missingID + "/send", "", 404},
+		{"receive", "POST", "/purchase-orders/:id/receive", "/purchase-orders/" + id + "/receive", receipt, 200},
+		{"receive nothing", "POST", "/purchase-orders/:id/receive", "/purchase-orders/" + id + "/receive", `{}`, 400},
+		{"receive draft", "POST", "/purchase-orders/:id/receive", "/purchase-orders/" + lockedID + "/receive", receipt, 422},
+		{"movements", "GET", "/purchase-orders/:id/movements", "/purchase-orders/" + id + "/movements", "", 200},
+		{"movements missing", "GET", "/purchase-orders/:id/movements", "/purchase-orders/" + missingID + "/movements", "", 404},
+	})
+}
+
+func TestReorderRoutesContract(t *testing.T) {
+	s := reorder.NewService(contractProductService{}, contractPurchaseOrderService{}, contractSupplierService{})
+	h := handlers.NewReorderHandler(s)
+	pass := func(c *fiber.Ctx) error { return c.Next() }
+
+	runContract(t, func(api *openapi.Router) { reorderRoutes(api, h, pass) }, []contractCase{
+		{"suggestions", "GET", "/reorder-suggestions", "/reorder-suggestions?days=7&service_level=0.9&all=true", "", 200},
+		{"suggestions invalid days", "GET", "/reorder-suggestions", "/reorder-suggestions?days=week", "", 400},
+		{"suggestions invalid service level", "GET", "/reorder-suggestions", "/reorder-suggestions?service_level=2", "", 400},
+		{"draft orders", "POST", "/reorder-suggestions/purchase-orders", "/reorder-suggestions/purchase-orders", "", 201},
+	})
+}
+
+// contractStockTakeService is a stocktake.Service returning canned data.
+type contractStockTakeService struct {
+	stocktake.Service
+}
+
+func sampleStockTake(id string) *stocktake.Session {
+	counted, variance := 9, -1
+	return &stocktake.Session{
+		BaseModel: model.BaseModel{ID: uuid.MustParse(id)},
+		Name:      "Year end",
+		Status:    stocktake.StatusSubmitted,
+		Lines: []stocktake.Line{{
+			BaseModel:        model.BaseModel{ID: uuid.New()},
+			SessionID:        uuid.MustParse(id),
+			ProductID:        uuid.New(),
+			ExpectedQuantity: 10,
+			CountedQuantity:  &counted,
+			Variance:         &variance,
+			CountedBy:        "sam",
+		}},
+	}
+}
+
+func (contractStockTakeService) CreateStockTake(_ context.Context, scope stocktake.Scope) (*stocktake.Session, error) {
+	if scope.Name == "" {
+		return nil, apperrors.NewMissingRequiredDataError("name")
+	}
+	session := sampleStockTake(uuid.NewString())
+	session.Name, session.Status = scope.Name, stocktake.StatusOpen
+	return session, nil
+}
+
+func (contractStockTakeService) GetAllStockTakes(context.Context, stocktake.Filter) ([]stocktake.Session, error) {
+	return []stocktake.Session{*sampleStockTake(uuid.NewString())}, nil
+}
+
+func (contractStockTakeService) GetStockTakeByID(_ context.Context, id string) (*stocktake.Session, error) {
+	if err := contractFind(id, apperrors.NewStockTakeNotFoundError); err != nil {
+		return nil, err
+	}
+	return sampleStockTake(id), nil
+}
+
+func (contractStockTakeService) RecordCounts(_ context.Context, id string, counts stocktake.Counts) error {
+	if counts.CountedBy == "" {
+		return apperrors.NewMissingRequiredDataError("counted_by")
+	}
+	return contractChange(id, apperrors.NewStockTakeNotFoundError)
+}
+
+func (contractStockTakeService) SubmitStockTake(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewStockTakeNotFoundError)
+}
+
+func (contractStockTakeService) ApproveStockTake(_ context.Context, id string, approval stocktake.Approval) error {
+	if approval.ApprovedBy == "" {
+		return apperrors.NewMissingRequiredDataError("approved_by")
+	}
+	return contractChange(id, apperrors.NewStockTakeNotFoundError)
+}
+
+func (contractStockTakeService) CancelStockTake(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewStockTakeNotFoundError)
+}
+
+func (contractStockTakeService) GetAdjustmentMovements(_ context.Context, id string) ([]product.StockMovement, error) {
+	if err := contractFind(id, apperrors.NewStockTakeNotFoundError); err != nil {
+		return nil, err
+	}
+	m := contractMovements(stocktake.ReferenceType, id)
+	m[0].Quantity, m[0].Reason, m[0].UnitCost = -1, product.ReasonCount, 0
+	return m, nil
+}
+
+func TestStockTakeRoutesContract(t *testing.T) {
+	id := uuid.NewString()
+	h := handlers.NewStockTakeHandler(contractStockTakeService{})
+	counts := `{"counted_by":"sam","lines":[{"line_id":"` + uuid.NewString() + `","quantity":9}]}`
+	pass := func(c *fiber.Ctx) error { return c.Next() }
+
+	runContract(t, func(api *openapi.Router) { stockTakeRoutes(api, h, pass) }, []contractCase{
+		{"create", "POST", "/stock-takes/", "/stock-takes", `{"name":"Year end"}`, 201},
+		{"create without name", "POST", "/stock-takes/", "/stock-takes", `{}`, 400},
+		{"list", "GET", "/stock-takes/", "/stock-takes?status=open", "", 200},
+		{"get", "GET", "/stock-takes/:id", "/stock-takes/" + id, "", 200},
+		{"get missing", "GET", "/stock-takes/:id", "/stock-takes/" + missingID, "", 404},
+		{"counts", "POST", "/stock-takes/:id/counts", "/stock-takes/" + id + "/counts", counts, 200},
+		{"counts without counter", "POST", "/stock-takes/:id/counts", "/stock-takes/" + id + "/counts", `{"lines":[]}`, 400},
+		{"counts closed", "POST", "/stock-takes/:id/counts", "/stock-takes/" + lockedID + "/counts", counts, 422},
+		{"submit", "POST", "/stock-takes/:id/submit", "/stock-takes/" + id + "/submit", "", 200},
+		{"approve", "POST", "/stock-takes/:id/approve", "/stock-takes/" + id + "/approve", `{"approved_by":"kim"}`, 200},
+		{"approve open", "POST", "/stock-takes/:id/approve", "/stock-takes/" + lockedID + "/approve", `{"approved_by":"kim"}`, 422},
+		{"cancel", "POST", "/stock-takes/:id/cancel", "/stock-takes/" + id + "/cancel", "", 200},
+		{"cancel missing", "POST", "/stock-takes/:id/cancel", "/stock-takes/" + missingID + "/cancel", "", 404},
+		{"movements", "GET", "/stock-takes/:id/movements", "/stock-takes/" + id + "/movements", "", 200},
+	})
+}
+
+// contractSalesOrderService is a salesorder.Service returning canned data.
+type contractSalesOrderService struct {
+	salesorder.Service
+}
+
+func sampleSalesOrder(id string) *salesorder.SalesOrder {
+	return &salesorder.SalesOrder{
+		BaseModel: model.BaseModel{ID: uuid.MustParse(id)},
+		Customer:  "Globex",
+		Status:    salesorder.StatusPartiallyFulfilled,
+		Currency:  "USD",
+		Lines: []salesorder.Line{{
+			BaseModel:         model.BaseModel{ID: uuid.New()},
+			SalesOrderID:      uuid.MustParse(id),
+			ProductID:         uuid.New(),
+			QuantityOrdered:   5,
+			QuantityAllocated: 5,
+			QuantityFulfilled: 2,
+			UnitPrice:         900,
+		}},
+	}
+}
+
+func (contractSalesOrderService) CreateSalesOrder(_ context.Context, so *salesorder.SalesOrder) error {
+	if so.Customer == "" {
+		return apperrors.NewMissingRequiredDataError("customer")
+	}
+	so.ID = uuid.New()
+	so.Status = salesorder.StatusPending
+	return nil
+}
+
+func (contractSalesOrderService) GetAllSalesOrders(context.Context, salesorder.Filter) ([]salesorder.SalesOrder, error) {
+	return []salesorder.SalesOrder{*sampleSalesOrder(uuid.NewString())}, nil
+}
+
+func (contractSalesOrderService) GetSalesOrderByID(_ context.Context, id string) (*salesorder.SalesOrder, error) {
+	if err := contractFind(id, apperrors.NewSalesOrderNotFoundError); err != nil {
+		return nil, err
+	}
+	return sampleSalesOrder(id), nil
+}
+
+func (contractSalesOrderService) AllocateSalesOrder(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewSalesOrderNotFoundError)
+}
+
+func (contractSalesOrderService) FulfilSalesOrder(_ context.Context, id string, fulfilment salesorder.Fulfilment) error {
+	for _, l := range fulfilment.Lines {
+		if l.Quantity > 3 {
+			return apperrors.NewInsufficientStockError(3, l.Quantity)
+		}
+	}
+	return contractChange(id, apperrors.NewSalesOrderNotFoundError)
+}
+
+func (contractSalesOrderService) CancelSalesOrder(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewSalesOrderNotFoundError)
+}
+
+func (contractSalesOrderService) GetFulfilmentMovements(_ context.Context, id string) ([]product.StockMovement, error) {
+	if err := contractFind(id, apperrors.NewSalesOrderNotFoundError); err != nil {
+		return nil, err
+	}
+	m := contractMovements(salesorder.ReferenceType, id)
+	m[0].Quantity, m[0].Reason, m[0].UnitCost = -2, product.ReasonSale, 0
+	return m, nil
+}
+
+func TestSalesOrderRoutesContract(t *testing.T) {
+	id := uuid.NewString()
+	h := handlers.NewSalesOrderHandler(contractSalesOrderService{})
+	fulfil := func(quantity string) string {
+		return `{"lines":[{"line_id":"` + uuid.NewString() + `","quantity":` + quantity + `}]}`
+	}
+
+	runContract(t, func(api *openapi.Router) { salesOrderRoutes(api, h) }, []contractCase{
+		{"create", "POST", "/sales-orders/", "/sales-orders", `{"customer":"Globex","lines":[{"product_id":"` + uuid.NewString() + `","quantity_ordered":5}]}`, 201},
+		{"create without customer", "POST", "/sales-orders/", "/sales-orders", `{}`, 400},
+		{"list", "GET", "/sales-orders/", "/sales-orders?customer=Globex", "", 200},
+		{"get", "GET", "/sales-orders/:id", "/sales-orders/" + id, "", 200},
+		{"get missing", "GET", "/sales-orders/:id", "/sales-orders/" + missingID, "", 404},
+		{"allocate", "POST", "/sales-orders/:id/allocate", "/sales-orders/" + id + "/allocate", "", 200},
+		{"allocate cancelled", "POST", "/sales-orders/:id/allocate", "/sales-orders/" + lockedID + "/allocate", "", 422},
+		{"fulfil", "POST", "/sales-orders/:id/fulfil", "/sales-orders/" + id + "/fulfil", fulfil("2"), 200},
+		{"fulfil more than allocated", "POST", "/sales-orders/:id/fulfil", "/sales-orders/" + id + "/fulfil", fulfil("9"), 409},
+		{"fulfil malformed", "POST", "/sales-orders/:id/fulfil", "/sales-orders/" + id + "/fulfil", `{`, 400},
+		{"cancel", "POST", "/sales-orders/:id/cancel", "/sales-orders/" + id + "/cancel", "", 200},
+		{"cancel missing", "POST", "/sales-orders/:id/cancel", "/sales-orders/" + missingID + "/cancel", "", 404},
+		{"movements", "GET", "/sales-orders/:id/movements", "/sales-orders/" + id + "/movements", "", 200},
+	})
+}
+
+// contractReturnService is an rma.Service returning canned data.
+type contractReturnService struct {
+	rma.Service
+}
+
+func sampleReturn(id string) *rma.Return {
+	inspected := time.Now()
+	return &rma.Return{
+		BaseModel: model.BaseModel{ID: uuid.MustParse(id)},
+		Customer:  "Globex",
+		Status:    rma.StatusCompleted,
+		Reason:    "damaged in transit",
+		Lines: []rma.Line{{
+			BaseModel:   model.BaseModel{ID: uuid.New()},
+			ReturnID:    uuid.MustParse(id),
+			ProductID:   uuid.New(),
+			Quantity:    1,
+			Disposition: rma.DispositionQuarantine,
+			InspectedBy: "kim",
+			InspectedAt: &inspected,
+		}},
+	}
+}
+
+func (contractReturnService) CreateReturn(_ context.Context, r *rma.Return) error {
+	if r.Customer == "" {
+		return apperrors.NewMissingRequiredDataError("customer")
+	}
+	if r.SalesOrderID != nil && r.SalesOrderID.String() == lockedID {
+		return apperrors.NewBusinessLogicError("the sales order did not fulfil that many units")
+	}
+	r.ID = uuid.New()
+	r.Status = rma.StatusOpen
+	for i := range r.Lines {
+		r.Lines[i].Disposition = rma.DispositionPending
+	}
+	return nil
+}
+
+func (contractReturnService) GetAllReturns(context.Context, rma.Filter) ([]rma.Return, error) {
+	return []rma.Return{*sampleReturn(uuid.NewString())}, nil
+}
+
+func (contractReturnService) GetReturnByID(_ context.Context, id string) (*rma.Return, error) {
+	if err := contractFind(id, apperrors.NewReturnNotFoundError); err != nil {
+		return nil, err
+	}
+	return sampleReturn(id), nil
+}
+
+func (contractReturnService) InspectReturn(_ context.Context, id string, inspection rma.Inspection) error {
+	if inspection.InspectedBy == "" {
+		return apperrors.NewMissingRequiredDataError("inspected_by")
+	}
+	return contractChange(id, apperrors.NewReturnNotFoundError)
+}
+
+func (contractReturnService) CancelReturn(_ context.Context, id string) error {
+	return contractChange(id, apperrors.NewReturnNotFoundError)
+}
+
+func (contractReturnService) GetDispositionMovements(_ context.Context, id string) ([]product.StockMovement, error) {
+	if err := contractFind(id, apperrors.NewReturnNotFoundError); err != nil {
+		return nil, err
+	}
+	m := contractMovements(rma.ReferenceType, id)
+	m[0].Quantity, m[0].Reason = 1, product.ReasonReturn
+	return m, nil
+}
+
+func TestReturnRoutesContract(t *testing.T) {
+	id := uuid.NewString()
+	h := handlers.NewReturnHandler(contractReturnService{})
+	newReturn := func(salesOrderID string) string {
+		return `{"customer":"Globex","sales_order_id":"` + salesOrderID + `","lines":[{"product_id":"` + uuid.NewString() + `","quantity":1}]}`
+	}
+	inspection := `{"inspected_by":"kim","lines":[{"line_id":"` + uuid.NewString() + `","disposition":"quarantine"}]}`
+
+	runContract(t, func(api *openapi.Router) { returnRoutes(api, h) }, []contractCase{
+		{"create", "POST", "/returns/", "/returns", newReturn(uuid.NewString()), 201},
+		{"create without customer", "POST", "/returns/", "/returns", `{}`, 400},
+		{"create beyond fulfilled", "POST", "/returns/", "/returns", newReturn(lockedID), 422},
+		{"list", "GET", "/returns/", "/returns?status=completed", "", 200},
+		{"get", "GET", "/returns/:id", "/returns/" + id, "", 200},
+		{"get missing", "GET", "/returns/:id", "/returns/" + missingID, "", 404},
+		{"inspect", "POST", "/returns/:id/inspect", "/returns/" + id + "/inspect", inspection, 200},
+		{"inspect without inspector", "POST", "/returns/:id/inspect", "/returns/" + id + "/inspect", `{"lines":[]}`, 400},
+		{"inspect cancelled", "POST", "/returns/:id/inspect", "/returns/" + lockedID + "/inspect", inspection, 422},
+		{"cancel", "POST", "/returns/:id/cancel", "/returns/" + id + "/cancel", "", 200},
+		{"cancel missing", "POST", "/returns/:id/cancel", "/returns/" + missingID + "/cancel", "", 404},
+		{"movements", "GET", "/returns/:id/movements", "/returns/" + id + "/movements", "", 200},
+	})
+}
+
+// contractAttachmentService is an attachment.Service returning canned data.
+type contractAttachmentService struct {
+	attachment.Service
+}
+
+func sampleAttachment(productID, id string) *attachment.Attachment {
+	return &attachment.Attachment{
+		BaseModel:    model.BaseModel{ID: uuid.MustParse(id)},
+		ProductID:    uuid.MustParse(productID),
+		Filename:     "photo.png",
+		ContentType:  "image/png",
+		Size:         4,
+		Kind:         attachment.KindImage,
+		HasThumbnail: true,
+	}
+}
+
+func (contractAttachmentService) UploadAttachment(_ context.Context, productID string, upload attachment.Upload) (*attachment.Attachment, error) {
+	if err := contractFind(productID, apperrors.NewProductNotFoundError); err != nil {
+		return nil, err
+	}
+	a := sampleAttachment(productID, uuid.NewString())
+	a.Filename, a.Size = upload.Filename, int64(len(upload.Data))
+	return a, nil
+}
+
+func (contractAttachmentService) GetAttachments(_ context.Context, productID string) ([]attachment.Attachment, error) {
+	if err := contractFind(productID, apperrors.NewProductNotFoundError); err != nil {
+		return nil, err
+	}
+	return []attachment.Attachment{*sampleAttachment(productID, uuid.NewString())}, nil
+}
+
+func (contractAttachmentService) GetAttachmentByID(_ context.Context, productID string, id string) (*attachment.Attachment, error) {
+	if err := contractFind(id, apperrors.NewAttachmentNotFoundError); err != nil {
+		return nil, err
+	}
+	return sampleAttachment(productID, id), nil
+}
+
+func (contractAttachmentService) OpenAttachment(_ context.Context, productID string, id string, _ bool) (*attachment.Attachment, io.ReadCloser, error) {
+	if err := contractFind(id, apperrors.NewAttachmentNotFoundError); err != nil {
+		return nil, nil, err
+	}
+	return sampleAttachment(productID, id), io.NopCloser(strings.NewReader("\x89PNG")), nil
+}
+
+func (contractAttachmentService) DeleteAttachment(_ context.Context, _ string, id string) error {
+	return contractFind(id, apperrors.NewAttachmentNotFoundError)
+}
+
+func (contractAttachmentService) PurgeOrphaned(context.Context) (int, error) {
+	return 2, nil
+}
+
+func TestAttachmentRoutesContract(t *testing.T) {
+	id, attachmentID := uuid.NewString(), uuid.NewString()
+	h := handlers.NewAttachmentHandler(contractAttachmentService{})
+	base := "/products/" + id + "/attachments/"
+	pass := func(c *fiber.Ctx) error { return c.Next() }
+
+	runContract(t, func(api *openapi.Router) { attachmentRoutes(api, h, pass, middleware.BodyLimit(1024, nil)) }, []contractCase{
+		{"upload", "POST", "/products/:id/attachments/", base, multipartBody(handlers.AttachmentField, "photo.png", []byte("\x89PNG")), 201},
+		{"upload without file", "POST", "/products/:id/attachments/", base, multipartBody("other", "photo.png", []byte("\x89PNG")), 400},
+		{"upload over the limit", "POST", "/products/:id/attachments/", base, multipartBody(handlers.AttachmentField, "scan.pdf", bytes.Repeat([]byte("x"), 2048)), 413},
+		{"upload for missing product", "POST", "/products/:id/attachments/", "/products/" + missingID + "/attachments/", multipartBody(handlers.AttachmentField, "photo.png", []byte("\x89PNG")), 404},
+		{"list", "GET", "/products/:id/attachments/", base, "", 200},
+		{"list missing product", "GET", "/products/:id/attachments/", "/products/" + missingID + "/attachments/", "", 404},
+		{"get", "GET", "/products/:id/attachments/:attachmentId", base + attachmentID, "", 200},
+		{"get missing", "GET", "/products/:id/attachments/:attachmentId", base + missingID, "", 404},
+		{"content", "GET", "/products/:id/attachments/:attachmentId/content", base + attachmentID + "/content", "", 200},
+		{"thumbnail", "GET", "/products/:id/attachments/:attachmentId/thumbnail", base + attachmentID + "/thumbnail", "", 200},
+		{"thumbnail missing", "GET", "/products/:id/attachments/:attachmentId/thumbnail", base + missingID + "/thumbnail", "", 404},
+		{"delete", "DELETE", "/products/:id/attachments/:attachmentId", base + attachmentID, "", 204},
+		{"delete missing", "DELETE", "/products/:id/attachments/:attachmentId", base + missingID, "", 404},
+		{"purge", "POST", "/attachments/purge", "/attachments/purge", "", 200},
+	})
+}
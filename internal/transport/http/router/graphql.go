@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/gql"
+)
+
+func (r *Router) graphqlRouter(middleware []fiber.Handler) {
+	s := r.productService(r.app.PostgresConn)
+	h := gql.NewHandler(s, r.app.Appconfig.GraphQLConfig)
+
+	r.app.Post("/graphql", append(middleware, h.Query())...)
+}
@@ -1,27 +1,237 @@
 package router
 
 import (
-	"github.com/gofiber/fiber/v2"
 	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
-	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
 	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
 )
 
-func (r *Router) productRouter(grp fiber.Router) {
-	pgrp := grp.Group("/products")
-
-	repo := postgres.NewProductRepository(r.app.PostgresConn)
-	s := product.NewService(repo)
+func (r *Router) productRouter(grp *openapi.Router) {
+	s := r.productService(r.app.PostgresConn)
 	h := handlers.NewProductHandler(s)
 
+	productRoutes(grp, h)
+}
+
+func productRoutes(grp *openapi.Router, h *handlers.ProductHandler) {
+	pgrp := grp.Group("/products")
+	lgrp := grp.Group("/lots")
+
 	{
-		pgrp.Post("/", h.CreateProduct())
-		pgrp.Get("/", h.GetAllProducts())
-		pgrp.Get("/:id", h.GetProductByID())
-		pgrp.Put("/:id", h.UpdateProduct())
-		pgrp.Delete("/:id", h.DeleteProduct())
-
-		pgrp.Post("/:id/increment-stock", h.IncrementStock())
-		pgrp.Post("/:id/decrement-stock", h.DecrementStock())
+		pgrp.Post("/", openapi.Op{
+			Summary:     "Create a new product",
+			Description: "Create a new product in the inventory",
+			Tags:        []string{"Products"},
+			Body:        product.Product{},
+			Status:      201,
+			Response:    product.Product{},
+			Errors:      []int{400, 500},
+		}, h.CreateProduct())
+		pgrp.Get("/", openapi.Op{
+			Summary:     "Get all products",
+			Description: "Retrieve all products, optionally filtered by category, attributes and tags. When `low-stock=true` is provided, returns only products where `stock_quantity <= low_stock_threshold`.",
+			Tags:        []string{"Products"},
+			Query: []openapi.Param{
+				{Name: "low-stock", Description: "Filter products with low stock", Enum: []any{"true", "false"}},
+				{Name: "category", Description: "Only products assigned to this category ID"},
+				{Name: "include-descendants", Description: "Also match products in sub-categories of `category` (default true)", Enum: []any{"true", "false"}},
+				{Name: "attr.{name}", Description: "Only products whose attribute `name` has this value, e.g. `attr.color=red`; may be repeated for different attributes"},
+				{Name: "tag", Description: "Only products carrying every one of these comma-separated tags, e.g. `tag=clearance`"},
+				{Name: "as_of", Description: "List products as they were at this RFC 3339 time, with stock replayed from the ledger, e.g. `2024-05-01T12:00:00Z`"},
+			},
+			Response: []product.Product{},
+			Errors:   []int{400, 500},
+		}, h.GetAllProducts())
+		pgrp.Get("/search", openapi.Op{
+			Summary:     "Search products",
+			Description: "Full-text search over product names and descriptions, with trigram similarity on names, descriptions and variant SKUs so that misspelt terms still match. Results are ranked by relevance, with matched words wrapped in `<mark>` tags in `highlights`; the rest of the text is HTML-escaped.",
+			Tags:        []string{"Products"},
+			Query: []openapi.Param{
+				{Name: "q", Description: "Search text; supports quoted phrases, `or` and `-` exclusions"},
+				{Name: "limit", Description: "Page size, 1 to 100 (default 20)"},
+				{Name: "offset", Description: "Matches to skip (default 0)"},
+			},
+			Response: product.SearchPage{},
+			Errors:   []int{400, 500},
+		}, h.SearchProducts())
+		pgrp.Get("/:id", openapi.Op{
+			Summary:     "Get product by ID",
+			Description: "Retrieve a specific product by its ID, including its option axes and variants with per-variant stock. With `as_of`, the product is returned as it was at that time, with stock replayed from the ledger and without associations.",
+			Tags:        []string{"Products"},
+			Query: []openapi.Param{
+				{Name: "as_of", Description: "RFC 3339 time to return the product as of, e.g. `2024-05-01T12:00:00Z`"},
+			},
+			Response: product.Product{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetProductByID())
+		pgrp.Put("/:id", openapi.Op{
+			Summary:     "Update product",
+			Description: "Update an existing product's information. Fields the body omits keep their current values. Stock levels are not changed here; use the stock endpoints, which record every change in the ledger.",
+			Tags:        []string{"Products"},
+			Body:        product.Product{},
+			Response:    product.Product{},
+			Errors:      []int{400, 404, 500},
+		}, h.UpdateProduct())
+		pgrp.Delete("/:id", openapi.Op{
+			Summary:     "Delete product",
+			Description: "Delete a product from the inventory. Products that are still kit components cannot be deleted.",
+			Tags:        []string{"Products"},
+			Status:      204,
+			Errors:      []int{400, 404, 422, 500},
+		}, h.DeleteProduct())
+		pgrp.Post("/:id/restore", openapi.Op{
+			Summary:     "Restore a deleted product",
+			Description: "Undo the deletion of a product",
+			Tags:        []string{"Products"},
+			Response:    product.Product{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.RestoreProduct())
+
+		pgrp.Post("/:id/increment-stock", openapi.Op{
+			Summary:     "Increment product stock",
+			Description: "Increase the stock quantity of a product. Serialized products must list one new serial number per unit.",
+			Tags:        []string{"Stock"},
+			Body:        handlers.StockIncrementRequest{},
+			Response:    handlers.StockIncrementResponse{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.IncrementStock())
+		pgrp.Post("/:id/decrement-stock", openapi.Op{
+			Summary:     "Decrement product stock",
+			Description: "Decrease the stock quantity of a product. Serialized products must list the serial numbers leaving stock.",
+			Tags:        []string{"Stock"},
+			Body:        handlers.StockDecrementRequest{},
+			Response:    handlers.StockDecrementResponse{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.DecrementStock())
+		pgrp.Get("/:id/movements", openapi.Op{
+			Summary:     "Get stock movements",
+			Description: "List the product's stock ledger in the order the movements occurred",
+			Tags:        []string{"Stock"},
+			Response:    []product.StockMovement{},
+			Errors:      []int{400, 404, 500},
+		}, h.ListMovements())
+
+		pgrp.Get("/:id/lots", openapi.Op{
+			Summary:     "Get lots",
+			Description: "List a lot-tracked product's lots, earliest expiry first",
+			Tags:        []string{"Lots"},
+			Response:    []product.Lot{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetLots())
+		pgrp.Post("/:id/lots", openapi.Op{
+			Summary:     "Receive stock into a lot",
+			Description: "Increment a lot-tracked product's stock, creating the lot if it does not exist yet. Returns the product's lots.",
+			Tags:        []string{"Lots", "Stock"},
+			Body:        product.LotReceipt{},
+			Status:      201,
+			Response:    []product.Lot{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.ReceiveLot())
+		pgrp.Get("/:id/stock-buckets", openapi.Op{
+			Summary:     "Get stock buckets",
+			Description: "Get the stock of the product, or of each of its variants, per bucket. Only available units can be sold and count toward low-stock checks.",
+			Tags:        []string{"Stock"},
+			Response:    []product.BucketLevels{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetStockBuckets())
+		pgrp.Post("/:id/stock-buckets/move", openapi.Op{
+			Summary:     "Move stock between buckets",
+			Description: "Move units between the available, quarantined, damaged and in-transit buckets. Moves out of or into available stock change `stock_quantity` and are posted as `transfer` movements naming the other bucket. Returns the product's buckets.",
+			Tags:        []string{"Stock"},
+			Body:        product.BucketMove{},
+			Response:    []product.BucketLevels{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.MoveStock())
+		pgrp.Get("/:id/serials", openapi.Op{
+			Summary:     "Get serials",
+			Description: "List a serialized product's serial numbers",
+			Tags:        []string{"Serials"},
+			Query: []openapi.Param{
+				{Name: "in-stock", Description: "Only serials currently in stock", Enum: []any{"true", "false"}},
+			},
+			Response: []product.Serial{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetSerials())
+		pgrp.Get("/:id/serials/:serial", openapi.Op{
+			Summary:     "Get serial history",
+			Description: "Get a serial number with every stock movement that named it",
+			Tags:        []string{"Serials", "Stock"},
+			Response:    product.SerialHistory{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetSerialHistory())
+		lgrp.Get("/expiring", openapi.Op{
+			Summary:     "Get expiring lots",
+			Description: "List lots still holding stock that expire within `days`, including lots that have already expired. Expired lots cannot be sold from.",
+			Tags:        []string{"Lots"},
+			Query: []openapi.Param{
+				{Name: "days", Description: "Look-ahead in days (default 30)"},
+			},
+			Response: []product.Lot{},
+			Errors:   []int{400, 500},
+		}, h.GetExpiringLots())
+
+		pgrp.Put("/:id/units", openapi.Op{
+			Summary:     "Set units of measure",
+			Description: "Replace the units the product's stock can be given in besides its base unit, each with the number of base units it holds. Stock operations accept `quantity` and `unit` in place of a bare count; the quantity is converted exactly and must come to a whole number of base units, so weighable items should use a fine base unit such as g.",
+			Tags:        []string{"Products"},
+			Body:        handlers.SetUnitsRequest{},
+			Response:    product.Product{},
+			Errors:      []int{400, 404, 500},
+		}, h.SetUnits())
+		pgrp.Put("/:id/components", openapi.Op{
+			Summary:     "Set kit components",
+			Description: "Make the product a kit of the given component products and quantities, replacing any components it had; an empty list makes it an ordinary product again. A kit holds no stock of its own: its stock_quantity is the number of kits its components' unreserved stock can make, and decrementing a kit decrements every component atomically or fails naming the short component.",
+			Tags:        []string{"Kits"},
+			Body:        handlers.SetKitComponentsRequest{},
+			Response:    product.Product{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.SetKitComponents())
+		pgrp.Put("/:id/options", openapi.Op{
+			Summary:     "Set option axes",
+			Description: "Replace the option axes (e.g. size, colour) and their allowed values. Only allowed before any variant exists.",
+			Tags:        []string{"Variants"},
+			Body:        handlers.SetOptionsRequest{},
+			Response:    product.Product{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.SetOptions())
+		pgrp.Post("/:id/variants", openapi.Op{
+			Summary:     "Create a variant",
+			Description: "Add a variant with one value for each option axis",
+			Tags:        []string{"Variants"},
+			Body:        product.Variant{},
+			Status:      201,
+			Response:    product.Variant{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.CreateVariant())
+		pgrp.Put("/:id/variants/:variantId", openapi.Op{
+			Summary:     "Update a variant",
+			Description: "Update a variant's SKU, options and low-stock threshold. Stock is changed through the stock operations.",
+			Tags:        []string{"Variants"},
+			Body:        product.Variant{},
+			Response:    product.Product{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.UpdateVariant())
+		pgrp.Delete("/:id/variants/:variantId", openapi.Op{
+			Summary: "Delete a variant",
+			Tags:    []string{"Variants"},
+			Status:  204,
+			Errors:  []int{400, 404, 500},
+		}, h.DeleteVariant())
+		pgrp.Post("/:id/variants/:variantId/increment-stock", openapi.Op{
+			Summary:     "Increment variant stock",
+			Description: "Increase the stock of a variant; the product's stock is the sum of its variants",
+			Tags:        []string{"Stock", "Variants"},
+			Body:        handlers.StockIncrementRequest{},
+			Response:    handlers.StockIncrementResponse{},
+			Errors:      []int{400, 404, 500},
+		}, h.IncrementVariantStock())
+		pgrp.Post("/:id/variants/:variantId/decrement-stock", openapi.Op{
+			Summary:     "Decrement variant stock",
+			Description: "Decrease the stock of a variant; the product's stock is the sum of its variants",
+			Tags:        []string{"Stock", "Variants"},
+			Body:        handlers.StockDecrementRequest{},
+			Response:    handlers.StockDecrementResponse{},
+			Errors:      []int{400, 404, 409, 500},
+		}, h.DecrementVariantStock())
 	}
 }
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) purchaseOrderRouter(grp *openapi.Router) {
+	conn := r.app.PostgresConn
+
+	// Receiving posts stock through a product service bound to the same transaction
+	tx := func(ctx context.Context, fn func(purchaseorder.Repository, product.Service) error) error {
+		return conn.Transaction(ctx, func(tx *postgres.ConnectionManager) error {
+			return fn(postgres.NewPurchaseOrderRepository(tx), r.productService(tx))
+		})
+	}
+
+	s := purchaseorder.NewService(
+		postgres.NewPurchaseOrderRepository(conn),
+		r.productService(conn),
+		supplier.NewService(postgres.NewSupplierRepository(conn)),
+		tx,
+	)
+	h := handlers.NewPurchaseOrderHandler(s)
+
+	purchaseOrderRoutes(grp, h, r.bulk)
+}
+
+func purchaseOrderRoutes(grp *openapi.Router, h *handlers.PurchaseOrderHandler, bulk fiber.Handler) {
+	pgrp := grp.Group("/purchase-orders")
+
+	{
+		pgrp.Post("/", openapi.Op{
+			Summary:     "Create a purchase order",
+			Description: "Create a draft purchase order. Line costs default to the products' unit cost.",
+			Tags:        []string{"Purchase Orders"},
+			Body:        purchaseorder.PurchaseOrder{},
+			Status:      201,
+			Response:    purchaseorder.PurchaseOrder{},
+			Errors:      []int{400, 404, 500},
+		}, h.CreatePurchaseOrder())
+		pgrp.Get("/", openapi.Op{
+			Summary: "Get all purchase orders",
+			Tags:    []string{"Purchase Orders"},
+			Query: []openapi.Param{
+				{Name: "supplier", Description: "Only orders from this supplier ID"},
+				{Name: "status", Description: "Only orders in this status", Enum: []any{"draft", "sent", "partially_received", "received"}},
+			},
+			Response: []purchaseorder.PurchaseOrder{},
+			Errors:   []int{500},
+		}, h.GetAllPurchaseOrders())
+		pgrp.Get("/:id", openapi.Op{
+			Summary:  "Get purchase order by ID",
+			Tags:     []string{"Purchase Orders"},
+			Response: purchaseorder.PurchaseOrder{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetPurchaseOrderByID())
+		pgrp.Put("/:id", openapi.Op{
+			Summary:     "Update purchase order",
+			Description: "Replace a draft order's supplier, details and lines",
+			Tags:        []string{"Purchase Orders"},
+			Body:        purchaseorder.PurchaseOrder{},
+			Response:    purchaseorder.PurchaseOrder{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.UpdatePurchaseOrder())
+		pgrp.Delete("/:id", openapi.Op{
+			Summary: "Delete a draft purchase order",
+			Tags:    []string{"Purchase Orders"},
+			Status:  204,
+			Errors:  []int{400, 404, 422, 500},
+		}, h.DeletePurchaseOrder())
+
+		pgrp.Post("/:id/send", openapi.Op{
+			Summary:     "Send purchase order",
+			Description: "Mark a draft order as sent to the supplier",
+			Tags:        []string{"Purchase Orders"},
+			Response:    purchaseorder.PurchaseOrder{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.SendPurchaseOrder())
+		pgrp.Post("/:id/receive", openapi.Op{
+			Summary:     "Receive goods",
+			Description: "Post received quantities as stock increments linked to the order. Quantities may exceed what is outstanding; set `close` to finish an order delivered short.",
+			Tags:        []string{"Purchase Orders", "Stock"},
+			Body:        purchaseorder.Receipt{},
+			Response:    purchaseorder.PurchaseOrder{},
+			Errors:      []int{400, 404, 422, 500},
+		}, bulk, h.ReceivePurchaseOrder())
+		pgrp.Get("/:id/movements", openapi.Op{
+			Summary:     "Get receipt movements",
+			Description: "List the stock movements posted by receiving this order",
+			Tags:        []string{"Purchase Orders", "Stock"},
+			Response:    []product.StockMovement{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetReceiptMovements())
+	}
+}
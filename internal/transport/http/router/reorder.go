@@ -0,0 +1,64 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/purchaseorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/reorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) reorderRouter(grp *openapi.Router) {
+	conn := r.app.PostgresConn
+
+	tx := func(ctx context.Context, fn func(purchaseorder.Repository, product.Service) error) error {
+		return conn.Transaction(ctx, func(tx *postgres.ConnectionManager) error {
+			return fn(postgres.NewPurchaseOrderRepository(tx), r.productService(tx))
+		})
+	}
+
+	products := r.productService(conn)
+	suppliers := supplier.NewService(postgres.NewSupplierRepository(conn))
+	orders := purchaseorder.NewService(postgres.NewPurchaseOrderRepository(conn), products, suppliers, tx)
+
+	h := handlers.NewReorderHandler(reorder.NewService(products, orders, suppliers))
+
+	reorderRoutes(grp, h, r.bulk)
+}
+
+func reorderRoutes(grp *openapi.Router, h *handlers.ReorderHandler, bulk fiber.Handler) {
+	rgrp := grp.Group("/reorder-suggestions")
+
+	params := []openapi.Param{
+		{Name: "days", Description: "Days of decrement history used to measure consumption (default 30)"},
+		{Name: "service_level", Description: "Probability of not running out during the lead time, between 0 and 1 (default 0.95)"},
+		{Name: "cover_days", Description: "Days of consumption an order covers beyond the lead time (default 14)"},
+	}
+
+	{
+		rgrp.Get("/", openapi.Op{
+			Summary:     "Get reorder suggestions",
+			Description: "Compute each product's reorder point and quantity from its average daily consumption, lead time and a safety stock for the requested service level. Only products at or below their reorder point are listed unless `all` is set.",
+			Tags:        []string{"Reorder"},
+			Query: append(params,
+				openapi.Param{Name: "all", Description: "Include products that do not need reordering yet", Enum: []any{"true", "false"}},
+			),
+			Response: []reorder.Suggestion{},
+			Errors:   []int{400, 500},
+		}, h.GetSuggestions())
+		rgrp.Post("/purchase-orders", openapi.Op{
+			Summary:     "Create draft purchase orders",
+			Description: "Put every product that needs reordering on a draft purchase order to its preferred supplier, one order per supplier. Products without a supplier or with variants are reported as skipped.",
+			Tags:        []string{"Reorder", "Purchase Orders"},
+			Query:       params,
+			Status:      201,
+			Response:    reorder.DraftOrders{},
+			Errors:      []int{400, 500},
+		}, bulk, h.CreateDraftOrders())
+	}
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/valuation"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) reportRouter(grp *openapi.Router) {
+	repo := postgres.NewProductRepository(r.app.PostgresConn)
+	v := valuation.NewService(product.NewService(repo), postgres.NewValuationRepository(r.app.PostgresConn))
+	h := handlers.NewReportHandler(v)
+
+	reportRoutes(grp, h)
+}
+
+func reportRoutes(grp *openapi.Router, h *handlers.ReportHandler) {
+	rgrp := grp.Group("/reports")
+
+	{
+		rgrp.Get("/valuation", openapi.Op{
+			Summary:     "Get inventory valuation",
+			Description: "Value the stock on hand per product and in total per currency by replaying the stock ledger up to `as_of`.",
+			Tags:        []string{"Reports"},
+			Query: []openapi.Param{
+				{Name: "as_of", Description: "RFC 3339 timestamp, or a date to value stock at the end of that day (UTC). Defaults to now."},
+				{Name: "method", Description: "Costing method (default fifo)", Enum: []any{"fifo", "weighted_average"}},
+			},
+			Response: valuation.Report{},
+			Errors:   []int{400, 500},
+		}, h.GetValuation())
+	}
+}
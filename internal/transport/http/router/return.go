@@ -0,0 +1,92 @@
+package router
+
+import (
+	"context"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/rma"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) returnRouter(grp *openapi.Router) {
+	conn := r.app.PostgresConn
+
+	// Inspection posts dispositions through a product service bound to the same transaction
+	tx := func(ctx context.Context, fn func(rma.Repository, product.Service) error) error {
+		return conn.Transaction(ctx, func(tx *postgres.ConnectionManager) error {
+			return fn(postgres.NewReturnRepository(tx), r.productService(tx))
+		})
+	}
+
+	orderTx := func(ctx context.Context, fn func(salesorder.Repository, product.Service) error) error {
+		return conn.Transaction(ctx, func(tx *postgres.ConnectionManager) error {
+			return fn(postgres.NewSalesOrderRepository(tx), r.productService(tx))
+		})
+	}
+
+	products := r.productService(conn)
+	orders := salesorder.NewService(postgres.NewSalesOrderRepository(conn), products, orderTx)
+
+	h := handlers.NewReturnHandler(rma.NewService(postgres.NewReturnRepository(conn), products, orders, tx))
+
+	returnRoutes(grp, h)
+}
+
+func returnRoutes(grp *openapi.Router, h *handlers.ReturnHandler) {
+	rgrp := grp.Group("/returns")
+
+	{
+		rgrp.Post("/", openapi.Op{
+			Summary:     "Open a return",
+			Description: "Open a return against a sales order or for products directly. Returns against an order cannot exceed what it fulfilled, less earlier returns.",
+			Tags:        []string{"Returns"},
+			Body:        rma.Return{},
+			Status:      201,
+			Response:    rma.Return{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.CreateReturn())
+		rgrp.Get("/", openapi.Op{
+			Summary: "Get all returns",
+			Tags:    []string{"Returns"},
+			Query: []openapi.Param{
+				{Name: "sales_order_id", Description: "Only returns against this sales order"},
+				{Name: "status", Description: "Only returns in this status", Enum: []any{"open", "completed", "cancelled"}},
+			},
+			Response: []rma.Return{},
+			Errors:   []int{500},
+		}, h.GetAllReturns())
+		rgrp.Get("/:id", openapi.Op{
+			Summary:     "Get return by ID",
+			Description: "Get a return with its lines and their dispositions",
+			Tags:        []string{"Returns"},
+			Response:    rma.Return{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetReturnByID())
+
+		rgrp.Post("/:id/inspect", openapi.Op{
+			Summary:     "Inspect return lines",
+			Description: "Choose a disposition per line. Every line is posted as a `return` movement; quarantined units then move to the quarantined bucket and scrapped units are written off, so only restocked units increase the available stock.",
+			Tags:        []string{"Returns", "Stock"},
+			Body:        rma.Inspection{},
+			Response:    rma.Return{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.InspectReturn())
+		rgrp.Post("/:id/cancel", openapi.Op{
+			Summary:     "Cancel return",
+			Description: "Cancel an open return none of whose lines has been inspected",
+			Tags:        []string{"Returns"},
+			Response:    rma.Return{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.CancelReturn())
+		rgrp.Get("/:id/movements", openapi.Op{
+			Summary:     "Get disposition movements",
+			Description: "List the stock movements posted by inspecting this return",
+			Tags:        []string{"Returns", "Stock"},
+			Response:    []product.StockMovement{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetDispositionMovements())
+	}
+}
@@ -5,29 +5,95 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/watchakorn-18k/scalar-go"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/ratelimit"
 	"github.com/xxthunderblastxx/ase-challenge/internal/server"
 	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/middleware"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
 )
 
 type Router struct {
 	app *server.App
+
+	// doc is the OpenAPI document built from the registered routes
+	doc *openapi.Document
+
+	// bulk applies the stricter bulk-endpoint rate limit to a route
+	bulk fiber.Handler
 }
 
 func NewRouter(s *server.App) *Router {
 	return &Router{
-		app: s,
+		app:  s,
+		doc:  newDocument(),
+		bulk: func(c *fiber.Ctx) error { return c.Next() },
 	}
 }
 
+// productService returns a product service over conn that publishes the stock changes it
+// commits to the server's event bus.
+func (r *Router) productService(conn *postgres.ConnectionManager) product.Service {
+	return product.NewServiceWithEvents(postgres.NewProductRepository(conn), r.app.Events)
+}
+
+func newDocument() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Product Inventory Management API",
+		Description: "A RESTful API service for managing product inventory with stock tracking and low-stock monitoring capabilities.",
+		Version:     "0.1.0",
+	}, "/api/v1")
+
+	doc.AddTag("Products", "Product management operations")
+	doc.AddTag("Stock", "Stock management operations")
+	doc.AddTag("Variants", "Product variants and their option axes")
+	doc.AddTag("Lots", "Lot and expiry tracking")
+	doc.AddTag("Serials", "Serial-number tracking")
+	doc.AddTag("Kits", "Kits made of component products")
+	doc.AddTag("Attachments", "Product images and documents")
+	doc.AddTag("Categories", "Category hierarchy and product assignment")
+	doc.AddTag("Suppliers", "Supplier management")
+	doc.AddTag("Purchase Orders", "Purchase orders and goods receipt")
+	doc.AddTag("Sales Orders", "Sales orders with stock allocation and fulfilment")
+	doc.AddTag("Returns", "Customer returns, inspection and disposition")
+	doc.AddTag("Stock Takes", "Stock-take sessions and variance reconciliation")
+	doc.AddTag("Reorder", "Reorder suggestions from consumption history")
+	doc.AddTag("Reports", "Inventory reports")
+	doc.AddTag("Audit", "Audit trail of changes")
+	doc.AddTag("Streams", "Real-time stock events")
+	doc.AddTag("System", "System and maintenance operations")
+
+	return doc
+}
+
 func (r *Router) RegisterRoutes() {
 	// Middleware
-	r.app.Use(cors.New())
+	r.app.Use(cors.New(cors.Config{
+		ExposeHeaders: "RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After,X-Request-ID",
+	}))
 	r.app.Use(recover.New())
 
+	// Attribute changes to the actor and request for the audit trail
+	r.app.Use(middleware.AuditContext())
+
+	// Let read-only requests be served by the read replica
+	r.app.Use(func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			c.SetUserContext(postgres.WithReadOnly(c.UserContext()))
+		}
+		return c.Next()
+	})
+
+	// API specification, generated from the routes registered below
+	r.app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return c.JSON(r.doc)
+	})
+
 	// API Documentation route
 	r.app.Use("/docs", func(c *fiber.Ctx) error {
 		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
-			SpecURL: "./docs/swagger.yaml",
+			SpecURL: "/openapi.json",
 			CustomOptions: scalar.CustomOptions{
 				PageTitle: "Product Inventory Management API Docs",
 			},
@@ -44,15 +110,83 @@ func (r *Router) RegisterRoutes() {
 	// Base Group
 	g := r.app.Group("/api/v1")
 
+	// Limits shared by the REST API and the GraphQL endpoint
+	var limits []fiber.Handler
+
+	// Per-client rate limiting
+	if cfg := r.app.Appconfig.RateLimitConfig; cfg.Enabled {
+		clients := middleware.NewClients(cfg.APIKeys)
+		limits = append(limits, middleware.RateLimit(clients,
+			ratelimit.New(cfg.ReadPerMinute, cfg.ReadBurst, cfg.MaxClients),
+			ratelimit.New(cfg.WritePerMinute, cfg.WriteBurst, cfg.MaxClients),
+		))
+		r.bulk = middleware.Throttle(clients, ratelimit.New(cfg.BulkPerMinute, cfg.BulkBurst, cfg.MaxClients))
+	}
+
+	// The server accepts bodies large enough for attachments; hold everything but the upload
+	// route, which has a limit of its own, to the body limit
+	limits = append(limits, middleware.BodyLimit(r.app.Appconfig.RateLimitConfig.BodyLimit, isAttachmentUpload))
+
+	for _, h := range limits {
+		g.Use(h)
+	}
+
+	api := openapi.NewRouter(g, r.doc)
+
+	// System routes see no tenant's data
+	r.healthRouter(api)
+	r.migrateDBRouter(api)
+
+	// Confine every route below to the request's tenant
+	g.Use(middleware.Tenant(r.app.Appconfig.TenantConfig))
+
 	// Register other routes here
-	r.migrateDBRouter(g)
-	r.productRouter(g)
+	r.productRouter(api)
+	r.attachmentRouter(api)
+	r.categoryRouter(api)
+	r.supplierRouter(api)
+	r.purchaseOrderRouter(api)
+	r.reorderRouter(api)
+	r.salesOrderRouter(api)
+	r.returnRouter(api)
+	r.stockTakeRouter(api)
+	r.reportRouter(api)
+	r.auditRouter(api)
+	r.streamRouter(api)
+
+	// GraphQL, under the same limits and tenant scope as the REST API
+	r.graphqlRouter(append(limits, middleware.Tenant(r.app.Appconfig.TenantConfig)))
 }
 
-func (r *Router) migrateDBRouter(grp fiber.Router) {
+func (r *Router) migrateDBRouter(grp *openapi.Router) {
 	dbConn := r.app.PostgresConn
 
 	h := handlers.NewMigrateDBHandler(dbConn)
 
-	grp.Get("/migrate", h.MigrateDB())
+	grp.Get("/migrate", openapi.Op{
+		Summary:     "Run database migrations",
+		Description: "Execute database schema migrations",
+		Tags:        []string{"System"},
+		Response:    "",
+		Errors:      []int{503, 500},
+	}, r.bulk, h.MigrateDB())
+}
+
+func (r *Router) healthRouter(grp *openapi.Router) {
+	h := handlers.NewHealthHandler(r.app.PostgresConn, r.app.Appconfig.Uptime)
+
+	hgrp := grp.Group("/health")
+	hgrp.Get("/live", openapi.Op{
+		Summary:     "Liveness probe",
+		Description: "Reports that the process is running, regardless of database state",
+		Tags:        []string{"System"},
+		Response:    handlers.HealthStatus{},
+	}, h.Liveness())
+	hgrp.Get("/ready", openapi.Op{
+		Summary:     "Readiness probe",
+		Description: "Reports whether the last periodic database health check succeeded",
+		Tags:        []string{"System"},
+		Response:    handlers.HealthStatus{},
+		Errors:      []int{503},
+	}, h.Readiness())
 }
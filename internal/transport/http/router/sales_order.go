@@ -0,0 +1,93 @@
+package router
+
+import (
+	"context"
+
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/salesorder"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) salesOrderRouter(grp *openapi.Router) {
+	conn := r.app.PostgresConn
+
+	// State transitions reserve, release and ship stock through a product service bound to the same transaction
+	tx := func(ctx context.Context, fn func(salesorder.Repository, product.Service) error) error {
+		return conn.Transaction(ctx, func(tx *postgres.ConnectionManager) error {
+			return fn(postgres.NewSalesOrderRepository(tx), r.productService(tx))
+		})
+	}
+
+	s := salesorder.NewService(
+		postgres.NewSalesOrderRepository(conn),
+		r.productService(conn),
+		tx,
+	)
+	h := handlers.NewSalesOrderHandler(s)
+
+	salesOrderRoutes(grp, h)
+}
+
+func salesOrderRoutes(grp *openapi.Router, h *handlers.SalesOrderHandler) {
+	sgrp := grp.Group("/sales-orders")
+
+	{
+		sgrp.Post("/", openapi.Op{
+			Summary:     "Create a sales order",
+			Description: "Create a pending order. Line prices default to the products' sale prices; nothing is allocated until the order is allocated.",
+			Tags:        []string{"Sales Orders"},
+			Body:        salesorder.SalesOrder{},
+			Status:      201,
+			Response:    salesorder.SalesOrder{},
+			Errors:      []int{400, 404, 500},
+		}, h.CreateSalesOrder())
+		sgrp.Get("/", openapi.Op{
+			Summary: "Get all sales orders",
+			Tags:    []string{"Sales Orders"},
+			Query: []openapi.Param{
+				{Name: "customer", Description: "Only orders for this customer"},
+				{Name: "status", Description: "Only orders in this status", Enum: []any{"pending", "partially_allocated", "allocated", "partially_fulfilled", "fulfilled", "cancelled"}},
+			},
+			Response: []salesorder.SalesOrder{},
+			Errors:   []int{500},
+		}, h.GetAllSalesOrders())
+		sgrp.Get("/:id", openapi.Op{
+			Summary:  "Get sales order by ID",
+			Tags:     []string{"Sales Orders"},
+			Response: salesorder.SalesOrder{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetSalesOrderByID())
+
+		sgrp.Post("/:id/allocate", openapi.Op{
+			Summary:     "Allocate sales order",
+			Description: "Reserve available stock for every line's outstanding quantity. Lines that cannot be covered stay partially allocated; allocate again once stock arrives.",
+			Tags:        []string{"Sales Orders", "Stock"},
+			Response:    salesorder.SalesOrder{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.AllocateSalesOrder())
+		sgrp.Post("/:id/fulfil", openapi.Op{
+			Summary:     "Fulfil sales order",
+			Description: "Ship allocated units, posting them as `sale` stock movements linked to the order, in one transaction with the line quantities and status.",
+			Tags:        []string{"Sales Orders", "Stock"},
+			Body:        salesorder.Fulfilment{},
+			Response:    salesorder.SalesOrder{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.FulfilSalesOrder())
+		sgrp.Post("/:id/cancel", openapi.Op{
+			Summary:     "Cancel sales order",
+			Description: "Release every allocated unit that has not been fulfilled back to available stock",
+			Tags:        []string{"Sales Orders", "Stock"},
+			Response:    salesorder.SalesOrder{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.CancelSalesOrder())
+		sgrp.Get("/:id/movements", openapi.Op{
+			Summary:     "Get fulfilment movements",
+			Description: "List the stock movements posted by fulfilling this order",
+			Tags:        []string{"Sales Orders", "Stock"},
+			Response:    []product.StockMovement{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetFulfilmentMovements())
+	}
+}
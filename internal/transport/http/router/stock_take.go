@@ -0,0 +1,101 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/stocktake"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) stockTakeRouter(grp *openapi.Router) {
+	conn := r.app.PostgresConn
+
+	// Approval posts adjustments through a product service bound to the same transaction
+	tx := func(ctx context.Context, fn func(stocktake.Repository, product.Service) error) error {
+		return conn.Transaction(ctx, func(tx *postgres.ConnectionManager) error {
+			return fn(postgres.NewStockTakeRepository(tx), r.productService(tx))
+		})
+	}
+
+	s := stocktake.NewService(
+		postgres.NewStockTakeRepository(conn),
+		r.productService(conn),
+		tx,
+	)
+	h := handlers.NewStockTakeHandler(s)
+
+	stockTakeRoutes(grp, h, r.bulk)
+}
+
+func stockTakeRoutes(grp *openapi.Router, h *handlers.StockTakeHandler, bulk fiber.Handler) {
+	sgrp := grp.Group("/stock-takes")
+
+	{
+		sgrp.Post("/", openapi.Op{
+			Summary:     "Open a stock take",
+			Description: "Open a count session with a line per product, or per variant, in scope, snapshotting the expected stock.",
+			Tags:        []string{"Stock Takes"},
+			Body:        stocktake.Scope{},
+			Status:      201,
+			Response:    stocktake.Session{},
+			Errors:      []int{400, 404, 500},
+		}, h.CreateStockTake())
+		sgrp.Get("/", openapi.Op{
+			Summary: "Get all stock takes",
+			Tags:    []string{"Stock Takes"},
+			Query: []openapi.Param{
+				{Name: "status", Description: "Only sessions in this status", Enum: []any{"open", "submitted", "approved", "cancelled"}},
+			},
+			Response: []stocktake.Session{},
+			Errors:   []int{500},
+		}, h.GetAllStockTakes())
+		sgrp.Get("/:id", openapi.Op{
+			Summary:     "Get stock take by ID",
+			Description: "Get a session with its lines and their variances",
+			Tags:        []string{"Stock Takes"},
+			Response:    stocktake.Session{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetStockTakeByID())
+
+		sgrp.Post("/:id/counts", openapi.Op{
+			Summary:     "Record counts",
+			Description: "Record counted quantities against the session's lines. The expected quantity is snapshotted again as each count is recorded; counting a line again replaces its count.",
+			Tags:        []string{"Stock Takes"},
+			Body:        stocktake.Counts{},
+			Response:    stocktake.Session{},
+			Errors:      []int{400, 404, 422, 500},
+		}, bulk, h.RecordCounts())
+		sgrp.Post("/:id/submit", openapi.Op{
+			Summary:     "Submit stock take",
+			Description: "Submit a fully counted session for approval",
+			Tags:        []string{"Stock Takes"},
+			Response:    stocktake.Session{},
+			Errors:      []int{400, 404, 422, 500},
+		}, h.SubmitStockTake())
+		sgrp.Post("/:id/approve", openapi.Op{
+			Summary:     "Approve stock take",
+			Description: "Post every variance as a `count` stock movement linked to the session, in one transaction with the approval.",
+			Tags:        []string{"Stock Takes", "Stock"},
+			Body:        stocktake.Approval{},
+			Response:    stocktake.Session{},
+			Errors:      []int{400, 404, 409, 422, 500},
+		}, h.ApproveStockTake())
+		sgrp.Post("/:id/cancel", openapi.Op{
+			Summary:  "Cancel stock take",
+			Tags:     []string{"Stock Takes"},
+			Response: stocktake.Session{},
+			Errors:   []int{400, 404, 422, 500},
+		}, h.CancelStockTake())
+		sgrp.Get("/:id/movements", openapi.Op{
+			Summary:     "Get adjustment movements",
+			Description: "List the stock movements posted by approving this session",
+			Tags:        []string{"Stock Takes", "Stock"},
+			Response:    []product.StockMovement{},
+			Errors:      []int{400, 404, 500},
+		}, h.GetAdjustmentMovements())
+	}
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) streamRouter(grp *openapi.Router) {
+	h := handlers.NewStreamHandler(r.app.Events, r.app.Appconfig.StreamConfig.HeartbeatInterval)
+
+	streamRoutes(grp, h)
+}
+
+// streamQuery lists the query parameters both stream endpoints accept.
+var streamQuery = []openapi.Param{
+	{Name: "products", Description: "Comma-separated product IDs to receive events of; all products when omitted"},
+	{Name: "low-stock", Description: "Receive only stock.low events", Type: "boolean"},
+	{Name: "last-event-id", Description: "Resume after this event ID; the Last-Event-ID header takes precedence", Type: "integer"},
+}
+
+func streamRoutes(grp *openapi.Router, h *handlers.StreamHandler) {
+	sgrp := grp.Group("/stream")
+
+	{
+		sgrp.Get("/", openapi.Op{
+			Summary: "Stream stock events",
+			Description: "Server-Sent Events of the tenant's stock changes. Each `stock.changed` or `stock.low` event carries its ID and " +
+				"a JSON `Event` with the levels the change left. Reconnecting clients resume after `Last-Event-ID`; a `reset` event " +
+				"means events were missed and the client should reload. Idle streams get a `: heartbeat` comment.",
+			Tags:     []string{"Streams"},
+			Query:    streamQuery,
+			Download: "text/event-stream",
+			Errors:   []int{400},
+		}, h.Events())
+		sgrp.Get("/ws", openapi.Op{
+			Summary: "Stream stock events over a WebSocket",
+			Description: "The events of `GET /stream` as JSON messages `{\"id\", \"type\", \"event\"}`, with `reset` and `heartbeat` " +
+				"messages as for Server-Sent Events. Send `{\"products\": [...], \"low_stock\": true}` at any time to change the " +
+				"events received; it is acknowledged with a `subscribed` message.",
+			Tags:   []string{"Streams"},
+			Query:  streamQuery,
+			Status: 101,
+			Errors: []int{400, 426},
+		}, h.WebSocket())
+	}
+}
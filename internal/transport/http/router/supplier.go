@@ -0,0 +1,56 @@
+package router
+
+import (
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/supplier"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/handlers"
+	"github.com/xxthunderblastxx/ase-challenge/internal/transport/http/openapi"
+)
+
+func (r *Router) supplierRouter(grp *openapi.Router) {
+	repo := postgres.NewSupplierRepository(r.app.PostgresConn)
+	s := supplier.NewService(repo)
+	h := handlers.NewSupplierHandler(s)
+
+	supplierRoutes(grp, h)
+}
+
+func supplierRoutes(grp *openapi.Router, h *handlers.SupplierHandler) {
+	sgrp := grp.Group("/suppliers")
+
+	{
+		sgrp.Post("/", openapi.Op{
+			Summary:  "Create a supplier",
+			Tags:     []string{"Suppliers"},
+			Body:     supplier.Supplier{},
+			Status:   201,
+			Response: supplier.Supplier{},
+			Errors:   []int{400, 500},
+		}, h.CreateSupplier())
+		sgrp.Get("/", openapi.Op{
+			Summary:  "Get all suppliers",
+			Tags:     []string{"Suppliers"},
+			Response: []supplier.Supplier{},
+			Errors:   []int{500},
+		}, h.GetAllSuppliers())
+		sgrp.Get("/:id", openapi.Op{
+			Summary:  "Get supplier by ID",
+			Tags:     []string{"Suppliers"},
+			Response: supplier.Supplier{},
+			Errors:   []int{400, 404, 500},
+		}, h.GetSupplierByID())
+		sgrp.Put("/:id", openapi.Op{
+			Summary:  "Update supplier",
+			Tags:     []string{"Suppliers"},
+			Body:     supplier.Supplier{},
+			Response: supplier.Supplier{},
+			Errors:   []int{400, 404, 500},
+		}, h.UpdateSupplier())
+		sgrp.Delete("/:id", openapi.Op{
+			Summary: "Delete supplier",
+			Tags:    []string{"Suppliers"},
+			Status:  204,
+			Errors:  []int{400, 404, 500},
+		}, h.DeleteSupplier())
+	}
+}
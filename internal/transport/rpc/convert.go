@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	inventoryv1 "github.com/xxthunderblastxx/ase-challenge/api/inventory/v1"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var bucketsToProto = map[product.Bucket]inventoryv1.Bucket{
+	product.BucketAvailable:   inventoryv1.Bucket_BUCKET_AVAILABLE,
+	product.BucketQuarantined: inventoryv1.Bucket_BUCKET_QUARANTINED,
+	product.BucketDamaged:     inventoryv1.Bucket_BUCKET_DAMAGED,
+	product.BucketInTransit:   inventoryv1.Bucket_BUCKET_IN_TRANSIT,
+}
+
+var bucketsFromProto = map[inventoryv1.Bucket]product.Bucket{
+	inventoryv1.Bucket_BUCKET_AVAILABLE:   product.BucketAvailable,
+	inventoryv1.Bucket_BUCKET_QUARANTINED: product.BucketQuarantined,
+	inventoryv1.Bucket_BUCKET_DAMAGED:     product.BucketDamaged,
+	inventoryv1.Bucket_BUCKET_IN_TRANSIT:  product.BucketInTransit,
+}
+
+// productFromProto returns the writable fields of a product message as a product.
+func productFromProto(m *inventoryv1.Product) (*product.Product, error) {
+	if m == nil {
+		return nil, apperrors.NewMissingRequiredDataError("product")
+	}
+
+	p := &product.Product{
+		Name:             m.GetName(),
+		Description:      m.GetDescription(),
+		Attributes:       m.GetAttributes().AsMap(),
+		Tags:             m.GetTags(),
+		StockQuantity:    int(m.GetStockQuantity()),
+		LowStockThresold: int(m.GetLowStockThreshold()),
+		BaseUnit:         m.GetBaseUnit(),
+		Currency:         m.GetCurrency(),
+		UnitCost:         m.GetUnitCost(),
+		SalePrice:        m.GetSalePrice(),
+		LeadTimeDays:     int(m.GetLeadTimeDays()),
+		LotTracked:       m.GetLotTracked(),
+		Serialized:       m.GetSerialized(),
+	}
+
+	if m.GetAttributes() == nil {
+		p.Attributes = nil
+	}
+
+	if m.GetSupplierId() != "" {
+		id, err := uuid.Parse(m.GetSupplierId())
+		if err != nil {
+			return nil, apperrors.NewInvalidFormatError("supplier_id")
+		}
+		p.SupplierID = &id
+	}
+
+	return p, nil
+}
+
+func productToProto(p *product.Product) (*inventoryv1.Product, error) {
+	m := &inventoryv1.Product{
+		Id:                p.ID.String(),
+		Name:              p.Name,
+		Description:       p.Description,
+		Tags:              p.Tags,
+		StockQuantity:     int32(p.StockQuantity),
+		ReservedQuantity:  int32(p.ReservedQuantity),
+		LowStockThreshold: int32(p.LowStockThresold),
+		BaseUnit:          p.BaseUnit,
+		Currency:          p.Currency,
+		UnitCost:          p.UnitCost,
+		SalePrice:         p.SalePrice,
+		LeadTimeDays:      int32(p.LeadTimeDays),
+		LotTracked:        p.LotTracked,
+		Serialized:        p.Serialized,
+		CreatedAt:         timestamp(p.CreatedAt),
+		UpdatedAt:         timestamp(p.UpdatedAt),
+	}
+
+	if len(p.Attributes) > 0 {
+		attrs, err := structpb.NewStruct(p.Attributes)
+		if err != nil {
+			return nil, apperrors.NewInternalServerError("failed to encode product attributes: " + err.Error())
+		}
+		m.Attributes = attrs
+	}
+
+	if p.SupplierID != nil {
+		m.SupplierId = p.SupplierID.String()
+	}
+
+	for _, c := range p.Categories {
+		m.CategoryIds = append(m.CategoryIds, c.ID.String())
+	}
+
+	for _, o := range p.Options {
+		m.Options = append(m.Options, &inventoryv1.OptionAxis{Name: o.Name, Values: o.Values})
+	}
+
+	for _, v := range p.Variants {
+		m.Variants = append(m.Variants, &inventoryv1.Variant{
+			Id:                v.ID.String(),
+			Sku:               v.SKU,
+			Options:           v.Options,
+			StockQuantity:     int32(v.StockQuantity),
+			ReservedQuantity:  int32(v.ReservedQuantity),
+			LowStockThreshold: int32(v.LowStockThreshold),
+		})
+	}
+
+	for _, c := range p.Components {
+		m.Components = append(m.Components, &inventoryv1.KitComponent{
+			ComponentId: c.ComponentID.String(),
+			Quantity:    int32(c.Quantity),
+		})
+	}
+
+	return m, nil
+}
+
+func productsToProto(products []product.Product) ([]*inventoryv1.Product, error) {
+	out := make([]*inventoryv1.Product, 0, len(products))
+	for i := range products {
+		m, err := productToProto(&products[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func bucketLevelsToProto(levels []product.BucketLevels) *inventoryv1.StockBuckets {
+	out := &inventoryv1.StockBuckets{}
+	for _, l := range levels {
+		m := &inventoryv1.BucketLevels{
+			ProductId:   l.ProductID.String(),
+			Available:   int32(l.Available),
+			Quarantined: int32(l.Quarantined),
+			Damaged:     int32(l.Damaged),
+			InTransit:   int32(l.InTransit),
+		}
+		if l.VariantID != nil {
+			m.VariantId = l.VariantID.String()
+		}
+		out.Levels = append(out.Levels, m)
+	}
+	return out
+}
+
+func movementToProto(mv product.StockMovement) *inventoryv1.StockMovement {
+	m := &inventoryv1.StockMovement{
+		Id:            mv.ID.String(),
+		ProductId:     mv.ProductID.String(),
+		Serials:       mv.Serials,
+		Quantity:      int32(mv.Quantity),
+		Bucket:        bucketsToProto[mv.Bucket],
+		Reason:        string(mv.Reason),
+		UnitCost:      mv.UnitCost,
+		Currency:      mv.Currency,
+		ReferenceType: mv.ReferenceType,
+		ReferenceId:   mv.ReferenceID,
+		Note:          mv.Note,
+		OccurredAt:    timestamp(mv.OccurredAt),
+	}
+	if mv.VariantID != nil {
+		m.VariantId = mv.VariantID.String()
+	}
+	if mv.LotID != nil {
+		m.LotId = mv.LotID.String()
+	}
+	return m
+}
+
+// timeFromProto returns the time of a timestamp field, or the zero time when it is unset.
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
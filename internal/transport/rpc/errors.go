@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain identifies the service in the ErrorInfo details of a status.
+const errorDomain = "inventory"
+
+// grpcCodes maps each AppError code to the gRPC status code closest to its HTTP status.
+var grpcCodes = map[apperrors.ErrorCode]codes.Code{
+	apperrors.ValidationError:     codes.InvalidArgument,
+	apperrors.InvalidInput:        codes.InvalidArgument,
+	apperrors.MissingRequiredData: codes.InvalidArgument,
+	apperrors.InvalidFormat:       codes.InvalidArgument,
+
+	apperrors.NotFoundError:         codes.NotFound,
+	apperrors.ProductNotFound:       codes.NotFound,
+	apperrors.CategoryNotFound:      codes.NotFound,
+	apperrors.VariantNotFound:       codes.NotFound,
+	apperrors.SupplierNotFound:      codes.NotFound,
+	apperrors.PurchaseOrderNotFound: codes.NotFound,
+	apperrors.StockTakeNotFound:     codes.NotFound,
+	apperrors.SalesOrderNotFound:    codes.NotFound,
+	apperrors.ReturnNotFound:        codes.NotFound,
+	apperrors.AttachmentNotFound:    codes.NotFound,
+	apperrors.UserNotFound:          codes.NotFound,
+
+	apperrors.BusinessLogicError: codes.FailedPrecondition,
+	apperrors.InsufficientStock:  codes.FailedPrecondition,
+	apperrors.DuplicateEntry:     codes.AlreadyExists,
+
+	apperrors.DatabaseError:   codes.Internal,
+	apperrors.ConnectionError: codes.Unavailable,
+	apperrors.MigrationError:  codes.Internal,
+
+	apperrors.InternalServerError: codes.Internal,
+	apperrors.UnknownError:        codes.Unknown,
+
+	apperrors.UnauthorizedError: codes.Unauthenticated,
+	apperrors.ForbiddenError:    codes.PermissionDenied,
+	apperrors.TokenExpiredError: codes.Unauthenticated,
+
+	apperrors.RateLimited:     codes.ResourceExhausted,
+	apperrors.PayloadTooLarge: codes.ResourceExhausted,
+}
+
+// toStatus converts an error returned by the service layer to a gRPC status error. An
+// AppError keeps its message, and its code is carried as the reason of an ErrorInfo
+// detail so that clients can tell, say, PRODUCT_NOT_FOUND from VARIANT_NOT_FOUND.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		log.Printf("☹️ unexpected gRPC error: %v", err)
+		return status.Error(codes.Internal, "an internal error occurred")
+	}
+
+	code, ok := grpcCodes[appErr.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st, detailErr := status.New(code, appErr.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: string(appErr.Code),
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, appErr.Message)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"validation", apperrors.NewInvalidInputError("bad"), codes.InvalidArgument},
+		{"not found", apperrors.NewVariantNotFoundError("v"), codes.NotFound},
+		{"wrapped", fmt.Errorf("reserve: %w", apperrors.NewInsufficientStockError(1, 2)), codes.FailedPrecondition},
+		{"unauthenticated", apperrors.NewTokenExpiredError(), codes.Unauthenticated},
+		{"forbidden", apperrors.NewForbiddenError("no"), codes.PermissionDenied},
+		{"database", apperrors.NewDatabaseError("down"), codes.Internal},
+		{"status", status.Error(codes.Aborted, "aborted"), codes.Aborted},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"unexpected", errors.New("boom"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(toStatus(tt.err)); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToStatusHidesUnexpectedErrors(t *testing.T) {
+	st := status.Convert(toStatus(errors.New("pq: password authentication failed")))
+	if st.Message() != "an internal error occurred" {
+		t.Fatalf("expected a generic message, got %q", st.Message())
+	}
+}
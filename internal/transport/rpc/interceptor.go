@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+	inventoryv1 "github.com/xxthunderblastxx/ase-challenge/api/inventory/v1"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys read from incoming calls. gRPC lower-cases metadata keys.
+const (
+	requestIDKey     = "x-request-id"
+	actorKey         = "x-actor"
+	authorizationKey = "authorization"
+	tenantKey        = "x-tenant-id"
+)
+
+// maxMetadataValue bounds the client-supplied values recorded in the audit trail.
+const maxMetadataValue = 128
+
+// readOnlyMethods may be served by the read replica.
+var readOnlyMethods = map[string]bool{
+	inventoryv1.ProductService_GetProduct_FullMethodName:      true,
+	inventoryv1.ProductService_ListProducts_FullMethodName:    true,
+	inventoryv1.ProductService_SearchProducts_FullMethodName:  true,
+	inventoryv1.ProductService_GetStockBuckets_FullMethodName: true,
+	inventoryv1.ProductService_ListMovements_FullMethodName:   true,
+}
+
+// recoverPanics turns a panic in a handler into an Internal status instead of crashing the process.
+func recoverPanics(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("☹️ panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "an internal error occurred")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// mapErrors converts the AppErrors returned by handlers to gRPC statuses.
+func mapErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatus(err)
+}
+
+// requestContext prepares the context of a call the way the HTTP middleware does for a
+// request: it attributes changes to the x-actor and x-request-id metadata (generating and
+// echoing the request ID), confines the call to the resolved tenant, and marks read-only
+// methods so that they may be served by the replica.
+func requestContext(resolver *tenant.Resolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		id := firstValue(md, requestIDKey)
+		if id == "" || len(id) > maxMetadataValue {
+			id = uuid.NewString()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+		actor := firstValue(md, actorKey)
+		if len(actor) > maxMetadataValue {
+			actor = actor[:maxMetadataValue]
+		}
+
+		ctx = audit.WithRequestID(ctx, id)
+		ctx = audit.WithActor(ctx, actor)
+
+		identity, err := resolver.Resolve(firstValue(md, authorizationKey), firstValue(md, tenantKey))
+		if err != nil {
+			return nil, err
+		}
+		if identity.Tenant != "" {
+			ctx = tenant.WithTenant(ctx, identity.Tenant)
+		}
+		if identity.Subject != "" {
+			ctx = audit.WithActor(ctx, identity.Subject)
+		}
+
+		if readOnlyMethods[info.FullMethod] {
+			ctx = postgres.WithReadOnly(ctx)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,325 @@
+package rpc
+
+import (
+	"context"
+
+	inventoryv1 "github.com/xxthunderblastxx/ase-challenge/api/inventory/v1"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// productServer implements inventoryv1.ProductServiceServer on top of product.Service.
+type productServer struct {
+	inventoryv1.UnimplementedProductServiceServer
+
+	service product.Service
+}
+
+func newProductServer(s product.Service) *productServer {
+	return &productServer{
+		service: s,
+	}
+}
+
+// CreateProduct implements inventoryv1.ProductServiceServer.
+func (s *productServer) CreateProduct(ctx context.Context, req *inventoryv1.CreateProductRequest) (*inventoryv1.Product, error) {
+	p, err := productFromProto(req.GetProduct())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.CreateProduct(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return s.product(ctx, p.ID.String())
+}
+
+// GetProduct implements inventoryv1.ProductServiceServer.
+func (s *productServer) GetProduct(ctx context.Context, req *inventoryv1.GetProductRequest) (*inventoryv1.Product, error) {
+	if req.GetAsOf() == nil {
+		return s.product(ctx, req.GetId())
+	}
+
+	p, err := s.service.GetProductAsOf(ctx, req.GetId(), req.GetAsOf().AsTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return productToProto(p)
+}
+
+// ListProducts implements inventoryv1.ProductServiceServer.
+func (s *productServer) ListProducts(ctx context.Context, req *inventoryv1.ListProductsRequest) (*inventoryv1.ListProductsResponse, error) {
+	filter := product.Filter{
+		CategoryID:         req.GetCategoryId(),
+		IncludeDescendants: req.IncludeDescendants == nil || req.GetIncludeDescendants(),
+		Attributes:         req.GetAttributes(),
+		Tags:               req.GetTags(),
+		AsOf:               timeFromProto(req.GetAsOf()),
+	}
+
+	products, err := s.service.GetAllProducts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep products at or below their individual low-stock threshold
+	if req.GetLowStock() {
+		lowStock := []product.Product{}
+		for _, p := range products {
+			if p.StockQuantity <= p.LowStockThresold {
+				lowStock = append(lowStock, p)
+			}
+		}
+		products = lowStock
+	}
+
+	out, err := productsToProto(products)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryv1.ListProductsResponse{Products: out}, nil
+}
+
+// SearchProducts implements inventoryv1.ProductServiceServer.
+func (s *productServer) SearchProducts(ctx context.Context, req *inventoryv1.SearchProductsRequest) (*inventoryv1.SearchProductsResponse, error) {
+	page, err := s.service.SearchProducts(ctx, product.SearchQuery{
+		Text:   req.GetQuery(),
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &inventoryv1.SearchProductsResponse{
+		Total:  page.Total,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	}
+	for i := range page.Results {
+		r := &page.Results[i]
+
+		p, err := productToProto(&r.Product)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Results = append(resp.Results, &inventoryv1.SearchProductsResponse_Result{
+			Product:              p,
+			Rank:                 r.Rank,
+			NameHighlight:        r.Highlights.Name,
+			DescriptionHighlight: r.Highlights.Description,
+			MatchedSku:           r.MatchedSKU,
+		})
+	}
+
+	return resp, nil
+}
+
+// UpdateProduct implements inventoryv1.ProductServiceServer.
+func (s *productServer) UpdateProduct(ctx context.Context, req *inventoryv1.UpdateProductRequest) (*inventoryv1.Product, error) {
+	p, err := productFromProto(req.GetProduct())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.UpdateProduct(ctx, req.GetId(), p); err != nil {
+		return nil, err
+	}
+
+	return s.product(ctx, req.GetId())
+}
+
+// DeleteProduct implements inventoryv1.ProductServiceServer.
+func (s *productServer) DeleteProduct(ctx context.Context, req *inventoryv1.DeleteProductRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeleteProduct(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreProduct implements inventoryv1.ProductServiceServer.
+func (s *productServer) RestoreProduct(ctx context.Context, req *inventoryv1.RestoreProductRequest) (*inventoryv1.Product, error) {
+	if err := s.service.RestoreProduct(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return s.product(ctx, req.GetId())
+}
+
+// IncrementStock implements inventoryv1.ProductServiceServer.
+func (s *productServer) IncrementStock(ctx context.Context, req *inventoryv1.StockChangeRequest) (*inventoryv1.StockChangeResponse, error) {
+	id := req.GetId()
+
+	if req.GetQuantity() <= 0 {
+		return nil, apperrors.NewInvalidInputError("quantity must be greater than 0")
+	}
+
+	quantity, err := s.baseQuantity(ctx, id, req.GetQuantity(), req.GetUnit())
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(req.GetSerials()) > 0:
+		if quantity != len(req.GetSerials()) {
+			return nil, apperrors.NewInvalidInputError("quantity must equal the number of serials")
+		}
+		err = s.service.IncrementSerials(ctx, id, req.GetSerials())
+	case req.GetVariantId() != "":
+		err = s.service.IncrementVariantStock(ctx, id, req.GetVariantId(), quantity)
+	default:
+		err = s.service.IncermentStock(ctx, id, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stockChanged(ctx, id, quantity)
+}
+
+// DecrementStock implements inventoryv1.ProductServiceServer.
+func (s *productServer) DecrementStock(ctx context.Context, req *inventoryv1.StockChangeRequest) (*inventoryv1.StockChangeResponse, error) {
+	id := req.GetId()
+
+	if req.GetQuantity() <= 0 {
+		return nil, apperrors.NewInvalidInputError("quantity must be greater than 0")
+	}
+
+	quantity, err := s.baseQuantity(ctx, id, req.GetQuantity(), req.GetUnit())
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(req.GetSerials()) > 0:
+		if quantity != len(req.GetSerials()) {
+			return nil, apperrors.NewInvalidInputError("quantity must equal the number of serials")
+		}
+		err = s.service.DecrementSerials(ctx, id, req.GetSerials())
+	case req.GetVariantId() != "":
+		err = s.service.DecrementVariantStock(ctx, id, req.GetVariantId(), quantity)
+	default:
+		err = s.service.DecrementStock(ctx, id, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stockChanged(ctx, id, quantity)
+}
+
+// ReserveStock implements inventoryv1.ProductServiceServer.
+func (s *productServer) ReserveStock(ctx context.Context, req *inventoryv1.ReserveStockRequest) (*inventoryv1.ReserveStockResponse, error) {
+	reserved, err := s.service.ReserveStock(ctx, req.GetId(), req.GetVariantId(), int(req.GetQuantity()))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.product(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryv1.ReserveStockResponse{Product: p, Reserved: int32(reserved)}, nil
+}
+
+// ReleaseStock implements inventoryv1.ProductServiceServer.
+func (s *productServer) ReleaseStock(ctx context.Context, req *inventoryv1.ReleaseStockRequest) (*inventoryv1.Product, error) {
+	if err := s.service.ReleaseStock(ctx, req.GetId(), req.GetVariantId(), int(req.GetQuantity())); err != nil {
+		return nil, err
+	}
+
+	return s.product(ctx, req.GetId())
+}
+
+// MoveStock implements inventoryv1.ProductServiceServer.
+func (s *productServer) MoveStock(ctx context.Context, req *inventoryv1.MoveStockRequest) (*inventoryv1.StockBuckets, error) {
+	move := product.BucketMove{
+		VariantID: req.GetVariantId(),
+		From:      bucketsFromProto[req.GetFrom()],
+		To:        bucketsFromProto[req.GetTo()],
+		Quantity:  int(req.GetQuantity()),
+		Unit:      req.GetUnit(),
+		LotNumber: req.GetLotNumber(),
+		Serials:   req.GetSerials(),
+		Note:      req.GetNote(),
+	}
+
+	if err := s.service.MoveStock(ctx, req.GetId(), move); err != nil {
+		return nil, err
+	}
+
+	return s.GetStockBuckets(ctx, &inventoryv1.GetStockBucketsRequest{Id: req.GetId()})
+}
+
+// GetStockBuckets implements inventoryv1.ProductServiceServer.
+func (s *productServer) GetStockBuckets(ctx context.Context, req *inventoryv1.GetStockBucketsRequest) (*inventoryv1.StockBuckets, error) {
+	levels, err := s.service.GetStockBuckets(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return bucketLevelsToProto(levels), nil
+}
+
+// ListMovements implements inventoryv1.ProductServiceServer.
+func (s *productServer) ListMovements(ctx context.Context, req *inventoryv1.ListMovementsRequest) (*inventoryv1.ListMovementsResponse, error) {
+	// Check the product exists so an unknown ID is NotFound rather than an empty ledger
+	if _, err := s.service.GetProductByID(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	movements, err := s.service.ListMovements(ctx, product.MovementFilter{
+		ProductID:     req.GetId(),
+		ReferenceType: req.GetReferenceType(),
+		ReferenceID:   req.GetReferenceId(),
+		Serial:        req.GetSerial(),
+		Since:         timeFromProto(req.GetSince()),
+		Until:         timeFromProto(req.GetUntil()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &inventoryv1.ListMovementsResponse{}
+	for _, mv := range movements {
+		resp.Movements = append(resp.Movements, movementToProto(mv))
+	}
+
+	return resp, nil
+}
+
+// baseQuantity converts a request's quantity in unit to the product's base unit.
+func (s *productServer) baseQuantity(ctx context.Context, id string, n int32, unit string) (int, error) {
+	if unit == "" {
+		return int(n), nil
+	}
+
+	return s.service.ToBaseUnits(ctx, id, quantity.FromInt(int(n)), unit)
+}
+
+// product re-reads a product after a change, with its associations.
+func (s *productServer) product(ctx context.Context, id string) (*inventoryv1.Product, error) {
+	p, err := s.service.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return productToProto(p)
+}
+
+func (s *productServer) stockChanged(ctx context.Context, id string, quantity int) (*inventoryv1.StockChangeResponse, error) {
+	p, err := s.product(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryv1.StockChangeResponse{Product: p, Quantity: int32(quantity)}, nil
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	inventoryv1 "github.com/xxthunderblastxx/ase-challenge/api/inventory/v1"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	"github.com/xxthunderblastxx/ase-challenge/internal/infrastructure/postgres"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server serves the inventory gRPC API alongside the HTTP server.
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+	conn   *postgres.ConnectionManager
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New builds the gRPC server on the same product service as the HTTP API, publishing
+// stock changes to events. Its health service reports SERVING while the database health
+// check passes.
+func New(cfg *config.AppConfig, conn *postgres.ConnectionManager, events product.Publisher) *Server {
+	s := newServer(product.NewServiceWithEvents(postgres.NewProductRepository(conn), events), cfg.TenantConfig)
+	s.conn = conn
+
+	s.updateHealth()
+	if interval := cfg.PostgresConfig.HealthCheckInterval; interval > 0 {
+		go s.watchHealth(interval)
+	}
+
+	return s
+}
+
+func newServer(svc product.Service, cfg config.TenantConfig) *Server {
+	resolver := tenant.NewResolver(cfg.TokenSecret, cfg.Default, cfg.Required)
+
+	g := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		recoverPanics,
+		mapErrors,
+		requestContext(resolver),
+	))
+
+	hs := health.NewServer()
+
+	inventoryv1.RegisterProductServiceServer(g, newProductServer(svc))
+	healthpb.RegisterHealthServer(g, hs)
+	reflection.Register(g)
+
+	return &Server{
+		grpc:   g,
+		health: hs,
+		stop:   make(chan struct{}),
+	}
+}
+
+// ListenAndServe accepts connections on addr until the server is shut down.
+func (s *Server) ListenAndServe(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(lis)
+}
+
+// Serve accepts connections on lis until the server is shut down.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown stops accepting calls and waits for in-flight ones to finish, cancelling
+// any still running after timeout.
+func (s *Server) Shutdown(timeout time.Duration) {
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		s.grpc.Stop()
+	}
+}
+
+func (s *Server) watchHealth(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.updateHealth()
+		}
+	}
+}
+
+// updateHealth mirrors the database readiness in the overall and product service status.
+func (s *Server) updateHealth() {
+	st := healthpb.HealthCheckResponse_NOT_SERVING
+	if s.conn != nil && s.conn.IsReady() {
+		st = healthpb.HealthCheckResponse_SERVING
+	}
+
+	s.health.SetServingStatus("", st)
+	s.health.SetServingStatus(inventoryv1.ProductService_ServiceDesc.ServiceName, st)
+}
@@ -0,0 +1,239 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	inventoryv1 "github.com/xxthunderblastxx/ase-challenge/api/inventory/v1"
+	"github.com/xxthunderblastxx/ase-challenge/internal/config"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/audit"
+	"github.com/xxthunderblastxx/ase-challenge/internal/domain/product"
+	apperrors "github.com/xxthunderblastxx/ase-challenge/internal/pkg/errors"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/model"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/quantity"
+	"github.com/xxthunderblastxx/ase-challenge/internal/pkg/tenant"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const missingID = "00000000-0000-0000-0000-000000000000"
+
+// rpcProductService is a product.Service holding one product, with missingID reported as
+// not found. It records the tenant and actor of the last call. Operations not covered by
+// the tests fall through to the nil embedded Service.
+type rpcProductService struct {
+	product.Service
+
+	product *product.Product
+	tenant  string
+	actor   string
+	variant string
+}
+
+func (m *rpcProductService) GetProductByID(ctx context.Context, id string) (*product.Product, error) {
+	m.tenant, m.actor = tenant.FromContext(ctx), audit.ActorFrom(ctx)
+	if id == missingID {
+		return nil, apperrors.NewProductNotFoundError(id)
+	}
+	return m.product, nil
+}
+
+func (m *rpcProductService) GetAllProducts(context.Context, product.Filter) ([]product.Product, error) {
+	low := *m.product
+	low.ID, low.StockQuantity = uuid.New(), low.LowStockThresold
+	return []product.Product{*m.product, low}, nil
+}
+
+func (m *rpcProductService) IncermentStock(_ context.Context, _ string, quantity int) error {
+	m.product.StockQuantity += quantity
+	return nil
+}
+
+func (m *rpcProductService) IncrementVariantStock(_ context.Context, _, variantID string, _ int) error {
+	m.variant = variantID
+	return nil
+}
+
+// ToBaseUnits knows a single unit, cases of 12.
+func (m *rpcProductService) ToBaseUnits(_ context.Context, _ string, q quantity.Decimal, unit string) (int, error) {
+	if unit != "case" {
+		return 0, apperrors.NewInvalidInputError("unit " + unit + " is not defined for this product")
+	}
+	n, err := strconv.Atoi(string(q))
+	return n * 12, err
+}
+
+func (m *rpcProductService) DecrementStock(_ context.Context, _ string, quantity int) error {
+	if quantity > m.product.StockQuantity {
+		return apperrors.NewInsufficientStockError(m.product.StockQuantity, quantity)
+	}
+	m.product.StockQuantity -= quantity
+	return nil
+}
+
+func (m *rpcProductService) DeleteProduct(context.Context, string) error {
+	panic("boom")
+}
+
+func newTestClient(t *testing.T, svc product.Service, cfg config.TenantConfig) *grpc.ClientConn {
+	t.Helper()
+
+	s := newServer(svc, cfg)
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(func() { s.Shutdown(0) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func newTestProduct() *product.Product {
+	return &product.Product{
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		Name:             "Widget",
+		Attributes:       map[string]any{"colour": "red"},
+		StockQuantity:    10,
+		LowStockThresold: 5,
+	}
+}
+
+func TestProductServer(t *testing.T) {
+	svc := &rpcProductService{product: newTestProduct()}
+	client := inventoryv1.NewProductServiceClient(newTestClient(t, svc, config.TenantConfig{Default: "default"}))
+	ctx := context.Background()
+
+	t.Run("gets a product in the caller's tenant", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(ctx, tenantKey, "acme", actorKey, "alice", requestIDKey, "req-1")
+
+		var header metadata.MD
+		p, err := client.GetProduct(ctx, &inventoryv1.GetProductRequest{Id: svc.product.ID.String()}, grpc.Header(&header))
+		if err != nil {
+			t.Fatalf("GetProduct failed: %v", err)
+		}
+		if p.GetName() != "Widget" || p.GetAttributes().AsMap()["colour"] != "red" {
+			t.Fatalf("unexpected product: %v", p)
+		}
+		if svc.tenant != "acme" || svc.actor != "alice" {
+			t.Fatalf("expected acme as alice, got %q as %q", svc.tenant, svc.actor)
+		}
+		if got := header.Get(requestIDKey); len(got) != 1 || got[0] != "req-1" {
+			t.Fatalf("expected the request ID to be echoed, got %v", got)
+		}
+	})
+
+	t.Run("maps AppErrors to status codes with the error code as reason", func(t *testing.T) {
+		_, err := client.GetProduct(ctx, &inventoryv1.GetProductRequest{Id: missingID})
+		st := status.Convert(err)
+		if st.Code() != codes.NotFound {
+			t.Fatalf("expected NotFound, got %v", st.Code())
+		}
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		if !ok || info.GetReason() != string(apperrors.ProductNotFound) {
+			t.Fatalf("expected a PRODUCT_NOT_FOUND reason, got %v", st.Details())
+		}
+
+		_, err = client.DecrementStock(ctx, &inventoryv1.StockChangeRequest{Id: svc.product.ID.String(), Quantity: 1000})
+		if code := status.Code(err); code != codes.FailedPrecondition {
+			t.Fatalf("expected FailedPrecondition for insufficient stock, got %v", code)
+		}
+	})
+
+	t.Run("validates and routes stock changes", func(t *testing.T) {
+		id := svc.product.ID.String()
+
+		_, err := client.IncrementStock(ctx, &inventoryv1.StockChangeRequest{Id: id})
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for a zero quantity, got %v", code)
+		}
+
+		_, err = client.IncrementStock(ctx, &inventoryv1.StockChangeRequest{Id: id, Quantity: 2, Serials: []string{"SN-1"}})
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for a serial count mismatch, got %v", code)
+		}
+
+		resp, err := client.IncrementStock(ctx, &inventoryv1.StockChangeRequest{Id: id, Quantity: 5})
+		if err != nil || resp.GetProduct().GetStockQuantity() != 15 || resp.GetQuantity() != 5 {
+			t.Fatalf("expected stock of 15 after adding 5, got %v (%v)", resp, err)
+		}
+
+		variantID := uuid.NewString()
+		if _, err := client.IncrementStock(ctx, &inventoryv1.StockChangeRequest{Id: id, VariantId: variantID, Quantity: 1}); err != nil || svc.variant != variantID {
+			t.Fatalf("expected the variant to be incremented, got %q (%v)", svc.variant, err)
+		}
+
+		resp, err = client.IncrementStock(ctx, &inventoryv1.StockChangeRequest{Id: id, Quantity: 1, Unit: "case"})
+		if err != nil || resp.GetProduct().GetStockQuantity() != 27 || resp.GetQuantity() != 12 {
+			t.Fatalf("expected stock of 27 after adding a case of 12, got %v (%v)", resp, err)
+		}
+
+		_, err = client.IncrementStock(ctx, &inventoryv1.StockChangeRequest{Id: id, Quantity: 1, Unit: "pallet"})
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for an unknown unit, got %v", code)
+		}
+	})
+
+	t.Run("filters low stock", func(t *testing.T) {
+		resp, err := client.ListProducts(ctx, &inventoryv1.ListProductsRequest{LowStock: true})
+		if err != nil || len(resp.GetProducts()) != 1 {
+			t.Fatalf("expected one low-stock product, got %v (%v)", resp, err)
+		}
+	})
+
+	t.Run("recovers from panics", func(t *testing.T) {
+		_, err := client.DeleteProduct(ctx, &inventoryv1.DeleteProductRequest{Id: svc.product.ID.String()})
+		if code := status.Code(err); code != codes.Internal {
+			t.Fatalf("expected Internal after a panic, got %v", code)
+		}
+	})
+}
+
+func TestProductServerTenantRequired(t *testing.T) {
+	svc := &rpcProductService{product: newTestProduct()}
+	client := inventoryv1.NewProductServiceClient(newTestClient(t, svc, config.TenantConfig{TokenSecret: "secret"}))
+
+	_, err := client.GetProduct(context.Background(), &inventoryv1.GetProductRequest{Id: svc.product.ID.String()})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a token, got %v", code)
+	}
+
+	token, _ := tenant.NewToken(tenant.Claims{Tenant: "acme", Subject: "alice"}, []byte("secret"))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), authorizationKey, "Bearer "+token)
+	if _, err := client.GetProduct(ctx, &inventoryv1.GetProductRequest{Id: svc.product.ID.String()}); err != nil {
+		t.Fatalf("GetProduct failed: %v", err)
+	}
+	if svc.tenant != "acme" || svc.actor != "alice" {
+		t.Fatalf("expected acme as alice, got %q as %q", svc.tenant, svc.actor)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, tenantKey, "globex")
+	_, err = client.GetProduct(ctx, &inventoryv1.GetProductRequest{Id: svc.product.ID.String()})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another tenant, got %v", code)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	conn := newTestClient(t, &rpcProductService{}, config.TenantConfig{})
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v (%v)", resp, err)
+	}
+}